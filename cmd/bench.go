@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/bench"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/spf13/cobra"
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench [scenario...]",
+	Short: "Measure prompt rendering performance",
+	Long: `Renders the prompt repeatedly, and reports the time and memory each render
+takes.  By default, this renders your prompt with your configuration in the
+current directory, exactly as "prompt" would, including loading the
+configuration.  This takes the same flags as "prompt", so you can measure
+the prompt in a different folder:
+
+  ` + programName + ` bench --path ~/dev/myproject
+
+If scenarios are given, or if --all is passed, this instead renders the
+prompt in a series of synthetic scenarios, which makes it easier to compare
+results between machines.  Please include this output when reporting
+performance issues.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runs, _ := cmd.Flags().GetInt("runs")
+		list, _ := cmd.Flags().GetBool("list")
+		all, _ := cmd.Flags().GetBool("all")
+
+		if list {
+			for _, scenario := range bench.Scenarios() {
+				fmt.Printf("%-14s %s\n", scenario.Name, scenario.Description)
+			}
+			return
+		}
+
+		if len(args) == 0 && !all {
+			benchCurrentConfig(cmd, runs)
+			return
+		}
+
+		scenarios := bench.Scenarios()
+		if len(args) > 0 {
+			scenarios = scenarios[:0]
+			for _, name := range args {
+				scenario, ok := bench.FindScenario(name)
+				if !ok {
+					log.Error("Unknown scenario: " + name)
+					os.Exit(1)
+				}
+				scenarios = append(scenarios, scenario)
+			}
+		}
+
+		printBenchHeader()
+
+		for _, scenario := range scenarios {
+			result, err := bench.Run(scenario, runs)
+			if errors.Is(err, bench.ErrScenarioSkipped) {
+				fmt.Printf("%-14s %s\n", scenario.Name, gchalk.BrightBlack("skipped"))
+				continue
+			} else if err != nil {
+				fmt.Printf("%-14s %s\n", scenario.Name, gchalk.Red(err.Error()))
+				continue
+			}
+
+			printBenchResult(result)
+		}
+	},
+}
+
+// benchCurrentConfig measures how long it takes to load the configuration and
+// render the prompt in the current directory.
+func benchCurrentConfig(cmd *cobra.Command, runs int) {
+	// Load the configuration once up front, so any errors are only reported once.
+	configuration, err := readConfig()
+	if err != nil {
+		log.Error("Error loading configuration: ", err)
+		os.Exit(1)
+	}
+
+	context := newPromptContext(cmd, configuration)
+	fmt.Printf("Rendering prompt in %s\n", context.Globals.CWD)
+	if loadedConfigFile != "" {
+		fmt.Printf("Using configuration from %s\n", loadedConfigFile)
+	}
+
+	printBenchHeader()
+	printBenchResult(bench.Measure("current", runs, func() {
+		configuration, err := readConfig()
+		if err != nil {
+			return
+		}
+		context := newPromptContext(cmd, configuration)
+		modules.RenderPrompt(context, configuration.Prompt)
+	}))
+}
+
+func printBenchHeader() {
+	fmt.Printf("%s %s %s/%s, %s, %d CPUs\n\n", programName, version, runtime.GOOS, runtime.GOARCH, runtime.Version(), runtime.NumCPU())
+	fmt.Printf("%-14s %10s %10s %10s %12s %12s\n", "scenario", "mean", "min", "max", "allocs/op", "bytes/op")
+}
+
+func printBenchResult(result bench.Result) {
+	fmt.Printf(
+		"%-14s %10s %10s %10s %12d %12d\n",
+		result.Scenario,
+		result.Mean.Round(time.Microsecond),
+		result.Min.Round(time.Microsecond),
+		result.Max.Round(time.Microsecond),
+		result.AllocsPerRun,
+		result.BytesPerRun,
+	)
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.Flags().IntP("runs", "n", 20, "Number of times to render the prompt in each scenario")
+	benchCmd.Flags().Bool("list", false, "List available scenarios")
+	benchCmd.Flags().Bool("all", false, "Run every synthetic scenario instead of the current configuration")
+	addPromptContextFlags(benchCmd)
+}
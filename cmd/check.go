@@ -16,7 +16,10 @@ var checkCmd = &cobra.Command{
 	Use:   "check [file]",
 	Short: "Check configuration file for errors",
 	Long: `Checks a configuration file for errors.  If no filename is given,
-it will check the default configuration file.`,
+it will check the default configuration file.
+
+This will report unknown modules and fields, invalid styles and colors, and
+templates that fail to compile.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		log.SetVerbose(true)
@@ -33,15 +36,25 @@ it will check the default configuration file.`,
 
 		fmt.Println("Checking config file: " + configFile)
 
-		contents, err := os.ReadFile(configFile)
+		errs, err := config.CheckConfigurationFile(configFile)
 		if err != nil {
 			log.Error("Could not read configuration file " + configFile + ": " + err.Error())
 			os.Exit(1)
 		}
 
-		err = config.ValidateConfiguration(contents)
-		if err != nil {
-			log.Error(err.Error())
+		if len(errs) > 0 {
+			for _, configError := range errs {
+				if configError.Line != 0 {
+					fmt.Printf("%s:%s %s\n",
+						configFile,
+						gchalk.BrightCyan(fmt.Sprintf("%d:%d:", configError.Line, configError.Column)),
+						configError.Message,
+					)
+				} else {
+					fmt.Printf("%s: %s\n", configFile, configError.Message)
+				}
+			}
+			fmt.Println(gchalk.BrightRed(fmt.Sprintf("Found %d error(s)", len(errs))))
 			os.Exit(1)
 		}
 
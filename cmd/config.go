@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Commands for working with the configuration file",
+}
+
+var configPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the fully resolved configuration",
+	Long: `Print the configuration that will be used to render the prompt, as YAML.
+
+The printed configuration has any extended configuration files merged in, the
+selected profile applied, and the default project types added, so this shows
+exactly what the prompt is rendered from.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configuration, err := readConfig()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		localFile := ""
+		if cwd, err := os.Getwd(); err == nil {
+			applyLocalConfig(configuration, cwd)
+			localFile = config.FindLocalConfig(cwd)
+		}
+
+		// Everything has been merged in already.
+		configuration.Extends = ""
+		configuration.Profiles = nil
+		configuration.Definitions = nil
+
+		if loadedConfigFile != "" {
+			fmt.Println("# Configuration file: " + loadedConfigFile)
+		} else {
+			fmt.Println("# Configuration file: (built-in default)")
+		}
+		if profile := selectedProfile(); profile != "" {
+			fmt.Println("# Profile: " + profile)
+		}
+		if localFile != "" {
+			fmt.Println("# Local configuration file: " + localFile)
+		}
+
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(configuration); err != nil {
+			log.Error("Error printing configuration: ", err)
+			os.Exit(1)
+		}
+		_ = encoder.Close()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPrintCmd)
+}
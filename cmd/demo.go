@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/demo"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/spf13/cobra"
+)
+
+var demoCmd = &cobra.Command{
+	Use:   "demo [scenario...]",
+	Short: "Show what the prompt looks like in different situations",
+	Long: `Renders the prompt in a series of canned scenarios - a clean git repo, a repo
+with merge conflicts, an SSH session as root, after a failed or slow command,
+and so on - so you can see every state of your prompt without having to set
+each one up by hand.  If no scenarios are given, all scenarios will be shown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		list, _ := cmd.Flags().GetBool("list")
+		terminalWidth, _ := cmd.Flags().GetInt("terminal-width")
+
+		if list {
+			for _, scenario := range demo.Scenarios() {
+				fmt.Printf("%-16s %s\n", scenario.Name, scenario.Description)
+			}
+			return
+		}
+
+		scenarios := demo.Scenarios()
+		if len(args) > 0 {
+			scenarios = scenarios[:0]
+			for _, name := range args {
+				scenario, ok := demo.FindScenario(name)
+				if !ok {
+					log.Error("Unknown scenario: " + name)
+					os.Exit(1)
+				}
+				scenarios = append(scenarios, scenario)
+			}
+		}
+
+		configuration, err := readConfig()
+		if err != nil {
+			os.Exit(1)
+		}
+		applyTheme(configuration)
+
+		styles := styling.Registry{}
+		styles.AddCustomColors(configuration.ColorsForBackground(terminalBackground()))
+		partials := compilePartials(configuration)
+
+		for _, scenario := range scenarios {
+			demoConfig := scenario.Config
+			if terminalWidth > 0 {
+				demoConfig.Globals.TerminalWidth = terminalWidth
+			}
+
+			context := modules.NewDemoContext(demoConfig, &styles)
+			context.ProjectTypes = configuration.ProjectsTypes
+			context.Partials = partials
+
+			_, prompt := modules.RenderPrompt(&context, configuration.Prompt)
+			printWarnings(&context)
+
+			fmt.Println(gchalk.BrightBlack(scenario.Name + ": " + scenario.Description))
+			fmt.Println(prompt)
+			fmt.Println()
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+	demoCmd.Flags().Bool("list", false, "List available scenarios")
+	demoCmd.Flags().Int("terminal-width", 80, "The width of the terminal")
+}
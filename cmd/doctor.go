@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/go-supportscolor"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/doctor"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check for common problems",
+	Long: `Checks for common problems that can stop ` + programName + ` from working
+properly, such as git missing from the PATH, a terminal without color support,
+a font without the icons the prompt uses, ` + programName + ` not being set up in your
+shell, or errors in your configuration file, and suggests how to fix them.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile := cfgFile
+		if configFile == "" && fileutils.FileExists(defaultConfigFile) {
+			configFile = defaultConfigFile
+		}
+
+		homeDir, _ := os.UserHomeDir()
+
+		results := doctor.Run(doctor.Options{
+			ProgramName: programName,
+			Shell:       detectShell(),
+			ConfigFile:  configFile,
+			HomeDir:     homeDir,
+			ColorLevel:  supportscolor.SupportsColor(os.Stdout.Fd()).Level,
+			Getenv:      os.Getenv,
+			LookPath:    fileutils.LookPathSafe,
+		})
+
+		problems := 0
+		for _, result := range results {
+			var status string
+			switch result.Status {
+			case doctor.StatusOK:
+				status = gchalk.BrightGreen("✔")
+			case doctor.StatusInfo:
+				status = gchalk.BrightCyan("?")
+			case doctor.StatusWarning:
+				status = gchalk.BrightYellow("!")
+			default:
+				status = gchalk.BrightRed("✘")
+				problems++
+			}
+
+			fmt.Printf("%s %s: %s\n", status, gchalk.Bold(result.Name), result.Message)
+			if result.Fix != "" {
+				fmt.Printf("  %s\n", gchalk.BrightBlack(result.Fix))
+			}
+		}
+
+		if problems > 0 {
+			fmt.Println()
+			fmt.Println(gchalk.BrightRed(fmt.Sprintf("Found %d problem(s)", problems)))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
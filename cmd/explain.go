@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Render the prompt and explain what each module did",
+	Long: `Renders the prompt once, and then prints, for each module, the text it
+produced, how long it took, the template data available to it, and any
+warnings it generated.
+
+This takes the same flags as "prompt", so you can see what the prompt would
+look like in a different folder, or after a command failed:
+
+  ` + programName + ` explain --path ~/dev/myproject --status 1
+
+If a template fails with an error like "can't evaluate field X", pass --data
+to include the data that was passed to the template in the warning.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.SetVerbose(true)
+
+		configuration, err := readConfig()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		context := newPromptContext(cmd, configuration)
+		context.Explainer = modules.NewExplainer()
+		if showData, _ := cmd.Flags().GetBool("data"); showData {
+			context.DebugTemplates = true
+		}
+
+		_, prompt := modules.RenderPrompt(context, configuration.Prompt)
+
+		fmt.Println(gchalk.Bold("Prompt:"))
+		fmt.Println(prompt)
+		fmt.Println()
+
+		fmt.Println(gchalk.Bold("Globals:"))
+		fmt.Println(indent(toJSON(context.Globals), "  "))
+		fmt.Println()
+
+		fmt.Println(gchalk.Bold("Modules:"))
+		printExplanation(context.Explainer.Explain(configuration.Prompt), "")
+	},
+}
+
+func printExplanation(explanation modules.ModuleExplanation, prefix string) {
+	status := ""
+	switch {
+	case !explanation.Executed:
+		status = gchalk.BrightBlack("not run")
+	case explanation.Skipped:
+		status = gchalk.BrightBlack("skipped - conditions did not match")
+	case explanation.TimedOut:
+		status = gchalk.Red("timed out after " + explanation.Duration.Round(time.Microsecond).String())
+	default:
+		status = explanation.Duration.Round(time.Microsecond).String()
+	}
+	fmt.Printf("%s%s %s\n", prefix, gchalk.BrightCyan(explanation.Module), status)
+
+	detailPrefix := prefix + "  "
+	if explanation.Executed && !explanation.Skipped {
+		if explanation.Text == "" {
+			fmt.Printf("%sText: %s\n", detailPrefix, gchalk.BrightBlack("(empty)"))
+		} else {
+			fmt.Printf("%sText: %s\n", detailPrefix, explanation.Text)
+		}
+	}
+	for _, warning := range explanation.Warnings {
+		// Warnings can span several lines, so indent them to line up with the module.
+		fmt.Printf("%s%s %s\n", detailPrefix, gchalk.BrightYellow("Warning:"), strings.ReplaceAll(warning, "\n", "\n"+detailPrefix+"  "))
+	}
+
+	// The data for a module with children repeats the children's data, so
+	// we only show the data for leaf modules.
+	if len(explanation.Children) == 0 && explanation.Data != nil {
+		fmt.Printf("%sData:\n%s\n", detailPrefix, indent(toJSON(explanation.Data), detailPrefix+"  "))
+	}
+
+	for _, child := range explanation.Children {
+		printExplanation(child, detailPrefix)
+	}
+}
+
+// toJSON returns a human readable JSON representation of a value.  JSON is used
+// here instead of YAML because the field names match the names used in
+// templates.
+func toJSON(value interface{}) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%+v", value)
+	}
+	return string(data)
+}
+
+func indent(text string, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+	addPromptContextFlags(explainCmd)
+	explainCmd.Flags().Bool("data", false, "Include the data passed to a template in the warning when a template fails (same as --debug-templates)")
+}
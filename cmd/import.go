@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/starship"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import configuration from another prompt",
+}
+
+var importStarshipCmd = &cobra.Command{
+	Use:   "starship [path]",
+	Short: "Convert a starship configuration file into a kitsch configuration",
+	Long: heredoc.Doc(`
+		Convert a starship.toml file into an equivalent kitsch configuration.
+		If no path is given, this reads from $STARSHIP_CONFIG, or from
+		~/.config/starship.toml.
+
+		The conversion is best-effort.  Modules, format strings, styles, and
+		symbols are translated where kitsch has an equivalent, and anything that
+		couldn't be translated is noted in a comment in the generated file.
+		Run "` + programName + ` check" on the result to verify it.
+	`),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		starshipFile := starshipConfigFile()
+		if len(args) > 0 {
+			starshipFile = args[0]
+		}
+
+		tomlData, err := os.ReadFile(starshipFile)
+		if err != nil {
+			log.Error("Could not read " + starshipFile + ": " + err.Error())
+			os.Exit(1)
+		}
+
+		result, err := starship.Convert(tomlData)
+		if err != nil {
+			log.Error("Could not convert " + starshipFile + ": " + err.Error())
+			os.Exit(1)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			fmt.Print(string(result))
+			return
+		}
+
+		err = os.WriteFile(output, result, 0644)
+		if err != nil {
+			log.Error("Could not write configuration to " + output + ": " + err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// starshipConfigFile returns the default location of the starship configuration.
+func starshipConfigFile() string {
+	if configFile := os.Getenv("STARSHIP_CONFIG"); configFile != "" {
+		return configFile
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = os.Getenv("HOME")
+	}
+	return filepath.Join(home, ".config", "starship.toml")
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importStarshipCmd)
+	importStarshipCmd.Flags().StringP("output", "o", "", "Write the configuration to the given file instead of stdout")
+}
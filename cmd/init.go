@@ -30,7 +30,7 @@ var initCmd = &cobra.Command{
 		}
 
 		if !printFullInit {
-			shortScript, err := initscripts.ShortInitScript(shell, cfgFile)
+			shortScript, err := initscripts.ShortInitScript(shell, cfgFile, profileName)
 			if err != nil {
 				cmd.PrintErrln(err.Error())
 				os.Exit(1)
@@ -38,7 +38,15 @@ var initCmd = &cobra.Command{
 
 			fmt.Println(shortScript)
 		} else {
-			script, err := initscripts.InitScript(shell, cfgFile)
+			// The notification threshold is baked into the init script, so
+			// the shell can decide if it needs to run `notify` without
+			// starting a process after every command.
+			notifyThreshold := int64(0)
+			if configuration, err := readConfig(); err == nil && configuration.Notifications.Enabled() {
+				notifyThreshold = configuration.Notifications.Threshold
+			}
+
+			script, err := initscripts.InitScript(shell, cfgFile, profileName, notifyThreshold)
 			if err != nil {
 				cmd.PrintErrln(err.Error())
 				os.Exit(1)
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var moduleCmd = &cobra.Command{
+	Use:   "module",
+	Short: "Commands for working with individual modules",
+}
+
+var moduleRunCmd = &cobra.Command{
+	Use:   "run <id or type>",
+	Short: "Run a single module and show its output",
+	Long: `Runs a single module from your prompt, and prints the text it renders and
+the data available to its template.  This makes it easy to work on the
+template for a single module without having to render the whole prompt.
+
+The module is found by its ID, or failing that, by its type.  If your prompt
+doesn't have a module of the given type, a module with the default settings
+is run instead.  Use --data to change the module's configuration:
+
+  ` + programName + ` module run git_head --data 'template={{ .Data.ShortHash }}'
+  ` + programName + ` module run directory --dir /tmp --data truncationLength=2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.SetVerbose(true)
+
+		configuration, err := readConfig()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		wrapper, err := findModuleToRun(configuration.Prompt, args[0])
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		data, _ := cmd.Flags().GetStringArray("data")
+		if len(data) > 0 {
+			wrapper, err = overrideModuleConfig(wrapper, data)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+		}
+
+		if dir, _ := cmd.Flags().GetString("dir"); dir != "" {
+			_ = cmd.Flags().Set("path", dir)
+		}
+
+		context := newPromptContext(cmd, configuration)
+		context.ShareModules(configuration.Prompt)
+		result := wrapper.Execute(context)
+
+		fmt.Println(gchalk.Bold("Module:"), wrapper.String())
+		fmt.Println(gchalk.Bold("Duration:"), result.Duration)
+		fmt.Println(gchalk.Bold("Text:"))
+		fmt.Println(result.Text)
+		fmt.Println(gchalk.Bold("Data:"))
+		fmt.Println(toJSON(result.Data))
+	},
+}
+
+var modulePluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "List plugin modules found on the PATH",
+	Long: `Lists all the plugin executables on the PATH.  A plugin is any executable
+named "` + modules.PluginExecutablePrefix + `<name>".  To use a plugin in your prompt,
+add a module like:
+
+  - type: plugin
+    name: <name>`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		plugins := modules.DiscoverPlugins()
+		if len(plugins) == 0 {
+			fmt.Println("No plugins found.")
+			return
+		}
+
+		for _, plugin := range plugins {
+			fmt.Printf("%s\t%s\n", gchalk.Bold(plugin.Name), plugin.Path)
+		}
+	},
+}
+
+// findModuleToRun finds the module with the given ID or type in the prompt, or
+// creates a new module of the given type if there isn't one.
+func findModuleToRun(prompt modules.ModuleWrapper, name string) (modules.ModuleWrapper, error) {
+	if wrapper, ok := modules.FindModule(prompt, name); ok {
+		return wrapper, nil
+	}
+
+	for _, moduleType := range modules.RegisteredModuleTypes() {
+		if moduleType == name {
+			var wrapper modules.ModuleWrapper
+			err := yaml.Unmarshal([]byte("type: "+name), &wrapper)
+			return wrapper, err
+		}
+	}
+
+	return modules.ModuleWrapper{}, fmt.Errorf("no module with ID or type %q", name)
+}
+
+// overrideModuleConfig returns a copy of the given module with each "key=value"
+// in `data` set in the module's configuration.
+func overrideModuleConfig(wrapper modules.ModuleWrapper, data []string) (modules.ModuleWrapper, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if wrapper.YamlNode != nil {
+		node.Line = wrapper.YamlNode.Line
+		node.Column = wrapper.YamlNode.Column
+		node.Content = append(node.Content, wrapper.YamlNode.Content...)
+	}
+
+	for _, item := range data {
+		index := strings.Index(item, "=")
+		if index == -1 {
+			return wrapper, fmt.Errorf("invalid --data %q: expected key=value", item)
+		}
+		key := item[:index]
+
+		// Parse the value as YAML, so numbers and booleans have the right type.
+		var value yaml.Node
+		if err := yaml.Unmarshal([]byte(item[index+1:]), &value); err != nil || len(value.Content) == 0 {
+			value = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: item[index+1:]}}}
+		}
+
+		setMappingValue(node, key, value.Content[0])
+	}
+
+	var result modules.ModuleWrapper
+	err := node.Decode(&result)
+	return result, err
+}
+
+// setMappingValue sets the value of `key` in the given mapping node.
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if node.Content[index].Value == key {
+			node.Content[index+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func init() {
+	rootCmd.AddCommand(moduleCmd)
+	moduleCmd.AddCommand(moduleRunCmd)
+	moduleCmd.AddCommand(modulePluginsCmd)
+	addPromptContextFlags(moduleRunCmd)
+	moduleRunCmd.Flags().StringArray("data", nil, "Set a field in the module's configuration, as key=value")
+	moduleRunCmd.Flags().String("dir", "", "The folder to run the module in (same as --path)")
+}
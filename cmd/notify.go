@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/spf13/cobra"
+)
+
+// notifyCmd is called by the init scripts when a command runs for longer than
+// the notification threshold.  This is run from the shell's precmd hook rather
+// than as part of `prompt`, since the prompt can be redrawn many times for a
+// single command.
+var notifyCmd = &cobra.Command{
+	Use:    "notify",
+	Short:  "Send a notification that a long running command has finished",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		duration, _ := cmd.Flags().GetInt64("cmd-duration")
+		status, _ := cmd.Flags().GetInt("status")
+
+		configuration, err := readConfig()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		if err := configuration.Notifications.Notify(os.Stdout, duration, status); err != nil {
+			log.Warn("Error sending notification: " + err.Error())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.Flags().Int64P("cmd-duration", "d", 0, "The execution duration of the last command, in milliseconds")
+	notifyCmd.Flags().IntP("status", "s", 0, "The status code of the previously run command")
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/sampleconfig"
+	"github.com/spf13/cobra"
+)
+
+var presetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "Commands for working with the bundled preset configurations",
+	Long: heredoc.Doc(`
+		` + programName + ` comes with several complete configurations built in.  Use
+		"` + programName + ` preset list" to see them, "` + programName + ` preset show <name>" to
+		print one, or "` + programName + ` preset apply <name>" to make one your configuration.
+	`),
+}
+
+var presetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the bundled presets",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, preset := range sampleconfig.Presets() {
+			fmt.Fprintf(writer, "%s\t%s\n", preset.Name, preset.Description)
+		}
+		writer.Flush()
+	},
+}
+
+var presetShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print a preset configuration",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := sampleconfig.GetPreset(args[0])
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		fmt.Print(string(data))
+	},
+}
+
+var presetApplyCmd = &cobra.Command{
+	Use:   "apply <name>",
+	Short: "Install a preset as your configuration file",
+	Long: heredoc.Doc(`
+		Write the named preset to your configuration file (or to the file given
+		by --config).  If the configuration file already exists, this will
+		refuse to overwrite it unless --force is given, in which case the old
+		file is first copied to a ".bak" file.
+	`),
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := sampleconfig.GetPreset(args[0])
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		target := cfgFile
+		if target == "" {
+			target = filepath.Join(userConfigDir, "kitsch.yaml")
+		}
+
+		if fileutils.FileExists(target) {
+			force, _ := cmd.Flags().GetBool("force")
+			if !force {
+				log.Error(target + " already exists.  Use --force to overwrite it.")
+				os.Exit(1)
+			}
+
+			existing, err := os.ReadFile(target)
+			if err == nil {
+				err = os.WriteFile(target+".bak", existing, 0644)
+			}
+			if err != nil {
+				log.Error("Could not back up " + target + ": " + err.Error())
+				os.Exit(1)
+			}
+			fmt.Println("Backed up existing configuration to " + target + ".bak")
+		}
+
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			log.Error("Could not write configuration to " + target + ": " + err.Error())
+			os.Exit(1)
+		}
+		fmt.Println("Wrote preset " + args[0] + " to " + target)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(presetCmd)
+	presetCmd.AddCommand(presetListCmd)
+	presetCmd.AddCommand(presetShowCmd)
+	presetCmd.AddCommand(presetApplyCmd)
+	presetApplyCmd.Flags().BoolP("force", "f", false, "Overwrite an existing configuration file")
+}
@@ -6,11 +6,15 @@ import (
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jwalton/gchalk"
 	"github.com/jwalton/go-supportscolor"
+	"github.com/jwalton/kitsch/internal/colortools"
+	"github.com/jwalton/kitsch/internal/kitsch/config"
 	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
 	"github.com/jwalton/kitsch/internal/kitsch/modules"
 	"github.com/jwalton/kitsch/internal/kitsch/styling"
 	"github.com/jwalton/kitsch/internal/perf"
@@ -24,27 +28,13 @@ var promptCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		performance := perf.New(4)
 
-		cacheDir := filepath.Join(userConfigDir, "cache")
-
-		jobs, _ := cmd.Flags().GetInt("jobs")
-		status, _ := cmd.Flags().GetInt("status")
-		terminalWidth, _ := cmd.Flags().GetInt("terminal-width")
-		keymap, _ := cmd.Flags().GetString("keymap")
-		shell, _ := cmd.Flags().GetString("shell")
 		perf, _ := cmd.Flags().GetBool("perf")
 		demo, _ := cmd.Flags().GetString("demo")
-		cwd, _ := cmd.Flags().GetString("path")
-		logicalCWD, _ := cmd.Flags().GetString("logical-path")
-
-		verbose, _ := cmd.Flags().GetBool("verbose")
-		if verbose {
-			log.SetVerbose(true)
-		}
-
-		cmdDurationStr, _ := cmd.Flags().GetString("cmd-duration")
-		cmdDuration := int64(0)
-		if cmdDurationStr != "" {
-			cmdDuration, _ = strconv.ParseInt(cmdDurationStr, 10, 64)
+		format, _ := cmd.Flags().GetString("format")
+		right, _ := cmd.Flags().GetBool("right")
+		if format != "" && format != "json" && format != "tmux" {
+			log.Error("Unknown format: " + format)
+			os.Exit(1)
 		}
 
 		if runtime.GOOS == "windows" {
@@ -73,59 +63,201 @@ var promptCmd = &cobra.Command{
 			fmt.Print("$ ")
 			os.Exit(1)
 		}
-
-		styles := styling.Registry{}
-		styles.AddCustomColors(configuration.Colors)
-
 		performance.End("Config parsing")
 
 		// Create our context.
-		var context modules.Context
-		if demo != "" {
-			demoConfig := &modules.DemoConfig{}
-			err := demoConfig.Load(demo)
-			if err != nil {
-				log.Error("Failed to load demo config:", err)
-				os.Exit(1)
-			}
-			context = modules.NewDemoContext(*demoConfig, &styles)
-		} else {
-			globals := modules.NewGlobals(shell, cwd, logicalCWD, terminalWidth, status, jobs, cmdDuration, keymap)
-			context = modules.NewContext(
-				globals,
-				configuration.ProjectsTypes,
-				time.Duration(configuration.Timeout)*time.Millisecond,
-				time.Duration(configuration.ScanTimeout)*time.Millisecond,
-				cacheDir,
-				&styles,
-			)
+		context := newPromptContext(cmd, configuration)
+		if format == "json" {
+			context.Explainer = modules.NewExplainer()
 		}
 		performance.End("Context setup")
 
+		root := configuration.Prompt
+		if right {
+			if configuration.RightPrompt == nil {
+				// No right prompt is configured, so there is nothing to show.
+				return
+			}
+			root = *configuration.RightPrompt
+		}
+
 		// Execute the prompt.
-		moduleResult, promptTest := modules.RenderPrompt(&context, configuration.Prompt)
+		moduleResult, promptTest := modules.RenderPrompt(context, root)
 		performance.Add("Prompt", moduleResult.Duration, moduleResult.Performance)
+		if configuration.WarningBadge.IsEnabled() && format != "json" {
+			// The badge lets the user know something went wrong, so keep the
+			// details out of the terminal.
+			promptTest = configuration.WarningBadge.Apply(context, promptTest)
+			for _, warning := range context.Warnings() {
+				log.Info(warning)
+			}
+		} else {
+			printWarnings(context)
+		}
 
 		if perf {
 			performance.Print()
 		}
 
-		withEscapes := shellprompt.AddZeroWidthCharacterEscapes(context.Globals.Shell, promptTest)
+		if format == "json" {
+			err := writeJSONPrompt(os.Stdout, context, root, promptTest)
+			if err != nil {
+				log.Error("Error writing JSON: ", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if format == "tmux" {
+			fmt.Print(shellprompt.ToTmux(promptTest))
+			return
+		}
+
+		if demo == "" && !right {
+			promptTest = configuration.ShellIntegration.Render(context) + promptTest
+		}
+
+		withEscapes := shellprompt.EscapeSpecialCharacters(context.Globals.Shell, promptTest)
+		withEscapes = shellprompt.AddZeroWidthCharacterEscapes(context.Globals.Shell, withEscapes)
 		fmt.Print(withEscapes)
 	},
 }
 
+// printWarnings writes any warnings generated while rendering the prompt to
+// stderr.  These are held until the prompt is finished, so they are printed
+// together instead of in between the output of different modules.
+func printWarnings(context *modules.Context) {
+	for _, warning := range context.Warnings() {
+		log.Warn(warning)
+	}
+}
+
+// newPromptContext applies any local configuration and creates the context to
+// render the prompt with, based on the flags passed to `cmd`.
+func newPromptContext(cmd *cobra.Command, configuration *config.Config) *modules.Context {
+	jobs, _ := cmd.Flags().GetInt("jobs")
+	status, _ := cmd.Flags().GetInt("status")
+	pipeStatusStr, _ := cmd.Flags().GetString("pipestatus")
+	terminalWidth, _ := cmd.Flags().GetInt("terminal-width")
+	keymap, _ := cmd.Flags().GetString("keymap")
+	previousCommand, _ := cmd.Flags().GetString("previous-command")
+	shell, _ := cmd.Flags().GetString("shell")
+	demo, _ := cmd.Flags().GetString("demo")
+	cwd, _ := cmd.Flags().GetString("path")
+	logicalCWD, _ := cmd.Flags().GetString("logical-path")
+	debugTemplates, _ := cmd.Flags().GetBool("debug-templates")
+
+	cmdDurationStr, _ := cmd.Flags().GetString("cmd-duration")
+	cmdDuration := int64(0)
+	if cmdDurationStr != "" {
+		cmdDuration, _ = strconv.ParseInt(cmdDurationStr, 10, 64)
+	}
+
+	var pipeStatus []int
+	for _, field := range strings.Fields(pipeStatusStr) {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			pipeStatus = nil
+			break
+		}
+		pipeStatus = append(pipeStatus, value)
+	}
+
+	if demo == "" {
+		localFolder := cwd
+		if localFolder == "" {
+			localFolder, _ = os.Getwd()
+		}
+		applyLocalConfig(configuration, localFolder)
+	}
+	applyTheme(configuration)
+
+	styles := styling.Registry{StyledUnderlines: styledUnderlines()}
+	styles.AddCustomColors(configuration.ColorsForBackground(terminalBackground()))
+
+	if demo != "" {
+		demoConfig := &modules.DemoConfig{}
+		err := demoConfig.Load(demo)
+		if err != nil {
+			log.Error("Failed to load demo config:", err)
+			os.Exit(1)
+		}
+		context := modules.NewDemoContext(*demoConfig, &styles)
+		context.Partials = compilePartials(configuration)
+		context.DebugTemplates = debugTemplates
+		return &context
+	}
+
+	globals := modules.NewGlobals(shell, cwd, logicalCWD, terminalWidth, status, pipeStatus, jobs, cmdDuration, previousCommand, keymap)
+	context := modules.NewContext(
+		globals,
+		configuration.ProjectsTypes,
+		time.Duration(configuration.Timeout)*time.Millisecond,
+		time.Duration(configuration.ScanTimeout)*time.Millisecond,
+		int(configuration.MaxScanEntries),
+		filepath.Join(userConfigDir, "cache"),
+		&styles,
+	)
+	context.Partials = compilePartials(configuration)
+	context.DebugTemplates = debugTemplates
+	context.FilesystemRules = configuration.FilesystemRules
+	context.DisabledModules = configuration.DisabledModules
+	return &context
+}
+
+// compilePartials compiles the named templates from the configuration.
+func compilePartials(configuration *config.Config) *modtemplate.Partials {
+	partials, err := modtemplate.CompilePartials(configuration.Templates)
+	if err != nil {
+		log.Warn(err.Error())
+	}
+	return partials
+}
+
+// terminalBackground returns "light" or "dark" depending on the terminal's
+// background color, or "" if unknown.  The user can force a background with
+// KITSCH_BACKGROUND, otherwise we use the reply to the OSC 11 query made by
+// the init script, or COLORFGBG.
+func terminalBackground() string {
+	background := os.Getenv("KITSCH_BACKGROUND")
+	if background == "light" || background == "dark" {
+		return background
+	}
+	return colortools.DetectBackground(os.Getenv("KITSCH_TERMINAL_BG"), os.Getenv("COLORFGBG"))
+}
+
+// styledUnderlines returns true if the terminal supports styled underlines
+// and underline colors.
+func styledUnderlines() bool {
+	switch os.Getenv("KITSCH_STYLED_UNDERLINES") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return colortools.SupportsStyledUnderlines(os.Getenv)
+}
+
 func init() {
 	rootCmd.AddCommand(promptCmd)
-	promptCmd.Flags().String("shell", "", "The type of shell")
-	promptCmd.Flags().String("path", "", "The current working directory")
-	promptCmd.Flags().String("logical-path", "", "The display name for the current working directory")
-	promptCmd.Flags().StringP("cmd-duration", "d", "", "The execution duration of the last command, in milliseconds")
-	promptCmd.Flags().StringP("keymap", "k", "", "The keymap of fish/zsh")
-	promptCmd.Flags().IntP("jobs", "j", 0, "The number of currently running jobs")
-	promptCmd.Flags().IntP("status", "s", 0, "The status code of the previously run command")
-	promptCmd.Flags().Int("terminal-width", 0, "The width of the terminal")
+	addPromptContextFlags(promptCmd)
 	promptCmd.Flags().Bool("perf", false, "Print performance information about each module")
-	promptCmd.Flags().Bool("verbose", false, "Print verbose output")
-	promptCmd.Flags().String("demo", "", "If present, "+programName+" will run in demo mode, loading values from the specified file.")
+	promptCmd.Flags().Bool("right", false, "Show the right prompt (rightPrompt in the configuration) instead of the prompt")
+	promptCmd.Flags().String("format", "", "Output format.  \"json\" prints the result of every module as JSON, and \"tmux\" prints the prompt for use in a tmux status line")
+}
+
+// addPromptContextFlags adds the flags read by newPromptContext to a command.
+func addPromptContextFlags(command *cobra.Command) {
+	command.Flags().String("shell", "", "The type of shell")
+	command.Flags().String("path", "", "The current working directory")
+	command.Flags().String("logical-path", "", "The display name for the current working directory")
+	command.Flags().StringP("cmd-duration", "d", "", "The execution duration of the last command, in milliseconds")
+	command.Flags().StringP("keymap", "k", "", "The keymap of fish/zsh")
+	command.Flags().String("previous-command", "", "The command line of the previously run command")
+	command.Flags().IntP("jobs", "j", 0, "The number of currently running jobs")
+	command.Flags().IntP("status", "s", 0, "The status code of the previously run command")
+	command.Flags().String("pipestatus", "", "The space separated status codes of each command in the previously run pipeline")
+	command.Flags().Int("terminal-width", 0, "The width of the terminal")
+	command.Flags().String("demo", "", "If present, "+programName+" will run in demo mode, loading values from the specified file.")
+	command.Flags().Bool("debug-templates", false, "Include the data passed to a template in the warning when a template fails")
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/jwalton/kitsch/internal/shellprompt"
+)
+
+// jsonPrompt is the output of `prompt --format json`.
+type jsonPrompt struct {
+	// Prompt is the rendered prompt, including escape codes.
+	Prompt string `json:"prompt"`
+	// PlainText is the rendered prompt with escape codes removed.
+	PlainText string `json:"plainText"`
+	// Globals are the global values available to templates.
+	Globals modules.Globals `json:"globals"`
+	// Module is the result of the root module of the prompt.
+	Module jsonModule `json:"module"`
+}
+
+// jsonModule is the result of rendering a single module.
+type jsonModule struct {
+	Type       string                  `json:"type"`
+	ID         string                  `json:"id,omitempty"`
+	Line       int                     `json:"line"`
+	Column     int                     `json:"column"`
+	Executed   bool                    `json:"executed"`
+	Skipped    bool                    `json:"skipped,omitempty"`
+	TimedOut   bool                    `json:"timedOut,omitempty"`
+	Text       string                  `json:"text"`
+	PlainText  string                  `json:"plainText"`
+	StartStyle styling.CharacterColors `json:"startStyle"`
+	EndStyle   styling.CharacterColors `json:"endStyle"`
+	// DurationMs is the time it took to execute the module, in milliseconds.
+	DurationMs float64 `json:"durationMs"`
+	// Data is the template data for the module.  This is left out for modules
+	// with children, since it just repeats the children's results.
+	Data     interface{}  `json:"data,omitempty"`
+	Warnings []string     `json:"warnings,omitempty"`
+	Children []jsonModule `json:"children,omitempty"`
+}
+
+func toJSONModule(explanation modules.ModuleExplanation) jsonModule {
+	result := jsonModule{
+		Type:       explanation.Type,
+		ID:         explanation.ID,
+		Line:       explanation.Line,
+		Column:     explanation.Column,
+		Executed:   explanation.Executed,
+		Skipped:    explanation.Skipped,
+		TimedOut:   explanation.TimedOut,
+		Text:       explanation.Text,
+		PlainText:  shellprompt.StripEscapeCodes(explanation.Text),
+		StartStyle: explanation.StartStyle,
+		EndStyle:   explanation.EndStyle,
+		DurationMs: float64(explanation.Duration.Microseconds()) / 1000,
+		Warnings:   explanation.Warnings,
+	}
+
+	if len(explanation.Children) == 0 {
+		result.Data = explanation.Data
+	}
+	for _, child := range explanation.Children {
+		result.Children = append(result.Children, toJSONModule(child))
+	}
+
+	return result
+}
+
+// writeJSONPrompt writes the result of rendering the prompt as JSON.
+func writeJSONPrompt(out io.Writer, context *modules.Context, root modules.ModuleWrapper, prompt string) error {
+	output := jsonPrompt{
+		Prompt:    prompt,
+		PlainText: shellprompt.StripEscapeCodes(prompt),
+		Globals:   context.Globals,
+		Module:    toJSONModule(context.Explainer.Explain(root)),
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(output)
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/cache"
+	"github.com/jwalton/kitsch/internal/fileutils"
 	"github.com/jwalton/kitsch/internal/kitsch/config"
 	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/projects"
@@ -15,6 +17,11 @@ import (
 
 var userConfigDir string
 var cfgFile string
+var profileName string
+
+// loadedConfigFile is the configuration file loaded by readConfig(), or "" if
+// the built-in default configuration was used.
+var loadedConfigFile string
 var defaultConfigFile string
 
 // rootCmd represents the base command when called without any subcommands
@@ -36,6 +43,9 @@ var rootCmd = &cobra.Command{
 		  # Display what your prompt would look like using a certain config
 		  ` + programName + ` show --config ./config.yaml --dry-run
 	`),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		configureLogging(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -54,44 +64,128 @@ func init() {
 		userConfigDir = "~"
 	}
 	defaultConfigFile = filepath.Join(userConfigDir, "kitsch.yaml")
+	if tomlConfigFile := filepath.Join(userConfigDir, "kitsch.toml"); !fileutils.FileExists(defaultConfigFile) &&
+		fileutils.FileExists(tomlConfigFile) {
+		defaultConfigFile = tomlConfigFile
+	}
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is "+defaultConfigFile+")")
-	rootCmd.PersistentFlags().Bool("verbose", false, "Use verbose output")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "configuration profile to use (default is $KITSCH_PROFILE)")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Use verbose output (sets the log level to \"debug\", and shows every warning)")
+}
+
+// configureLogging sets up logging based on the `--verbose` flag, and the
+// KITSCH_LOG_LEVEL and KITSCH_LOG_FILE environment variables.
+func configureLogging(cmd *cobra.Command) {
+	if levelName := os.Getenv("KITSCH_LOG_LEVEL"); levelName != "" {
+		level, err := log.ParseLevel(levelName)
+		if err != nil {
+			log.Warn("Invalid KITSCH_LOG_LEVEL: ", err)
+		} else {
+			log.SetLevel(level)
+		}
+	}
+
+	if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+		log.SetVerbose(true)
+	}
+
+	if logFile := os.Getenv("KITSCH_LOG_FILE"); logFile != "" {
+		if err := log.SetLogFile(logFile); err != nil {
+			log.Warn("Unable to open KITSCH_LOG_FILE: ", err)
+		}
+	}
+}
+
+// selectedProfile returns the name of the configuration profile to use, or ""
+// if no profile was selected.
+func selectedProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	return os.Getenv("KITSCH_PROFILE")
+}
+
+// applyTheme applies the theme selected with KITSCH_THEME, or the theme from
+// the configuration if there is none.
+func applyTheme(configuration *config.Config) {
+	theme := os.Getenv("KITSCH_THEME")
+	if theme == "" {
+		theme = configuration.Theme
+	}
+	if err := configuration.ApplyTheme(theme, filepath.Join(userConfigDir, "themes")); err != nil {
+		log.Warn(err.Error())
+	}
 }
 
 func readConfig() (*config.Config, error) {
 	var configuration *config.Config
 	var err error
 
+	// Parsing the configuration is a big part of the time it takes to show the
+	// prompt, so we keep a parsed copy of the configuration in the cache.
+	documentCache := cache.NewFileCache(filepath.Join(userConfigDir, "cache"))
+
 	if cfgFile != "" {
-		configuration, err = config.LoadConfigFromFile(cfgFile, false)
+		configuration, err = config.LoadConfigFromFileWithCache(cfgFile, false, documentCache)
 		if err != nil {
 			log.Error("Error loading config file "+cfgFile+": ", err)
+		} else {
+			loadedConfigFile = cfgFile
 		}
 	}
 
 	if configuration == nil && cfgFile != defaultConfigFile {
-		configuration, err = config.LoadConfigFromFile(defaultConfigFile, false)
+		configuration, err = config.LoadConfigFromFileWithCache(defaultConfigFile, false, documentCache)
 		if err != nil && !os.IsNotExist(err) {
 			log.Error("Error loading config file "+defaultConfigFile+": ", err)
+		} else if err == nil {
+			loadedConfigFile = defaultConfigFile
 		}
 	}
 
 	if configuration == nil {
-		configuration, err = config.LoadDefaultConfig()
+		configuration, err = config.LoadDefaultConfigWithCache(documentCache)
 		if err != nil {
 			log.Error("Error loading default config: ", err)
 		}
 	}
 
+	if loadedConfigFile != "" {
+		log.Debug("Loaded configuration from ", loadedConfigFile)
+	} else {
+		log.Debug("Using default configuration")
+	}
+
+	if configuration != nil {
+		prepareConfig(configuration)
+	}
+
+	return configuration, err
+}
+
+// prepareConfig applies the overrides for this machine and the selected
+// profile to a freshly loaded configuration, and merges in the default project
+// types.
+func prepareConfig(configuration *config.Config) {
+	if hostname, err := os.Hostname(); err == nil {
+		if hostErr := configuration.ApplyHostOverrides(hostname); hostErr != nil {
+			log.Warn(hostErr.Error())
+		}
+	}
+
+	if profile := selectedProfile(); profile != "" {
+		if profileErr := configuration.ApplyProfile(profile); profileErr != nil {
+			log.Warn(profileErr.Error())
+		}
+	}
+
 	// Merge in default project types.
 	configuration.ProjectsTypes = projects.MergeProjectTypes(
 		configuration.ProjectsTypes,
 		projects.DefaultProjectTypes,
 		true,
 	)
-
-	return configuration, err
 }
 
 // getConfigFolder returns the folder that contains configuration
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/spf13/cobra"
 )
 
@@ -11,11 +14,37 @@ import (
 var schemaCmd = &cobra.Command{
 	Use:   "schema",
 	Short: "Print the JSON schema for the configuration file",
+	Long: heredoc.Doc(`
+		Print the JSON schema for the configuration file.  The schema includes
+		the options for every module, and can be used by editors to provide
+		autocomplete and validation.  For example, to use the schema with VS
+		Code's YAML extension, write the schema to a file:
+
+		    ` + programName + ` schema --output ~/.config/kitsch/kitsch.schema.json
+
+		and then add this comment to the top of your configuration file:
+
+		    # yaml-language-server: $schema=./kitsch.schema.json
+	`),
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(config.JSONSchema())
+		schema := config.JSONSchema()
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			fmt.Println(schema)
+			return
+		}
+
+		err := os.WriteFile(output, []byte(schema+"\n"), 0644)
+		if err != nil {
+			log.Error("Could not write schema to " + output + ": " + err.Error())
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.Flags().StringP("output", "o", "", "Write the schema to the given file instead of stdout")
 }
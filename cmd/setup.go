@@ -19,15 +19,9 @@ var setupCmd = &cobra.Command{
 
 		if len(args) > 0 {
 			shell = args[0]
-		} else if runtime.GOOS == "windows" {
-			shell = "powershell"
 		} else {
-			shellType := os.Getenv("SHELL")
-			if strings.HasSuffix(shellType, "/zsh") {
-				shell = "zsh"
-			} else if strings.HasSuffix(shellType, "/bash") {
-				shell = "bash"
-			} else {
+			shell = detectShell()
+			if shell == "" {
 				shell = "unknown"
 			}
 		}
@@ -37,6 +31,30 @@ var setupCmd = &cobra.Command{
 	},
 }
 
+// detectShell tries to work out which shell the user is running, and returns
+// "" if it can't.
+func detectShell() string {
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+
+	shellType := os.Getenv("SHELL")
+	if strings.HasSuffix(shellType, "/zsh") {
+		return "zsh"
+	} else if strings.HasSuffix(shellType, "/bash") {
+		return "bash"
+	} else if strings.HasSuffix(shellType, "/elvish") {
+		return "elvish"
+	} else if strings.HasSuffix(shellType, "/xonsh") {
+		return "xonsh"
+	} else if strings.HasSuffix(shellType, "/tcsh") || strings.HasSuffix(shellType, "/csh") {
+		return "tcsh"
+	} else if strings.HasSuffix(shellType, "/pwsh") {
+		return "powershell"
+	}
+	return ""
+}
+
 func init() {
 	rootCmd.AddCommand(setupCmd)
 }
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [dir]",
+	Short: "Check the prompt still renders the same way",
+	Long: `Renders the prompt in each of the scenarios shown by "kitsch demo", and in
+each fixture in the given folder, and compares the results to the ".golden"
+files in that folder.  If no folder is given, "snapshots" is used.
+
+Fixtures are ".yaml" files in the same format as the file passed to
+"kitsch prompt --demo", so each fixture can set up its own globals,
+environment variables, files, and git state.
+
+Run with "--update" to record the current output as the golden files.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		update, _ := cmd.Flags().GetBool("update")
+
+		dir := "snapshots"
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		configuration, err := readConfig()
+		if err != nil {
+			os.Exit(1)
+		}
+
+		fixtures, err := snapshot.LoadFixtures(dir)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		fixtures = append(snapshot.DemoFixtures(), fixtures...)
+
+		results, err := snapshot.Check(configuration, fixtures, dir, update)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		failed := 0
+		for _, result := range results {
+			switch result.Status {
+			case snapshot.StatusMatched:
+				fmt.Println(gchalk.BrightGreen("ok      ") + result.Fixture)
+			case snapshot.StatusUpdated:
+				fmt.Println(gchalk.BrightYellow("updated ") + result.Fixture)
+			case snapshot.StatusMissing:
+				failed++
+				fmt.Println(gchalk.BrightRed("missing ") + result.Fixture + " (no " + result.GoldenFile + ")")
+			case snapshot.StatusChanged:
+				failed++
+				fmt.Println(gchalk.BrightRed("changed ") + result.Fixture)
+				fmt.Println("  expected: " + result.Expected)
+				fmt.Println("  actual:   " + result.Actual)
+			}
+			for _, warning := range result.Warnings {
+				fmt.Println("  warning: " + warning)
+			}
+		}
+
+		if failed > 0 {
+			fmt.Println(gchalk.BrightRed(fmt.Sprintf("%d of %d snapshot(s) failed.", failed, len(results))) +
+				`  Run with "--update" to accept the new output.`)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().Bool("update", false, "Write the current output to the golden files")
+}
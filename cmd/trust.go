@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/spf13/cobra"
+)
+
+// trustStoreFile returns the file where trusted local configuration files are recorded.
+func trustStoreFile() string {
+	return filepath.Join(userConfigDir, "trusted.yaml")
+}
+
+// applyLocalConfig finds the local configuration file for the given folder,
+// and if the user has trusted it, merges it into the configuration.
+func applyLocalConfig(configuration *config.Config, folder string) {
+	localFile := config.FindLocalConfig(folder)
+	if localFile == "" {
+		return
+	}
+
+	data, err := os.ReadFile(localFile)
+	if err != nil {
+		log.Warn("Could not read " + localFile + ": " + err.Error())
+		return
+	}
+
+	store, err := config.LoadTrustStore(trustStoreFile())
+	if err != nil {
+		log.Warn("Could not read " + trustStoreFile() + ": " + err.Error())
+		return
+	}
+
+	switch store.Status(localFile, data) {
+	case config.Trusted:
+		if err := configuration.ApplyLocalConfig(data); err != nil {
+			log.Warn("Error loading " + localFile + ": " + err.Error())
+		}
+	case config.Changed:
+		log.Warn(localFile + " has changed since it was trusted.  Review it, then run `" + programName + " trust` to use it.")
+	case config.Untrusted:
+		log.Warn(localFile + " is not trusted.  Review it, then run `" + programName + " trust` to use it, or `" + programName + " untrust` to ignore it.")
+	}
+}
+
+// localConfigArg works out which local configuration file the trust and
+// untrust commands should operate on.
+func localConfigArg(args []string) (string, error) {
+	var localFile string
+
+	if len(args) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		localFile = config.FindLocalConfig(cwd)
+		if localFile == "" {
+			return "", fmt.Errorf("no %s found in %s", config.LocalConfigFile, cwd)
+		}
+	} else {
+		localFile = args[0]
+		if fileutils.IsDirectory(localFile) {
+			localFile = filepath.Join(localFile, config.LocalConfigFile)
+		}
+	}
+
+	localFile, err := filepath.Abs(localFile)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(localFile)
+}
+
+var trustCmd = &cobra.Command{
+	Use:   "trust [path]",
+	Short: "Trust a local configuration file",
+	Long: heredoc.Doc(`
+		Trust a local ` + config.LocalConfigFile + ` configuration file, so it will be used
+		when rendering the prompt.  If no path is given, this trusts the closest
+		` + config.LocalConfigFile + ` in the current folder or its parents.
+
+		A local configuration file can add custom modules which run commands, so
+		be sure to review it before trusting it.  If the file changes, it will
+		need to be trusted again.
+	`),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		localFile, err := localConfigArg(args)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(localFile)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		updateTrustStore(func(store *config.TrustStore) {
+			store.Trust(localFile, data)
+		})
+		fmt.Println("Trusted " + localFile)
+	},
+}
+
+var untrustCmd = &cobra.Command{
+	Use:   "untrust [path]",
+	Short: "Stop using a local configuration file",
+	Long: heredoc.Doc(`
+		Mark a local ` + config.LocalConfigFile + ` configuration file as untrusted.  The
+		file will not be used when rendering the prompt, and you won't be warned
+		about it.  If no path is given, this uses the closest ` + config.LocalConfigFile + `
+		in the current folder or its parents.
+	`),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		localFile, err := localConfigArg(args)
+		if err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+
+		updateTrustStore(func(store *config.TrustStore) {
+			store.Deny(localFile)
+		})
+		fmt.Println("Untrusted " + localFile)
+	},
+}
+
+func updateTrustStore(update func(store *config.TrustStore)) {
+	store, err := config.LoadTrustStore(trustStoreFile())
+	if err != nil {
+		log.Error("Could not read " + trustStoreFile() + ": " + err.Error())
+		os.Exit(1)
+	}
+
+	update(store)
+
+	if err := store.Save(); err != nil {
+		log.Error("Could not write " + trustStoreFile() + ": " + err.Error())
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(trustCmd)
+	rootCmd.AddCommand(untrustCmd)
+}
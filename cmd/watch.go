@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/spf13/cobra"
+)
+
+// clearScreen moves the cursor to the top left corner and clears the terminal.
+const clearScreen = "\u001B[H\u001B[2J"
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Re-render the prompt every time the configuration file changes",
+	Long: `Renders the prompt in the current directory, and then watches the
+configuration file (and any file it extends), and renders the prompt again
+every time one of them is saved.  Run this in one terminal while you edit your
+configuration in another to get a live preview of your changes.
+
+This takes the same flags as "prompt", so you can preview the prompt in a
+different folder, or after a command failed:
+
+  ` + programName + ` watch --path ~/dev/myproject --status 1`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		interval, err := watchInterval(cmd)
+		if err != nil {
+			log.Error(err.Error())
+			os.Exit(1)
+		}
+
+		configFile := cfgFile
+		if configFile == "" {
+			configFile = defaultConfigFile
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var watched []string
+		var lastState []fileState
+		for {
+			state := statFiles(append([]string{configFile}, watched...))
+			if !fileStatesEqual(state, lastState) {
+				watched = renderWatchedConfig(cmd, configFile)
+				// The list of files might have changed if `extends` changed.
+				lastState = statFiles(append([]string{configFile}, watched...))
+			}
+
+			select {
+			case <-interrupt:
+				fmt.Println()
+				return
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// watchInterval returns the value of the "--interval" flag.  Returns an error
+// if the interval is not positive.
+func watchInterval(cmd *cobra.Command) (time.Duration, error) {
+	interval, err := cmd.Flags().GetInt("interval")
+	if err != nil {
+		return 0, err
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("--interval must be greater than 0, got %d", interval)
+	}
+	return time.Duration(interval) * time.Millisecond, nil
+}
+
+// renderWatchedConfig clears the screen, loads the given configuration file,
+// and renders the prompt.  Returns the list of other configuration files that
+// `configFile` extends, so they can be watched too.
+func renderWatchedConfig(cmd *cobra.Command, configFile string) []string {
+	fmt.Print(clearScreen)
+	fmt.Println(gchalk.BrightBlack(fmt.Sprintf(
+		"Watching %s - last rendered at %s.  Press Ctrl-C to stop.",
+		configFile,
+		time.Now().Format("15:04:05"),
+	)))
+	fmt.Println()
+
+	extends := extendedConfigFiles(configFile)
+
+	configuration, err := config.LoadConfigFromFile(configFile, false)
+	if err != nil {
+		fmt.Println(gchalk.Red("Error loading " + configFile + ": " + err.Error()))
+		return extends
+	}
+	prepareConfig(configuration)
+
+	context := newPromptContext(cmd, configuration)
+	_, prompt := modules.RenderPrompt(context, configuration.Prompt)
+	fmt.Println(prompt)
+	fmt.Println()
+
+	for _, warning := range context.Warnings() {
+		fmt.Println(gchalk.Yellow(warning))
+	}
+
+	return extends
+}
+
+// extendedConfigFiles returns the chain of configuration files that
+// `configFile` extends.
+func extendedConfigFiles(configFile string) []string {
+	var result []string
+	seen := map[string]bool{configFile: true}
+	for {
+		parent := config.ExtendsFile(configFile)
+		if parent == "" || seen[parent] {
+			return result
+		}
+		seen[parent] = true
+		result = append(result, parent)
+		configFile = parent
+	}
+}
+
+// fileState is the size and modification time of a file, used to tell when
+// the file has changed.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statFiles(files []string) []fileState {
+	result := make([]fileState, len(files))
+	for index, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			result[index] = fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+	return result
+}
+
+func fileStatesEqual(a []fileState, b []fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if a[index].exists != b[index].exists ||
+			a[index].size != b[index].size ||
+			!a[index].modTime.Equal(b[index].modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	addPromptContextFlags(watchCmd)
+	watchCmd.Flags().Int("interval", 250, "How often to check the configuration file for changes, in milliseconds")
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchInterval(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("interval", 250, "")
+		assert.NoError(t, cmd.Flags().Parse(args))
+		return cmd
+	}
+
+	interval, err := watchInterval(newCmd())
+	assert.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, interval)
+
+	interval, err = watchInterval(newCmd("--interval", "1000"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, interval)
+
+	_, err = watchInterval(newCmd("--interval", "0"))
+	assert.Error(t, err)
+
+	_, err = watchInterval(newCmd("--interval", "-5"))
+	assert.Error(t, err)
+}
@@ -1,12 +1,21 @@
 package ansigradient
 
 import (
+	"bytes"
 	"image/color"
-	"strings"
+	"strconv"
+	"sync"
 
 	"github.com/jwalton/gchalk/pkg/ansistyles"
 )
 
+// bufferPool holds buffers for renderRGBAs to reuse.  Every colored character
+// needs its own escape codes, so building a gradient into a fresh builder
+// allocates a lot of memory that is thrown away as soon as the string is done.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
 // ApplyGradients will apply the given background and foreground gradients to the given string.
 //
 // ApplyGradients will attempt to automatically detect the current color support level
@@ -72,32 +81,46 @@ var compareColors = [](func(color.RGBA, color.RGBA) bool){
 	},
 }
 
-var fgColorize = [](func(out *strings.Builder, c color.RGBA)){
-	LevelNone: func(out *strings.Builder, c color.RGBA) {},
-	LevelBasic: func(out *strings.Builder, c color.RGBA) {
-		ansistyles.WriteStringAnsi(out, ansistyles.Ansi256ToAnsi(ansistyles.RGBToAnsi256(c.R, c.G, c.B)))
+var fgColorize = [](func(out *bytes.Buffer, c color.RGBA)){
+	LevelNone: func(out *bytes.Buffer, c color.RGBA) {},
+	LevelBasic: func(out *bytes.Buffer, c color.RGBA) {
+		writeEscapeCode(out, "\u001B[", ansistyles.Ansi256ToAnsi(ansistyles.RGBToAnsi256(c.R, c.G, c.B)))
 	},
-	LevelAnsi256: func(out *strings.Builder, c color.RGBA) {
-		ansistyles.WriteStringAnsi256(out, ansistyles.RGBToAnsi256(c.R, c.G, c.B))
+	LevelAnsi256: func(out *bytes.Buffer, c color.RGBA) {
+		writeEscapeCode(out, "\u001B[38;5;", ansistyles.RGBToAnsi256(c.R, c.G, c.B))
 	},
-	LevelAnsi16m: func(out *strings.Builder, c color.RGBA) {
-		ansistyles.WriteStringAnsi16m(out, c.R, c.G, c.B)
+	LevelAnsi16m: func(out *bytes.Buffer, c color.RGBA) {
+		writeEscapeCode(out, "\u001B[38;2;", c.R, c.G, c.B)
 	},
 }
 
-var bgColorize = [](func(out *strings.Builder, c color.RGBA)){
-	LevelNone: func(out *strings.Builder, c color.RGBA) {},
-	LevelBasic: func(out *strings.Builder, c color.RGBA) {
-		ansistyles.WriteStringBgAnsi(out, ansistyles.Ansi256ToAnsi(ansistyles.RGBToAnsi256(c.R, c.G, c.B)))
+var bgColorize = [](func(out *bytes.Buffer, c color.RGBA)){
+	LevelNone: func(out *bytes.Buffer, c color.RGBA) {},
+	LevelBasic: func(out *bytes.Buffer, c color.RGBA) {
+		writeEscapeCode(out, "\u001B[", ansistyles.Ansi256ToAnsi(ansistyles.RGBToAnsi256(c.R, c.G, c.B))+10)
 	},
-	LevelAnsi256: func(out *strings.Builder, c color.RGBA) {
-		ansistyles.WriteStringBgAnsi256(out, ansistyles.RGBToAnsi256(c.R, c.G, c.B))
+	LevelAnsi256: func(out *bytes.Buffer, c color.RGBA) {
+		writeEscapeCode(out, "\u001B[48;5;", ansistyles.RGBToAnsi256(c.R, c.G, c.B))
 	},
-	LevelAnsi16m: func(out *strings.Builder, c color.RGBA) {
-		ansistyles.WriteStringBgAnsi16m(out, c.R, c.G, c.B)
+	LevelAnsi16m: func(out *bytes.Buffer, c color.RGBA) {
+		writeEscapeCode(out, "\u001B[48;2;", c.R, c.G, c.B)
 	},
 }
 
+// writeEscapeCode writes `prefix`, followed by the given values separated by
+// semicolons, followed by an "m".
+func writeEscapeCode(out *bytes.Buffer, prefix string, values ...uint8) {
+	var scratch [3]byte
+	out.WriteString(prefix)
+	for index, value := range values {
+		if index > 0 {
+			out.WriteByte(';')
+		}
+		out.Write(strconv.AppendUint(scratch[:0], uint64(value), 10))
+	}
+	out.WriteByte('m')
+}
+
 type colorizeContext struct {
 	level       ColorLevel
 	lastFgColor color.RGBA
@@ -113,9 +136,11 @@ func renderRGBAs(
 	bgColors ColorGenerator,
 	level ColorLevel,
 ) string {
-	out := strings.Builder{}
-	worstCaseLength := printWidth * 20
-	out.Grow(worstCaseLength)
+	out := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		out.Reset()
+		bufferPool.Put(out)
+	}()
 
 	column := 0
 	context := colorizeContext{level: level}
@@ -128,9 +153,9 @@ func renderRGBAs(
 		case tokenString:
 			if (fgColors == nil || token.fg != "") && (bgColors == nil || token.bg != "") {
 				// Don't color this string.
-				out.Write([]byte(token.content))
+				out.WriteString(token.content)
 			} else {
-				colorizeASCIIString(&context, token.content, column, fgColors, bgColors, &out)
+				colorizeASCIIString(&context, token.content, column, fgColors, bgColors, out)
 			}
 			column += len(token.content)
 
@@ -138,7 +163,7 @@ func renderRGBAs(
 			if (bgColors == nil || token.fg != "") && (bgColors == nil || token.bg != "") {
 				// Don't color this string.
 			} else {
-				renderColorCodes(&context, float64(column)+(float64(token.printWidth)/2), fgColors, bgColors, &out)
+				renderColorCodes(&context, float64(column)+(float64(token.printWidth)/2), fgColors, bgColors, out)
 			}
 			out.WriteString(token.content)
 
@@ -195,7 +220,7 @@ func colorizeASCIIString(
 	column int,
 	fgColors ColorGenerator,
 	bgColors ColorGenerator,
-	out *strings.Builder,
+	out *bytes.Buffer,
 ) {
 	for charIndex := range str {
 		renderColorCodes(context, float64(column)+0.5, fgColors, bgColors, out)
@@ -210,7 +235,7 @@ func renderColorCodes(
 	position float64,
 	fgColors ColorGenerator,
 	bgColors ColorGenerator,
-	out *strings.Builder,
+	out *bytes.Buffer,
 ) {
 	// Write the forground color, if any.
 	if fgColors != nil {
@@ -2,7 +2,7 @@ package ansigradient
 
 import (
 	"github.com/jwalton/go-ansiparser"
-	"github.com/mattn/go-runewidth"
+	"github.com/jwalton/kitsch/internal/textwidth"
 	"github.com/rivo/uniseg"
 )
 
@@ -118,7 +118,7 @@ func tokenizeStringTokenWithUnicodeCharacters(ansiToken ansiparser.AnsiToken) []
 				content:    grapheme,
 				fg:         ansiToken.FG,
 				bg:         ansiToken.BG,
-				printWidth: runewidth.StringWidth(grapheme),
+				printWidth: textwidth.GraphemeWidth(grapheme),
 			})
 
 			position += len(grapheme)
@@ -0,0 +1,76 @@
+package colortools
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ParseXColor parses a color in the "rgb:RRRR/GGGG/BBBB" format used by
+// terminals when replying to an OSC 11 query.  Each component may have between
+// one and four hex digits.
+func ParseXColor(str string) (color.RGBA, bool) {
+	str = strings.TrimSpace(str)
+	if !strings.HasPrefix(str, "rgb:") {
+		return color.RGBA{}, false
+	}
+
+	parts := strings.Split(str[len("rgb:"):], "/")
+	if len(parts) != 3 {
+		return color.RGBA{}, false
+	}
+
+	var components [3]uint8
+	for index, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return color.RGBA{}, false
+		}
+		value, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return color.RGBA{}, false
+		}
+		// Scale the value to 8 bits.
+		max := uint64(1)<<(4*uint(len(part))) - 1
+		components[index] = uint8(value * 255 / max)
+	}
+
+	return color.RGBA{components[0], components[1], components[2], 255}, true
+}
+
+// IsLight returns true if the given color is closer to white than to black.
+func IsLight(c color.RGBA) bool {
+	// Perceived brightness, from https://www.w3.org/TR/AERT/#color-contrast.
+	brightness := (299*int(c.R) + 587*int(c.G) + 114*int(c.B)) / 1000
+	return brightness > 127
+}
+
+// DetectBackground works out if the terminal has a "light" or "dark"
+// background.  `reply` is the color the terminal reported in reply to an OSC
+// 11 query (e.g. "rgb:ffff/ffff/ffff"), and `colorfgbg` is the value of the
+// COLORFGBG environment variable, set by some terminals (e.g. "15;0").  The
+// OSC 11 reply is preferred, as it is always up to date.  Returns "" if the
+// background can't be determined.
+func DetectBackground(reply string, colorfgbg string) string {
+	if background, ok := ParseXColor(reply); ok {
+		if IsLight(background) {
+			return "light"
+		}
+		return "dark"
+	}
+
+	if colorfgbg != "" {
+		// COLORFGBG is "fg;bg" or "fg;default;bg".  The background is an ANSI
+		// color index.
+		parts := strings.Split(colorfgbg, ";")
+		bg, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil {
+			return ""
+		}
+		if bg == 7 || (bg >= 9 && bg <= 15) {
+			return "light"
+		}
+		return "dark"
+	}
+
+	return ""
+}
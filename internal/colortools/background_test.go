@@ -0,0 +1,32 @@
+package colortools
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseXColor(t *testing.T) {
+	c, ok := ParseXColor("rgb:ffff/8080/0000")
+	assert.True(t, ok)
+	assert.Equal(t, color.RGBA{255, 128, 0, 255}, c)
+
+	c, ok = ParseXColor("rgb:f/80/000")
+	assert.True(t, ok)
+	assert.Equal(t, color.RGBA{255, 128, 0, 255}, c)
+
+	_, ok = ParseXColor("rgb:ffff/ffff")
+	assert.False(t, ok)
+	_, ok = ParseXColor("#ffffff")
+	assert.False(t, ok)
+}
+
+func TestDetectBackground(t *testing.T) {
+	assert.Equal(t, "light", DetectBackground("rgb:ffff/ffff/ffff", ""))
+	assert.Equal(t, "dark", DetectBackground("rgb:1e1e/1e1e/1e1e", "0;15"))
+	assert.Equal(t, "light", DetectBackground("", "0;15"))
+	assert.Equal(t, "dark", DetectBackground("", "15;default;0"))
+	assert.Equal(t, "", DetectBackground("", "15;default"))
+	assert.Equal(t, "", DetectBackground("", ""))
+}
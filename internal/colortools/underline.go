@@ -0,0 +1,50 @@
+package colortools
+
+import (
+	"strconv"
+	"strings"
+)
+
+// styledUnderlineTerminals are values of TERM_PROGRAM for terminals which
+// support styled underlines and underline colors.
+var styledUnderlineTerminals = map[string]bool{
+	"iTerm.app": true,
+	"WezTerm":   true,
+	"vscode":    true,
+	"ghostty":   true,
+}
+
+// SupportsStyledUnderlines returns true if the terminal is known to support
+// styled underlines (e.g. curly underlines) and underline colors.  Terminals
+// which don't understand these ignore them or, worse, misinterpret them, so
+// this errs on the side of returning false.
+func SupportsStyledUnderlines(getenv func(string) string) bool {
+	// tmux only passes styled underlines through if it has been configured to,
+	// and we can't tell from here.
+	if getenv("TMUX") != "" {
+		return false
+	}
+
+	if styledUnderlineTerminals[getenv("TERM_PROGRAM")] {
+		return true
+	}
+
+	term := getenv("TERM")
+	for _, name := range []string{"kitty", "wezterm", "foot", "ghostty", "alacritty", "contour"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+
+	if getenv("KITTY_WINDOW_ID") != "" || getenv("WT_SESSION") != "" {
+		return true
+	}
+
+	// VTE based terminals (e.g. GNOME Terminal) support styled underlines
+	// since 0.51.2.
+	if version, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && version >= 5102 {
+		return true
+	}
+
+	return false
+}
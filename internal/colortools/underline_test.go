@@ -0,0 +1,20 @@
+package colortools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupportsStyledUnderlines(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	assert.True(t, SupportsStyledUnderlines(env(map[string]string{"TERM_PROGRAM": "iTerm.app"})))
+	assert.True(t, SupportsStyledUnderlines(env(map[string]string{"TERM": "xterm-kitty"})))
+	assert.True(t, SupportsStyledUnderlines(env(map[string]string{"VTE_VERSION": "6800"})))
+	assert.False(t, SupportsStyledUnderlines(env(map[string]string{"VTE_VERSION": "5000"})))
+	assert.False(t, SupportsStyledUnderlines(env(map[string]string{"TERM": "xterm-256color"})))
+	assert.False(t, SupportsStyledUnderlines(env(map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux-1000/default,1,0"})))
+}
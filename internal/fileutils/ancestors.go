@@ -0,0 +1,143 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Ancestors finds files in a folder, or in any ancestor of that folder.
+//
+// Many modules want to know if there's a particular file in the current folder
+// or one of its parents (".git", ".nvmrc", ".tool-versions", etc...).  Rather
+// than having each of them walk all the way up to "/", Ancestors reads the
+// contents of each folder at most once, and answers every query from what it
+// has already read.  Folders are read lazily, from the starting folder
+// upwards, so finding a file in the current folder never reads any of its
+// parents.  Ancestors is safe to use from multiple goroutines.
+type Ancestors struct {
+	mutex sync.Mutex
+	// folders is the starting folder, followed by each of its ancestors.
+	folders []string
+	// contents holds the names of the files in each folder in `folders`.  A
+	// nil entry is a folder we haven't read yet.
+	contents []map[string]bool
+	// unreadable is true for each folder we tried to read, but couldn't.
+	unreadable []bool
+	// results caches the result of each call to Find.
+	results map[string]string
+	// allResults caches the result of each call to FindAll.
+	allResults map[string][]string
+}
+
+// NewAncestors returns a new Ancestors for the given folder.
+func NewAncestors(folder string) *Ancestors {
+	var folders []string
+	current := filepath.Clean(folder)
+	for {
+		folders = append(folders, current)
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+
+	return &Ancestors{
+		folders:    folders,
+		contents:   make([]map[string]bool, len(folders)),
+		unreadable: make([]bool, len(folders)),
+		results:    map[string]string{},
+		allResults: map[string][]string{},
+	}
+}
+
+// Find searches for a file or directory with the given name in the starting
+// folder, or in any ancestor of that folder.  Returns the path to the nearest
+// match, or empty string if the file could not be found.  `name` may be a
+// relative path like "_opam/.opam-switch/switch-state".
+func (ancestors *Ancestors) Find(name string) string {
+	ancestors.mutex.Lock()
+	defer ancestors.mutex.Unlock()
+
+	if result, ok := ancestors.results[name]; ok {
+		return result
+	}
+
+	result := ""
+	if matches := ancestors.find(name, false); len(matches) > 0 {
+		result = matches[0]
+	}
+
+	ancestors.results[name] = result
+	return result
+}
+
+// FindAll is like Find, but returns every match for `name`, starting with the
+// nearest.  This has to read every ancestor of the starting folder.
+func (ancestors *Ancestors) FindAll(name string) []string {
+	ancestors.mutex.Lock()
+	defer ancestors.mutex.Unlock()
+
+	if result, ok := ancestors.allResults[name]; ok {
+		return result
+	}
+
+	result := ancestors.find(name, true)
+	ancestors.allResults[name] = result
+	return result
+}
+
+// find searches for `name` in each folder, starting with the nearest.  If `all`
+// is false, this stops at the first match.  Caller must hold the mutex.
+func (ancestors *Ancestors) find(name string, all bool) []string {
+	// For "a/b/c", look for "a" in each folder's contents, then check for
+	// the rest of the path on disk.
+	first := name
+	if index := strings.IndexAny(name, `/\`); index != -1 {
+		first = name[:index]
+	}
+
+	var result []string
+	for index, folder := range ancestors.folders {
+		if !ancestors.mightContain(index, first) {
+			continue
+		}
+		candidate := filepath.Join(folder, name)
+		if (first == name && !ancestors.unreadable[index]) || FileExists(candidate) {
+			result = append(result, candidate)
+			if !all {
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// mightContain returns true if the folder at the given index contains a file
+// with the given name, or if we can't read the folder to find out.  Caller
+// must hold the mutex.
+func (ancestors *Ancestors) mightContain(index int, name string) bool {
+	if ancestors.contents[index] == nil && !ancestors.unreadable[index] {
+		ancestors.contents[index] = map[string]bool{}
+
+		file, err := os.Open(ancestors.folders[index])
+		if err == nil {
+			names, err := file.Readdirnames(-1)
+			file.Close()
+			if err == nil {
+				for _, entry := range names {
+					ancestors.contents[index][entry] = true
+				}
+			} else {
+				ancestors.unreadable[index] = true
+			}
+		} else {
+			ancestors.unreadable[index] = true
+		}
+	}
+
+	return ancestors.unreadable[index] || ancestors.contents[index][name]
+}
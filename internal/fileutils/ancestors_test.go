@@ -0,0 +1,86 @@
+package fileutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+}
+
+func TestAncestorsFind(t *testing.T) {
+	root := t.TempDir()
+	cwd := filepath.Join(root, "a", "b", "c")
+	require.NoError(t, os.MkdirAll(cwd, 0755))
+
+	writeTestFile(t, filepath.Join(root, ".tool-versions"))
+	writeTestFile(t, filepath.Join(root, "a", ".tool-versions"))
+	writeTestFile(t, filepath.Join(root, "a", "b", ".nvmrc"))
+	writeTestFile(t, filepath.Join(root, "a", "_opam", ".opam-switch", "switch-state"))
+
+	ancestors := NewAncestors(cwd)
+
+	// Finds the nearest match.
+	assert.Equal(t, filepath.Join(root, "a", ".tool-versions"), ancestors.Find(".tool-versions"))
+	assert.Equal(t, filepath.Join(root, "a", "b", ".nvmrc"), ancestors.Find(".nvmrc"))
+
+	// Finds relative paths.
+	assert.Equal(t,
+		filepath.Join(root, "a", "_opam", ".opam-switch", "switch-state"),
+		ancestors.Find("_opam/.opam-switch/switch-state"),
+	)
+	assert.Equal(t, "", ancestors.Find("_opam/.opam-switch/missing"))
+
+	assert.Equal(t, "", ancestors.Find("kitsch-no-such-file"))
+}
+
+func TestAncestorsFindAll(t *testing.T) {
+	root := t.TempDir()
+	cwd := filepath.Join(root, "a", "b", "c")
+	require.NoError(t, os.MkdirAll(cwd, 0755))
+
+	writeTestFile(t, filepath.Join(root, ".tool-versions"))
+	writeTestFile(t, filepath.Join(root, "a", ".tool-versions"))
+	writeTestFile(t, filepath.Join(root, "a", ".config", "mise.toml"))
+
+	ancestors := NewAncestors(cwd)
+
+	assert.Equal(t,
+		[]string{filepath.Join(root, "a", ".tool-versions"), filepath.Join(root, ".tool-versions")},
+		ancestors.FindAll(".tool-versions"),
+	)
+	assert.Equal(t,
+		[]string{filepath.Join(root, "a", ".config", "mise.toml")},
+		ancestors.FindAll(".config/mise.toml"),
+	)
+	assert.Empty(t, ancestors.FindAll("kitsch-no-such-file"))
+
+	// Find and FindAll agree on the nearest match.
+	assert.Equal(t, filepath.Join(root, "a", ".tool-versions"), ancestors.Find(".tool-versions"))
+}
+
+func TestAncestorsReadsEachFolderOnce(t *testing.T) {
+	root := t.TempDir()
+	cwd := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(cwd, 0755))
+	writeTestFile(t, filepath.Join(root, "a", "go.mod"))
+
+	ancestors := NewAncestors(cwd)
+	assert.Equal(t, filepath.Join(root, "a", "go.mod"), ancestors.Find("go.mod"))
+
+	// Folders above the match haven't been read.
+	assert.NotNil(t, ancestors.contents[0])
+	assert.NotNil(t, ancestors.contents[1])
+	assert.Nil(t, ancestors.contents[2])
+
+	// Files created after a folder has been read aren't seen, because we
+	// don't read the folder again.
+	writeTestFile(t, filepath.Join(cwd, ".nvmrc"))
+	assert.Equal(t, "", ancestors.Find(".nvmrc"))
+}
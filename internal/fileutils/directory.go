@@ -4,6 +4,8 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -13,37 +15,65 @@ import (
 
 // Directory represents a directory on the file system.  Directory has functions
 // for efficiently checking if files or extensions exist in the directory.
+//
+// The contents of the directory are read from disk at most once, the first time
+// they are needed, and are then shared by every caller.  All functions are
+// thread safe.
 type Directory interface {
 	// Path returns the path to this directory on disk.
 	Path() string
 	// HasExtension returns true if the directory contains a file with the specified
-	// extension (e.g. `HasExtension("gif")`).`  This function is thread safe.
+	// extension (e.g. `HasExtension("gif")`).
 	HasExtension(extension string) bool
+	// ExtensionCount returns the number of files in the directory with the
+	// specified extension.
+	ExtensionCount(extension string) int
 	// HasFile returns true if the directory contains a file with the specified name.
 	HasFile(name string) bool
+	// FileCount returns the number of files in the directory.
+	FileCount() int
 	// HasGlob returns true if the directory contains files which match the
 	// specified glob pattern.  The pattern is the same as for `match.Match`.
 	// The pattern may describe hierarchical paths like "*/*.js".
 	HasGlob(glob string) bool
+	// GlobCount returns the number of files which match the specified glob
+	// pattern.
+	GlobCount(glob string) int
+	// Truncated returns true if we stopped reading the contents of the
+	// directory early, because it took too long or because the directory
+	// contains too many files.  If this is true, the other functions only
+	// know about some of the files in the directory.
+	Truncated() bool
 	// FileSystem returns an fs.FS rooted in the directory.
 	FileSystem() fs.FS
 	// FindFileInAncestors searches for the specified file in this directory or any
 	// ancestor of this directory in the file system.  If the file is found,
-	// the complete path is returned.
+	// the complete path is returned.  Each ancestor is read at most once, no
+	// matter how many files are searched for.
 	FindFileInAncestors(name string) string
+	// FindAllInAncestors is like FindFileInAncestors, but returns every
+	// match, starting with the one nearest to this directory.
+	FindAllInAncestors(name string) []string
+	// ReadFile reads the named file.  `name` is either relative to this
+	// directory, or an absolute path like the ones returned by
+	// FindFileInAncestors.
+	ReadFile(name string) ([]byte, error)
 	// Stat returns the os.FileInfo for the specified file.
 	Stat(path string) (os.FileInfo, error)
 }
 
 // NewDirectory creates a new Directory object for the directory at the given path.
-// scanTimeout is the maximum time to spend reading files from disk.  If 0,
-// no timeout is used.
-func NewDirectory(path string, scanTimeout time.Duration) Directory {
+// scanTimeout is the maximum time to spend reading files from disk, and
+// maxEntries is the maximum number of files to read.  If either is 0, there
+// is no limit.
+func NewDirectory(path string, scanTimeout time.Duration, maxEntries int) Directory {
 	return &directory{
 		path:         path,
 		fileSystem:   os.DirFS(path),
 		testInstance: false,
 		scanTimeout:  scanTimeout,
+		maxEntries:   maxEntries,
+		ancestors:    NewAncestors(path),
 	}
 }
 
@@ -65,20 +95,30 @@ type directory struct {
 	filesOnce  sync.Once
 	// files is a map of all files in the current directory.
 	files map[string]interface{}
-	// extensions is a map of all file extensions in the current directory, without
-	// a leading ".".  For example, if there's a "foo.gif" in the current folder,
-	// then `extensions["gif"]` will be set.
-	extensions   map[string]interface{}
+	// names is a list of all files in the current directory, in the order
+	// they were read.
+	names []string
+	// extensions is a count of files with each file extension in the current
+	// directory, without a leading ".".  For example, if there's a "foo.gif"
+	// in the current folder, then `extensions["gif"]` will be 1.
+	extensions   map[string]int
 	testInstance bool
 	// scanTimeout is the maximum time to wait for loading directory contents to complete.
 	scanTimeout time.Duration
+	// maxEntries is the maximum number of files to read.
+	maxEntries int
+	// truncated is true if we stopped reading files before reaching the end
+	// of the directory.
+	truncated bool
+	// ancestors is used to find files in this directory's ancestors.
+	ancestors *Ancestors
 }
 
 // Note that caller must have mutex.
 func (dir *directory) lazyInitFiles() {
 	dir.filesOnce.Do(func() {
 		dir.files = make(map[string]interface{})
-		dir.extensions = make(map[string]interface{})
+		dir.extensions = make(map[string]int)
 
 		if dir.testInstance {
 			// For test instance, use fs.ReadDir.  This is MUCH
@@ -90,6 +130,10 @@ func (dir *directory) lazyInitFiles() {
 			}
 
 			for _, file := range files {
+				if dir.isFull() {
+					dir.truncated = true
+					break
+				}
 				dir.cacheFile(file.Name())
 			}
 
@@ -103,7 +147,6 @@ func (dir *directory) lazyInitFiles() {
 			start := time.Now()
 
 			done := false
-			fileCount := 0
 			for !done {
 				// Grab files, 256 at a time.
 				files, err := f.Readdirnames(256)
@@ -113,25 +156,42 @@ func (dir *directory) lazyInitFiles() {
 					return
 				} else {
 					for _, file := range files {
-						fileCount++
+						if dir.isFull() {
+							dir.truncated = true
+							break
+						}
 						dir.cacheFile(file)
 					}
 				}
 
-				if dir.scanTimeout > 0 && time.Since(start) > dir.scanTimeout {
+				if dir.truncated {
+					// There are too many files in this directory.  Use the
+					// ones we have so far.
+					done = true
+					log.Info("Directory scan of ", dir.path, " stopped after ", len(dir.names), " files.")
+				}
+
+				if !done && dir.scanTimeout > 0 && time.Since(start) > dir.scanTimeout {
 					// We've spent too long reading files from the disk.  Use what
 					// we have so far.
 					done = true
-					log.Info("Directory scan timed out ", dir.path, " after ", fileCount, " files.")
+					dir.truncated = true
+					log.Info("Directory scan timed out ", dir.path, " after ", len(dir.names), " files.")
 				}
 			}
 		}
 	})
 }
 
+// isFull returns true if we've read as many files as we're allowed to.
+func (dir *directory) isFull() bool {
+	return dir.maxEntries > 0 && len(dir.names) >= dir.maxEntries
+}
+
 // cacheFile caches information about a file in the current directory.
 func (dir *directory) cacheFile(filename string) {
 	dir.files[filename] = nil
+	dir.names = append(dir.names, filename)
 
 	parts := strings.Split(filename, ".")
 
@@ -141,7 +201,7 @@ func (dir *directory) cacheFile(filename string) {
 		// If there are two parts, use the second part as the extension.
 		ext := parts[1]
 		if len(ext) > 0 {
-			dir.extensions[ext] = nil
+			dir.extensions[ext]++
 		}
 	} else if len(parts) > 2 {
 		// If there are multiple parts, like "foo.rc.js", then we want to
@@ -149,26 +209,29 @@ func (dir *directory) cacheFile(filename string) {
 		for i := 1; i < len(parts); i++ {
 			ext := strings.Join(parts[i:], ".")
 			if len(ext) > 0 {
-				dir.extensions[ext] = nil
+				dir.extensions[ext]++
 			}
 		}
 	}
 }
 
 func (dir *directory) HasExtension(extension string) bool {
+	return dir.ExtensionCount(extension) > 0
+}
+
+func (dir *directory) ExtensionCount(extension string) int {
 	// Strip the starting ".", if there is one.
 	if len(extension) > 0 && extension[0] == '.' {
 		extension = extension[1:]
 	}
 
 	if len(extension) == 0 {
-		return false
+		return 0
 	}
 
 	dir.lazyInitFiles()
 
-	_, ok := dir.extensions[extension]
-	return ok
+	return dir.extensions[extension]
 }
 
 func (dir *directory) Path() string {
@@ -182,13 +245,53 @@ func (dir *directory) HasFile(name string) bool {
 	return ok
 }
 
-func (dir *directory) HasGlob(name string) bool {
+func (dir *directory) FileCount() int {
+	dir.lazyInitFiles()
+	return len(dir.names)
+}
+
+func (dir *directory) HasGlob(glob string) bool {
+	return dir.globCount(glob, 1) > 0
+}
+
+func (dir *directory) GlobCount(glob string) int {
+	return dir.globCount(glob, -1)
+}
+
+// globCount returns the number of files which match the specified glob, up
+// to `limit`.  If `limit` is negative, all matching files are counted.
+func (dir *directory) globCount(glob string, limit int) int {
 	// TODO: Would be nice if this supported "**" style globs.
-	files, err := fs.Glob(dir.fileSystem, name)
-	if err != nil {
-		return false
+	if strings.Contains(glob, "/") {
+		// Hierarchical globs need to read subdirectories.
+		files, err := fs.Glob(dir.fileSystem, glob)
+		if err != nil {
+			return 0
+		}
+		return len(files)
+	}
+
+	if _, err := path.Match(glob, ""); err != nil {
+		return 0
 	}
-	return len(files) > 0
+
+	dir.lazyInitFiles()
+
+	count := 0
+	for _, name := range dir.names {
+		if matched, _ := path.Match(glob, name); matched {
+			count++
+			if count == limit {
+				break
+			}
+		}
+	}
+	return count
+}
+
+func (dir *directory) Truncated() bool {
+	dir.lazyInitFiles()
+	return dir.truncated
 }
 
 func (dir *directory) FileSystem() fs.FS {
@@ -199,7 +302,29 @@ func (dir *directory) FindFileInAncestors(name string) string {
 	if dir.testInstance {
 		return ""
 	}
-	return FindFileInAncestors(dir.Path(), name)
+	return dir.ancestors.Find(name)
+}
+
+func (dir *directory) FindAllInAncestors(name string) []string {
+	if dir.testInstance {
+		return nil
+	}
+	return dir.ancestors.FindAll(name)
+}
+
+func (dir *directory) ReadFile(name string) ([]byte, error) {
+	if !filepath.IsAbs(name) {
+		return fs.ReadFile(dir.fileSystem, filepath.ToSlash(name))
+	}
+	if dir.testInstance {
+		// Test instances can only read files from their own FS.
+		rel, err := filepath.Rel(dir.path, name)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return nil, fs.ErrNotExist
+		}
+		return fs.ReadFile(dir.fileSystem, filepath.ToSlash(rel))
+	}
+	return os.ReadFile(name)
 }
 
 func (dir *directory) Stat(path string) (os.FileInfo, error) {
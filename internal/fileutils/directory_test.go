@@ -1,12 +1,17 @@
 package fileutils
 
 import (
+	"fmt"
+	"os"
 	"path"
+	"path/filepath"
 	"runtime"
+	"sync"
 	"testing"
 	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHasExtension(t *testing.T) {
@@ -16,7 +21,7 @@ func TestHasExtension(t *testing.T) {
 	}
 
 	sourceDir := path.Dir(sourceFile)
-	dir := NewDirectory(sourceDir, 0)
+	dir := NewDirectory(sourceDir, 0, 0)
 
 	assert.Equal(t, true, dir.HasExtension("go"))
 	assert.Equal(t, true, dir.HasExtension(".go"))
@@ -69,3 +74,123 @@ func TestHasFile(t *testing.T) {
 
 	assert.Equal(t, true, dir.HasFile("src"))
 }
+
+func TestCounts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.js":       &fstest.MapFile{},
+		"util.js":        &fstest.MapFile{},
+		"util.test.js":   &fstest.MapFile{},
+		"README.md":      &fstest.MapFile{},
+		"src/widgets.js": &fstest.MapFile{},
+	}
+
+	dir := NewDirectoryTestFS("/foo/bar", fsys)
+
+	assert.Equal(t, 5, dir.FileCount())
+	assert.Equal(t, 3, dir.ExtensionCount("js"))
+	assert.Equal(t, 1, dir.ExtensionCount(".test.js"))
+	assert.Equal(t, 0, dir.ExtensionCount("go"))
+	assert.Equal(t, false, dir.Truncated())
+}
+
+func TestGlobs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.js":       &fstest.MapFile{},
+		"util.js":        &fstest.MapFile{},
+		"README.md":      &fstest.MapFile{},
+		"src/widgets.js": &fstest.MapFile{},
+		"src/gadgets.js": &fstest.MapFile{},
+	}
+
+	dir := NewDirectoryTestFS("/foo/bar", fsys)
+
+	assert.Equal(t, true, dir.HasGlob("*.js"))
+	assert.Equal(t, 2, dir.GlobCount("*.js"))
+	assert.Equal(t, 1, dir.GlobCount("README.*"))
+	assert.Equal(t, false, dir.HasGlob("*.go"))
+
+	// Hierarchical globs.
+	assert.Equal(t, true, dir.HasGlob("*/*.js"))
+	assert.Equal(t, 2, dir.GlobCount("src/*.js"))
+
+	// Invalid globs never match.
+	assert.Equal(t, false, dir.HasGlob("[.js"))
+}
+
+func TestMaxEntries(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.js": &fstest.MapFile{},
+		"b.js": &fstest.MapFile{},
+		"c.go": &fstest.MapFile{},
+		"d.go": &fstest.MapFile{},
+	}
+
+	dir := &directory{path: "/foo/bar", fileSystem: fsys, testInstance: true, maxEntries: 2}
+	assert.Equal(t, 2, dir.FileCount())
+	assert.Equal(t, true, dir.Truncated())
+	assert.Equal(t, true, dir.HasFile("a.js"))
+	assert.Equal(t, false, dir.HasExtension("go"))
+
+	// A directory with exactly maxEntries files isn't truncated.
+	dir = &directory{path: "/foo/bar", fileSystem: fsys, testInstance: true, maxEntries: 4}
+	assert.Equal(t, 4, dir.FileCount())
+	assert.Equal(t, false, dir.Truncated())
+}
+
+func TestMaxEntriesOnDisk(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 600; i++ {
+		err := os.WriteFile(filepath.Join(root, fmt.Sprintf("file%d.txt", i)), nil, 0644)
+		require.NoError(t, err)
+	}
+
+	dir := NewDirectory(root, 0, 300)
+	assert.Equal(t, 300, dir.FileCount())
+	assert.Equal(t, true, dir.Truncated())
+
+	dir = NewDirectory(root, 0, 0)
+	assert.Equal(t, 600, dir.FileCount())
+	assert.Equal(t, false, dir.Truncated())
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":  &fstest.MapFile{},
+		"main.go": &fstest.MapFile{},
+	}
+
+	dir := NewDirectoryTestFS("/foo/bar", fsys)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, true, dir.HasFile("go.mod"))
+			assert.Equal(t, 1, dir.ExtensionCount("go"))
+			assert.Equal(t, 1, dir.GlobCount("*.go"))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestReadFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"src/index.js": &fstest.MapFile{
+			Data: []byte("hello"),
+		},
+	}
+
+	dir := NewDirectoryTestFS("/foo/bar", fsys)
+
+	contents, err := dir.ReadFile("src/index.js")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(contents))
+
+	contents, err = dir.ReadFile("/foo/bar/src/index.js")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(contents))
+
+	_, err = dir.ReadFile("/foo/package.json")
+	assert.Error(t, err)
+}
@@ -21,6 +21,13 @@ func FileExists(path string) bool {
 	}
 }
 
+// IsDirectory returns true if the path specified by "path" exists and is a
+// directory, false otherwise.
+func IsDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
 // FindFileInAncestors searches for a file or directory with the given name
 // in the specified folder, or in any ancestor of that folder.  Returns the
 // path to the file, or empty string if the file could not be found.
@@ -0,0 +1,227 @@
+package gitutils
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jwalton/kitsch/internal/cache"
+)
+
+// maxAheadBehindCommits is the maximum number of commits GetAheadBehind will
+// look at.  If two branches have diverged by more than this, the counts
+// returned by GetAheadBehind will be too low.
+const maxAheadBehindCommits = 1000
+
+// Flags used to mark which side of the comparison a commit is reachable from.
+const (
+	reachableFromLocal  = 1
+	reachableFromRemote = 2
+	reachableFromBoth   = reachableFromLocal | reachableFromRemote
+)
+
+// GetAheadBehind returns how many commits ahead and behind the given
+// localRef is compared to remoteRef.  This is computed by walking the commit
+// graph with go-git, which is much faster than running git, but will stop
+// after `maxAheadBehindCommits` commits, so very large counts are estimates.
+func (g *gitUtils) GetAheadBehind(localRef string, remoteRef string) (ahead int, behind int, err error) {
+	localHash, remoteHash, err := g.resolveRefs(localRef, remoteRef)
+	if err != nil {
+		return 0, 0, err
+	}
+	if localHash == remoteHash {
+		return 0, 0, nil
+	}
+
+	ahead, behind, _, err = g.walkAheadBehind(localHash, remoteHash, maxAheadBehindCommits)
+	return ahead, behind, err
+}
+
+// CountAheadBehind returns exactly how many commits ahead and behind the given
+// localRef is compared to remoteRef, by running `git rev-list`.  If git takes
+// longer than `timeout`, it is killed.  Since the answer only depends on the
+// commits the two refs point to, results are stored in `valueCache` (if it is
+// not nil) keyed on the hashes of the two commits, so git only needs to be run
+// once for any pair of commits.
+func (g *gitUtils) CountAheadBehind(
+	localRef string,
+	remoteRef string,
+	timeout time.Duration,
+	valueCache cache.Cache,
+) (ahead int, behind int, err error) {
+	localHash, remoteHash, err := g.resolveRefs(localRef, remoteRef)
+	if err != nil {
+		return 0, 0, err
+	}
+	if localHash == remoteHash {
+		return 0, 0, nil
+	}
+
+	cacheKey := "git-ahead-behind:" + localHash.String() + "..." + remoteHash.String()
+	if valueCache != nil {
+		if ahead, behind, ok := parseAheadBehind(string(valueCache.Get(cacheKey))); ok {
+			return ahead, behind, nil
+		}
+	}
+
+	if g.pathToGit == "" {
+		return 0, 0, ErrNoGit
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, g.pathToGit, "rev-list", "--left-right", "--count", localHash.String()+"..."+remoteHash.String())
+	cmd.Dir = g.repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, 0, fmt.Errorf("git rev-list timed out after %v", timeout)
+		}
+		return 0, 0, err
+	}
+
+	ahead, behind, ok := parseAheadBehind(string(out))
+	if !ok {
+		return 0, 0, fmt.Errorf("unexpected output from git rev-list: %q", string(out))
+	}
+
+	if valueCache != nil {
+		valueCache.Set(cacheKey, []byte(strconv.Itoa(ahead)+" "+strconv.Itoa(behind)))
+	}
+	return ahead, behind, nil
+}
+
+// parseAheadBehind parses the output of `git rev-list --left-right --count`,
+// which is the ahead and behind counts separated by whitespace.
+func parseAheadBehind(value string) (ahead int, behind int, ok bool) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	ahead, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	behind, err = strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return ahead, behind, true
+}
+
+// resolveRefs returns the hashes the given refs point to.
+func (g *gitUtils) resolveRefs(localRef string, remoteRef string) (plumbing.Hash, plumbing.Hash, error) {
+	local, err := g.storer.Reference(plumbing.ReferenceName(localRef))
+	if err != nil {
+		return plumbing.ZeroHash, plumbing.ZeroHash, err
+	}
+	remote, err := g.storer.Reference(plumbing.ReferenceName(remoteRef))
+	if err != nil {
+		return plumbing.ZeroHash, plumbing.ZeroHash, err
+	}
+	return local.Hash(), remote.Hash(), nil
+}
+
+// walkAheadBehind counts the commits which are reachable from only `local`
+// (ahead) and from only `remote` (behind).  Commits are visited newest first,
+// and the walk stops once every commit left to visit is reachable from both
+// sides, or after `limit` commits.  `complete` is false if the walk was cut
+// short by `limit`.
+func (g *gitUtils) walkAheadBehind(
+	local plumbing.Hash,
+	remote plumbing.Hash,
+	limit int,
+) (ahead int, behind int, complete bool, err error) {
+	flags := map[plumbing.Hash]int{}
+	queued := map[plumbing.Hash]bool{}
+	queue := &commitQueue{}
+	// interesting is the number of commits in the queue which are not yet
+	// reachable from both sides.
+	interesting := 0
+
+	push := func(hash plumbing.Hash, flag int) error {
+		if existing, seen := flags[hash]; seen {
+			if existing != reachableFromBoth && existing|flag == reachableFromBoth && queued[hash] {
+				interesting--
+			}
+			flags[hash] = existing | flag
+			return nil
+		}
+
+		commit, err := object.GetCommit(g.storer, hash)
+		if err == plumbing.ErrObjectNotFound {
+			// This can happen in a shallow clone.
+			return nil
+		} else if err != nil {
+			return err
+		}
+		flags[hash] = flag
+		queued[hash] = true
+		heap.Push(queue, commit)
+		if flag != reachableFromBoth {
+			interesting++
+		}
+		return nil
+	}
+
+	if err := push(local, reachableFromLocal); err != nil {
+		return 0, 0, false, err
+	}
+	if err := push(remote, reachableFromRemote); err != nil {
+		return 0, 0, false, err
+	}
+
+	for visited := 0; interesting > 0; visited++ {
+		if visited >= limit {
+			return ahead, behind, false, nil
+		}
+
+		commit := heap.Pop(queue).(*object.Commit)
+		delete(queued, commit.Hash)
+		flag := flags[commit.Hash]
+		switch flag {
+		case reachableFromLocal:
+			ahead++
+			interesting--
+		case reachableFromRemote:
+			behind++
+			interesting--
+		}
+
+		for _, parent := range commit.ParentHashes {
+			if err := push(parent, flag); err != nil {
+				return 0, 0, false, err
+			}
+		}
+	}
+
+	return ahead, behind, true, nil
+}
+
+// commitQueue is a priority queue of commits, newest first.
+type commitQueue struct {
+	commits []*object.Commit
+}
+
+func (q *commitQueue) Len() int { return len(q.commits) }
+func (q *commitQueue) Less(i, j int) bool {
+	return q.commits[i].Committer.When.After(q.commits[j].Committer.When)
+}
+func (q *commitQueue) Swap(i, j int)      { q.commits[i], q.commits[j] = q.commits[j], q.commits[i] }
+func (q *commitQueue) Push(x interface{}) { q.commits = append(q.commits, x.(*object.Commit)) }
+func (q *commitQueue) Pop() interface{} {
+	last := q.commits[len(q.commits)-1]
+	q.commits = q.commits[:len(q.commits)-1]
+	return last
+}
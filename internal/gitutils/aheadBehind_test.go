@@ -2,38 +2,30 @@ package gitutils
 
 import (
 	"io/fs"
+	"os"
+	"os/exec"
+	"strconv"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
-	"github.com/go-git/go-git/v5/plumbing/cache"
-	"github.com/go-git/go-git/v5/storage/filesystem"
-	"github.com/jwalton/kitsch/internal/billyutils"
+	"github.com/jwalton/kitsch/internal/cache"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // testGitUtils creates a new gitUtils for unit testing.
 func testGitUtils(repoRoot string, fsys fs.FS) *gitUtils {
-	var storer *filesystem.Storage
-	if fsys != nil {
-		repositoryFs, err := billyutils.FsToBilly(fsys)
-		if err != nil {
-			panic(err)
-		}
-		dotGitFs, err := repositoryFs.Chroot(".git")
-		if err != nil {
-			panic(err)
-		}
-
-		storer = filesystem.NewStorage(dotGitFs, cache.NewObjectLRUDefault())
+	if fsys == nil {
+		return &gitUtils{pathToGit: "git", repoRoot: repoRoot}
 	}
 
-	return &gitUtils{
-		pathToGit: "git",
-		fsys:      fsys,
-		repoRoot:  repoRoot,
-		storer:    storer,
+	git, err := newFromFS("git", repoRoot, fsys)
+	if err != nil {
+		panic(err)
 	}
+	return git
 }
 
 func TestGetUpstream(t *testing.T) {
@@ -87,3 +79,89 @@ func TestGetUpstreamNoConfig(t *testing.T) {
 		git.GetUpstream("feature/projects"),
 	)
 }
+
+// divergedRepo creates a git repository in a temporary folder, where "master"
+// is `ahead` commits ahead of, and `behind` commits behind, "origin/master".
+func divergedRepo(t *testing.T, ahead int, behind int) *gitUtils {
+	pathToGit, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	commitTime := 1600000000
+	run := func(args ...string) {
+		cmd := exec.Command(pathToGit, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=kitsch", "GIT_AUTHOR_EMAIL=kitsch@example.com",
+			"GIT_COMMITTER_NAME=kitsch", "GIT_COMMITTER_EMAIL=kitsch@example.com",
+			"GIT_AUTHOR_DATE="+strconv.Itoa(commitTime)+" +0000",
+			"GIT_COMMITTER_DATE="+strconv.Itoa(commitTime)+" +0000",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	commit := func(message string) {
+		commitTime += 60
+		run("commit", "--allow-empty", "-q", "-m", message)
+	}
+
+	run("init", "-q", "-b", "master")
+	commit("base")
+	commit("shared")
+	run("branch", "upstream")
+	for i := 0; i < ahead; i++ {
+		commit("local " + strconv.Itoa(i))
+	}
+	run("checkout", "-q", "upstream")
+	for i := 0; i < behind; i++ {
+		commit("remote " + strconv.Itoa(i))
+	}
+	run("checkout", "-q", "master")
+	run("update-ref", "refs/remotes/origin/master", "upstream")
+
+	return New(pathToGit, dir).(*gitUtils)
+}
+
+func TestGetAheadBehindWalk(t *testing.T) {
+	git := divergedRepo(t, 3, 2)
+
+	ahead, behind, err := git.GetAheadBehind("refs/heads/master", "refs/remotes/origin/master")
+	require.NoError(t, err)
+	assert.Equal(t, 3, ahead)
+	assert.Equal(t, 2, behind)
+
+	ahead, behind, err = git.GetAheadBehind("refs/remotes/origin/master", "refs/heads/master")
+	require.NoError(t, err)
+	assert.Equal(t, 2, ahead)
+	assert.Equal(t, 3, behind)
+
+	// If the walk is cut short, we should get an estimate.
+	localHash, remoteHash, err := git.resolveRefs("refs/heads/master", "refs/remotes/origin/master")
+	require.NoError(t, err)
+	ahead, behind, complete, err := git.walkAheadBehind(localHash, remoteHash, 2)
+	require.NoError(t, err)
+	assert.False(t, complete)
+	assert.Equal(t, 2, ahead+behind)
+}
+
+func TestCountAheadBehind(t *testing.T) {
+	git := divergedRepo(t, 4, 1)
+	valueCache := cache.NewMemoryCache()
+
+	ahead, behind, err := git.CountAheadBehind("refs/heads/master", "refs/remotes/origin/master", 10*time.Second, valueCache)
+	require.NoError(t, err)
+	assert.Equal(t, 4, ahead)
+	assert.Equal(t, 1, behind)
+
+	// The result should be cached, so we shouldn't need git anymore.
+	git.pathToGit = ""
+	ahead, behind, err = git.CountAheadBehind("refs/heads/master", "refs/remotes/origin/master", 10*time.Second, valueCache)
+	require.NoError(t, err)
+	assert.Equal(t, 4, ahead)
+	assert.Equal(t, 1, behind)
+
+	_, _, err = git.CountAheadBehind("refs/heads/master", "refs/remotes/origin/master", 10*time.Second, nil)
+	assert.Equal(t, ErrNoGit, err)
+}
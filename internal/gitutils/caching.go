@@ -1,6 +1,11 @@
 package gitutils
 
-import "sync"
+import (
+	"sync"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/cache"
+)
 
 // caching is a gitutils that caches results - it assumes the underlying repo
 // is not going to change between calls.
@@ -21,6 +26,14 @@ type caching struct {
 	ahead                int
 	behind               int
 
+	countLocalRef  string
+	countRemoteRef string
+	countAhead     int
+	countBehind    int
+
+	defaultBranchOnce sync.Once
+	defaultBranch     string
+
 	headInfoTagsSearched int
 	headInfo             *HeadInfo
 	stateOnce            sync.Once
@@ -28,6 +41,13 @@ type caching struct {
 	statsOnce            sync.Once
 	stats                GitStats
 	statsError           error
+	lfsOnce              sync.Once
+	lfs                  LFSInfo
+	sparseOnce           sync.Once
+	sparse               SparseCheckoutInfo
+	userOnce             sync.Once
+	user                 UserInfo
+	userErr              error
 }
 
 // NewCaching returns a new caching instance of Git.  The returned instance
@@ -85,6 +105,38 @@ func (c *caching) GetAheadBehind(localRef string, remoteRef string) (ahead int,
 	return c.ahead, c.behind, nil
 }
 
+// CountAheadBehind returns exactly how many commits ahead and behind the
+// given localRef is compared to remoteRef.
+func (c *caching) CountAheadBehind(
+	localRef string,
+	remoteRef string,
+	timeout time.Duration,
+	valueCache cache.Cache,
+) (ahead int, behind int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.countLocalRef != localRef || c.countRemoteRef != remoteRef {
+		ahead, behind, err = c.underlying.CountAheadBehind(localRef, remoteRef, timeout, valueCache)
+		if err != nil {
+			return 0, 0, err
+		}
+		c.countLocalRef = localRef
+		c.countRemoteRef = remoteRef
+		c.countAhead = ahead
+		c.countBehind = behind
+	}
+	return c.countAhead, c.countBehind, nil
+}
+
+// DefaultBranch returns the name of the repository's default branch.
+func (c *caching) DefaultBranch() string {
+	c.defaultBranchOnce.Do(func() {
+		c.defaultBranch = c.underlying.DefaultBranch()
+	})
+	return c.defaultBranch
+}
+
 // Head returns information about the current head.
 func (c *caching) Head(maxTagsToSearch int) (head HeadInfo, err error) {
 	c.mutex.Lock()
@@ -102,6 +154,11 @@ func (c *caching) Head(maxTagsToSearch int) (head HeadInfo, err error) {
 	return *c.headInfo, nil
 }
 
+// CommitTime returns the committer time of the commit with the given hash.
+func (c *caching) CommitTime(hash string) (time.Time, error) {
+	return c.underlying.CommitTime(hash)
+}
+
 // State returns the current state of the repository.
 func (c *caching) State() RepositoryState {
 	c.stateOnce.Do(func() {
@@ -118,3 +175,28 @@ func (c *caching) Stats() (GitStats, error) {
 	})
 	return c.stats, c.statsError
 }
+
+// LFS returns information about whether this repo uses Git LFS.
+func (c *caching) LFS() LFSInfo {
+	c.lfsOnce.Do(func() {
+		c.lfs = c.underlying.LFS()
+	})
+	return c.lfs
+}
+
+// SparseCheckout returns information about whether this repo is a sparse
+// checkout.
+func (c *caching) SparseCheckout() SparseCheckoutInfo {
+	c.sparseOnce.Do(func() {
+		c.sparse = c.underlying.SparseCheckout()
+	})
+	return c.sparse
+}
+
+// User returns the name and email git will use for new commits in this repo.
+func (c *caching) User() (UserInfo, error) {
+	c.userOnce.Do(func() {
+		c.user, c.userErr = c.underlying.User()
+	})
+	return c.user, c.userErr
+}
@@ -0,0 +1,44 @@
+package gitutils
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// DefaultBranch returns the name of the repository's default branch (e.g.
+// "main" or "master").
+//
+// If the repo was cloned, this is the branch "origin/HEAD" points to.
+// Otherwise we use `init.defaultBranch` from the git config, and if that isn't
+// set, we fall back to "master" (git's own default), or "main" if the repo
+// has a "main" branch but no "master".
+func (g *gitUtils) DefaultBranch() string {
+	ref, err := g.storer.Reference(plumbing.ReferenceName("refs/remotes/origin/HEAD"))
+	if err == nil && ref.Type() == plumbing.SymbolicReference {
+		target := ref.Target().String()
+		if strings.HasPrefix(target, "refs/remotes/origin/") {
+			return target[len("refs/remotes/origin/"):]
+		}
+	}
+
+	if g.pathToGit != "" {
+		// init.defaultBranch is almost always set in the global config, which
+		// go-git doesn't read, so we need to ask git for it.
+		out, err := g.git("config", "init.defaultBranch")
+		if err == nil && strings.TrimSpace(out) != "" {
+			return strings.TrimSpace(out)
+		}
+	}
+
+	if !g.hasBranch("master") && g.hasBranch("main") {
+		return "main"
+	}
+	return "master"
+}
+
+// hasBranch returns true if the given local branch exists.
+func (g *gitUtils) hasBranch(branch string) bool {
+	_, err := g.storer.Reference(plumbing.NewBranchReferenceName(branch))
+	return err == nil
+}
@@ -0,0 +1,49 @@
+package gitutils
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultBranchFromOriginHead(t *testing.T) {
+	files := fstest.MapFS{
+		".git/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/heads/feature\n"),
+		},
+		".git/refs/remotes/origin/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/remotes/origin/trunk\n"),
+		},
+		".git/refs/heads/master": &fstest.MapFile{
+			Data: []byte("7c088a39dcd2dcda89f4dee1fd3eb41c1d34ea2f\n"),
+		},
+	}
+
+	git := testGitUtils("/Users/oriana/dev/kitsch", files)
+	assert.Equal(t, "trunk", git.DefaultBranch())
+}
+
+func TestDefaultBranchFallback(t *testing.T) {
+	git, err := NewFromFS("/Users/oriana/dev/kitsch", fstest.MapFS{
+		".git/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/heads/feature\n"),
+		},
+		".git/refs/heads/master": &fstest.MapFile{
+			Data: []byte("7c088a39dcd2dcda89f4dee1fd3eb41c1d34ea2f\n"),
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "master", git.DefaultBranch())
+
+	git, err = NewFromFS("/Users/oriana/dev/kitsch", fstest.MapFS{
+		".git/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/heads/feature\n"),
+		},
+		".git/refs/heads/main": &fstest.MapFile{
+			Data: []byte("7c088a39dcd2dcda89f4dee1fd3eb41c1d34ea2f\n"),
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "main", git.DefaultBranch())
+}
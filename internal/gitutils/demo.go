@@ -3,6 +3,9 @@ package gitutils
 import (
 	"fmt"
 	"regexp"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/cache"
 )
 
 // DemoGit is an instance of the Git interface which returns demo values.  This
@@ -19,6 +22,10 @@ type DemoGit struct {
 	IsTag bool `yaml:"isTag"`
 	// CurrentBranchUpstream is the current upstream branch, or "" if none.
 	CurrentBranchUpstream string `yaml:"currentBranchUpstream"`
+	// DefaultBranchName is the name of the default branch.  Defaults to "master".
+	DefaultBranchName string `yaml:"defaultBranch"`
+	// HeadCommitTime is the time of the commit at HEAD.
+	HeadCommitTime time.Time `yaml:"headCommitTime"`
 
 	// CurrentState is the current state of this repo.
 	CurrentState RepositoryStateType `yaml:"state"`
@@ -37,6 +44,13 @@ type DemoGit struct {
 
 	// Stats for the current git repo.
 	CurrentStats GitStats `yaml:"stats"`
+
+	// CurrentUser is the identity git will use for new commits.
+	CurrentUser UserInfo `yaml:"user"`
+	// CurrentLFS describes whether this repo uses Git LFS.
+	CurrentLFS LFSInfo `yaml:"lfs"`
+	// CurrentSparseCheckout describes whether this repo is a sparse checkout.
+	CurrentSparseCheckout SparseCheckoutInfo `yaml:"sparseCheckout"`
 }
 
 // RepoRoot returns the root of the git repository.
@@ -70,6 +84,25 @@ func (git DemoGit) GetAheadBehind(localRef string, remoteRef string) (ahead int,
 	return 0, 0, fmt.Errorf("Unknown")
 }
 
+// CountAheadBehind returns exactly how many commits ahead and behind the
+// given localRef is compared to remoteRef.
+func (git DemoGit) CountAheadBehind(
+	localRef string,
+	remoteRef string,
+	timeout time.Duration,
+	valueCache cache.Cache,
+) (ahead int, behind int, err error) {
+	return git.GetAheadBehind(localRef, remoteRef)
+}
+
+// DefaultBranch returns the name of the repository's default branch.
+func (git DemoGit) DefaultBranch() string {
+	if git.DefaultBranchName == "" {
+		return "master"
+	}
+	return git.DefaultBranchName
+}
+
 // Head returns information about the current head.
 func (git DemoGit) Head(maxTagsToSearch int) (head HeadInfo, err error) {
 	var headDescription string
@@ -89,6 +122,11 @@ func (git DemoGit) Head(maxTagsToSearch int) (head HeadInfo, err error) {
 	}, nil
 }
 
+// CommitTime returns the committer time of the commit with the given hash.
+func (git DemoGit) CommitTime(hash string) (time.Time, error) {
+	return git.HeadCommitTime, nil
+}
+
 // State returns the current state of the repository.
 func (git DemoGit) State() RepositoryState {
 	return RepositoryState{
@@ -102,3 +140,19 @@ func (git DemoGit) State() RepositoryState {
 func (git DemoGit) Stats() (GitStats, error) {
 	return git.CurrentStats, nil
 }
+
+// LFS returns information about whether this repo uses Git LFS.
+func (git DemoGit) LFS() LFSInfo {
+	return git.CurrentLFS
+}
+
+// SparseCheckout returns information about whether this repo is a sparse
+// checkout.
+func (git DemoGit) SparseCheckout() SparseCheckoutInfo {
+	return git.CurrentSparseCheckout
+}
+
+// User returns the name and email git will use for new commits in this repo.
+func (git DemoGit) User() (UserInfo, error) {
+	return git.CurrentUser, nil
+}
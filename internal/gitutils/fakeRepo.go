@@ -0,0 +1,209 @@
+package gitutils
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"testing/fstest"
+)
+
+// FakeRepo builds the contents of a ".git" folder, so we can create a git
+// repository in a particular state (on a branch, detached at a tag, in the
+// middle of a rebase, etc...) without having to run git.  This is used by unit
+// tests, and by demo mode.
+//
+// Every method returns the FakeRepo, so calls can be chained:
+//
+//     repo := NewFakeRepo().
+//         Branch("main", "7c088a39dcd2dcda89f4dee1fd3eb41c1d34ea2f").
+//         Checkout("main").
+//         Merging("0123456789abcdef0123456789abcdef01234567")
+//
+type FakeRepo struct {
+	// files is a map of paths, relative to the ".git" folder, to file contents.
+	files map[string]string
+	// packedRefs is a map of ref names to hashes to write to "packed-refs".
+	packedRefs map[string]string
+	// config is the sections of the ".git/config" file.
+	config []string
+}
+
+// NewFakeRepo returns a new FakeRepo for a brand new repository, with HEAD
+// pointing at "master", and no commits.
+func NewFakeRepo() *FakeRepo {
+	return &FakeRepo{
+		files: map[string]string{
+			"HEAD": "ref: refs/heads/master\n",
+		},
+		packedRefs: map[string]string{},
+	}
+}
+
+// File adds an arbitrary file to the ".git" folder.  `name` is relative to the
+// ".git" folder.
+func (repo *FakeRepo) File(name string, contents string) *FakeRepo {
+	repo.files[name] = contents
+	return repo
+}
+
+// Ref creates a loose ref (e.g. "refs/heads/master") pointing to the given hash.
+func (repo *FakeRepo) Ref(ref string, hash string) *FakeRepo {
+	return repo.File(ref, hash+"\n")
+}
+
+// PackedRef adds a ref to the "packed-refs" file, instead of creating a loose
+// ref.
+func (repo *FakeRepo) PackedRef(ref string, hash string) *FakeRepo {
+	repo.packedRefs[ref] = hash
+	return repo
+}
+
+// Branch creates a local branch pointing to the given hash.
+func (repo *FakeRepo) Branch(name string, hash string) *FakeRepo {
+	return repo.Ref("refs/heads/"+name, hash)
+}
+
+// RemoteBranch creates a remote tracking branch pointing to the given hash.
+func (repo *FakeRepo) RemoteBranch(remote string, name string, hash string) *FakeRepo {
+	return repo.Ref("refs/remotes/"+remote+"/"+name, hash)
+}
+
+// Tag creates a lightweight tag pointing to the given hash.
+func (repo *FakeRepo) Tag(name string, hash string) *FakeRepo {
+	return repo.Ref("refs/tags/"+name, hash)
+}
+
+// Checkout points HEAD at the given branch.  The branch doesn't need to exist.
+func (repo *FakeRepo) Checkout(branch string) *FakeRepo {
+	return repo.File("HEAD", "ref: refs/heads/"+branch+"\n")
+}
+
+// Detach points HEAD directly at the given hash.
+func (repo *FakeRepo) Detach(hash string) *FakeRepo {
+	return repo.File("HEAD", hash+"\n")
+}
+
+// Upstream sets the upstream of a local branch to `remoteBranch` on `remote`.
+func (repo *FakeRepo) Upstream(branch string, remote string, remoteBranch string) *FakeRepo {
+	repo.config = append(repo.config, fmt.Sprintf(
+		"[branch %q]\n\tremote = %s\n\tmerge = refs/heads/%s\n",
+		branch, remote, remoteBranch,
+	))
+	return repo
+}
+
+// Stashes adds `count` entries to the stash.
+func (repo *FakeRepo) Stashes(count int) *FakeRepo {
+	log := strings.Builder{}
+	previous := strings.Repeat("0", 40)
+	for index := 0; index < count; index++ {
+		hash := fmt.Sprintf("%040x", index+1)
+		fmt.Fprintf(&log, "%s %s Kitsch <kitsch@example.com> %d +0000\tWIP on master: stash %d\n",
+			previous, hash, 1642705392+index, index)
+		previous = hash
+	}
+	return repo.File("logs/refs/stash", log.String())
+}
+
+// Merging puts the repo in the middle of a merge with the given commit.
+func (repo *FakeRepo) Merging(hash string) *FakeRepo {
+	return repo.Ref("MERGE_HEAD", hash)
+}
+
+// CherryPicking puts the repo in the middle of cherry-picking the given commit.
+func (repo *FakeRepo) CherryPicking(hash string) *FakeRepo {
+	return repo.Ref("CHERRY_PICK_HEAD", hash)
+}
+
+// Reverting puts the repo in the middle of reverting the given commit.
+func (repo *FakeRepo) Reverting(hash string) *FakeRepo {
+	return repo.Ref("REVERT_HEAD", hash)
+}
+
+// Bisecting puts the repo in the middle of a bisect.
+func (repo *FakeRepo) Bisecting() *FakeRepo {
+	return repo.File("BISECT_LOG", "git bisect start\n")
+}
+
+// Rebasing puts the repo in the middle of rebasing `branch` with the merge
+// backend (i.e. `git rebase -m`, or `git rebase -i` if `interactive` is true),
+// at step `step` of `total`.  Like a real rebase, this does not change HEAD;
+// use Detach to point HEAD at the commit being rebased onto.
+func (repo *FakeRepo) Rebasing(branch string, interactive bool, step int, total int) *FakeRepo {
+	repo.File("rebase-merge/head-name", "refs/heads/"+branch+"\n")
+	repo.File("rebase-merge/msgnum", strconv.Itoa(step)+"\n")
+	repo.File("rebase-merge/end", strconv.Itoa(total)+"\n")
+	if interactive {
+		repo.File("rebase-merge/interactive", "")
+	}
+	return repo
+}
+
+// Applying puts the repo in the middle of `git am` at patch `step` of `total`,
+// or in the middle of a rebase with the apply backend if `rebasing` is true.
+func (repo *FakeRepo) Applying(rebasing bool, step int, total int) *FakeRepo {
+	repo.File("rebase-apply/next", strconv.Itoa(step)+"\n")
+	repo.File("rebase-apply/last", strconv.Itoa(total)+"\n")
+	if rebasing {
+		repo.File("rebase-apply/rebasing", "")
+	} else {
+		repo.File("rebase-apply/applying", "")
+	}
+	return repo
+}
+
+// Files returns the contents of the ".git" folder, as a map of paths relative
+// to the ".git" folder to file contents.
+func (repo *FakeRepo) Files() map[string]string {
+	result := make(map[string]string, len(repo.files)+2)
+	for name, contents := range repo.files {
+		result[name] = contents
+	}
+
+	if len(repo.packedRefs) > 0 {
+		refs := make([]string, 0, len(repo.packedRefs))
+		for ref := range repo.packedRefs {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+
+		packedRefs := strings.Builder{}
+		packedRefs.WriteString("# pack-refs with: peeled fully-peeled sorted \n")
+		for _, ref := range refs {
+			packedRefs.WriteString(repo.packedRefs[ref] + " " + ref + "\n")
+		}
+		result["packed-refs"] = packedRefs.String()
+	}
+
+	if len(repo.config) > 0 {
+		result["config"] = strings.Join(repo.config, "")
+	}
+
+	return result
+}
+
+// FS returns a filesystem containing the ".git" folder.
+func (repo *FakeRepo) FS() fstest.MapFS {
+	return GitFilesFS(repo.Files())
+}
+
+// Git returns a Git instance for this repo, as if it were checked out at
+// `repoRoot`.
+func (repo *FakeRepo) Git(repoRoot string) (Git, error) {
+	return NewFromFS(repoRoot, repo.FS())
+}
+
+// GitFilesFS returns a filesystem with a ".git" folder containing the given
+// files.  `files` is a map of paths relative to the ".git" folder to file
+// contents, such as the one returned by `FakeRepo.Files()`.
+func GitFilesFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{
+		".git": &fstest.MapFile{Mode: fs.ModeDir | 0755},
+	}
+	for name, contents := range files {
+		fsys[".git/"+name] = &fstest.MapFile{Data: []byte(contents), Mode: 0644}
+	}
+	return fsys
+}
@@ -0,0 +1,143 @@
+package gitutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	fakeHash1 = "7c088a39dcd2dcda89f4dee1fd3eb41c1d34ea2f"
+	fakeHash2 = "0123456789abcdef0123456789abcdef01234567"
+)
+
+func fakeGit(t *testing.T, repo *FakeRepo) Git {
+	git, err := repo.Git("/Users/oriana/dev/kitsch")
+	require.NoError(t, err)
+	return git
+}
+
+func TestFakeRepoBranch(t *testing.T) {
+	git := fakeGit(t, NewFakeRepo().
+		Branch("feature/widgets", fakeHash1).
+		Checkout("feature/widgets").
+		RemoteBranch("origin", "feature/widgets", fakeHash1).
+		Upstream("feature/widgets", "origin", "feature/widgets").
+		Stashes(2),
+	)
+
+	head, err := git.Head(100)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		HeadInfo{Description: "feature/widgets", Hash: fakeHash1},
+		head,
+	)
+
+	assert.Equal(t, "/Users/oriana/dev/kitsch", git.RepoRoot())
+	assert.Equal(t, "origin/feature/widgets", git.GetUpstream("feature/widgets"))
+
+	ahead, behind, err := git.GetAheadBehind("refs/heads/feature/widgets", "refs/remotes/origin/feature/widgets")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, ahead)
+	assert.Equal(t, 0, behind)
+
+	stashCount, err := git.GetStashCount()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stashCount)
+
+	_, err = git.Stats()
+	assert.Equal(t, ErrNoGit, err)
+}
+
+func TestFakeRepoPackedRefs(t *testing.T) {
+	git := fakeGit(t, NewFakeRepo().
+		PackedRef("refs/heads/master", fakeHash1).
+		PackedRef("refs/tags/v1.0.0", fakeHash2).
+		Detach(fakeHash2),
+	)
+
+	head, err := git.Head(100)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		HeadInfo{Description: "(v1.0.0)", Detached: true, Hash: fakeHash2, IsTag: true},
+		head,
+	)
+}
+
+func TestFakeRepoNewRepo(t *testing.T) {
+	git := fakeGit(t, NewFakeRepo())
+
+	head, err := git.Head(100)
+	assert.NoError(t, err)
+	assert.Equal(t, "master", head.Description)
+	assert.Equal(t, RepositoryState{State: StateNone}, git.State())
+}
+
+func TestFakeRepoStates(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     *FakeRepo
+		expected RepositoryState
+	}{
+		{
+			name:     "merging",
+			repo:     NewFakeRepo().Merging(fakeHash2),
+			expected: RepositoryState{State: StateMerging},
+		},
+		{
+			name:     "cherry-picking",
+			repo:     NewFakeRepo().CherryPicking(fakeHash2),
+			expected: RepositoryState{State: StateCherryPicking},
+		},
+		{
+			name:     "reverting",
+			repo:     NewFakeRepo().Reverting(fakeHash2),
+			expected: RepositoryState{State: StateReverting},
+		},
+		{
+			name:     "bisecting",
+			repo:     NewFakeRepo().Bisecting(),
+			expected: RepositoryState{State: StateBisecting},
+		},
+		{
+			name:     "interactive rebase",
+			repo:     NewFakeRepo().Rebasing("main", true, 2, 5),
+			expected: RepositoryState{State: StateRebasingInteractive, Step: "2", Total: "5"},
+		},
+		{
+			name:     "merge rebase",
+			repo:     NewFakeRepo().Rebasing("main", false, 1, 3),
+			expected: RepositoryState{State: StateRebaseMerging, Step: "1", Total: "3"},
+		},
+		{
+			name:     "apply rebase",
+			repo:     NewFakeRepo().Applying(true, 4, 7),
+			expected: RepositoryState{State: StateRebasing, Step: "4", Total: "7"},
+		},
+		{
+			name:     "am",
+			repo:     NewFakeRepo().Applying(false, 1, 2),
+			expected: RepositoryState{State: StateAMing, Step: "1", Total: "2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, fakeGit(t, test.repo).State())
+		})
+	}
+}
+
+func TestFakeRepoRebasingHead(t *testing.T) {
+	git := fakeGit(t, NewFakeRepo().
+		Branch("main", fakeHash1).
+		Detach(fakeHash2).
+		Rebasing("main", true, 2, 5),
+	)
+
+	head, err := git.Head(100)
+	assert.NoError(t, err)
+	assert.Equal(t, "main", head.Description)
+	assert.Equal(t, fakeHash2, head.Hash)
+}
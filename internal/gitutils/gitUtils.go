@@ -2,16 +2,17 @@ package gitutils
 
 import (
 	"errors"
-	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/go-git/go-billy/v5/osfs"
-	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/cache"
 	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/jwalton/kitsch/internal/billyutils"
+	valuecache "github.com/jwalton/kitsch/internal/cache"
 	"github.com/jwalton/kitsch/internal/fileutils"
 )
 
@@ -58,12 +59,29 @@ type Git interface {
 	// GetAheadBehind returns how many commits ahead and behind the given
 	// localRef is compared to remoteRef.
 	GetAheadBehind(localRef string, remoteRef string) (ahead int, behind int, err error)
+	// CountAheadBehind returns exactly how many commits ahead and behind the
+	// given localRef is compared to remoteRef, by running git.  This can be
+	// slow in large repos, so git is killed after `timeout`.  Results are
+	// stored in `valueCache`, if it is not nil.
+	CountAheadBehind(localRef string, remoteRef string, timeout time.Duration, valueCache valuecache.Cache) (ahead int, behind int, err error)
+	// DefaultBranch returns the name of the repository's default branch.
+	DefaultBranch() string
 	// Head returns information about the current head.
 	Head(maxTagsToSearch int) (head HeadInfo, err error)
+	// CommitTime returns the committer time of the commit with the given hash.
+	CommitTime(hash string) (time.Time, error)
 	// State returns the current state of the repository.
 	State() RepositoryState
 	// Stats returns status counters for the given git repo.
 	Stats() (GitStats, error)
+	// LFS returns information about whether this repo uses Git LFS.
+	LFS() LFSInfo
+	// SparseCheckout returns information about whether this repo is a sparse
+	// checkout.
+	SparseCheckout() SparseCheckoutInfo
+	// User returns the name and email git will use for new commits in this
+	// repo, taking `include` and `includeIf` directives into account.
+	User() (UserInfo, error)
 }
 
 // New returns a new instance of `GitUtils` for the specified folder.
@@ -99,6 +117,32 @@ func New(pathToGit string, folder string) Git {
 	}
 }
 
+// NewFromFS returns a new instance of `GitUtils` for a repository whose files,
+// including the ".git" folder, are in `fsys`.  This never runs the git
+// executable, so anything which needs to shell out to git (like Stats) will
+// return ErrNoGit.
+func NewFromFS(repoRoot string, fsys fs.FS) (Git, error) {
+	return newFromFS("", repoRoot, fsys)
+}
+
+func newFromFS(pathToGit string, repoRoot string, fsys fs.FS) (*gitUtils, error) {
+	repositoryFs, err := billyutils.FsToBilly(fsys)
+	if err != nil {
+		return nil, err
+	}
+	dotGitFs, err := repositoryFs.Chroot(".git")
+	if err != nil {
+		return nil, err
+	}
+
+	return &gitUtils{
+		pathToGit: pathToGit,
+		storer:    filesystem.NewStorage(dotGitFs, cache.NewObjectLRUDefault()),
+		fsys:      fsys,
+		repoRoot:  repoRoot,
+	}, nil
+}
+
 // FindGitRoot returns the root of the current git repo.
 func FindGitRoot(cwd string) string {
 	gitFolder := fileutils.FindFileInAncestors(cwd, ".git")
@@ -159,28 +203,3 @@ func (g *gitUtils) GetUpstream(branch string) string {
 
 	return branchConfig.Remote + "/" + branchConfig.Merge.String()[11:]
 }
-
-// GetAheadBehind returns how many commits ahead and behind the given
-// localRef is compared to remoteRef.
-func (g *gitUtils) GetAheadBehind(localRef string, remoteRef string) (ahead int, behind int, err error) {
-	// If branch and compareToBranch are the same hash, we're done.
-	branchRef, err := g.storer.Reference(plumbing.ReferenceName(localRef))
-	if err == nil {
-		compareBranchRef, err := g.storer.Reference(plumbing.ReferenceName(remoteRef))
-		if err == nil {
-			if branchRef.Hash() == compareBranchRef.Hash() {
-				return 0, 0, nil
-			}
-		}
-	}
-
-	// If not, we need to shell-out to git to find the answer.
-	// TODO: Rewrite this as native, as there is quite a bit of overhead going to the shell.
-	aheadBehind, err := g.git("rev-list", "--left-right", "--count", localRef+"..."+remoteRef)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	fmt.Sscanf(aheadBehind, "%d %d", &ahead, &behind)
-	return ahead, behind, nil
-}
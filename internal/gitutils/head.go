@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -69,6 +70,19 @@ func (g *gitUtils) Head(maxTagsToSearch int) (HeadInfo, error) {
 	}, nil
 }
 
+// CommitTime returns the committer time of the commit with the given hash.
+func (g *gitUtils) CommitTime(hash string) (time.Time, error) {
+	if g.fsys == nil {
+		return time.Time{}, fmt.Errorf("no git repo found")
+	}
+
+	commit, err := object.GetCommit(g.storer, plumbing.NewHash(hash))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return commit.Committer.When, nil
+}
+
 // GetTagNameForHash returns the tag name for the hash, or an error if no such
 // tag exists.  "hash" can be a short hash.
 //
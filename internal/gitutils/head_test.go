@@ -186,3 +186,20 @@ func TestHeadOnNewGitRepo(t *testing.T) {
 		state,
 	)
 }
+
+func TestCommitTime(t *testing.T) {
+	files := fstest.MapFS{
+		".git/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/heads/master\n"),
+		},
+		".git/objects/aa/aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": &fstest.MapFile{
+			Data: generateGitObject("commit", "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor Jason Walton <dev@lucid.thedreaming.org> 1642726000 -0500\ncommitter Jason Walton <dev@lucid.thedreaming.org> 1642726592 -0500\n\nInitial commit\n"),
+		},
+	}
+
+	git := testGitUtils("/Users/oriana/dev/kitsch", files)
+
+	commitTime, err := git.CommitTime("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1642726592), commitTime.Unix())
+}
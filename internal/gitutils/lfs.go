@@ -0,0 +1,57 @@
+package gitutils
+
+import (
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+)
+
+// LFSInfo describes whether a repository uses Git LFS.
+type LFSInfo struct {
+	// Enabled is true if the repo's attributes send files through the LFS filter.
+	Enabled bool `yaml:"enabled"`
+	// Installed is true if the git-lfs executable is available.
+	Installed bool `yaml:"installed"`
+}
+
+// Missing is true if the repo uses LFS, but git-lfs is not installed.  In this
+// case git will check out LFS files as small pointer files, without any
+// warning.
+func (info LFSInfo) Missing() bool {
+	return info.Enabled && !info.Installed
+}
+
+// LFS returns information about whether this repo uses Git LFS.
+func (g *gitUtils) LFS() LFSInfo {
+	info := LFSInfo{Enabled: g.usesLFS()}
+	if info.Enabled {
+		_, err := fileutils.LookPathSafe("git-lfs")
+		info.Installed = err == nil
+	}
+	return info
+}
+
+// usesLFS returns true if the .gitattributes file at the root of the repo, or
+// the repo's info/attributes file, assigns the "lfs" filter to any files.
+func (g *gitUtils) usesLFS() bool {
+	return attributesUseLFS(g.readFileIfExist(".gitattributes")) ||
+		attributesUseLFS(g.readFileIfExist(".git/info/attributes"))
+}
+
+// attributesUseLFS returns true if the contents of a gitattributes file assign
+// the "lfs" filter to any pattern.
+func attributesUseLFS(attributes string) bool {
+	for _, line := range strings.Split(attributes, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		for _, attr := range fields[1:] {
+			if attr == "filter=lfs" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package gitutils
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributesUseLFS(t *testing.T) {
+	assert.True(t, attributesUseLFS(heredoc.Doc(`
+		*.go text eol=lf
+		*.psd filter=lfs diff=lfs merge=lfs -text
+	`)))
+
+	assert.False(t, attributesUseLFS(heredoc.Doc(`
+		# *.psd filter=lfs diff=lfs merge=lfs -text
+		*.go text eol=lf
+		filter=lfs
+	`)))
+
+	assert.False(t, attributesUseLFS(""))
+}
+
+func TestLFS(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	git := testGitUtils("/Users/oriana/dev/kitsch", fstest.MapFS{
+		".git/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/heads/master\n"),
+		},
+		".gitattributes": &fstest.MapFile{
+			Data: []byte("*.png filter=lfs diff=lfs merge=lfs -text\n"),
+		},
+	})
+
+	info := git.LFS()
+	assert.Equal(t, LFSInfo{Enabled: true, Installed: false}, info)
+	assert.True(t, info.Missing())
+
+	git = testGitUtils("/Users/oriana/dev/kitsch", fstest.MapFS{
+		".git/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/heads/master\n"),
+		},
+	})
+
+	info = git.LFS()
+	assert.Equal(t, LFSInfo{}, info)
+	assert.False(t, info.Missing())
+}
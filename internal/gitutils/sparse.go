@@ -0,0 +1,88 @@
+package gitutils
+
+import (
+	"strings"
+
+	format "github.com/go-git/go-git/v5/plumbing/format/config"
+)
+
+// SparseCheckoutInfo describes whether a repository's worktree is a sparse
+// checkout.
+type SparseCheckoutInfo struct {
+	// Enabled is true if sparse checkout is turned on, in which case only some
+	// of the files in the repo are checked out.
+	Enabled bool `yaml:"enabled"`
+	// Cone is true if sparse checkout is using "cone mode", where the
+	// patterns in `.git/info/sparse-checkout` are a list of directories.
+	Cone bool `yaml:"cone"`
+}
+
+// SparseCheckout returns information about whether this repo is a sparse
+// checkout.
+func (g *gitUtils) SparseCheckout() SparseCheckoutInfo {
+	// `git sparse-checkout` writes these settings to config.worktree if
+	// `extensions.worktreeConfig` is set, so we need to check both files.
+	// Settings in config.worktree take precedence.
+	info := SparseCheckoutInfo{}
+	for _, file := range []string{".git/config", ".git/config.worktree"} {
+		config := g.readConfigFile(file)
+		if config == nil {
+			continue
+		}
+		if value, ok := configBool(config, "core", "sparseCheckout"); ok {
+			info.Enabled = value
+		}
+		if value, ok := configBool(config, "core", "sparseCheckoutCone"); ok {
+			info.Cone = value
+		}
+	}
+
+	if !info.Enabled {
+		return SparseCheckoutInfo{}
+	}
+	return info
+}
+
+// readConfigFile reads and parses a git config file.  Returns nil if the
+// file does not exist or can't be parsed.
+func (g *gitUtils) readConfigFile(path string) *format.Config {
+	contents := g.readFileIfExist(path)
+	if contents == "" {
+		return nil
+	}
+
+	config := format.New()
+	err := format.NewDecoder(strings.NewReader(contents)).Decode(config)
+	if err != nil {
+		return nil
+	}
+	return config
+}
+
+// configBool returns the value of a boolean option from a git config file.
+// If the option appears more than once, the last value wins.  ok will be false
+// if the option is not set.
+func configBool(config *format.Config, section string, key string) (value bool, ok bool) {
+	for _, s := range config.Sections {
+		if !s.IsName(section) {
+			continue
+		}
+		for _, option := range s.Options {
+			if option.IsKey(key) {
+				value, ok = parseConfigBool(option.Value), true
+			}
+		}
+	}
+	return value, ok
+}
+
+// parseConfigBool parses a boolean value from a git config file.  A key with
+// no value (e.g. "[core] sparseCheckout") is true.
+func parseConfigBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "true", "yes", "on", "1":
+		return true
+	default:
+		return false
+	}
+}
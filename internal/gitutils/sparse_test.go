@@ -0,0 +1,77 @@
+package gitutils
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func sparseTestRepo(files map[string]string) *gitUtils {
+	fsys := fstest.MapFS{
+		".git/HEAD": &fstest.MapFile{
+			Data: []byte("ref: refs/heads/master\n"),
+		},
+	}
+	for name, contents := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(contents)}
+	}
+	return testGitUtils("/Users/oriana/dev/kitsch", fsys)
+}
+
+func TestSparseCheckout(t *testing.T) {
+	git := sparseTestRepo(map[string]string{
+		".git/config": heredoc.Doc(`
+			[core]
+				repositoryformatversion = 0
+				sparseCheckout = true
+				sparseCheckoutCone = true
+		`),
+		".git/info/sparse-checkout": "/*\n!/*/\n/services/\n",
+	})
+
+	assert.Equal(t, SparseCheckoutInfo{Enabled: true, Cone: true}, git.SparseCheckout())
+}
+
+func TestSparseCheckoutWorktreeConfig(t *testing.T) {
+	git := sparseTestRepo(map[string]string{
+		".git/config": heredoc.Doc(`
+			[core]
+				repositoryformatversion = 1
+			[extensions]
+				worktreeConfig = true
+		`),
+		".git/config.worktree": heredoc.Doc(`
+			[core]
+				sparseCheckout = true
+		`),
+	})
+
+	assert.Equal(t, SparseCheckoutInfo{Enabled: true, Cone: false}, git.SparseCheckout())
+}
+
+func TestSparseCheckoutDisabled(t *testing.T) {
+	git := sparseTestRepo(map[string]string{
+		".git/config": heredoc.Doc(`
+			[core]
+				sparseCheckout = true
+				sparseCheckoutCone = true
+		`),
+		".git/config.worktree": heredoc.Doc(`
+			[core]
+				sparseCheckout = false
+		`),
+	})
+
+	assert.Equal(t, SparseCheckoutInfo{}, git.SparseCheckout())
+
+	git = sparseTestRepo(map[string]string{
+		".git/config": heredoc.Doc(`
+			[core]
+				repositoryformatversion = 0
+		`),
+	})
+
+	assert.Equal(t, SparseCheckoutInfo{}, git.SparseCheckout())
+}
@@ -27,6 +27,8 @@ type GitStats struct {
 	Unstaged GitFileStats `yaml:"unstaged"`
 	// Unmerged is a count of unmerged files.
 	Unmerged int `yaml:"unmerged"`
+	// Conflicts breaks down the unmerged files by the type of conflict.
+	Conflicts GitConflictStats `yaml:"conflicts"`
 }
 
 // GitFileStats contains counts of files in the index or in the work tree.
@@ -39,6 +41,25 @@ type GitFileStats struct {
 	Deleted int `yaml:"deleted"`
 }
 
+// GitConflictStats contains counts of unmerged files, by the type of conflict.
+// These correspond to the "unmerged" states shown by `git status`.
+type GitConflictStats struct {
+	// BothModified is the number of files modified by both sides ("UU").
+	BothModified int `yaml:"bothModified"`
+	// BothAdded is the number of files added by both sides ("AA").
+	BothAdded int `yaml:"bothAdded"`
+	// BothDeleted is the number of files deleted by both sides ("DD").
+	BothDeleted int `yaml:"bothDeleted"`
+	// AddedByUs is the number of files added by us ("AU").
+	AddedByUs int `yaml:"addedByUs"`
+	// AddedByThem is the number of files added by them ("UA").
+	AddedByThem int `yaml:"addedByThem"`
+	// DeletedByUs is the number of files deleted by us ("DU").
+	DeletedByUs int `yaml:"deletedByUs"`
+	// DeletedByThem is the number of files deleted by them ("UD").
+	DeletedByThem int `yaml:"deletedByThem"`
+}
+
 // Total is the sum of Added, Modifed, and Deleted.
 func (stats GitFileStats) Total() int {
 	return stats.Added + stats.Modified + stats.Deleted
@@ -46,9 +67,34 @@ func (stats GitFileStats) Total() int {
 
 type statusWriter struct {
 	linePos int
+	x       byte
 	stats   *GitStats
 }
 
+// countConflict counts an unmerged file in `stats`.  Returns false if `x` and
+// `y` are not an unmerged state.
+func countConflict(stats *GitConflictStats, x byte, y byte) bool {
+	switch string([]byte{x, y}) {
+	case "UU":
+		stats.BothModified++
+	case "AA":
+		stats.BothAdded++
+	case "DD":
+		stats.BothDeleted++
+	case "AU":
+		stats.AddedByUs++
+	case "UA":
+		stats.AddedByThem++
+	case "DU":
+		stats.DeletedByUs++
+	case "UD":
+		stats.DeletedByThem++
+	default:
+		return false
+	}
+	return true
+}
+
 func countStats(stats *GitFileStats, x byte) {
 	switch x {
 	case 'M':
@@ -67,16 +113,18 @@ func countStats(stats *GitFileStats, x byte) {
 // Write parses the output of `git status -z` and counts files in a GitStats.
 func (status *statusWriter) Write(p []byte) (n int, err error) {
 	var i int
-	var x byte
 
 	for i = 0; i < len(p); i++ {
 		if status.linePos == 0 {
-			x = p[i]
+			// Save x in the statusWriter, since the output may be split
+			// between calls to Write.
+			status.x = p[i]
 			status.linePos++
 		} else if status.linePos == 1 {
+			x := status.x
 			y := p[i]
 
-			if (x == 'D' && y == 'D') || (x == 'A' && y == 'A') || x == 'U' || y == 'U' {
+			if countConflict(&status.stats.Conflicts, x, y) {
 				status.stats.Unmerged++
 			} else if x == '?' {
 				status.stats.Unstaged.Added++
@@ -0,0 +1,40 @@
+package gitutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusWriter(t *testing.T) {
+	output := "M  staged.go\x00 M unstaged.go\x00?? new.go\x00" +
+		"UU both-modified.go\x00UU both-modified-2.go\x00AA both-added.go\x00DD both-deleted.go\x00" +
+		"AU added-by-us.go\x00UA added-by-them.go\x00DU deleted-by-us.go\x00UD deleted-by-them.go\x00"
+
+	stats := GitStats{}
+	writer := &statusWriter{stats: &stats}
+
+	// Write the output one byte at a time, to make sure we handle entries
+	// which are split across writes.
+	for i := 0; i < len(output); i++ {
+		writer.Write([]byte{output[i]})
+	}
+
+	assert.Equal(t,
+		GitStats{
+			Index:    GitFileStats{Modified: 1},
+			Unstaged: GitFileStats{Added: 1, Modified: 1},
+			Unmerged: 8,
+			Conflicts: GitConflictStats{
+				BothModified:  2,
+				BothAdded:     1,
+				BothDeleted:   1,
+				AddedByUs:     1,
+				AddedByThem:   1,
+				DeletedByUs:   1,
+				DeletedByThem: 1,
+			},
+		},
+		stats,
+	)
+}
@@ -0,0 +1,59 @@
+package gitutils
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// UserInfo is the identity git will use for new commits in a repository.
+type UserInfo struct {
+	// Name is the value of `user.name`, or "" if it is not set.
+	Name string `yaml:"name"`
+	// Email is the value of `user.email`, or "" if it is not set.
+	Email string `yaml:"email"`
+}
+
+// User returns the effective `user.name` and `user.email` for this repo.
+func (g *gitUtils) User() (UserInfo, error) {
+	if g.pathToGit == "" {
+		return UserInfo{}, ErrNoGit
+	}
+
+	// We ask git for these instead of reading the config with go-git, because
+	// go-git only reads the repo's own config file, and doesn't understand
+	// `includeIf`, which is how most people set up a different email for work
+	// repos.
+	out, err := g.git("config", "-z", "--get-regexp", `^user\.(name|email)$`)
+	if err != nil {
+		// git exits with status 1 if neither key is set.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return UserInfo{}, nil
+		}
+		return UserInfo{}, err
+	}
+
+	return parseUserConfig(out), nil
+}
+
+// parseUserConfig parses the output of `git config -z --get-regexp`.  Each
+// entry is a key and a value separated by a newline, and entries are separated
+// by NUL characters.  If a key appears more than once, the last value wins,
+// just as it does in git.
+func parseUserConfig(out string) UserInfo {
+	user := UserInfo{}
+	for _, entry := range strings.Split(out, "\x00") {
+		parts := strings.SplitN(entry, "\n", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch strings.ToLower(parts[0]) {
+		case "user.name":
+			user.Name = parts[1]
+		case "user.email":
+			user.Email = parts[1]
+		}
+	}
+	return user
+}
@@ -0,0 +1,67 @@
+package gitutils
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserConfig(t *testing.T) {
+	assert.Equal(t,
+		UserInfo{Name: "Jason Walton", Email: "dev@lucid.thedreaming.org"},
+		parseUserConfig("user.name\nJason Walton\x00user.email\nme@example.com\x00user.email\ndev@lucid.thedreaming.org\x00"),
+	)
+
+	assert.Equal(t, UserInfo{}, parseUserConfig(""))
+}
+
+func TestUser(t *testing.T) {
+	pathToGit, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Isolate the test from the user's own git config.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	workDir := filepath.Join(home, "work")
+	repoDir := filepath.Join(workDir, "project")
+	require.NoError(t, os.MkdirAll(repoDir, 0755))
+
+	cmd := exec.Command(pathToGit, "init", "-q")
+	cmd.Dir = repoDir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	git := New(pathToGit, repoDir)
+
+	user, err := git.User()
+	require.NoError(t, err)
+	assert.Equal(t, UserInfo{}, user)
+
+	workConfig := filepath.Join(home, ".gitconfig-work")
+	require.NoError(t, os.WriteFile(workConfig, []byte("[user]\n\temail = jwalton@work.example.com\n"), 0644))
+	globalConfig := "[user]\n" +
+		"\tname = Jason Walton\n" +
+		"\temail = jwalton@example.com\n" +
+		"[includeIf \"gitdir:" + filepath.ToSlash(workDir) + "/\"]\n" +
+		"\tpath = " + filepath.ToSlash(workConfig) + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(globalConfig), 0644))
+
+	user, err = git.User()
+	require.NoError(t, err)
+	assert.Equal(t, UserInfo{Name: "Jason Walton", Email: "jwalton@work.example.com"}, user)
+}
+
+func TestUserNoGit(t *testing.T) {
+	git := &gitUtils{pathToGit: "", repoRoot: "/Users/jwalton/dev/kitsch"}
+	_, err := git.User()
+	assert.Equal(t, ErrNoGit, err)
+}
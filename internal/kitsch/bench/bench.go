@@ -0,0 +1,203 @@
+// Package bench contains a set of scenarios used to measure how long it takes
+// to render a prompt.  These scenarios are used both by the go benchmarks and
+// by the hidden `kitsch bench` command, so users can run the same measurements
+// on their own machines.
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/projects"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+)
+
+// ErrScenarioSkipped is returned when a scenario cannot be run on this machine
+// (for example, because git is not installed).
+var ErrScenarioSkipped = errors.New("scenario skipped")
+
+// Scenario is a single benchmark scenario.
+type Scenario struct {
+	// Name is the unique name of this scenario.
+	Name string
+	// Description is a short, human readable description of the scenario.
+	Description string
+	// Config is the YAML configuration to use for this scenario.  If empty,
+	// the default configuration will be used.
+	Config string
+	// Setup is called to populate the working directory for this scenario.
+	Setup func(dir string) error
+}
+
+// Result is the result of running a scenario.
+type Result struct {
+	// Scenario is the name of the scenario that was run.
+	Scenario string
+	// Runs is the number of times the prompt was rendered.
+	Runs int
+	// Min is the fastest render time.
+	Min time.Duration
+	// Max is the slowest render time.
+	Max time.Duration
+	// Mean is the average render time.
+	Mean time.Duration
+	// AllocsPerRun is the average number of heap allocations per render.
+	AllocsPerRun uint64
+	// BytesPerRun is the average number of bytes allocated per render.
+	BytesPerRun uint64
+}
+
+// Fixture is a prepared scenario, ready to be rendered.
+type Fixture struct {
+	// Dir is the working directory the prompt will be rendered in.
+	Dir string
+
+	root          string
+	cacheDir      string
+	configuration *config.Config
+}
+
+// Prepare creates a temporary directory for the scenario, populates it, and
+// loads the scenario's configuration.  The caller must call `Close()` on the
+// returned fixture when they are done with it.
+func (scenario Scenario) Prepare() (*Fixture, error) {
+	root, err := os.MkdirTemp("", "kitsch-bench-")
+	if err != nil {
+		return nil, err
+	}
+
+	fixture := &Fixture{
+		Dir:      filepath.Join(root, "cwd"),
+		root:     root,
+		cacheDir: filepath.Join(root, "cache"),
+	}
+
+	err = fixture.init(scenario)
+	if err != nil {
+		fixture.Close()
+		return nil, err
+	}
+
+	return fixture, nil
+}
+
+func (fixture *Fixture) init(scenario Scenario) error {
+	err := os.MkdirAll(fixture.Dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	if scenario.Setup != nil {
+		err = scenario.Setup(fixture.Dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	if scenario.Config == "" {
+		fixture.configuration, err = config.LoadDefaultConfig()
+	} else {
+		configFile := filepath.Join(fixture.root, "config.yaml")
+		err = os.WriteFile(configFile, []byte(scenario.Config), 0644)
+		if err != nil {
+			return err
+		}
+		fixture.configuration, err = config.LoadConfigFromFile(configFile, true)
+	}
+	if err != nil {
+		return fmt.Errorf("error loading configuration for scenario %s: %w", scenario.Name, err)
+	}
+
+	fixture.configuration.ProjectsTypes = projects.MergeProjectTypes(
+		fixture.configuration.ProjectsTypes,
+		projects.DefaultProjectTypes,
+		true,
+	)
+
+	return nil
+}
+
+// Render renders the prompt once, exactly as `kitsch prompt` would, and
+// returns the resulting text.
+func (fixture *Fixture) Render() string {
+	styles := styling.Registry{}
+	styles.AddCustomColors(fixture.configuration.Colors)
+
+	globals := modules.NewGlobals("bash", fixture.Dir, "", 80, 0, nil, 0, 0, "", "")
+	context := modules.NewContext(
+		globals,
+		fixture.configuration.ProjectsTypes,
+		time.Duration(fixture.configuration.Timeout)*time.Millisecond,
+		time.Duration(fixture.configuration.ScanTimeout)*time.Millisecond,
+		int(fixture.configuration.MaxScanEntries),
+		fixture.cacheDir,
+		&styles,
+	)
+
+	_, text := modules.RenderPrompt(&context, fixture.configuration.Prompt)
+	return text
+}
+
+// Close removes all temporary files created by the fixture.
+func (fixture *Fixture) Close() error {
+	return os.RemoveAll(fixture.root)
+}
+
+// Run prepares the given scenario, and then renders the prompt `runs` times,
+// recording wall time and allocations for each render.  The first render is
+// not included in the results, as it is used to warm the value cache.
+func Run(scenario Scenario, runs int) (Result, error) {
+	fixture, err := scenario.Prepare()
+	if err != nil {
+		return Result{}, err
+	}
+	defer fixture.Close()
+
+	return Measure(scenario.Name, runs, func() { fixture.Render() }), nil
+}
+
+// Measure calls `render` once to warm up any caches, and then `runs` more
+// times, recording wall time and allocations for each call.
+func Measure(name string, runs int, render func()) Result {
+	if runs < 1 {
+		runs = 1
+	}
+
+	render()
+
+	result := Result{Scenario: name, Runs: runs}
+
+	var before, after runtime.MemStats
+	var total time.Duration
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	for i := 0; i < runs; i++ {
+		start := time.Now()
+		render()
+		duration := time.Since(start)
+
+		total += duration
+		if i == 0 || duration < result.Min {
+			result.Min = duration
+		}
+		if duration > result.Max {
+			result.Max = duration
+		}
+	}
+
+	runtime.ReadMemStats(&after)
+
+	result.Mean = total / time.Duration(runs)
+	result.AllocsPerRun = (after.Mallocs - before.Mallocs) / uint64(runs)
+	result.BytesPerRun = (after.TotalAlloc - before.TotalAlloc) / uint64(runs)
+
+	return result
+}
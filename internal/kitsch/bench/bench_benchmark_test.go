@@ -0,0 +1,31 @@
+package bench
+
+import (
+	"errors"
+	"testing"
+)
+
+func BenchmarkScenarios(b *testing.B) {
+	for _, scenario := range Scenarios() {
+		scenario := scenario
+		b.Run(scenario.Name, func(b *testing.B) {
+			fixture, err := scenario.Prepare()
+			if errors.Is(err, ErrScenarioSkipped) {
+				b.Skip(err)
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer fixture.Close()
+
+			// Warm the value cache.
+			fixture.Render()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				fixture.Render()
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package bench
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenariosRender(t *testing.T) {
+	for _, scenario := range Scenarios() {
+		fixture, err := scenario.Prepare()
+		if errors.Is(err, ErrScenarioSkipped) {
+			continue
+		}
+		if !assert.NoError(t, err, scenario.Name) {
+			continue
+		}
+
+		text := fixture.Render()
+		assert.NotEmpty(t, text, scenario.Name)
+
+		if scenario.Name == "plugin-heavy" {
+			assert.True(t, strings.Contains(text, "v39.78.0"), text)
+		}
+
+		assert.NoError(t, fixture.Close())
+	}
+}
+
+func TestRun(t *testing.T) {
+	scenario, ok := FindScenario("empty")
+	assert.True(t, ok)
+
+	result, err := Run(scenario, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "empty", result.Scenario)
+	assert.Equal(t, 3, result.Runs)
+	assert.True(t, result.Min <= result.Mean && result.Mean <= result.Max)
+	assert.NotZero(t, result.AllocsPerRun)
+}
+
+func TestMeasure(t *testing.T) {
+	calls := 0
+	result := Measure("current", 4, func() { calls++ })
+	assert.Equal(t, "current", result.Scenario)
+	assert.Equal(t, 4, result.Runs)
+	// The first call warms up the caches, and isn't measured.
+	assert.Equal(t, 5, calls)
+}
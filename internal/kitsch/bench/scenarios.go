@@ -0,0 +1,182 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// hugeDirFileCount is the number of files to create in the "huge-dir" scenario.
+const hugeDirFileCount = 10000
+
+// gitRepoFileCount is the number of files to commit in the "git-repo" scenario.
+const gitRepoFileCount = 2000
+
+// pluginModuleCount is the number of file modules in the "plugin-heavy" scenario.
+const pluginModuleCount = 40
+
+// Scenarios returns the list of all available scenarios.
+func Scenarios() []Scenario {
+	return []Scenario{
+		{
+			Name:        "empty",
+			Description: "Default configuration in an empty directory",
+		},
+		{
+			Name:        "huge-dir",
+			Description: fmt.Sprintf("Default configuration in a directory with %d files", hugeDirFileCount),
+			Setup:       setupHugeDir,
+		},
+		{
+			Name:        "git-repo",
+			Description: fmt.Sprintf("Default configuration in a git repo with %d files and local changes", gitRepoFileCount),
+			Setup:       setupGitRepo,
+		},
+		{
+			Name:        "polyglot",
+			Description: "Default configuration in a directory containing many project types",
+			Setup:       setupPolyglot,
+		},
+		{
+			Name:        "plugin-heavy",
+			Description: fmt.Sprintf("Configuration with %d file modules and templates", pluginModuleCount),
+			Config:      pluginHeavyConfig(),
+			Setup:       setupPluginHeavy,
+		},
+	}
+}
+
+// FindScenario returns the scenario with the given name.
+func FindScenario(name string) (Scenario, bool) {
+	for _, scenario := range Scenarios() {
+		if scenario.Name == name {
+			return scenario, true
+		}
+	}
+	return Scenario{}, false
+}
+
+func writeFiles(dir string, files map[string]string) error {
+	for name, contents := range files {
+		filename := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(filename), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filename, []byte(contents), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupHugeDir(dir string) error {
+	for i := 0; i < hugeDirFileCount; i++ {
+		err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file-%05d.txt", i)), nil, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setupGitRepo(dir string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return ErrScenarioSkipped
+	}
+
+	git := func(args ...string) error {
+		args = append([]string{
+			"-c", "user.name=kitsch",
+			"-c", "user.email=kitsch@example.com",
+			"-c", "commit.gpgsign=false",
+		}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, output)
+		}
+		return nil
+	}
+
+	err := git("init", "-q", "-b", "main")
+	if err != nil {
+		return err
+	}
+
+	files := make(map[string]string, gitRepoFileCount)
+	for i := 0; i < gitRepoFileCount; i++ {
+		files[fmt.Sprintf("src/pkg%02d/file%04d.go", i%50, i)] = fmt.Sprintf("package pkg%02d\n", i%50)
+	}
+	err = writeFiles(dir, files)
+	if err != nil {
+		return err
+	}
+
+	err = git("add", "-A")
+	if err == nil {
+		err = git("commit", "-q", "-m", "Initial commit")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Leave some modified, staged, and untracked files behind.
+	for i := 0; i < 50; i++ {
+		err = writeFiles(dir, map[string]string{
+			fmt.Sprintf("src/pkg%02d/file%04d.go", i%50, i): "package changed\n",
+			fmt.Sprintf("untracked/file%02d.txt", i):        "untracked\n",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return git("add", "src/pkg00")
+}
+
+func setupPolyglot(dir string) error {
+	return writeFiles(dir, map[string]string{
+		"package.json":      `{"name": "polyglot", "version": "1.0.0"}`,
+		"package-lock.json": "{}",
+		"go.mod":            "module example.com/polyglot\n\ngo 1.16\n",
+		"Cargo.toml":        "[package]\nname = \"polyglot\"\nversion = \"0.1.0\"\n",
+		"pyproject.toml":    "[project]\nname = \"polyglot\"\nversion = \"0.1.0\"\n",
+		"requirements.txt":  "requests\n",
+		"Gemfile":           "source 'https://rubygems.org'\n",
+		"pom.xml":           "<project></project>\n",
+		"main.go":           "package main\n",
+		"index.js":          "",
+		"main.py":           "",
+		"lib.rs":            "",
+		"Dockerfile":        "FROM scratch\n",
+		"helm/Chart.yaml":   "name: polyglot\n",
+	})
+}
+
+func setupPluginHeavy(dir string) error {
+	files := make(map[string]string, pluginModuleCount)
+	for i := 0; i < pluginModuleCount; i++ {
+		files[fmt.Sprintf("data/value%02d.txt", i)] = fmt.Sprintf("version: %d.%d.0\n", i, i*2)
+	}
+	return writeFiles(dir, files)
+}
+
+func pluginHeavyConfig() string {
+	var builder strings.Builder
+	builder.WriteString("prompt:\n  type: block\n  modules:\n")
+	for i := 0; i < pluginModuleCount; i++ {
+		fmt.Fprintf(&builder, "    - type: file\n")
+		fmt.Fprintf(&builder, "      file: data/value%02d.txt\n", i)
+		fmt.Fprintf(&builder, "      regex: 'version: (.*)'\n")
+		fmt.Fprintf(&builder, "      style: brightBlue\n")
+		fmt.Fprintf(&builder, "      template: '{{ with .Data.Text }}v{{ . | upper }}{{ end }}'\n")
+	}
+	builder.WriteString("    - type: directory\n")
+	builder.WriteString("    - type: prompt\n")
+	return builder.String()
+}
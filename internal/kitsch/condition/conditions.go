@@ -17,39 +17,82 @@ import (
 type Conditions struct {
 	// IfAncestorFiles is a list of files to search for in the current folder,
 	// or another folder higher up in the directory structure.
-	IfAncestorFiles []string `yaml:"ifAncestorFiles"`
-	// IfFiles is a list of files to search for in the current folder.
-	IfFiles []string `yaml:"ifFiles"`
+	IfAncestorFiles []string `yaml:"ifAncestorFiles,omitempty"`
+	// IfFiles is a list of files to search for in the current folder.  Entries
+	// that end in "/" only match directories.
+	IfFiles []string `yaml:"ifFiles,omitempty"`
 	// IfExtensions is a list of extensions to search for in the current folder.
-	IfExtensions []string `yaml:"ifExtensions"`
+	IfExtensions []string `yaml:"ifExtensions,omitempty"`
+	// IfEnv is a list of environment variables.  Each entry is either the name
+	// of a variable, which matches if the variable is set to a non-empty value,
+	// or a "NAME=value" pair, which matches if the variable has exactly that value.
+	IfEnv []string `yaml:"ifEnv,omitempty"`
+	// IfCommandExists is a list of executables to search for on the PATH.
+	IfCommandExists []string `yaml:"ifCommandExists,omitempty"`
+	// IfInGitRepo, if true, matches if the current folder is inside a git repo.
+	IfInGitRepo bool `yaml:"ifInGitRepo,omitempty"`
+	// IfShell is a list of shells (e.g. "zsh", "bash").  Matches if the current
+	// shell is one of the listed shells.
+	IfShell []string `yaml:"ifShell,omitempty"`
 	// OnlyIfOS is a list of operating systems.  If the current GOOS is not in
 	// the list, then the Conditions are not met, even if other conditions would
 	// be satisfied.
-	OnlyIfOS []string `yaml:"onlyIfOS"`
+	OnlyIfOS []string `yaml:"onlyIfOS,omitempty"`
 	// OnlyIfNotOS is a list of operating systems.  If the current GOOS is in
 	// the list, then the Conditions are not met, even if other conditions would
 	// be satisfied.
-	OnlyIfNotOS []string `yaml:"onlyIfNotOS"`
+	OnlyIfNotOS []string `yaml:"onlyIfNotOS,omitempty"`
+}
+
+// Environment provides the information needed to evaluate conditions that
+// don't depend on the contents of the current directory.
+type Environment interface {
+	// Getenv returns the value of the specified environment variable.
+	Getenv(key string) string
+	// HasCommand returns true if the specified executable is on the PATH.
+	HasCommand(command string) bool
+	// IsInGitRepo returns true if the current directory is inside a git repo.
+	IsInGitRepo() bool
+	// Shell returns the type of the current shell (e.g. "zsh", "bash").
+	Shell() string
+	// OS returns the current operating system (e.g. "linux", "darwin").
+	OS() string
 }
 
 // IsEmpty returns true if the condition has no conditions to match.
 func (conditions *Conditions) IsEmpty() bool {
 	return conditions == nil ||
-		(len(conditions.IfAncestorFiles) == 0 &&
-			len(conditions.IfFiles) == 0 &&
-			len(conditions.IfExtensions) == 0 &&
+		(!conditions.hasIfConditions() &&
 			len(conditions.OnlyIfOS) == 0 &&
 			len(conditions.OnlyIfNotOS) == 0)
 
 }
 
+// hasIfConditions returns true if any of the "if" conditions are set.
+func (conditions *Conditions) hasIfConditions() bool {
+	return len(conditions.IfAncestorFiles) != 0 ||
+		len(conditions.IfFiles) != 0 ||
+		len(conditions.IfExtensions) != 0 ||
+		len(conditions.IfEnv) != 0 ||
+		len(conditions.IfCommandExists) != 0 ||
+		conditions.IfInGitRepo ||
+		len(conditions.IfShell) != 0
+}
+
 // Matches returns true if this condition is matched in the given directory
-// and for the current operating system.
-func (conditions *Conditions) Matches(directory fileutils.Directory) bool {
-	if !conditions.matchesOS() {
+// and for the current operating system.  `environment` is used to evaluate
+// conditions that don't depend on the directory, and may be nil, in which
+// case those conditions will never match.
+func (conditions *Conditions) Matches(directory fileutils.Directory, environment Environment) bool {
+	if conditions == nil || !conditions.matchesOS(environment) {
 		return false
 	}
 
+	// If there are only "onlyIf" conditions, and they passed, we're done.
+	if !conditions.hasIfConditions() {
+		return true
+	}
+
 	for _, extension := range conditions.IfExtensions {
 		if directory.HasExtension(extension) {
 			return true
@@ -57,7 +100,11 @@ func (conditions *Conditions) Matches(directory fileutils.Directory) bool {
 	}
 
 	for _, file := range conditions.IfFiles {
-		if filepath.IsAbs(file) || strings.HasPrefix(file, "..") {
+		if strings.HasSuffix(file, "/") {
+			if matchesDirectory(directory, strings.TrimSuffix(file, "/")) {
+				return true
+			}
+		} else if filepath.IsAbs(file) || strings.HasPrefix(file, "..") {
 			// If the file is an absolute path or a parent directory,
 			// we need to go directly to the OS to see if it exists.
 			if fileutils.FileExists(filepath.Join(directory.Path(), file)) {
@@ -77,18 +124,74 @@ func (conditions *Conditions) Matches(directory fileutils.Directory) bool {
 		}
 	}
 
+	if environment != nil && conditions.matchesEnvironment(environment) {
+		return true
+	}
+
 	return false
 }
 
-func (conditions *Conditions) matchesOS() bool {
+func (conditions *Conditions) matchesEnvironment(environment Environment) bool {
+	for _, env := range conditions.IfEnv {
+		if index := strings.Index(env, "="); index != -1 {
+			if environment.Getenv(env[:index]) == env[index+1:] {
+				return true
+			}
+		} else if environment.Getenv(env) != "" {
+			return true
+		}
+	}
+
+	if len(conditions.IfShell) > 0 && contains(conditions.IfShell, environment.Shell()) {
+		return true
+	}
+
+	if conditions.IfInGitRepo && environment.IsInGitRepo() {
+		return true
+	}
+
+	// Check commands last, since searching the PATH is comparatively slow.
+	for _, command := range conditions.IfCommandExists {
+		if environment.HasCommand(command) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesDirectory returns true if the given path is a directory.  Relative
+// paths are relative to the current directory.
+func matchesDirectory(directory fileutils.Directory, path string) bool {
+	if filepath.IsAbs(path) || strings.HasPrefix(path, "..") {
+		return fileutils.IsDirectory(filepath.Join(directory.Path(), path))
+	}
+
+	if !directory.HasFile(path) {
+		return false
+	}
+	info, err := directory.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func (conditions *Conditions) matchesOS(environment Environment) bool {
+	if len(conditions.OnlyIfOS) == 0 && len(conditions.OnlyIfNotOS) == 0 {
+		return true
+	}
+
+	currentOS := runtime.GOOS
+	if environment != nil {
+		currentOS = environment.OS()
+	}
+
 	if len(conditions.OnlyIfNotOS) > 0 {
-		if contains(conditions.OnlyIfNotOS, runtime.GOOS) {
+		if contains(conditions.OnlyIfNotOS, currentOS) {
 			return false
 		}
 	}
 
 	if len(conditions.OnlyIfOS) > 0 {
-		return contains(conditions.OnlyIfOS, runtime.GOOS)
+		return contains(conditions.OnlyIfOS, currentOS)
 	}
 
 	return true
@@ -7,8 +7,12 @@ var conditionsJSONSchema = `{
   "type": "object",
   "properties": {
     "ifAncestorFiles": {"type": "array", "description": "IfAncestorFiles is a list of files to search for in the current folder, or another folder higher up in the directory structure.", "items": {"type": "string", "description": ""}},
-    "ifFiles": {"type": "array", "description": "IfFiles is a list of files to search for in the current folder.", "items": {"type": "string", "description": ""}},
+    "ifFiles": {"type": "array", "description": "IfFiles is a list of files to search for in the current folder.  Entries that end in \"/\" only match directories.", "items": {"type": "string", "description": ""}},
     "ifExtensions": {"type": "array", "description": "IfExtensions is a list of extensions to search for in the current folder.", "items": {"type": "string", "description": ""}},
+    "ifEnv": {"type": "array", "description": "IfEnv is a list of environment variables.  Each entry is either the name of a variable, which matches if the variable is set to a non-empty value, or a \"NAME=value\" pair, which matches if the variable has exactly that value.", "items": {"type": "string", "description": ""}},
+    "ifCommandExists": {"type": "array", "description": "IfCommandExists is a list of executables to search for on the PATH.", "items": {"type": "string", "description": ""}},
+    "ifInGitRepo": {"type": "boolean", "description": "IfInGitRepo, if true, matches if the current folder is inside a git repo."},
+    "ifShell": {"type": "array", "description": "IfShell is a list of shells (e.g. \"zsh\", \"bash\").  Matches if the current shell is one of the listed shells.", "items": {"type": "string", "description": ""}},
     "onlyIfOS": {"type": "array", "description": "OnlyIfOS is a list of operating systems.  If the current GOOS is not in the list, then the Conditions are not met, even if other conditions would be satisfied.", "items": {"type": "string", "description": ""}},
     "onlyIfNotOS": {"type": "array", "description": "OnlyIfNotOS is a list of operating systems.  If the current GOOS is in the list, then the Conditions are not met, even if other conditions would be satisfied.", "items": {"type": "string", "description": ""}}
   }}`
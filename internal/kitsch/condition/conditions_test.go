@@ -1,6 +1,7 @@
 package condition
 
 import (
+	"runtime"
 	"testing"
 	"testing/fstest"
 
@@ -22,29 +23,142 @@ func TestIfFiles(t *testing.T) {
 	)
 
 	conditions := Conditions{IfFiles: []string{"version.txt"}}
-	assert.Equal(t, true, conditions.Matches(directory))
+	assert.Equal(t, true, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfFiles: []string{"nothere.txt"}}
-	assert.Equal(t, false, conditions.Matches(directory))
+	assert.Equal(t, false, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfExtensions: []string{"go"}}
-	assert.Equal(t, true, conditions.Matches(directory))
+	assert.Equal(t, true, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfExtensions: []string{".go"}}
-	assert.Equal(t, true, conditions.Matches(directory))
+	assert.Equal(t, true, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfExtensions: []string{".js"}}
-	assert.Equal(t, false, conditions.Matches(directory))
+	assert.Equal(t, false, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfFiles: []string{"nothere.txt"}, IfExtensions: []string{".js"}}
-	assert.Equal(t, false, conditions.Matches(directory))
+	assert.Equal(t, false, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfFiles: []string{"version.txt"}, IfExtensions: []string{".js"}}
-	assert.Equal(t, true, conditions.Matches(directory))
+	assert.Equal(t, true, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfFiles: []string{"nothere.txt"}, IfExtensions: []string{".go"}}
-	assert.Equal(t, true, conditions.Matches(directory))
+	assert.Equal(t, true, conditions.Matches(directory, nil))
 
 	conditions = Conditions{IfFiles: []string{"version.txt"}, IfExtensions: []string{".go"}}
-	assert.Equal(t, true, conditions.Matches(directory))
+	assert.Equal(t, true, conditions.Matches(directory, nil))
+}
+
+type testEnvironment struct {
+	env      map[string]string
+	commands []string
+	inGit    bool
+	shell    string
+	os       string
+}
+
+func (env testEnvironment) Getenv(key string) string {
+	return env.env[key]
+}
+
+func (env testEnvironment) HasCommand(command string) bool {
+	return contains(env.commands, command)
+}
+
+func (env testEnvironment) IsInGitRepo() bool {
+	return env.inGit
+}
+
+func (env testEnvironment) Shell() string {
+	return env.shell
+}
+
+func (env testEnvironment) OS() string {
+	if env.os == "" {
+		return runtime.GOOS
+	}
+	return env.os
+}
+
+func TestEnvironmentConditions(t *testing.T) {
+	directory := fileutils.NewDirectoryTestFS("/foo/bar", fstest.MapFS{})
+	environment := testEnvironment{
+		env:      map[string]string{"AWS_PROFILE": "prod", "EMPTY": ""},
+		commands: []string{"kubectl"},
+		inGit:    true,
+		shell:    "zsh",
+	}
+
+	conditions := Conditions{IfEnv: []string{"AWS_PROFILE"}}
+	assert.Equal(t, true, conditions.Matches(directory, environment))
+
+	conditions = Conditions{IfEnv: []string{"EMPTY"}}
+	assert.Equal(t, false, conditions.Matches(directory, environment))
+
+	conditions = Conditions{IfEnv: []string{"AWS_PROFILE=prod"}}
+	assert.Equal(t, true, conditions.Matches(directory, environment))
+
+	conditions = Conditions{IfEnv: []string{"AWS_PROFILE=dev"}}
+	assert.Equal(t, false, conditions.Matches(directory, environment))
+
+	conditions = Conditions{IfCommandExists: []string{"kubectl"}}
+	assert.Equal(t, true, conditions.Matches(directory, environment))
+
+	conditions = Conditions{IfCommandExists: []string{"helm"}}
+	assert.Equal(t, false, conditions.Matches(directory, environment))
+
+	conditions = Conditions{IfInGitRepo: true}
+	assert.Equal(t, true, conditions.Matches(directory, environment))
+	assert.Equal(t, false, conditions.Matches(directory, testEnvironment{}))
+
+	conditions = Conditions{IfShell: []string{"bash", "zsh"}}
+	assert.Equal(t, true, conditions.Matches(directory, environment))
+
+	conditions = Conditions{IfShell: []string{"fish"}}
+	assert.Equal(t, false, conditions.Matches(directory, environment))
+
+	// Environment conditions never match without an environment.
+	conditions = Conditions{IfEnv: []string{"AWS_PROFILE"}}
+	assert.Equal(t, false, conditions.Matches(directory, nil))
+
+	// Conditions are OR'd together.
+	conditions = Conditions{IfFiles: []string{"nothere.txt"}, IfShell: []string{"zsh"}}
+	assert.Equal(t, true, conditions.Matches(directory, environment))
+}
+
+func TestOnlyIfOS(t *testing.T) {
+	directory := fileutils.NewDirectoryTestFS("/foo/bar", fstest.MapFS{})
+
+	conditions := Conditions{OnlyIfOS: []string{runtime.GOOS}}
+	assert.Equal(t, true, conditions.Matches(directory, nil))
+
+	conditions = Conditions{OnlyIfNotOS: []string{runtime.GOOS}}
+	assert.Equal(t, false, conditions.Matches(directory, nil))
+
+	conditions = Conditions{OnlyIfOS: []string{runtime.GOOS}, IfShell: []string{"fish"}}
+	assert.Equal(t, false, conditions.Matches(directory, testEnvironment{shell: "zsh"}))
+
+	conditions = Conditions{OnlyIfOS: []string{"plan9"}}
+	assert.Equal(t, true, conditions.Matches(directory, testEnvironment{os: "plan9"}))
+	assert.Equal(t, false, conditions.Matches(directory, testEnvironment{os: "windows"}))
+}
+
+func TestIfFilesDirectory(t *testing.T) {
+	directory := fileutils.NewDirectoryTestFS(
+		"/foo/bar",
+		fstest.MapFS{
+			"k8s/deployment.yaml": &fstest.MapFile{Data: []byte("kind: Deployment\n")},
+			"Chart.yaml":          &fstest.MapFile{Data: []byte("name: test\n")},
+		},
+	)
+
+	conditions := Conditions{IfFiles: []string{"k8s/"}}
+	assert.Equal(t, true, conditions.Matches(directory, nil))
+
+	conditions = Conditions{IfFiles: []string{"Chart.yaml/"}}
+	assert.Equal(t, false, conditions.Matches(directory, nil))
+
+	conditions = Conditions{IfFiles: []string{"charts/"}}
+	assert.Equal(t, false, conditions.Matches(directory, nil))
 }
@@ -0,0 +1,310 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/jwalton/kitsch/internal/kitsch/suggest"
+	"gopkg.in/yaml.v3"
+)
+
+// CheckConfigurationFile reads the given configuration file and checks it for
+// errors.  See CheckConfiguration.
+func CheckConfigurationFile(configFile string) ([]modules.ConfigError, error) {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTomlFile(configFile) {
+		data, err = tomlToYaml(data)
+		if err != nil {
+			return []modules.ConfigError{{Message: err.Error()}}, nil
+		}
+	}
+
+	if filepath.Base(configFile) == LocalConfigFile {
+		return CheckLocalConfiguration(data), nil
+	}
+	return CheckConfiguration(data), nil
+}
+
+// CheckConfiguration checks a YAML configuration file for errors.  This will
+// report unknown fields and module types, invalid styles and colors, and
+// templates that fail to compile.  All errors found are returned, sorted by
+// their position in the file.
+func CheckConfiguration(yamlData []byte) []modules.ConfigError {
+	return checkYaml(yamlData, false)
+}
+
+// CheckLocalConfiguration checks a local configuration file for errors.  This
+// is the same as CheckConfiguration, except that a local configuration file
+// may only contain the keys allowed in a profile, and doesn't need a prompt.
+func CheckLocalConfiguration(yamlData []byte) []modules.ConfigError {
+	return checkYaml(yamlData, true)
+}
+
+func checkYaml(yamlData []byte, local bool) []modules.ConfigError {
+	var document yaml.Node
+	if err := yaml.Unmarshal(yamlData, &document); err != nil {
+		return yamlErrors(err)
+	}
+	if err := expandDefinitions(&document); err != nil {
+		return yamlErrors(err)
+	}
+	if len(document.Content) == 0 {
+		if local {
+			return nil
+		}
+		return []modules.ConfigError{{Message: errNoPrompt.Error()}}
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []modules.ConfigError{{Line: root.Line, Column: root.Column, Message: "configuration must be a map"}}
+	}
+
+	checker := modules.Checker{Styles: &styling.Registry{}}
+
+	if local {
+		checkConfigNode(&checker, root, reflect.TypeOf(Profile{}))
+	} else {
+		checkConfigNode(&checker, root, reflect.TypeOf(Config{}))
+
+		if mappingValue(root, "prompt") == nil {
+			checker.Errorf(nil, "%s", errNoPrompt.Error())
+		}
+
+		if profiles := mappingValue(root, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+			for index := 0; index+1 < len(profiles.Content); index += 2 {
+				profile := profiles.Content[index+1]
+				if profile.Kind == yaml.MappingNode {
+					checkConfigNode(&checker, profile, reflect.TypeOf(Profile{}))
+				}
+			}
+		}
+	}
+
+	// A module inherited from a definition is checked every time it is used,
+	// so remove any duplicate errors.
+	errs := uniqueErrors(checker.Errors)
+
+	// Decoding the file will catch type errors, and errors in project types.
+	if local {
+		var profile Profile
+		decoder := yaml.NewDecoder(bytes.NewReader(yamlData))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&profile); err != nil {
+			errs = appendNewErrors(errs, yamlErrors(err))
+		}
+	} else {
+		var config = newConfig()
+		if err := config.LoadFromYaml(yamlData, true); err != nil {
+			errs = appendNewErrors(errs, yamlErrors(err))
+		}
+
+		// The schema catches anything else, but has less helpful error messages,
+		// so we only report schema errors if we didn't find anything else.
+		if len(errs) == 0 {
+			if err := ValidateConfiguration(yamlData); err != nil {
+				errs = append(errs, modules.ConfigError{Message: err.Error()})
+			}
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+
+	return errs
+}
+
+// checkConfigNode checks the top level of a configuration, or a profile.
+func checkConfigNode(checker *modules.Checker, node *yaml.Node, configType reflect.Type) {
+	fieldNames := yamlFieldNames(configType)
+
+	// Add custom colors first, so they can be used by styles.
+	colors := mappingValue(node, "colors")
+	checkColors(checker, colors)
+
+	if colorsLight := mappingValue(node, "colorsLight"); colorsLight != nil {
+		checkColors(checker, colorsLight)
+		if colorsLight.Kind == yaml.MappingNode && configType == reflect.TypeOf(Config{}) {
+			// A light color with no dark equivalent would be undefined on
+			// dark backgrounds.
+			for index := 0; index+1 < len(colorsLight.Content); index += 2 {
+				name := colorsLight.Content[index]
+				if strings.HasPrefix(name.Value, "$") && (colors == nil || mappingValue(colors, name.Value) == nil) {
+					checker.Errorf(name, "custom color %q is in colorsLight, but not in colors", name.Value)
+				}
+			}
+		}
+	}
+
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		keyNode := node.Content[index]
+		valueNode := node.Content[index+1]
+
+		if !containsString(fieldNames, keyNode.Value) {
+			checker.Errorf(keyNode, "unknown field %q%s", keyNode.Value,
+				suggest.DidYouMean(keyNode.Value, fieldNames))
+			continue
+		}
+
+		switch keyNode.Value {
+		case "prompt", "rightPrompt":
+			checker.CheckModule(valueNode)
+		case "shellIntegration":
+			if userVars := mappingValue(valueNode, "userVars"); userVars != nil && userVars.Kind == yaml.MappingNode {
+				for index := 1; index < len(userVars.Content); index += 2 {
+					checker.CheckTemplate(userVars.Content[index], "template")
+				}
+			}
+		case "templates":
+			if valueNode.Kind == yaml.MappingNode {
+				for index := 1; index < len(valueNode.Content); index += 2 {
+					checker.CheckTemplate(valueNode.Content[index], "template")
+				}
+			}
+		case "notifications":
+			for _, field := range []string{"message", "command"} {
+				if template := mappingValue(valueNode, field); template != nil {
+					checker.CheckTemplate(template, field)
+				}
+			}
+		case "extends":
+			if valueNode.Value != "" && !fileutils.FileExists(valueNode.Value) {
+				checker.Errorf(valueNode, "extended configuration file %q does not exist", valueNode.Value)
+			}
+		}
+	}
+}
+
+// checkColors checks a map of custom colors, and adds them to the checker's
+// style registry.
+func checkColors(checker *modules.Checker, colors *yaml.Node) {
+	if colors == nil || colors.Kind != yaml.MappingNode {
+		return
+	}
+	for index := 0; index+1 < len(colors.Content); index += 2 {
+		name := colors.Content[index]
+		if !strings.HasPrefix(name.Value, "$") {
+			checker.Errorf(name, "custom color %q must start with \"$\"", name.Value)
+			continue
+		}
+		checker.Styles.AddCustomColor(name.Value, colors.Content[index+1].Value)
+	}
+	for index := 0; index+1 < len(colors.Content); index += 2 {
+		if strings.HasPrefix(colors.Content[index].Value, "$") {
+			checker.CheckStyle(colors.Content[index+1], colors.Content[index].Value)
+		}
+	}
+}
+
+// yamlFieldNames returns the YAML names of all the fields in the given struct.
+func yamlFieldNames(structType reflect.Type) []string {
+	names := make([]string, 0, structType.NumField())
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+var yamlLineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+var moduleLineRegex = regexp.MustCompile(`^(.*) \((\d+):(\d+)\)$`)
+
+// yamlErrors converts an error from the yaml package into a list of
+// ConfigErrors, extracting line numbers where possible.
+func yamlErrors(err error) []modules.ConfigError {
+	var messages []string
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) {
+		messages = typeError.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+
+	result := make([]modules.ConfigError, 0, len(messages))
+	for _, message := range messages {
+		configError := modules.ConfigError{Message: message}
+		if matches := yamlLineRegex.FindStringSubmatch(message); matches != nil {
+			configError.Line, _ = strconv.Atoi(matches[1])
+			configError.Message = matches[2]
+		} else if matches := moduleLineRegex.FindStringSubmatch(message); matches != nil {
+			configError.Line, _ = strconv.Atoi(matches[2])
+			configError.Column, _ = strconv.Atoi(matches[3])
+			configError.Message = matches[1]
+		}
+		result = append(result, configError)
+	}
+	return result
+}
+
+// uniqueErrors returns the given errors with any duplicates removed.
+func uniqueErrors(errs []modules.ConfigError) []modules.ConfigError {
+	seen := map[modules.ConfigError]bool{}
+	result := make([]modules.ConfigError, 0, len(errs))
+	for _, err := range errs {
+		if !seen[err] {
+			seen[err] = true
+			result = append(result, err)
+		}
+	}
+	return result
+}
+
+// appendNewErrors appends any errors from newErrs to errs, skipping errors on
+// lines that already have an error.
+func appendNewErrors(errs []modules.ConfigError, newErrs []modules.ConfigError) []modules.ConfigError {
+	lines := map[int]bool{}
+	for _, err := range errs {
+		lines[err.Line] = true
+	}
+
+	for _, err := range newErrs {
+		if err.Line == 0 && len(errs) > 0 {
+			// Without a line number, we can't tell if this is a duplicate.
+			continue
+		}
+		if !lines[err.Line] {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if node.Content[index].Value == key {
+			return node.Content[index+1]
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/sampleconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckValidConfiguration(t *testing.T) {
+	errs := CheckConfiguration(sampleconfig.DefaultConfig)
+	assert.Empty(t, errs)
+}
+
+func TestCheckPresets(t *testing.T) {
+	presets := sampleconfig.Presets()
+	assert.NotEmpty(t, presets)
+
+	for _, preset := range presets {
+		data, err := sampleconfig.GetPreset(preset.Name)
+		if assert.NoError(t, err) {
+			assert.Empty(t, CheckConfiguration(data), preset.Name)
+			assert.NotEmpty(t, preset.Description, preset.Name)
+		}
+	}
+
+	_, err := sampleconfig.GetPreset("minmal")
+	assert.EqualError(t, err, `unknown preset "minmal" (did you mean "minimal"?)`)
+}
+
+func TestCheckConfigurationReportsAllErrors(t *testing.T) {
+	errs := CheckConfiguration([]byte(heredoc.Doc(`
+		colors:
+		  $fg: notacolor
+		prompt:
+		  type: block
+		  modules:
+		    - type: gti_head
+		    - type: text
+		      text: hi
+		      tempalte: "{{ .Text }}"
+		    - type: directory
+		      style: blue
+		      template: "{{ .Text "
+		promt: 7
+	`)))
+
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 2, Column: 8, Message: `error compiling style "$fg": unrecognized color`},
+		{Line: 6, Column: 13, Message: `unknown module type "gti_head" (did you mean "git_head"?)`},
+		{Line: 9, Column: 7, Message: `unknown field "tempalte" in text module (did you mean "template"?)`},
+		{Line: 12, Column: 17, Message: "invalid template: template: template:1: unclosed action"},
+		{Line: 13, Column: 1, Message: `unknown field "promt" (did you mean "prompt"?)`},
+	}, errs)
+}
+
+func TestCheckConfigurationChildModules(t *testing.T) {
+	errs := CheckConfiguration([]byte(heredoc.Doc(`
+		prompt:
+		  type: vcs
+		  git:
+		    type: git_head
+		    stlye: red
+		profiles:
+		  minimal:
+		    prompt:
+		      type: prompt
+		      errorStyle: notacolor
+	`)))
+
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 5, Column: 5, Message: `unknown field "stlye" in git_head module (did you mean "style"?)`},
+		{Line: 10, Column: 19, Message: `error compiling style "notacolor": unknown style "notacolor"`},
+	}, errs)
+}
+
+func TestCheckConfigurationMissingPrompt(t *testing.T) {
+	errs := CheckConfiguration([]byte("timeout: 100\n"))
+	assert.Equal(t, []modules.ConfigError{{Message: errNoPrompt.Error()}}, errs)
+}
+
+func TestCheckConfigurationTypeErrors(t *testing.T) {
+	errs := CheckConfiguration([]byte(heredoc.Doc(`
+		timeout: soon
+		prompt:
+		  type: text
+		  text: hello
+	`)))
+
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 1, errs[0].Line)
+	assert.Contains(t, errs[0].Message, "cannot unmarshal")
+}
+
+func TestCheckConfigurationColorsLight(t *testing.T) {
+	errs := CheckConfiguration([]byte(heredoc.Doc(`
+		colors:
+		  $fg: white
+		colorsLight:
+		  $fg: black
+		  $accent: blue
+		  $bad: notacolor
+		prompt:
+		  type: text
+		  text: hello
+		  style: $accent
+	`)))
+
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 5, Column: 3, Message: `custom color "$accent" is in colorsLight, but not in colors`},
+		{Line: 6, Column: 3, Message: `custom color "$bad" is in colorsLight, but not in colors`},
+		{Line: 6, Column: 9, Message: `error compiling style "$bad": unrecognized color`},
+	}, errs)
+}
+
+func TestCheckConfigurationShellIntegration(t *testing.T) {
+	errs := CheckConfiguration([]byte(heredoc.Doc(`
+		shellIntegration:
+		  enabled: true
+		  userVars:
+		    host: "{{ .Globals.Hostname }}"
+		    bad: "{{ .Globals.Hostname "
+		prompt:
+		  type: text
+		  text: hello
+	`)))
+
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 5, Column: 10, Message: "invalid template: template: template:1: unclosed action"},
+	}, errs)
+}
+
+func TestCheckConfigurationTemplates(t *testing.T) {
+	errs := CheckConfiguration([]byte(heredoc.Doc(`
+		templates:
+		  good: "[{{ . }}]"
+		  bad: "{{ . "
+		prompt:
+		  type: text
+		  text: hello
+		  template: '{{ template "good" .Text }}'
+	`)))
+
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 3, Column: 8, Message: "invalid template: template: template:1: unclosed action"},
+	}, errs)
+}
@@ -6,12 +6,16 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 
 	// embed required for sample configs below.
 	_ "embed"
 
+	"github.com/jwalton/kitsch/internal/cache"
 	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/notify"
 	"github.com/jwalton/kitsch/internal/kitsch/projects"
 	"github.com/jwalton/kitsch/sampleconfig"
 	"gopkg.in/yaml.v3"
@@ -19,6 +23,7 @@ import (
 
 const defaultTimeout = 500
 const defaultScanTimeout = 100
+const defaultMaxScanEntries = 10000
 
 var errNoPrompt = errors.New("configuration is missing prompt")
 
@@ -28,32 +33,145 @@ type Config struct {
 	Timeout int64 `yaml:"timeout"`
 	// ScanTimeout is the maximum time to spend scanning files in the current directory.
 	ScanTimeout int64 `yaml:"scanTimeout"`
+	// MaxScanEntries is the maximum number of files to read from the current directory.
+	MaxScanEntries int64 `yaml:"maxScanEntries"`
 	// Extends is the name of another configuration file to extend.
-	Extends string `yaml:"extends"`
+	Extends string `yaml:"extends,omitempty"`
 	// Colors is a collection of custom colors.
-	Colors map[string]string `yaml:"colors"`
+	Colors map[string]string `yaml:"colors,omitempty"`
+	// ColorsLight is a collection of custom colors to use in place of Colors
+	// when the terminal has a light background.
+	ColorsLight map[string]string `yaml:"colorsLight,omitempty"`
+	// Variables is a collection of values, like colors, styles, or symbols,
+	// which can be used in style strings and templates.
+	Variables map[string]string `yaml:"variables,omitempty"`
+	// Theme is the name of, or path to, a theme file which overrides Variables.
+	Theme string `yaml:"theme,omitempty"`
 	// ProjectTypes are used when detecting the project type of the current folder.
-	ProjectsTypes []projects.ProjectType `yaml:"projectTypes"`
+	ProjectsTypes []projects.ProjectType `yaml:"projectTypes,omitempty"`
 	// Prompt is the module to use to display the prompt.
 	Prompt modules.ModuleWrapper
+	// RightPrompt is the module to use to display the right prompt, in shells
+	// which support one.
+	RightPrompt *modules.ModuleWrapper `yaml:"rightPrompt,omitempty"`
+	// Profiles is a collection of named profiles which can be applied over
+	// top of this configuration.
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+	// ShellIntegration configures escape sequences which tell the terminal
+	// about the state of the shell.
+	ShellIntegration *modules.ShellIntegration `yaml:"shellIntegration,omitempty"`
+	// Notifications configures desktop notifications for long running commands.
+	Notifications *notify.Config `yaml:"notifications,omitempty"`
+	// WarningBadge configures a badge to show in the prompt when a module
+	// reports a warning.
+	WarningBadge *modules.WarningBadge `yaml:"warningBadge,omitempty"`
+	// FilesystemRules turn off modules, or git, in directories which match a
+	// path pattern or filesystem type.
+	FilesystemRules []modules.FilesystemRule `yaml:"filesystemRules,omitempty"`
+	// HostOverrides are overrides which are applied on machines with a
+	// matching hostname.
+	HostOverrides []HostOverride `yaml:"hosts,omitempty"`
+	// DisabledModules is a list of module types or IDs which have been turned
+	// off by HostOverrides.
+	DisabledModules []string `yaml:"-"`
+	// Definitions is a collection of named partial module configurations.  A
+	// module with an "extends" key inherits all the settings from the named
+	// definition.
+	Definitions map[string]yaml.Node `yaml:"definitions,omitempty"`
+	// Templates is a collection of named templates which can be used from any
+	// module's template with `{{ template "name" . }}`.
+	Templates map[string]string `yaml:"templates,omitempty"`
+}
+
+// Profile is a named set of overrides for a configuration.  Any value set in
+// the profile replaces the value from the base configuration, except for colors,
+// variables, project types, and templates, which are merged with the base
+// configuration.
+type Profile struct {
+	// Timeout is the default module timeout, in milliseconds.
+	Timeout *int64 `yaml:"timeout,omitempty"`
+	// ScanTimeout is the maximum time to spend scanning files in the current directory.
+	ScanTimeout *int64 `yaml:"scanTimeout,omitempty"`
+	// MaxScanEntries is the maximum number of files to read from the current directory.
+	MaxScanEntries *int64 `yaml:"maxScanEntries,omitempty"`
+	// Colors is a collection of custom colors.
+	Colors map[string]string `yaml:"colors,omitempty"`
+	// ColorsLight is a collection of custom colors for light backgrounds.
+	ColorsLight map[string]string `yaml:"colorsLight,omitempty"`
+	// Variables is a collection of variables.
+	Variables map[string]string `yaml:"variables,omitempty"`
+	// Theme is the name of, or path to, a theme file.
+	Theme string `yaml:"theme,omitempty"`
+	// ProjectTypes are used when detecting the project type of the current folder.
+	ProjectsTypes []projects.ProjectType `yaml:"projectTypes,omitempty"`
+	// Prompt is the module to use to display the prompt.
+	Prompt *modules.ModuleWrapper `yaml:"prompt,omitempty"`
+	// RightPrompt is the module to use to display the right prompt.
+	RightPrompt *modules.ModuleWrapper `yaml:"rightPrompt,omitempty"`
+	// Templates are named templates which replace the templates with the same
+	// name in the base configuration.
+	Templates map[string]string `yaml:"templates,omitempty"`
 }
 
 func newConfig() Config {
-	return Config{Timeout: defaultTimeout, ScanTimeout: defaultScanTimeout}
+	return Config{Timeout: defaultTimeout, ScanTimeout: defaultScanTimeout, MaxScanEntries: defaultMaxScanEntries}
 }
 
 // LoadFromYaml loads the configuration file from a YAML file.
 func (c *Config) LoadFromYaml(yamlData []byte, strict bool) error {
+	document, err := parseYaml(yamlData)
+	if err != nil {
+		return err
+	}
+	return c.loadFromDocument(document, strict, nil)
+}
+
+// LoadFromToml loads the configuration file from a TOML file.
+func (c *Config) LoadFromToml(tomlData []byte, strict bool) error {
+	yamlData, err := tomlToYaml(tomlData)
+	if err != nil {
+		return err
+	}
+	return c.LoadFromYaml(yamlData, strict)
+}
+
+// parseYaml parses a YAML document.
+func parseYaml(yamlData []byte) (*yaml.Node, error) {
+	var document yaml.Node
 	decoder := yaml.NewDecoder(bytes.NewReader(yamlData))
-	decoder.KnownFields(strict)
-	err := decoder.Decode(c)
+	err := decoder.Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// loadFromDocument loads the configuration from a parsed YAML document.  If
+// the configuration extends another configuration file, the parent will be
+// loaded using `documentCache`, which may be nil.
+func (c *Config) loadFromDocument(document *yaml.Node, strict bool, documentCache cache.Cache) error {
+	// Expand definitions before we decode, so modules that extend a definition
+	// are decoded with all the definition's settings.
+	err := expandDefinitions(document)
+	if err != nil {
+		return err
+	}
+
+	if strict {
+		err = checkKnownFields(document, reflect.TypeOf(c))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = document.Decode(c)
 	if err != nil {
 		return err
 	}
 
 	if c.Extends != "" {
 		// Load the parent configuration.
-		parentConfig, err := LoadConfigFromFile(c.Extends, strict)
+		parentConfig, err := LoadConfigFromFileWithCache(c.Extends, strict, documentCache)
 		if err != nil {
 			log.Warn(fmt.Sprintf("Unable to load parent configuration file: %s: %v", c.Extends, err))
 		} else {
@@ -74,30 +192,184 @@ func (c *Config) mergeParent(parent *Config) {
 		child.Prompt = parent.Prompt
 	}
 
-	// Copy any colors in the parent that are not in the child.
-	if child.Colors == nil {
-		child.Colors = parent.Colors
-	} else {
-		for key, value := range parent.Colors {
-			if _, ok := child.Colors[key]; !ok {
-				child.Colors[key] = value
-			}
-		}
+	if child.RightPrompt == nil {
+		child.RightPrompt = parent.RightPrompt
+	}
+
+	if child.ShellIntegration == nil {
+		child.ShellIntegration = parent.ShellIntegration
+	}
+	if child.Notifications == nil {
+		child.Notifications = parent.Notifications
+	}
+	if child.WarningBadge == nil {
+		child.WarningBadge = parent.WarningBadge
+	}
+
+	// Rules from both files apply.
+	child.FilesystemRules = append(child.FilesystemRules, parent.FilesystemRules...)
+	child.HostOverrides = append(child.HostOverrides, parent.HostOverrides...)
+
+	// Copy any colors and templates in the parent that are not in the child.
+	child.Colors = mergeStrings(child.Colors, parent.Colors)
+	child.Templates = mergeStrings(child.Templates, parent.Templates)
+	child.ColorsLight = mergeStrings(child.ColorsLight, parent.ColorsLight)
+	child.Variables = mergeStrings(child.Variables, parent.Variables)
+	if child.Theme == "" {
+		child.Theme = parent.Theme
 	}
 
 	// Merge the project types.
 	child.ProjectsTypes = projects.MergeProjectTypes(child.ProjectsTypes, parent.ProjectsTypes, true)
+
+	// Copy any profiles in the parent that are not in the child.
+	for name, profile := range parent.Profiles {
+		if _, ok := child.Profiles[name]; !ok {
+			if child.Profiles == nil {
+				child.Profiles = map[string]Profile{}
+			}
+			child.Profiles[name] = profile
+		}
+	}
 }
 
-// LoadConfigFromFile will load a configuration from a file.
+// ApplyProfile merges the named profile over top of this configuration.
+func (c *Config) ApplyProfile(name string) error {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	c.applyOverrides(profile)
+	return nil
+}
+
+// applyOverrides merges the values from a profile over top of this configuration.
+func (c *Config) applyOverrides(profile Profile) {
+	if profile.Timeout != nil {
+		c.Timeout = *profile.Timeout
+	}
+	if profile.ScanTimeout != nil {
+		c.ScanTimeout = *profile.ScanTimeout
+	}
+	if profile.MaxScanEntries != nil {
+		c.MaxScanEntries = *profile.MaxScanEntries
+	}
+	if profile.Prompt != nil {
+		c.Prompt = *profile.Prompt
+	}
+	if profile.RightPrompt != nil {
+		c.RightPrompt = profile.RightPrompt
+	}
+
+	if len(profile.Colors) > 0 {
+		c.Colors = overrideStrings(c.Colors, profile.Colors)
+	}
+	if len(profile.ColorsLight) > 0 {
+		c.ColorsLight = overrideStrings(c.ColorsLight, profile.ColorsLight)
+	}
+	if len(profile.Variables) > 0 {
+		c.Variables = overrideStrings(c.Variables, profile.Variables)
+	}
+	if profile.Theme != "" {
+		c.Theme = profile.Theme
+	}
+
+	if len(profile.ProjectsTypes) > 0 {
+		c.ProjectsTypes = projects.MergeProjectTypes(profile.ProjectsTypes, c.ProjectsTypes, true)
+	}
+
+	if len(profile.Templates) > 0 {
+		c.Templates = overrideStrings(c.Templates, profile.Templates)
+	}
+}
+
+// ColorsForBackground returns the custom colors and variables to use for the
+// given terminal background, which should be "light", "dark", or "" if unknown.
+// For a light background, any colors in ColorsLight replace those in Colors.
+// Variables replace any colors with the same name.
+func (c *Config) ColorsForBackground(background string) map[string]string {
+	colors := c.Colors
+	if background == "light" && len(c.ColorsLight) > 0 {
+		colors = overrideStrings(colors, c.ColorsLight)
+	}
+	if len(c.Variables) > 0 {
+		colors = overrideStrings(colors, c.Variables)
+	}
+	return colors
+}
+
+// mergeStrings copies any keys in parent that are not in child into child.
+// This is used to merge maps of colors, variables, and templates from a parent
+// configuration file.
+func mergeStrings(child map[string]string, parent map[string]string) map[string]string {
+	if child == nil {
+		return parent
+	}
+	for key, value := range parent {
+		if _, ok := child[key]; !ok {
+			child[key] = value
+		}
+	}
+	return child
+}
+
+// overrideStrings returns a new map with all the values from base, replaced
+// by any values with the same key in overrides.
+func overrideStrings(base map[string]string, overrides map[string]string) map[string]string {
+	result := make(map[string]string, len(base)+len(overrides))
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range overrides {
+		result[key] = value
+	}
+	return result
+}
+
+// LoadConfigFromFile will load a configuration from a file.  Files with a
+// ".toml" extension are read as TOML, and all other files are read as YAML.
 func LoadConfigFromFile(configFile string, strict bool) (*Config, error) {
+	return LoadConfigFromFileWithCache(configFile, strict, nil)
+}
+
+// LoadConfigFromFileWithCache is like LoadConfigFromFile, but stores the parsed
+// configuration in `documentCache`, so the next time the same file is loaded
+// we can skip parsing it.  The cached copy is thrown away if the contents of
+// the file change.  Templates are compiled when the prompt is rendered, so
+// they are not part of the cache.
+func LoadConfigFromFileWithCache(configFile string, strict bool, documentCache cache.Cache) (*Config, error) {
 	var config = newConfig()
-	yamlData, err := os.ReadFile(configFile)
+	data, err := os.ReadFile(configFile)
 	if err != nil {
 		return nil, err
 	}
 
-	err = config.LoadFromYaml(yamlData, strict)
+	cacheKey := "config:" + configFile
+	if absolute, err := filepath.Abs(configFile); err == nil {
+		cacheKey = "config:" + absolute
+	}
+
+	document := cachedDocument(documentCache, cacheKey, data)
+	if document == nil {
+		yamlData := data
+		if isTomlFile(configFile) {
+			yamlData, err = tomlToYaml(data)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		document, err = parseYaml(yamlData)
+		if err != nil {
+			return nil, err
+		}
+		if documentCache != nil {
+			documentCache.Set(cacheKey, encodeCachedDocument(data, document))
+		}
+	}
+
+	err = config.loadFromDocument(document, strict, documentCache)
 	if err != nil {
 		return nil, err
 	}
@@ -109,10 +381,55 @@ func LoadConfigFromFile(configFile string, strict bool) (*Config, error) {
 	return &config, nil
 }
 
+// ExtendsFile returns the configuration file that `configFile` extends, or ""
+// if it doesn't extend another file, or can't be read.
+func ExtendsFile(configFile string) string {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return ""
+	}
+	if isTomlFile(configFile) {
+		data, err = tomlToYaml(data)
+		if err != nil {
+			return ""
+		}
+	}
+
+	var extends struct {
+		Extends string `yaml:"extends"`
+	}
+	if err := yaml.Unmarshal(data, &extends); err != nil {
+		return ""
+	}
+	return extends.Extends
+}
+
 // LoadDefaultConfig will load a default configuration.
 func LoadDefaultConfig() (*Config, error) {
+	return LoadDefaultConfigWithCache(nil)
+}
+
+// LoadDefaultConfigWithCache is like LoadDefaultConfig, but stores the parsed
+// configuration in `documentCache`.
+func LoadDefaultConfigWithCache(documentCache cache.Cache) (*Config, error) {
 	var config = newConfig()
-	err := config.LoadFromYaml(sampleconfig.DefaultConfig, false)
+
+	const cacheKey = "config:default"
+	document := cachedDocument(documentCache, cacheKey, sampleconfig.DefaultConfig)
+	if document == nil {
+		var err error
+		document, err = parseYaml(sampleconfig.DefaultConfig)
+		if err != nil {
+			// Default config should not have errors!
+			println("kitch: Error in default configuration", err)
+			return nil, err
+		}
+		if documentCache != nil {
+			documentCache.Set(cacheKey, encodeCachedDocument(sampleconfig.DefaultConfig, document))
+		}
+	}
+
+	err := config.loadFromDocument(document, false, documentCache)
 	if err != nil {
 		// Default config should not have errors!
 		println("kitch: Error in default configuration", err)
@@ -120,3 +437,17 @@ func LoadDefaultConfig() (*Config, error) {
 	}
 	return &config, nil
 }
+
+// cachedDocument returns the document for `source` from `documentCache`, or
+// nil if it isn't in the cache, or if `source` has changed since it was
+// cached.
+func cachedDocument(documentCache cache.Cache, key string, source []byte) *yaml.Node {
+	if documentCache == nil {
+		return nil
+	}
+	data := documentCache.Get(key)
+	if data == nil {
+		return nil
+	}
+	return decodeCachedDocument(source, data)
+}
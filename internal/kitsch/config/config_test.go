@@ -0,0 +1,216 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/projects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var profileTestConfig = heredoc.Doc(`
+	timeout: 300
+	colors:
+	  $primary: blue
+	  $secondary: green
+	prompt:
+	  type: block
+	  modules:
+	    - type: directory
+	    - type: prompt
+	profiles:
+	  minimal:
+	    colors:
+	      $primary: red
+	    prompt:
+	      type: prompt
+	  slow:
+	    timeout: 1000
+`)
+
+func TestApplyProfile(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(profileTestConfig), true)
+	require.NoError(t, err)
+
+	err = config.ApplyProfile("minimal")
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(300), config.Timeout)
+	assert.Equal(t, map[string]string{"$primary": "red", "$secondary": "green"}, config.Colors)
+	assert.IsType(t, &modules.PromptModule{}, config.Prompt.Module)
+}
+
+func TestApplyProfileKeepsBasePrompt(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(profileTestConfig), true)
+	require.NoError(t, err)
+
+	err = config.ApplyProfile("slow")
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1000), config.Timeout)
+	assert.Equal(t, map[string]string{"$primary": "blue", "$secondary": "green"}, config.Colors)
+	assert.IsType(t, &modules.BlockModule{}, config.Prompt.Module)
+}
+
+func TestApplyUnknownProfile(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(profileTestConfig), true)
+	require.NoError(t, err)
+
+	err = config.ApplyProfile("work")
+	assert.EqualError(t, err, "unknown profile: work")
+}
+
+func TestValidateConfigWithProfiles(t *testing.T) {
+	err := ValidateConfiguration([]byte(profileTestConfig))
+	assert.NoError(t, err)
+}
+
+func TestMarshalResolvedConfig(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(profileTestConfig), true)
+	require.NoError(t, err)
+	require.NoError(t, config.ApplyProfile("minimal"))
+	config.Profiles = nil
+	config.ProjectsTypes = projects.DefaultProjectTypes
+
+	yamlData, err := yaml.Marshal(&config)
+	require.NoError(t, err)
+
+	// The marshalled configuration should load back to the same thing.
+	reloaded := newConfig()
+	err = reloaded.LoadFromYaml(yamlData, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.Timeout, reloaded.Timeout)
+	assert.Equal(t, config.Colors, reloaded.Colors)
+	assert.Equal(t, config.ProjectsTypes, reloaded.ProjectsTypes)
+	assert.IsType(t, &modules.PromptModule{}, reloaded.Prompt.Module)
+	assert.NotContains(t, string(yamlData), "profiles")
+}
+
+func TestRightPrompt(t *testing.T) {
+	yamlData := []byte(heredoc.Doc(`
+		prompt:
+		  type: prompt
+		rightPrompt:
+		  type: time
+		profiles:
+		  minimal:
+		    rightPrompt:
+		      type: text
+		      text: ""
+	`))
+	assert.Empty(t, CheckConfiguration(yamlData))
+
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml(yamlData, true))
+	require.NotNil(t, config.RightPrompt)
+	assert.IsType(t, &modules.TimeModule{}, config.RightPrompt.Module)
+
+	require.NoError(t, config.ApplyProfile("minimal"))
+	assert.IsType(t, &modules.TextModule{}, config.RightPrompt.Module)
+
+	config = newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(profileTestConfig), true))
+	assert.Nil(t, config.RightPrompt)
+}
+
+func TestColorsForBackground(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(heredoc.Doc(`
+		colors:
+		  $fg: brightWhite
+		  $accent: cyan
+		colorsLight:
+		  $fg: black
+		prompt:
+		  type: text
+		  text: hello
+		  style: $fg
+	`)), true)
+	require.NoError(t, err)
+
+	dark := map[string]string{"$fg": "brightWhite", "$accent": "cyan"}
+	assert.Equal(t, dark, config.ColorsForBackground("dark"))
+	assert.Equal(t, dark, config.ColorsForBackground(""))
+	assert.Equal(t,
+		map[string]string{"$fg": "black", "$accent": "cyan"},
+		config.ColorsForBackground("light"),
+	)
+	// Colors should not be modified.
+	assert.Equal(t, dark, config.Colors)
+}
+
+func TestMergeParentTemplates(t *testing.T) {
+	parent := newConfig()
+	err := parent.LoadFromYaml([]byte(heredoc.Doc(`
+		templates:
+		  icon: "parent"
+		  sep: " | "
+		prompt:
+		  type: prompt
+	`)), true)
+	require.NoError(t, err)
+
+	child := newConfig()
+	err = child.LoadFromYaml([]byte(heredoc.Doc(`
+		templates:
+		  icon: "child"
+	`)), true)
+	require.NoError(t, err)
+
+	child.mergeParent(&parent)
+	assert.Equal(t, map[string]string{"icon": "child", "sep": " | "}, child.Templates)
+}
+
+func TestMergeParentFilesystemRules(t *testing.T) {
+	parent := newConfig()
+	err := parent.LoadFromYaml([]byte(heredoc.Doc(`
+		filesystemRules:
+		  - paths: ["/mnt/**"]
+		    disableGit: true
+		prompt:
+		  type: prompt
+	`)), true)
+	require.NoError(t, err)
+
+	child := newConfig()
+	err = child.LoadFromYaml([]byte(heredoc.Doc(`
+		filesystemRules:
+		  - filesystemTypes: [network]
+		    disableModules: [git_status]
+	`)), true)
+	require.NoError(t, err)
+
+	child.mergeParent(&parent)
+	assert.Equal(t, []modules.FilesystemRule{
+		{FilesystemTypes: []string{"network"}, DisableModules: []string{"git_status"}},
+		{Paths: []string{"/mnt/**"}, DisableGit: true},
+	}, child.FilesystemRules)
+}
+
+func TestExtendsFile(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlFile := filepath.Join(dir, "kitsch.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("extends: ./parent.yaml\nprompt:\n  type: prompt\n"), 0644))
+	assert.Equal(t, "./parent.yaml", ExtendsFile(yamlFile))
+
+	tomlFile := filepath.Join(dir, "kitsch.toml")
+	require.NoError(t, os.WriteFile(tomlFile, []byte("extends = \"parent.toml\"\n"), 0644))
+	assert.Equal(t, "parent.toml", ExtendsFile(tomlFile))
+
+	plainFile := filepath.Join(dir, "plain.yaml")
+	require.NoError(t, os.WriteFile(plainFile, []byte("prompt:\n  type: prompt\n"), 0644))
+	assert.Equal(t, "", ExtendsFile(plainFile))
+
+	assert.Equal(t, "", ExtendsFile(filepath.Join(dir, "missing.yaml")))
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/suggest"
+	"gopkg.in/yaml.v3"
+)
+
+// definitionExpander replaces modules that have an "extends" key with the
+// named definition from the "definitions" section of the configuration.
+type definitionExpander struct {
+	// definitions are the raw definitions from the configuration file.
+	definitions map[string]*yaml.Node
+	// resolved are definitions that have been merged with any definitions
+	// they extend.
+	resolved map[string]*yaml.Node
+	// resolving is the set of definitions currently being resolved, used to
+	// detect loops.
+	resolving map[string]bool
+	// visited is the set of nodes we've already expanded.
+	visited map[*yaml.Node]bool
+}
+
+// expandDefinitions expands any module in the given YAML document which has
+// an "extends" key.  The module is replaced, in place, with the named
+// definition from the "definitions" section, with the module's own keys
+// merged over top.  Definitions may themselves extend other definitions.
+func expandDefinitions(document *yaml.Node) error {
+	root := document
+	if root.Kind == yaml.DocumentNode {
+		if len(root.Content) == 0 {
+			return nil
+		}
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	expander := definitionExpander{
+		definitions: map[string]*yaml.Node{},
+		resolved:    map[string]*yaml.Node{},
+		resolving:   map[string]bool{},
+		visited:     map[*yaml.Node]bool{},
+	}
+
+	definitionsNode := mappingValue(root, "definitions")
+	if definitionsNode != nil && definitionsNode.Kind == yaml.MappingNode {
+		for index := 0; index+1 < len(definitionsNode.Content); index += 2 {
+			value := resolveAlias(definitionsNode.Content[index+1])
+			if value.Kind != yaml.MappingNode {
+				return fmt.Errorf("definition %q must be a map (%d:%d)", definitionsNode.Content[index].Value, value.Line, value.Column)
+			}
+			expander.definitions[definitionsNode.Content[index].Value] = value
+		}
+	}
+
+	if prompt := mappingValue(root, "prompt"); prompt != nil {
+		if err := expander.expand(prompt); err != nil {
+			return err
+		}
+	}
+
+	if profiles := mappingValue(root, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+		for index := 0; index+1 < len(profiles.Content); index += 2 {
+			profile := resolveAlias(profiles.Content[index+1])
+			if profile.Kind != yaml.MappingNode {
+				continue
+			}
+			if prompt := mappingValue(profile, "prompt"); prompt != nil {
+				if err := expander.expand(prompt); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// expand expands the given node, and all of its children.
+func (expander *definitionExpander) expand(node *yaml.Node) error {
+	node = resolveAlias(node)
+	if node == nil || expander.visited[node] {
+		return nil
+	}
+	expander.visited[node] = true
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		if extends := mappingValue(node, "extends"); extends != nil {
+			definition, err := expander.resolve(extends)
+			if err != nil {
+				return err
+			}
+			node.Content = mergeMappings(definition, node)
+		}
+		for index := 1; index < len(node.Content); index += 2 {
+			if err := expander.expand(node.Content[index]); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := expander.expand(child); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolve returns the definition named by the given "extends" node, merged
+// with any definitions it extends in turn.
+func (expander *definitionExpander) resolve(extends *yaml.Node) (*yaml.Node, error) {
+	name := extends.Value
+
+	if resolved, ok := expander.resolved[name]; ok {
+		return resolved, nil
+	}
+
+	definition, ok := expander.definitions[name]
+	if !ok {
+		names := make([]string, 0, len(expander.definitions))
+		for definitionName := range expander.definitions {
+			names = append(names, definitionName)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown definition %q%s (%d:%d)",
+			name, suggest.DidYouMean(name, names), extends.Line, extends.Column)
+	}
+
+	if expander.resolving[name] {
+		return nil, fmt.Errorf("definition %q extends itself (%d:%d)", name, extends.Line, extends.Column)
+	}
+	expander.resolving[name] = true
+	defer delete(expander.resolving, name)
+
+	resolved := &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Tag:     definition.Tag,
+		Line:    definition.Line,
+		Column:  definition.Column,
+		Content: definition.Content,
+	}
+	if parent := mappingValue(definition, "extends"); parent != nil {
+		parentDefinition, err := expander.resolve(parent)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Content = mergeMappings(parentDefinition, definition)
+	}
+
+	expander.resolved[name] = resolved
+	return resolved, nil
+}
+
+// mergeMappings returns the content for a mapping node with all the keys from
+// base, replaced or added to by the keys in overrides.  The "extends" key from
+// overrides is dropped.
+func mergeMappings(base *yaml.Node, overrides *yaml.Node) []*yaml.Node {
+	content := make([]*yaml.Node, 0, len(base.Content)+len(overrides.Content))
+
+	for index := 0; index+1 < len(base.Content); index += 2 {
+		key := base.Content[index].Value
+		if mappingValue(overrides, key) == nil {
+			content = append(content, base.Content[index], base.Content[index+1])
+		}
+	}
+	for index := 0; index+1 < len(overrides.Content); index += 2 {
+		if overrides.Content[index].Value != "extends" {
+			content = append(content, overrides.Content[index], overrides.Content[index+1])
+		}
+	}
+
+	return content
+}
+
+// checkKnownFields returns an error if any mapping in the given node has a key
+// that doesn't correspond to a field in the given type.  This is equivalent
+// to decoding with yaml.Decoder.KnownFields(true), which isn't available when
+// decoding from a yaml.Node.  Types which unmarshal themselves are not checked.
+func checkKnownFields(node *yaml.Node, valueType reflect.Type) error {
+	var errs []string
+	collectUnknownFields(node, valueType, &errs)
+	if len(errs) > 0 {
+		return &yaml.TypeError{Errors: errs}
+	}
+	return nil
+}
+
+var unmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+var yamlNodeType = reflect.TypeOf(yaml.Node{})
+
+// addStructFields adds the YAML name and type of every field in the given
+// struct to `fields`, including the fields of any inlined structs.
+func addStructFields(fields map[string]reflect.Type, valueType reflect.Type) {
+	for index := 0; index < valueType.NumField(); index++ {
+		field := valueType.Field(index)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(field.Tag.Get("yaml"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if len(tag) > 1 && tag[1] == "inline" && field.Type.Kind() == reflect.Struct {
+			addStructFields(fields, field.Type)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+}
+
+func collectUnknownFields(node *yaml.Node, valueType reflect.Type, errs *[]string) {
+	node = resolveAlias(node)
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			collectUnknownFields(child, valueType, errs)
+		}
+		return
+	}
+
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+	}
+	if valueType == yamlNodeType || reflect.PtrTo(valueType).Implements(unmarshalerType) {
+		return
+	}
+
+	switch valueType.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := map[string]reflect.Type{}
+		addStructFields(fields, valueType)
+		for index := 0; index+1 < len(node.Content); index += 2 {
+			key := node.Content[index]
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				*errs = append(*errs, fmt.Sprintf("line %d: field %s not found in type %s", key.Line, key.Value, valueType.String()))
+				continue
+			}
+			collectUnknownFields(node.Content[index+1], fieldType, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind == yaml.SequenceNode {
+			for _, child := range node.Content {
+				collectUnknownFields(child, valueType.Elem(), errs)
+			}
+		}
+	case reflect.Map:
+		if node.Kind == yaml.MappingNode {
+			for index := 1; index < len(node.Content); index += 2 {
+				collectUnknownFields(node.Content[index], valueType.Elem(), errs)
+			}
+		}
+	}
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
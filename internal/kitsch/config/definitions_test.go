@@ -0,0 +1,176 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var definitionsTestConfig = heredoc.Doc(`
+	definitions:
+	  segment:
+	    style: bg:blue
+	    template: " {{ .Text }} "
+	  warning:
+	    extends: segment
+	    style: bg:yellow
+	prompt:
+	  type: block
+	  modules:
+	    - type: text
+	      extends: segment
+	      text: one
+	    - type: text
+	      extends: warning
+	      text: two
+	      template: "[{{ .Text }}]"
+`)
+
+func TestDefinitions(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(definitionsTestConfig), true)
+	require.NoError(t, err)
+
+	block := config.Prompt.Module.(*modules.BlockModule)
+	require.Len(t, block.Modules, 2)
+
+	assert.Equal(t, &modules.TextModule{Type: "text", Text: "one"}, block.Modules[0].Module)
+	assert.Equal(t, heredoc.Doc(`
+		style: bg:blue
+		template: " {{ .Text }} "
+		type: text
+		text: one
+	`), marshalModule(t, block.Modules[0]))
+
+	assert.Equal(t, &modules.TextModule{Type: "text", Text: "two"}, block.Modules[1].Module)
+	assert.Equal(t, heredoc.Doc(`
+		style: bg:yellow
+		type: text
+		text: two
+		template: "[{{ .Text }}]"
+	`), marshalModule(t, block.Modules[1]))
+	assert.Equal(t, 14, block.Modules[1].Line)
+
+	assert.Empty(t, CheckConfiguration([]byte(definitionsTestConfig)))
+	assert.NoError(t, ValidateConfiguration([]byte(definitionsTestConfig)))
+}
+
+func TestDefinitionsProvideType(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(heredoc.Doc(`
+		definitions:
+		  hello:
+		    type: text
+		    text: hello
+		prompt:
+		  extends: hello
+		  style: red
+	`)), true)
+	require.NoError(t, err)
+
+	assert.Equal(t, &modules.TextModule{Type: "text", Text: "hello"}, config.Prompt.Module)
+	assert.Equal(t, "type: text\ntext: hello\nstyle: red\n", marshalModule(t, config.Prompt))
+}
+
+func TestDefinitionsInProfiles(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(heredoc.Doc(`
+		definitions:
+		  hello:
+		    type: text
+		    text: hello
+		prompt:
+		  type: text
+		  text: base
+		profiles:
+		  demo:
+		    prompt:
+		      extends: hello
+	`)), true)
+	require.NoError(t, err)
+
+	require.NoError(t, config.ApplyProfile("demo"))
+	assert.Equal(t, &modules.TextModule{Type: "text", Text: "hello"}, config.Prompt.Module)
+}
+
+func marshalModule(t *testing.T, module modules.ModuleWrapper) string {
+	data, err := yaml.Marshal(module)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestUnknownDefinition(t *testing.T) {
+	yamlData := []byte(heredoc.Doc(`
+		definitions:
+		  segment:
+		    style: blue
+		prompt:
+		  type: text
+		  text: hi
+		  extends: segmnt
+	`))
+
+	config := newConfig()
+	err := config.LoadFromYaml(yamlData, true)
+	assert.EqualError(t, err, `unknown definition "segmnt" (did you mean "segment"?) (7:12)`)
+
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 7, Column: 12, Message: `unknown definition "segmnt" (did you mean "segment"?)`},
+	}, CheckConfiguration(yamlData))
+}
+
+func TestDefinitionLoop(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(heredoc.Doc(`
+		definitions:
+		  a:
+		    extends: b
+		  b:
+		    extends: a
+		prompt:
+		  type: text
+		  text: hi
+		  extends: a
+	`)), true)
+	assert.EqualError(t, err, `definition "a" extends itself (5:14)`)
+}
+
+func TestDefinitionsStrict(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(heredoc.Doc(`
+		definitions:
+		  segment:
+		    style: blue
+		prompt:
+		  type: text
+		  text: hi
+		  extends: segment
+		promt: 7
+	`)), true)
+	assert.EqualError(t, err, "yaml: unmarshal errors:\n  line 8: field promt not found in type config.Config")
+}
+
+func TestCheckConfigurationDefinitionErrorsReportedOnce(t *testing.T) {
+	errs := CheckConfiguration([]byte(heredoc.Doc(`
+		definitions:
+		  segment:
+		    template: "{{ .Text "
+		prompt:
+		  type: block
+		  modules:
+		    - type: text
+		      text: one
+		      extends: segment
+		    - type: text
+		      text: two
+		      extends: segment
+	`)))
+
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 3, Column: 15, Message: "invalid template: template: template:1: unclosed action"},
+	}, errs)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// HostOverride is a set of overrides which are applied to the configuration
+// on machines with a matching hostname.  This lets a single configuration
+// file, shared between machines, adapt itself to each machine.
+type HostOverride struct {
+	// Hosts is a list of glob patterns (e.g. "web-*") to match against the
+	// hostname.  Patterns are not case sensitive.
+	Hosts []string `yaml:"hosts"`
+	// UseProfile is the name of a profile to apply on matching machines.
+	UseProfile string `yaml:"profile,omitempty"`
+	// DisableModules is a list of module types or IDs to turn off on matching
+	// machines.
+	DisableModules []string `yaml:"disableModules,omitempty"`
+	// Profile holds any other overrides to apply on matching machines.  These
+	// are applied after UseProfile.
+	Profile `yaml:",inline"`
+}
+
+// matches returns true if this override applies to the given hostname.
+func (override HostOverride) matches(hostname string) bool {
+	hostname = strings.ToLower(hostname)
+	for _, pattern := range override.Hosts {
+		if matched, _ := path.Match(strings.ToLower(pattern), hostname); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyHostOverrides applies every HostOverride which matches the given
+// hostname, in the order they appear in the configuration.
+func (c *Config) ApplyHostOverrides(hostname string) error {
+	if hostname == "" {
+		return nil
+	}
+
+	for _, override := range c.HostOverrides {
+		if !override.matches(hostname) {
+			continue
+		}
+
+		if override.UseProfile != "" {
+			if err := c.ApplyProfile(override.UseProfile); err != nil {
+				return fmt.Errorf("hosts: %w", err)
+			}
+		}
+		c.applyOverrides(override.Profile)
+		c.DisabledModules = append(c.DisabledModules, override.DisableModules...)
+	}
+	return nil
+}
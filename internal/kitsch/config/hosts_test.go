@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var hostsTestConfig = heredoc.Doc(`
+	timeout: 300
+	colors:
+	  $primary: blue
+	templates:
+	  git-icon: ""
+	prompt:
+	  type: block
+	  modules:
+	    - type: directory
+	    - type: prompt
+	profiles:
+	  minimal:
+	    prompt:
+	      type: prompt
+	hosts:
+	  - hosts: ["web-*", "*.prod.example.com"]
+	    profile: minimal
+	    colors:
+	      $primary: red
+	  - hosts: [OLD-BOX]
+	    templates:
+	      git-icon: "git:"
+	    disableModules: [battery]
+`)
+
+func TestApplyHostOverrides(t *testing.T) {
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(hostsTestConfig), true))
+
+	require.NoError(t, config.ApplyHostOverrides("web-3"))
+	assert.Equal(t, "red", config.Colors["$primary"])
+	assert.Equal(t, int64(300), config.Timeout)
+	assert.IsType(t, &modules.PromptModule{}, config.Prompt.Module)
+	assert.Equal(t, "", config.Templates["git-icon"])
+	assert.Empty(t, config.DisabledModules)
+}
+
+func TestApplyHostOverridesCaseInsensitive(t *testing.T) {
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(hostsTestConfig), true))
+
+	require.NoError(t, config.ApplyHostOverrides("old-box"))
+	assert.Equal(t, "blue", config.Colors["$primary"])
+	assert.Equal(t, "git:", config.Templates["git-icon"])
+	assert.Equal(t, []string{"battery"}, config.DisabledModules)
+	assert.IsType(t, &modules.BlockModule{}, config.Prompt.Module)
+}
+
+func TestApplyHostOverridesNoMatch(t *testing.T) {
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(hostsTestConfig), true))
+
+	require.NoError(t, config.ApplyHostOverrides("laptop"))
+	assert.Equal(t, "blue", config.Colors["$primary"])
+	assert.IsType(t, &modules.BlockModule{}, config.Prompt.Module)
+
+	require.NoError(t, config.ApplyHostOverrides("db.prod.example.com"))
+	assert.Equal(t, "red", config.Colors["$primary"])
+}
+
+func TestApplyHostOverridesUnknownProfile(t *testing.T) {
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(heredoc.Doc(`
+		hosts:
+		  - hosts: ["*"]
+		    profile: missing
+		prompt:
+		  type: prompt
+	`)), true))
+
+	assert.EqualError(t, config.ApplyHostOverrides("laptop"), "hosts: unknown profile: missing")
+}
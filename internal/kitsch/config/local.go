@@ -0,0 +1,172 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"gopkg.in/yaml.v3"
+)
+
+// LocalConfigFile is the name of a per-directory configuration file.
+const LocalConfigFile = ".kitsch.yaml"
+
+// FindLocalConfig returns the path to the closest local configuration file in
+// the given folder or one of its ancestors, or "" if there is none.  The
+// search stops at the root of the git repository containing the folder, so a
+// repo can't pick up a local configuration from outside the repo.
+func FindLocalConfig(folder string) string {
+	basePath := filepath.Clean(folder)
+
+	for {
+		testPath := filepath.Join(basePath, LocalConfigFile)
+		if fileutils.FileExists(testPath) && !fileutils.IsDirectory(testPath) {
+			// Resolve symlinks, so the file is trusted no matter how we got to it.
+			if resolved, err := filepath.EvalSymlinks(testPath); err == nil {
+				return resolved
+			}
+			return testPath
+		}
+		if fileutils.FileExists(filepath.Join(basePath, ".git")) {
+			return ""
+		}
+
+		newBasePath := filepath.Dir(basePath)
+		if newBasePath == basePath {
+			return ""
+		}
+		basePath = newBasePath
+	}
+}
+
+// ApplyLocalConfig merges a local configuration file over top of this
+// configuration.  A local configuration file may contain any of the keys
+// allowed in a profile.
+func (c *Config) ApplyLocalConfig(yamlData []byte) error {
+	var local Profile
+	decoder := yaml.NewDecoder(bytes.NewReader(yamlData))
+	if err := decoder.Decode(&local); err == io.EOF {
+		// Empty file.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	c.applyOverrides(local)
+	return nil
+}
+
+// TrustStatus is the result of checking if a local configuration file is trusted.
+type TrustStatus int
+
+const (
+	// Untrusted means the user has never trusted or denied the file.
+	Untrusted TrustStatus = iota
+	// Trusted means the user has trusted the file, and it has not changed since.
+	Trusted
+	// Changed means the user trusted the file, but it has changed since.
+	Changed
+	// Denied means the user has explicitly chosen not to trust the file.
+	Denied
+)
+
+// TrustStore keeps track of which local configuration files the user has
+// trusted.  Local configuration files can run arbitrary commands via custom
+// modules, so we only load ones the user has explicitly trusted.  A file is
+// trusted along with a hash of its contents, so if the file changes it must
+// be trusted again.
+type TrustStore struct {
+	file string
+	// Trusted maps the path of each trusted file to a SHA-256 hash of its contents.
+	Trusted map[string]string `yaml:"trusted,omitempty"`
+	// Denied is a list of files the user has chosen not to trust.
+	Denied []string `yaml:"denied,omitempty"`
+}
+
+// LoadTrustStore loads the trust store from the given file.  If the file does
+// not exist, this returns an empty store which will be saved to the file.
+func LoadTrustStore(file string) (*TrustStore, error) {
+	store := &TrustStore{file: file}
+
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Status returns the trust status of the local configuration file at the
+// given path, with the given contents.
+func (store *TrustStore) Status(path string, data []byte) TrustStatus {
+	path = filepath.Clean(path)
+
+	for _, denied := range store.Denied {
+		if denied == path {
+			return Denied
+		}
+	}
+
+	hash, ok := store.Trusted[path]
+	switch {
+	case !ok:
+		return Untrusted
+	case hash != hashContents(data):
+		return Changed
+	default:
+		return Trusted
+	}
+}
+
+// Trust marks the given file as trusted.
+func (store *TrustStore) Trust(path string, data []byte) {
+	path = filepath.Clean(path)
+	store.removeDenied(path)
+	if store.Trusted == nil {
+		store.Trusted = map[string]string{}
+	}
+	store.Trusted[path] = hashContents(data)
+}
+
+// Deny marks the given file as untrusted.
+func (store *TrustStore) Deny(path string) {
+	path = filepath.Clean(path)
+	delete(store.Trusted, path)
+	store.removeDenied(path)
+	store.Denied = append(store.Denied, path)
+	sort.Strings(store.Denied)
+}
+
+func (store *TrustStore) removeDenied(path string) {
+	denied := store.Denied[:0]
+	for _, deniedPath := range store.Denied {
+		if deniedPath != path {
+			denied = append(denied, deniedPath)
+		}
+	}
+	store.Denied = denied
+}
+
+// Save writes the trust store back to the file it was loaded from.
+func (store *TrustStore) Save() error {
+	data, err := yaml.Marshal(store)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(store.file, data, 0600)
+}
+
+func hashContents(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindLocalConfig(t *testing.T) {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	repo := filepath.Join(dir, "repo")
+	subdir := filepath.Join(repo, "src", "pkg")
+	require.NoError(t, os.MkdirAll(subdir, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, LocalConfigFile), []byte{}, 0644))
+
+	// Should not look outside the repo.
+	assert.Equal(t, "", FindLocalConfig(subdir))
+
+	repoConfig := filepath.Join(repo, LocalConfigFile)
+	require.NoError(t, os.WriteFile(repoConfig, []byte{}, 0644))
+	assert.Equal(t, repoConfig, FindLocalConfig(subdir))
+	assert.Equal(t, repoConfig, FindLocalConfig(repo))
+
+	// Outside of a repo, we use the closest ancestor.
+	assert.Equal(t, filepath.Join(dir, LocalConfigFile), FindLocalConfig(dir))
+}
+
+func TestTrustStore(t *testing.T) {
+	storeFile := filepath.Join(t.TempDir(), "trusted.yaml")
+	store, err := LoadTrustStore(storeFile)
+	require.NoError(t, err)
+
+	localFile := filepath.Join("project", LocalConfigFile)
+	data := []byte("timeout: 100\n")
+
+	assert.Equal(t, Untrusted, store.Status(localFile, data))
+
+	store.Trust(localFile, data)
+	require.NoError(t, store.Save())
+
+	store, err = LoadTrustStore(storeFile)
+	require.NoError(t, err)
+	assert.Equal(t, Trusted, store.Status(localFile, data))
+	assert.Equal(t, Changed, store.Status(localFile, []byte("timeout: 200\n")))
+
+	store.Deny(localFile)
+	assert.Equal(t, Denied, store.Status(localFile, data))
+
+	store.Trust(localFile, data)
+	assert.Equal(t, Trusted, store.Status(localFile, data))
+	assert.Empty(t, store.Denied)
+}
+
+func TestApplyLocalConfig(t *testing.T) {
+	config := newConfig()
+	err := config.LoadFromYaml([]byte(profileTestConfig), true)
+	require.NoError(t, err)
+
+	err = config.ApplyLocalConfig([]byte(heredoc.Doc(`
+		colors:
+		  $primary: yellow
+		prompt:
+		  type: prompt
+	`)))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"$primary": "yellow", "$secondary": "green"}, config.Colors)
+	assert.IsType(t, &modules.PromptModule{}, config.Prompt.Module)
+
+	assert.NoError(t, config.ApplyLocalConfig([]byte{}))
+}
+
+func TestCheckLocalConfiguration(t *testing.T) {
+	assert.Empty(t, CheckLocalConfiguration([]byte("colors:\n  $fg: red\n")))
+
+	errs := CheckLocalConfiguration([]byte("extends: ../evil.yaml\n"))
+	assert.Equal(t, []modules.ConfigError{
+		{Line: 1, Column: 1, Message: `unknown field "extends"`},
+	}, errs)
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// nodeCacheVersion is written at the start of every cached document.  This
+// must be changed whenever the encoding below changes.
+const nodeCacheVersion = 1
+
+var errCorruptCache = errors.New("corrupt cached configuration")
+
+// Tags used to encode a `*yaml.Node`.
+const (
+	nodeNil       = 0
+	nodeNew       = 1
+	nodeReference = 2
+)
+
+// encodeCachedDocument encodes a parsed YAML document, along with a hash of
+// the source it was parsed from, so it can be stored in the cache.
+func encodeCachedDocument(source []byte, document *yaml.Node) []byte {
+	hash := sha256.Sum256(source)
+
+	encoder := nodeEncoder{indexes: map[*yaml.Node]uint64{}}
+	encoder.writeUint(nodeCacheVersion)
+	encoder.out.Write(hash[:])
+	encoder.writeNode(document)
+	return encoder.out.Bytes()
+}
+
+// decodeCachedDocument decodes a document written by encodeCachedDocument.
+// This returns nil if `source` has changed since the document was cached, or
+// if the cached data can't be read.
+func decodeCachedDocument(source []byte, data []byte) *yaml.Node {
+	decoder := nodeDecoder{data: data}
+	if decoder.readUint() != nodeCacheVersion || decoder.err != nil {
+		return nil
+	}
+
+	hash := sha256.Sum256(source)
+	if len(decoder.data) < len(hash) || !bytes.Equal(decoder.data[:len(hash)], hash[:]) {
+		return nil
+	}
+	decoder.data = decoder.data[len(hash):]
+
+	document := decoder.readNode()
+	if decoder.err != nil || document == nil || len(decoder.data) != 0 {
+		return nil
+	}
+	return document
+}
+
+// nodeEncoder writes a tree of YAML nodes in a compact binary format.  Every
+// node is given an index in the order it is written.  A node that appears in
+// the tree more than once (for example, the target of an alias) is only
+// written the first time, and is written as a reference to that index after
+// that, so the decoded tree shares nodes exactly the same way.
+type nodeEncoder struct {
+	out     bytes.Buffer
+	indexes map[*yaml.Node]uint64
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (encoder *nodeEncoder) writeUint(value uint64) {
+	count := binary.PutUvarint(encoder.scratch[:], value)
+	encoder.out.Write(encoder.scratch[:count])
+}
+
+func (encoder *nodeEncoder) writeString(value string) {
+	encoder.writeUint(uint64(len(value)))
+	encoder.out.WriteString(value)
+}
+
+func (encoder *nodeEncoder) writeNode(node *yaml.Node) {
+	if node == nil {
+		encoder.writeUint(nodeNil)
+		return
+	}
+	if index, ok := encoder.indexes[node]; ok {
+		encoder.writeUint(nodeReference)
+		encoder.writeUint(index)
+		return
+	}
+	encoder.indexes[node] = uint64(len(encoder.indexes))
+
+	encoder.writeUint(nodeNew)
+	encoder.writeUint(uint64(node.Kind))
+	encoder.writeUint(uint64(node.Style))
+	encoder.writeString(node.Tag)
+	encoder.writeString(node.Value)
+	encoder.writeString(node.Anchor)
+	encoder.writeString(node.HeadComment)
+	encoder.writeString(node.LineComment)
+	encoder.writeString(node.FootComment)
+	encoder.writeUint(uint64(node.Line))
+	encoder.writeUint(uint64(node.Column))
+	encoder.writeNode(node.Alias)
+	encoder.writeUint(uint64(len(node.Content)))
+	for _, child := range node.Content {
+		encoder.writeNode(child)
+	}
+}
+
+// nodeDecoder reads a tree of YAML nodes written by nodeEncoder.  The first
+// error is stored in `err`, and every read after that returns a zero value.
+type nodeDecoder struct {
+	data  []byte
+	nodes []*yaml.Node
+	err   error
+}
+
+func (decoder *nodeDecoder) readUint() uint64 {
+	if decoder.err != nil {
+		return 0
+	}
+	value, count := binary.Uvarint(decoder.data)
+	if count <= 0 {
+		decoder.err = errCorruptCache
+		return 0
+	}
+	decoder.data = decoder.data[count:]
+	return value
+}
+
+func (decoder *nodeDecoder) readString() string {
+	length := decoder.readUint()
+	if decoder.err != nil {
+		return ""
+	}
+	if uint64(len(decoder.data)) < length {
+		decoder.err = errCorruptCache
+		return ""
+	}
+	value := string(decoder.data[:length])
+	decoder.data = decoder.data[length:]
+	return value
+}
+
+func (decoder *nodeDecoder) readNode() *yaml.Node {
+	switch decoder.readUint() {
+	case nodeNil:
+		return nil
+	case nodeReference:
+		index := decoder.readUint()
+		if decoder.err != nil || index >= uint64(len(decoder.nodes)) {
+			decoder.err = errCorruptCache
+			return nil
+		}
+		return decoder.nodes[index]
+	case nodeNew:
+		// Handled below.
+	default:
+		decoder.err = errCorruptCache
+		return nil
+	}
+
+	// Add the node to the list before reading its children, so children can
+	// refer back to it.
+	node := &yaml.Node{}
+	decoder.nodes = append(decoder.nodes, node)
+
+	node.Kind = yaml.Kind(decoder.readUint())
+	node.Style = yaml.Style(decoder.readUint())
+	node.Tag = decoder.readString()
+	node.Value = decoder.readString()
+	node.Anchor = decoder.readString()
+	node.HeadComment = decoder.readString()
+	node.LineComment = decoder.readString()
+	node.FootComment = decoder.readString()
+	node.Line = int(decoder.readUint())
+	node.Column = int(decoder.readUint())
+	node.Alias = decoder.readNode()
+
+	count := decoder.readUint()
+	if decoder.err != nil {
+		return nil
+	}
+	if count > uint64(len(decoder.data)) {
+		// Every child takes at least one byte.
+		decoder.err = errCorruptCache
+		return nil
+	}
+	if count > 0 {
+		node.Content = make([]*yaml.Node, count)
+		for index := range node.Content {
+			node.Content[index] = decoder.readNode()
+		}
+	}
+
+	return node
+}
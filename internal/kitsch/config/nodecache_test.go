@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var cachedTestConfig = heredoc.Doc(`
+	# The prompt.
+	colors:
+	  $primary: &primary blue
+	prompt:
+	  type: block
+	  style: *primary
+	  modules:
+	    - type: directory
+	    - type: text # Some text.
+	      text: "hello"
+`)
+
+func TestCachedDocumentRoundTrip(t *testing.T) {
+	source := []byte(cachedTestConfig)
+	document, err := parseYaml(source)
+	require.NoError(t, err)
+
+	decoded := decodeCachedDocument(source, encodeCachedDocument(source, document))
+	require.NotNil(t, decoded)
+	assert.Equal(t, document, decoded)
+
+	// The alias should point at the decoded anchor, not a copy of it.
+	colors := mappingValue(decoded.Content[0], "colors")
+	prompt := mappingValue(decoded.Content[0], "prompt")
+	assert.Same(t, colors.Content[1], mappingValue(prompt, "style").Alias)
+}
+
+func TestCachedDocumentInvalidation(t *testing.T) {
+	source := []byte(cachedTestConfig)
+	document, err := parseYaml(source)
+	require.NoError(t, err)
+	encoded := encodeCachedDocument(source, document)
+
+	assert.Nil(t, decodeCachedDocument([]byte(cachedTestConfig+"\n"), encoded))
+	assert.Nil(t, decodeCachedDocument(source, encoded[:len(encoded)-3]))
+	assert.Nil(t, decodeCachedDocument(source, append(encoded, 0)))
+	assert.Nil(t, decodeCachedDocument(source, []byte("garbage")))
+	assert.Nil(t, decodeCachedDocument(source, nil))
+}
+
+func TestLoadConfigFromFileWithCache(t *testing.T) {
+	dir := t.TempDir()
+	parentFile := filepath.Join(dir, "parent.yaml")
+	configFile := filepath.Join(dir, "kitsch.yaml")
+	require.NoError(t, os.WriteFile(parentFile, []byte("colors:\n  $secondary: red\nprompt:\n  type: prompt\n"), 0644))
+	require.NoError(t, os.WriteFile(configFile, []byte("extends: "+parentFile+"\n"+cachedTestConfig), 0644))
+
+	documentCache := cache.NewMemoryCache()
+
+	uncached, err := LoadConfigFromFile(configFile, true)
+	require.NoError(t, err)
+
+	first, err := LoadConfigFromFileWithCache(configFile, true, documentCache)
+	require.NoError(t, err)
+	assert.NotNil(t, documentCache.Get("config:"+configFile))
+	assert.NotNil(t, documentCache.Get("config:"+parentFile))
+
+	second, err := LoadConfigFromFileWithCache(configFile, true, documentCache)
+	require.NoError(t, err)
+
+	for _, loaded := range []*Config{first, second} {
+		assert.Equal(t, uncached.Colors, loaded.Colors)
+		assert.Equal(t, map[string]string{"$primary": "blue", "$secondary": "red"}, loaded.Colors)
+		promptYaml, err := yaml.Marshal(loaded.Prompt)
+		require.NoError(t, err)
+		uncachedYaml, err := yaml.Marshal(uncached.Prompt)
+		require.NoError(t, err)
+		assert.Equal(t, string(uncachedYaml), string(promptYaml))
+	}
+
+	// Changing the file should throw away the cached copy.
+	require.NoError(t, os.WriteFile(configFile, []byte("colors:\n  $primary: green\nprompt:\n  type: prompt\n"), 0644))
+	changed, err := LoadConfigFromFileWithCache(configFile, true, documentCache)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"$primary": "green"}, changed.Colors)
+}
+
+func TestLoadDefaultConfigWithCache(t *testing.T) {
+	documentCache := cache.NewMemoryCache()
+
+	first, err := LoadDefaultConfigWithCache(documentCache)
+	require.NoError(t, err)
+	assert.NotNil(t, documentCache.Get("config:default"))
+
+	second, err := LoadDefaultConfigWithCache(documentCache)
+	require.NoError(t, err)
+	assert.Equal(t, first.Colors, second.Colors)
+	assert.Equal(t, first.Prompt.String(), second.Prompt.String())
+}
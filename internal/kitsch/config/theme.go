@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Theme is a file which overrides the variables in a configuration, so the
+// look of the prompt can be changed without changing any modules.
+type Theme struct {
+	// Variables replace the variables with the same name in the configuration.
+	Variables map[string]string `yaml:"variables"`
+}
+
+// ThemeFile returns the path to the given theme.  `theme` can be a path to a
+// file, or the name of a theme in `themesDir`.
+func ThemeFile(theme string, themesDir string) string {
+	if strings.HasPrefix(theme, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, theme[2:])
+		}
+	}
+
+	extension := filepath.Ext(theme)
+	if strings.ContainsAny(theme, `/\`) || extension == ".yaml" || extension == ".yml" {
+		return theme
+	}
+	return filepath.Join(themesDir, theme+".yaml")
+}
+
+// ApplyTheme loads the given theme and replaces any variables in this
+// configuration with the ones from the theme.  See ThemeFile for how `theme`
+// is found.
+func (c *Config) ApplyTheme(theme string, themesDir string) error {
+	if theme == "" {
+		return nil
+	}
+
+	file := ThemeFile(theme, themesDir)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("could not load theme %s: %w", theme, err)
+	}
+
+	var loaded Theme
+	document, err := parseYaml(data)
+	if err == nil {
+		err = checkKnownFields(document, reflect.TypeOf(loaded))
+	}
+	if err == nil {
+		err = document.Decode(&loaded)
+	}
+	if err != nil {
+		return fmt.Errorf("error in theme %s: %w", file, err)
+	}
+
+	c.Variables = overrideStrings(c.Variables, loaded.Variables)
+	return nil
+}
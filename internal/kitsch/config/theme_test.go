@@ -0,0 +1,76 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var themeTestConfig = heredoc.Doc(`
+	colors:
+	  $accent: blue
+	variables:
+	  $accent: cyan
+	  $muted: brightBlack
+	prompt:
+	  type: prompt
+	  style: $accent
+`)
+
+func TestThemeFile(t *testing.T) {
+	themesDir := filepath.Join("config", "themes")
+	assert.Equal(t, filepath.Join(themesDir, "solarized.yaml"), ThemeFile("solarized", themesDir))
+	assert.Equal(t, "./solarized.yaml", ThemeFile("./solarized.yaml", themesDir))
+	assert.Equal(t, "solarized.yml", ThemeFile("solarized.yml", themesDir))
+}
+
+func TestApplyTheme(t *testing.T) {
+	themesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(themesDir, "light.yaml"),
+		[]byte("variables:\n  $accent: blue\n  $icon: \">\"\n"),
+		0644,
+	))
+
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(themeTestConfig), true))
+	require.NoError(t, config.ApplyTheme("light", themesDir))
+
+	assert.Equal(t, map[string]string{
+		"$accent": "blue",
+		"$muted":  "brightBlack",
+		"$icon":   ">",
+	}, config.Variables)
+
+	// No theme is fine.
+	require.NoError(t, config.ApplyTheme("", themesDir))
+}
+
+func TestApplyThemeErrors(t *testing.T) {
+	themesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(themesDir, "bad.yaml"), []byte("colors:\n  $accent: blue\n"), 0644))
+
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(themeTestConfig), true))
+
+	assert.Error(t, config.ApplyTheme("missing", themesDir))
+	err := config.ApplyTheme("bad", themesDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field colors not found")
+}
+
+func TestVariablesOverrideColors(t *testing.T) {
+	config := newConfig()
+	require.NoError(t, config.LoadFromYaml([]byte(themeTestConfig), true))
+
+	assert.Equal(t,
+		map[string]string{"$accent": "cyan", "$muted": "brightBlack"},
+		config.ColorsForBackground(""),
+	)
+	// Colors should not be modified.
+	assert.Equal(t, map[string]string{"$accent": "blue"}, config.Colors)
+}
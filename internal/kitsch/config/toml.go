@@ -0,0 +1,117 @@
+package config
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// isTomlFile returns true if the given configuration file should be parsed as TOML.
+func isTomlFile(configFile string) bool {
+	return strings.EqualFold(filepath.Ext(configFile), ".toml")
+}
+
+// tomlToYaml converts a TOML configuration file into the equivalent YAML, so
+// TOML files can be loaded and validated exactly the same way YAML files are.
+// Keys are written in the same order they appear in the TOML file.
+func tomlToYaml(tomlData []byte) ([]byte, error) {
+	var data map[string]interface{}
+	meta, err := toml.Decode(string(tomlData), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the order each key first appears in, so we can preserve it.
+	keyOrder := map[string]int{}
+	for index, key := range meta.Keys() {
+		path := strings.Join(key, "\x00")
+		if _, exists := keyOrder[path]; !exists {
+			keyOrder[path] = index
+		}
+	}
+
+	converter := tomlConverter{keyOrder: keyOrder}
+	node, err := converter.toNode(nil, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(node)
+}
+
+type tomlConverter struct {
+	keyOrder map[string]int
+}
+
+func (converter tomlConverter) toNode(path []string, value interface{}) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return converter.mapToNode(path, v)
+	case []map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			child, err := converter.mapToNode(path, item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			child, err := converter.toNode(path, item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
+
+func (converter tomlConverter) mapToNode(path []string, value map[string]interface{}) (*yaml.Node, error) {
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		orderI, okI := converter.order(path, keys[i])
+		orderJ, okJ := converter.order(path, keys[j])
+		if okI && okJ && orderI != orderJ {
+			return orderI < orderJ
+		}
+		if okI != okJ {
+			return okI
+		}
+		return keys[i] < keys[j]
+	})
+
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		childPath := append(append([]string{}, path...), key)
+		child, err := converter.toNode(childPath, value[key])
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			child,
+		)
+	}
+	return node, nil
+}
+
+func (converter tomlConverter) order(path []string, key string) (int, bool) {
+	order, ok := converter.keyOrder[strings.Join(append(append([]string{}, path...), key), "\x00")]
+	return order, ok
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tomlTestConfig = `
+timeout = 250
+
+[colors]
+"$fg" = "#ffffff"
+
+[prompt]
+type = "block"
+join = "-"
+
+[[prompt.modules]]
+type = "text"
+text = "hello"
+style = "blue"
+
+[[prompt.modules]]
+type = "directory"
+
+[prompt.modules.conditions]
+ifFiles = ["go.mod"]
+`
+
+func TestTomlToYaml(t *testing.T) {
+	yamlData, err := tomlToYaml([]byte(tomlTestConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"timeout: 250\n"+
+			"colors:\n"+
+			"    $fg: '#ffffff'\n"+
+			"prompt:\n"+
+			"    type: block\n"+
+			"    join: '-'\n"+
+			"    modules:\n"+
+			"        - type: text\n"+
+			"          text: hello\n"+
+			"          style: blue\n"+
+			"        - type: directory\n"+
+			"          conditions:\n"+
+			"            ifFiles:\n"+
+			"                - go.mod\n",
+		string(yamlData),
+	)
+}
+
+func TestLoadTomlConfig(t *testing.T) {
+	tomlConfig := newConfig()
+	err := tomlConfig.LoadFromToml([]byte(tomlTestConfig), true)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(250), tomlConfig.Timeout)
+	assert.Equal(t, map[string]string{"$fg": "#ffffff"}, tomlConfig.Colors)
+	assert.IsType(t, &modules.BlockModule{}, tomlConfig.Prompt.Module)
+}
+
+func TestLoadTomlConfigStrict(t *testing.T) {
+	tomlConfig := newConfig()
+	err := tomlConfig.LoadFromToml([]byte("notAField = 7\n[prompt]\ntype = \"text\"\n"), true)
+	assert.Error(t, err)
+
+	err = tomlConfig.LoadFromToml([]byte("timeout = \n"), true)
+	assert.Error(t, err)
+}
+
+func TestValidateTomlConfigurationFile(t *testing.T) {
+	dir := t.TempDir()
+
+	configFile := filepath.Join(dir, "kitsch.toml")
+	require.NoError(t, os.WriteFile(configFile, []byte(tomlTestConfig), 0644))
+	assert.NoError(t, ValidateConfigurationFile(configFile))
+
+	config, err := LoadConfigFromFile(configFile, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), config.Timeout)
+
+	badFile := filepath.Join(dir, "bad.toml")
+	require.NoError(t, os.WriteFile(badFile, []byte("[prompt]\ntype = \"text\"\nstyle = 7\ntext = []\n"), 0644))
+	assert.Error(t, ValidateConfigurationFile(badFile))
+}
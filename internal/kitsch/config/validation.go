@@ -3,6 +3,7 @@ package config
 import (
 	"bytes"
 	"encoding/json"
+	"os"
 	"strings"
 
 	// For baseSchema.
@@ -14,6 +15,7 @@ import (
 	"github.com/jwalton/kitsch/internal/kitsch/modules"
 	"github.com/jwalton/kitsch/internal/kitsch/projects"
 	"github.com/jwalton/kitsch/internal/kitsch/schemautils"
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed jsonschema.json
@@ -55,6 +57,25 @@ func JSONSchema() string {
 	return string(result)
 }
 
+// ValidateConfigurationFile reads and validates the given configuration file.
+// Files with a ".toml" extension are read as TOML, and all other files are
+// read as YAML.
+func ValidateConfigurationFile(configFile string) error {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+
+	if isTomlFile(configFile) {
+		data, err = tomlToYaml(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ValidateConfiguration(data)
+}
+
 // ValidateConfiguration validates the configuration file.
 func ValidateConfiguration(yamlData []byte) error {
 	// First try to load the configuration file.
@@ -70,7 +91,16 @@ func ValidateConfiguration(yamlData []byte) error {
 	// Validate the configuration file against the JSON schema.  This
 	// will catch any errors in the configuration file, but tends to have
 	// not-so-pretty error messages.
-	err = schemautils.ValidateYamlAgainstSchema(yamlData, JSONSchema())
+	var document yaml.Node
+	err = yaml.Unmarshal(yamlData, &document)
+	if err != nil {
+		return err
+	}
+	err = expandDefinitions(&document)
+	if err != nil {
+		return err
+	}
+	err = schemautils.ValidateYamlNodeAgainstSchema(&document, JSONSchema())
 	if err != nil {
 		return err
 	}
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"encoding/json"
 	"testing"
 
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
 	"github.com/jwalton/kitsch/sampleconfig"
 	"github.com/stretchr/testify/assert"
 )
@@ -48,3 +50,19 @@ func TestValidateBuiltInConfigs(t *testing.T) {
 	err := ValidateConfiguration(sampleconfig.DefaultConfig)
 	assert.Nil(t, err)
 }
+
+func TestJSONSchemaIncludesAllModules(t *testing.T) {
+	var schema struct {
+		Definitions map[string]interface{} `json:"definitions"`
+	}
+	err := json.Unmarshal([]byte(JSONSchema()), &schema)
+	assert.Nil(t, err)
+
+	moduleDefinition, err := json.Marshal(schema.Definitions["module"])
+	assert.Nil(t, err)
+
+	for _, moduleType := range modules.RegisteredModuleTypes() {
+		assert.Contains(t, schema.Definitions, moduleType)
+		assert.Contains(t, string(moduleDefinition), `"#/definitions/`+moduleType+`"`)
+	}
+}
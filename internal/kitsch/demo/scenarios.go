@@ -0,0 +1,167 @@
+// Package demo contains canned scenarios that can be used to show what a
+// prompt looks like in different situations, without having to set each
+// situation up by hand.
+//
+package demo
+
+import (
+	"time"
+
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+)
+
+// Scenario is a situation to render the prompt in.
+type Scenario struct {
+	// Name is the name of the scenario.
+	Name string
+	// Description is a short description of the scenario.
+	Description string
+	// Config is the demo context to render the prompt with.
+	Config modules.DemoConfig
+}
+
+const projectDir = "/users/jwalton/dev/kitsch"
+
+// headCommitTime is used as the time of the commit at HEAD in all scenarios,
+// so output doesn't change from run to run.
+var headCommitTime = time.Date(2022, time.January, 20, 19, 3, 12, 0, time.UTC)
+
+// Scenarios returns the list of all available scenarios.
+func Scenarios() []Scenario {
+	return []Scenario{
+		{
+			Name:        "home",
+			Description: "Home directory, after a successful command",
+			Config:      modules.NewDemoConfig(),
+		},
+		{
+			Name:        "clean-repo",
+			Description: "Clean git repo, in sync with upstream",
+			Config: withGit(gitutils.DemoGit{
+				RepoRootDirectory:     projectDir,
+				HeadDescription:       "main",
+				CurrentBranchUpstream: "origin/main",
+				HeadCommitTime:        headCommitTime,
+			}),
+		},
+		{
+			Name:        "dirty-repo",
+			Description: "Git repo in the middle of a merge, with conflicts and local changes",
+			Config: withGit(gitutils.DemoGit{
+				RepoRootDirectory:     projectDir,
+				HeadDescription:       "feature/widgets",
+				CurrentBranchUpstream: "origin/feature/widgets",
+				HeadCommitTime:        headCommitTime,
+				CurrentState:          gitutils.StateMerging,
+				StashCount:            2,
+				Ahead:                 3,
+				Behind:                1,
+				CurrentStats: gitutils.GitStats{
+					Index:    gitutils.GitFileStats{Added: 1, Modified: 2},
+					Unstaged: gitutils.GitFileStats{Modified: 4, Deleted: 1},
+					Unmerged: 2,
+					Conflicts: gitutils.GitConflictStats{
+						BothModified: 2,
+					},
+				},
+			}),
+		},
+		{
+			Name:        "rebasing",
+			Description: "Git repo in the middle of an interactive rebase",
+			Config: withFakeRepo(gitutils.NewFakeRepo().
+				Branch("main", "5d4b3f1c9e0a7b2c8d6e4f1a3b5c7d9e0f2a4b6c").
+				Branch("feature/widgets", "9f139fbbde7200508adecc1b9adad67e99204ae3").
+				Detach("5d4b3f1c9e0a7b2c8d6e4f1a3b5c7d9e0f2a4b6c").
+				Rebasing("feature/widgets", true, 2, 5).
+				Stashes(1),
+			),
+		},
+		{
+			Name:        "detached-tag",
+			Description: "Git repo with a release tag checked out",
+			Config: withFakeRepo(gitutils.NewFakeRepo().
+				PackedRef("refs/heads/main", "5d4b3f1c9e0a7b2c8d6e4f1a3b5c7d9e0f2a4b6c").
+				PackedRef("refs/tags/v1.2.0", "b0592fb675bd471541928aa8c9900ba76f748ac8").
+				Detach("b0592fb675bd471541928aa8c9900ba76f748ac8"),
+			),
+		},
+		{
+			Name:        "ssh-root",
+			Description: "Logged in as root over SSH",
+			Config:      sshRoot(),
+		},
+		{
+			Name:        "failed-command",
+			Description: "The previous command failed",
+			Config:      withGlobals(func(globals *modules.Globals) { globals.Status = 1 }),
+		},
+		{
+			Name:        "slow-command",
+			Description: "The previous command took a long time, and there are background jobs",
+			Config: withGlobals(func(globals *modules.Globals) {
+				globals.PreviousCommandDuration = 83500
+				globals.Jobs = 2
+			}),
+		},
+		{
+			Name:        "node-project",
+			Description: "A node.js project",
+			Config:      nodeProject(),
+		},
+	}
+}
+
+// FindScenario returns the scenario with the given name.
+func FindScenario(name string) (Scenario, bool) {
+	for _, scenario := range Scenarios() {
+		if scenario.Name == name {
+			return scenario, true
+		}
+	}
+	return Scenario{}, false
+}
+
+func withGlobals(update func(globals *modules.Globals)) modules.DemoConfig {
+	config := modules.NewDemoConfig()
+	update(&config.Globals)
+	return config
+}
+
+func withGit(git gitutils.DemoGit) modules.DemoConfig {
+	config := modules.NewDemoConfig()
+	config.Globals.CWD = git.RepoRootDirectory
+	config.Git = git
+	return config
+}
+
+// withFakeRepo returns a config for a git repo whose ".git" folder is built
+// by `repo`.  Unlike withGit, this exercises the real git code.
+func withFakeRepo(repo *gitutils.FakeRepo) modules.DemoConfig {
+	config := modules.NewDemoConfig()
+	config.Globals.CWD = projectDir
+	config.GitFiles = repo.Files()
+	return config
+}
+
+func sshRoot() modules.DemoConfig {
+	config := modules.NewDemoConfig()
+	config.Globals.CWD = "/etc/nginx"
+	config.Globals.Home = "/root"
+	config.Globals.IsRoot = true
+	config.Globals.SetHostname("webserver")
+	config.Env["USER"] = "root"
+	config.Env["SSH_CONNECTION"] = "10.0.0.2 52710 10.0.0.3 22"
+	return config
+}
+
+func nodeProject() modules.DemoConfig {
+	config := modules.NewDemoConfig()
+	config.Globals.CWD = "/users/jwalton/dev/widgets"
+	config.Files = map[string]string{
+		"package.json": `{ "name": "widgets", "version": "1.2.3" }`,
+		"index.js":     "",
+	}
+	return config
+}
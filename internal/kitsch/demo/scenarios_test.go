@@ -0,0 +1,41 @@
+package demo
+
+import (
+	"testing"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderScenario(t *testing.T, name string) string {
+	scenario, ok := FindScenario(name)
+	require.True(t, ok)
+
+	configuration, err := config.LoadDefaultConfig()
+	require.NoError(t, err)
+
+	context := modules.NewDemoContext(scenario.Config, &styling.Registry{})
+	_, prompt := modules.RenderPrompt(&context, configuration.Prompt)
+	return prompt
+}
+
+func TestScenarios(t *testing.T) {
+	gchalk.SetLevel(gchalk.LevelNone)
+
+	assert.Contains(t, renderScenario(t, "home"), "[~]")
+	assert.Contains(t, renderScenario(t, "clean-repo"), "[main")
+	assert.Contains(t, renderScenario(t, "dirty-repo"), "MERGING")
+	assert.Contains(t, renderScenario(t, "rebasing"), "REBASE-i 2/5")
+	assert.Contains(t, renderScenario(t, "detached-tag"), "(v1.2.0)")
+	assert.Contains(t, renderScenario(t, "ssh-root"), "root@webserver")
+	assert.Contains(t, renderScenario(t, "slow-command"), "1m24s")
+}
+
+func TestFindScenario(t *testing.T) {
+	_, ok := FindScenario("not-a-scenario")
+	assert.False(t, ok)
+}
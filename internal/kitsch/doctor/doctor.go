@@ -0,0 +1,185 @@
+// Package doctor checks for common problems that stop kitsch from rendering
+// the prompt correctly, and suggests how to fix them.
+//
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jwalton/go-supportscolor"
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/initscripts"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	// StatusOK means the check passed.
+	StatusOK Status = iota
+	// StatusInfo means there's something the user needs to check for
+	// themselves.
+	StatusInfo
+	// StatusWarning means something isn't set up ideally, but kitsch will
+	// still work.
+	StatusWarning
+	// StatusProblem means something is broken.
+	StatusProblem
+)
+
+// Result is the result of a single check.
+type Result struct {
+	// Name is a short name for the check.
+	Name string
+	// Status is the outcome of the check.
+	Status Status
+	// Message describes what we found.
+	Message string
+	// Fix describes how to fix the problem, or "" if there's nothing to fix.
+	Fix string
+}
+
+// Options is the environment to run the checks in.
+type Options struct {
+	// ProgramName is the name of the kitsch executable.
+	ProgramName string
+	// Shell is the type of shell the user is running, or "" if unknown.
+	Shell string
+	// ConfigFile is the configuration file to check, or "" if the user is
+	// using the default configuration.
+	ConfigFile string
+	// HomeDir is the user's home directory.
+	HomeDir string
+	// ColorLevel is the color level supported by the terminal.
+	ColorLevel supportscolor.ColorLevel
+	// Getenv returns the value of an environment variable.
+	Getenv func(string) string
+	// LookPath finds an executable on the PATH.
+	LookPath func(string) (string, error)
+}
+
+// NerdFontGlyphs is a sample of glyphs from a Nerd Font, to show the user so
+// they can check that their font is set up correctly.
+const NerdFontGlyphs = "\ue0a0 \uf07c \ue718 \ue626 \uf418"
+
+// Run runs all checks.
+func Run(options Options) []Result {
+	return []Result{
+		checkConfig(options),
+		checkShellInit(options),
+		checkGit(options),
+		checkColors(options),
+		checkFont(options),
+	}
+}
+
+func checkConfig(options Options) Result {
+	result := Result{Name: "Configuration"}
+
+	if options.ConfigFile == "" {
+		result.Status = StatusOK
+		result.Message = "Using the built-in default configuration."
+		return result
+	}
+
+	errs, err := config.CheckConfigurationFile(options.ConfigFile)
+	if err != nil {
+		result.Status = StatusProblem
+		result.Message = fmt.Sprintf("Could not read %s: %v", options.ConfigFile, err)
+		result.Fix = "Make sure the file exists and is readable, or pass a different file with --config."
+	} else if len(errs) > 0 {
+		result.Status = StatusProblem
+		result.Message = fmt.Sprintf("Found %d error(s) in %s.", len(errs), options.ConfigFile)
+		result.Fix = fmt.Sprintf("Run \"%s check %s\" to see the errors.", options.ProgramName, options.ConfigFile)
+	} else {
+		result.Status = StatusOK
+		result.Message = options.ConfigFile + " has no errors."
+	}
+	return result
+}
+
+func checkShellInit(options Options) Result {
+	result := Result{Name: "Shell integration"}
+
+	if options.Shell == "" {
+		result.Status = StatusWarning
+		result.Message = "Could not work out which shell you are using."
+		result.Fix = fmt.Sprintf("Run \"%s setup [shell]\" for instructions on how to set up your shell.", options.ProgramName)
+		return result
+	}
+
+	// Every init script sets KITSCH_SESSION_KEY.
+	if options.Getenv("KITSCH_SESSION_KEY") != "" {
+		result.Status = StatusOK
+		result.Message = fmt.Sprintf("%s is running in this %s shell.", options.ProgramName, options.Shell)
+		return result
+	}
+
+	configFiles := initscripts.ShellConfigFiles(options.Shell)
+	for _, configFile := range configFiles {
+		configFile = strings.Replace(configFile, "~", options.HomeDir, 1)
+		contents, err := os.ReadFile(filepath.FromSlash(configFile))
+		if err == nil && strings.Contains(string(contents), options.ProgramName+" init") {
+			result.Status = StatusWarning
+			result.Message = fmt.Sprintf("%s is set up in %s, but isn't running in this shell.", options.ProgramName, configFile)
+			result.Fix = "Open a new shell, or make sure nothing later in " + configFile + " replaces the prompt."
+			return result
+		}
+	}
+
+	result.Status = StatusProblem
+	result.Message = fmt.Sprintf("%s isn't set up for %s.", options.ProgramName, options.Shell)
+	result.Fix = fmt.Sprintf("Run \"%s setup %s\" for instructions.", options.ProgramName, options.Shell)
+	return result
+}
+
+func checkGit(options Options) Result {
+	result := Result{Name: "git"}
+
+	path, err := options.LookPath("git")
+	if err != nil {
+		result.Status = StatusWarning
+		result.Message = "git was not found on the PATH, so git information won't be shown in the prompt."
+		result.Fix = "Install git from https://git-scm.com/downloads."
+		return result
+	}
+
+	result.Status = StatusOK
+	result.Message = "Found git at " + path + "."
+	return result
+}
+
+func checkColors(options Options) Result {
+	result := Result{Name: "Colors"}
+
+	switch options.ColorLevel {
+	case supportscolor.Ansi16m:
+		result.Status = StatusOK
+		result.Message = "Your terminal supports 16 million colors."
+	case supportscolor.Ansi256:
+		result.Status = StatusOK
+		result.Message = "Your terminal supports 256 colors.  Hex colors will be shown as the closest of these."
+		result.Fix = "If your terminal supports true color, set COLORTERM=truecolor."
+	case supportscolor.Basic:
+		result.Status = StatusWarning
+		result.Message = "Your terminal only supports 16 colors, so most colors will be approximated."
+		result.Fix = "If your terminal supports more colors, set COLORTERM=truecolor, or set TERM to something like xterm-256color."
+	default:
+		result.Status = StatusWarning
+		result.Message = "Color is disabled, so your prompt will be shown without any styles."
+		result.Fix = "Make sure NO_COLOR and FORCE_COLOR=0 aren't set, and that TERM isn't \"dumb\"."
+	}
+	return result
+}
+
+func checkFont(options Options) Result {
+	return Result{
+		Name:    "Font",
+		Status:  StatusInfo,
+		Message: "These should look like icons: " + NerdFontGlyphs,
+		Fix:     "If you see boxes or question marks, install a Nerd Font from https://www.nerdfonts.com/ and select it in your terminal, or use a configuration that doesn't use icons.",
+	}
+}
@@ -0,0 +1,80 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwalton/go-supportscolor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testOptions(t *testing.T) Options {
+	return Options{
+		ProgramName: "kitsch",
+		Shell:       "zsh",
+		HomeDir:     t.TempDir(),
+		ColorLevel:  supportscolor.Ansi16m,
+		Getenv:      func(string) string { return "" },
+		LookPath:    func(string) (string, error) { return "/usr/bin/git", nil },
+	}
+}
+
+func TestCheckShellInit(t *testing.T) {
+	options := testOptions(t)
+
+	result := checkShellInit(options)
+	assert.Equal(t, StatusProblem, result.Status)
+	assert.Equal(t, `Run "kitsch setup zsh" for instructions.`, result.Fix)
+
+	err := os.WriteFile(filepath.Join(options.HomeDir, ".zshrc"), []byte(`eval "$(kitsch init zsh)"`), 0600)
+	require.NoError(t, err)
+	result = checkShellInit(options)
+	assert.Equal(t, StatusWarning, result.Status)
+
+	options.Getenv = func(name string) string {
+		if name == "KITSCH_SESSION_KEY" {
+			return "1234"
+		}
+		return ""
+	}
+	result = checkShellInit(options)
+	assert.Equal(t, StatusOK, result.Status)
+}
+
+func TestCheckConfig(t *testing.T) {
+	options := testOptions(t)
+	options.ConfigFile = filepath.Join(options.HomeDir, "kitsch.yaml")
+
+	result := checkConfig(options)
+	assert.Equal(t, StatusProblem, result.Status)
+
+	err := os.WriteFile(options.ConfigFile, []byte("prompt:\n  type: nope\n"), 0600)
+	require.NoError(t, err)
+	result = checkConfig(options)
+	assert.Equal(t, StatusProblem, result.Status)
+	assert.Contains(t, result.Fix, "kitsch check")
+
+	err = os.WriteFile(options.ConfigFile, []byte("prompt:\n  type: prompt\n"), 0600)
+	require.NoError(t, err)
+	result = checkConfig(options)
+	assert.Equal(t, StatusOK, result.Status)
+}
+
+func TestCheckGit(t *testing.T) {
+	options := testOptions(t)
+	assert.Equal(t, StatusOK, checkGit(options).Status)
+
+	options.LookPath = func(string) (string, error) { return "", errors.New("not found") }
+	assert.Equal(t, StatusWarning, checkGit(options).Status)
+}
+
+func TestCheckColors(t *testing.T) {
+	options := testOptions(t)
+	assert.Equal(t, StatusOK, checkColors(options).Status)
+
+	options.ColorLevel = supportscolor.None
+	assert.Equal(t, StatusWarning, checkColors(options).Status)
+}
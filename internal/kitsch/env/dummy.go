@@ -1,9 +1,25 @@
 package env
 
+import (
+	"os/exec"
+	"runtime"
+
+	"github.com/jwalton/gchalk"
+)
+
 // DummyEnv is a dummy environment for use in unit testing.
 type DummyEnv struct {
 	// Env contains the environment variables for this dummy environment.
 	Env map[string]string
+	// Executables is a map of executable names to the path LookPath should
+	// return for each.  Any executable not in this map will not be found.
+	Executables map[string]string
+	// Level is the color level of the terminal.
+	Level gchalk.ColorLevel
+	// GOOS is the operating system to report.  If empty, runtime.GOOS is used.
+	GOOS string
+	// GOARCH is the architecture to report.  If empty, runtime.GOARCH is used.
+	GOARCH string
 }
 
 // Getenv returns the value of the specified environment variable.
@@ -25,3 +41,33 @@ func (env DummyEnv) HasSomeEnv(keys ...string) bool {
 	}
 	return false
 }
+
+// LookPath returns the path set for the executable in `env.Executables`.
+func (env DummyEnv) LookPath(file string) (string, error) {
+	path, ok := env.Executables[file]
+	if !ok {
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+	return path, nil
+}
+
+// ColorLevel returns `env.Level`.
+func (env DummyEnv) ColorLevel() gchalk.ColorLevel {
+	return env.Level
+}
+
+// OS returns `env.GOOS`, or runtime.GOOS if it is not set.
+func (env DummyEnv) OS() string {
+	if env.GOOS != "" {
+		return env.GOOS
+	}
+	return runtime.GOOS
+}
+
+// Arch returns `env.GOARCH`, or runtime.GOARCH if it is not set.
+func (env DummyEnv) Arch() string {
+	if env.GOARCH != "" {
+		return env.GOARCH
+	}
+	return runtime.GOARCH
+}
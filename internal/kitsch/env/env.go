@@ -1,11 +1,18 @@
-// Package env provides an interface to environment variables.
+// Package env provides an interface to the environment kitsch is running in -
+// environment variables, executables on the PATH, the terminal, and the
+// operating system.
 package env
 
 import (
 	"os"
+	"runtime"
+	"sync"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/fileutils"
 )
 
-// Env is an interface to environment variables which can be overridden for testing.
+// Env is an interface to the environment which can be overridden for testing.
 type Env interface {
 	// Getenv returns the value of the specified environment variable, or an empty
 	// string if the variable does not exist.
@@ -17,20 +24,40 @@ type Env interface {
 	//
 	// would return true if this is an SSH session.
 	HasSomeEnv(...string) bool
+	// LookPath returns the path to the specified executable on the PATH.  Like
+	// exec.LookPath, but will never find an executable in the current
+	// directory.
+	LookPath(file string) (string, error)
+	// ColorLevel returns the level of color the terminal supports.
+	ColorLevel() gchalk.ColorLevel
+	// OS returns the operating system we're running on, using Go's names for
+	// operating systems (e.g. "linux", "darwin", "windows").
+	OS() string
+	// Arch returns the architecture we're running on, using Go's names for
+	// architectures (e.g. "amd64", "arm64").
+	Arch() string
+}
+
+type lookPathResult struct {
+	path string
+	err  error
 }
 
-type defaultEnv struct{}
+type defaultEnv struct {
+	mutex    sync.Mutex
+	lookPath map[string]lookPathResult
+}
 
 // New creates a new instance of Env.
 func New() Env {
-	return defaultEnv{}
+	return &defaultEnv{lookPath: map[string]lookPathResult{}}
 }
 
-func (defaultEnv) Getenv(key string) string {
+func (*defaultEnv) Getenv(key string) string {
 	return os.Getenv(key)
 }
 
-func (defaultEnv) HasSomeEnv(keys ...string) bool {
+func (*defaultEnv) HasSomeEnv(keys ...string) bool {
 	for _, key := range keys {
 		if os.Getenv(key) != "" {
 			return true
@@ -38,3 +65,29 @@ func (defaultEnv) HasSomeEnv(keys ...string) bool {
 	}
 	return false
 }
+
+// LookPath returns the path to the specified executable.  The PATH doesn't
+// change while we're rendering a prompt, so results are cached.
+func (env *defaultEnv) LookPath(file string) (string, error) {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+
+	result, ok := env.lookPath[file]
+	if !ok {
+		result.path, result.err = fileutils.LookPathSafe(file)
+		env.lookPath[file] = result
+	}
+	return result.path, result.err
+}
+
+func (*defaultEnv) ColorLevel() gchalk.ColorLevel {
+	return gchalk.GetLevel()
+}
+
+func (*defaultEnv) OS() string {
+	return runtime.GOOS
+}
+
+func (*defaultEnv) Arch() string {
+	return runtime.GOARCH
+}
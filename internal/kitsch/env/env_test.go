@@ -0,0 +1,72 @@
+package env
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/jwalton/gchalk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDummyEnv(t *testing.T) {
+	env := DummyEnv{
+		Env:         map[string]string{"USER": "jwalton"},
+		Executables: map[string]string{"git": "/usr/bin/git"},
+		Level:       gchalk.LevelAnsi256,
+		GOOS:        "plan9",
+	}
+
+	assert.Equal(t, "jwalton", env.Getenv("USER"))
+	assert.True(t, env.HasSomeEnv("HOME", "USER"))
+
+	path, err := env.LookPath("git")
+	assert.NoError(t, err)
+	assert.Equal(t, "/usr/bin/git", path)
+
+	_, err = env.LookPath("hg")
+	assert.True(t, errors.Is(err, exec.ErrNotFound))
+
+	assert.Equal(t, gchalk.LevelAnsi256, env.ColorLevel())
+	assert.Equal(t, "plan9", env.OS())
+	assert.Equal(t, runtime.GOARCH, env.Arch())
+}
+
+func TestRecordingEnv(t *testing.T) {
+	env := NewRecordingEnv(DummyEnv{
+		Env:         map[string]string{"USER": "jwalton"},
+		Executables: map[string]string{"git": "/usr/bin/git"},
+	})
+
+	assert.Equal(t, "jwalton", env.Getenv("USER"))
+	assert.False(t, env.HasSomeEnv("SSH_CLIENT", "SSH_TTY"))
+	path, _ := env.LookPath("git")
+	assert.Equal(t, "/usr/bin/git", path)
+	env.OS()
+
+	assert.Equal(t, []Call{
+		{Method: "Getenv", Args: []string{"USER"}},
+		{Method: "HasSomeEnv", Args: []string{"SSH_CLIENT", "SSH_TTY"}},
+		{Method: "LookPath", Args: []string{"git"}},
+		{Method: "OS", Args: nil},
+	}, env.Calls())
+
+	assert.True(t, env.Called("LookPath"))
+	assert.True(t, env.Called("Getenv", "USER"))
+	assert.False(t, env.Called("Getenv", "HOME"))
+	assert.False(t, env.Called("Arch"))
+}
+
+func TestDefaultEnvLookPathIsCached(t *testing.T) {
+	env := New().(*defaultEnv)
+
+	t.Setenv("PATH", t.TempDir())
+	_, err := env.LookPath("kitsch-not-a-real-command")
+	assert.Error(t, err)
+
+	env.lookPath["kitsch-not-a-real-command"] = lookPathResult{path: "/bin/cached"}
+	path, err := env.LookPath("kitsch-not-a-real-command")
+	assert.NoError(t, err)
+	assert.Equal(t, "/bin/cached", path)
+}
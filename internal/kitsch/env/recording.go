@@ -0,0 +1,107 @@
+package env
+
+import (
+	"sync"
+
+	"github.com/jwalton/gchalk"
+)
+
+// Call is a call made to an Env.
+type Call struct {
+	// Method is the name of the method that was called (e.g. "Getenv").
+	Method string
+	// Args are the arguments passed to the method.
+	Args []string
+}
+
+// RecordingEnv is an Env which records every call made to it, and then
+// forwards the call to another Env.  This is useful in unit tests, to check
+// which environment variables a module reads, or which executables it looks
+// for.
+type RecordingEnv struct {
+	// Env is the Env to forward calls to.
+	Env Env
+
+	mutex sync.Mutex
+	calls []Call
+}
+
+// NewRecordingEnv returns a new RecordingEnv which forwards calls to `env`.
+func NewRecordingEnv(env Env) *RecordingEnv {
+	return &RecordingEnv{Env: env}
+}
+
+func (env *RecordingEnv) record(method string, args ...string) {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+	env.calls = append(env.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every call made to this Env, in order.
+func (env *RecordingEnv) Calls() []Call {
+	env.mutex.Lock()
+	defer env.mutex.Unlock()
+
+	result := make([]Call, len(env.calls))
+	copy(result, env.calls)
+	return result
+}
+
+// Called returns true if `method` was called.  If `args` are given, returns
+// true only if `method` was called with exactly those arguments.
+func (env *RecordingEnv) Called(method string, args ...string) bool {
+	for _, call := range env.Calls() {
+		if call.Method == method && (len(args) == 0 || equalStrings(call.Args, args)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Getenv records the call, and returns the value from the underlying Env.
+func (env *RecordingEnv) Getenv(key string) string {
+	env.record("Getenv", key)
+	return env.Env.Getenv(key)
+}
+
+// HasSomeEnv records the call, and returns the value from the underlying Env.
+func (env *RecordingEnv) HasSomeEnv(keys ...string) bool {
+	env.record("HasSomeEnv", keys...)
+	return env.Env.HasSomeEnv(keys...)
+}
+
+// LookPath records the call, and returns the value from the underlying Env.
+func (env *RecordingEnv) LookPath(file string) (string, error) {
+	env.record("LookPath", file)
+	return env.Env.LookPath(file)
+}
+
+// ColorLevel records the call, and returns the value from the underlying Env.
+func (env *RecordingEnv) ColorLevel() gchalk.ColorLevel {
+	env.record("ColorLevel")
+	return env.Env.ColorLevel()
+}
+
+// OS records the call, and returns the value from the underlying Env.
+func (env *RecordingEnv) OS() string {
+	env.record("OS")
+	return env.Env.OS()
+}
+
+// Arch records the call, and returns the value from the underlying Env.
+func (env *RecordingEnv) Arch() string {
+	env.record("Arch")
+	return env.Env.Arch()
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for index := range a {
+		if a[index] != b[index] {
+			return false
+		}
+	}
+	return true
+}
@@ -38,6 +38,22 @@ const (
 	TypeEnv
 )
 
+// MarshalYAML converts a GetterType into the string used in configuration files.
+func (item GetterType) MarshalYAML() (interface{}, error) {
+	switch item {
+	case TypeCustom:
+		return "custom", nil
+	case TypeFile:
+		return "file", nil
+	case TypeAncestorFile:
+		return "ancestorFile", nil
+	case TypeEnv:
+		return "env", nil
+	default:
+		return nil, fmt.Errorf("unknown GetterType: %d", item)
+	}
+}
+
 // UnmarshalYAML unmarshals a YAML node into a GetterType.
 func (item *GetterType) UnmarshalYAML(node *yaml.Node) error {
 	var value string
@@ -79,6 +95,24 @@ const (
 	AsTOML
 )
 
+// MarshalYAML converts an AsType into the string used in configuration files.
+func (item AsType) MarshalYAML() (interface{}, error) {
+	switch item {
+	case AsUndefined:
+		return nil, nil
+	case AsText:
+		return "text", nil
+	case AsJSON:
+		return "json", nil
+	case AsYAML:
+		return "yaml", nil
+	case AsTOML:
+		return "toml", nil
+	default:
+		return nil, fmt.Errorf("unknown AsType: %d", item)
+	}
+}
+
 // UnmarshalYAML unmarshals a YAML node into an AsType.
 func (item *AsType) UnmarshalYAML(node *yaml.Node) error {
 	var value string
@@ -111,13 +145,13 @@ type CacheSettings struct {
 	// At the moment, this only applied to getters with `Type: "custom"`.  This
 	// makes it so we will cache the output of a command instead of re-running that
 	// command.
-	Enabled bool `yaml:"enabled"`
+	Enabled bool `yaml:"enabled,omitempty"`
 	// Files is the path to one or more files to use as the key for the cache.  Each file's
 	// full path (following any symlinks), size, and last modified time will all
 	// form part of the cache key.  For "custom" getters, the executable is implicitly
 	// used as a file - if this is specified, then both the executable and these
 	// files will be used.
-	Files []string `yaml:"file"`
+	Files []string `yaml:"file,omitempty"`
 }
 
 // CustomGetter is a getter that can be configured from a YAML file.
@@ -126,17 +160,17 @@ type CustomGetter struct {
 	Type GetterType `yaml:"type" jsonschema:",required,enum=custom:file:ancestorFile:env"`
 	// From is the source to get data from.  The meaning of "From" is based on
 	// the provided "Type".
-	From string `yaml:"from"`
+	From string `yaml:"from,omitempty"`
 	// As will determine how to interpret the result of the getter.  One of "text", "json", "toml", or "yaml".
-	As AsType `yaml:"as" jsonschema:",enum=text:json:toml:yaml"`
+	As AsType `yaml:"as,omitempty" jsonschema:",enum=text:json:toml:yaml"`
 	// ValueTemplate is a golang template used to parse values out of the result of
 	// the getter.
-	ValueTemplate string `yaml:"valueTemplate"`
+	ValueTemplate string `yaml:"valueTemplate,omitempty"`
 	// Regex is a regular expression used to parse values out of the result of
 	// the getter.  If specified, then "As" and "Template" will be ignored.
-	Regex string `yaml:"regex"`
+	Regex string `yaml:"regex,omitempty"`
 	// Cache specified cache settings for this getter.
-	Cache CacheSettings `yaml:"cache" jsonschema:",ref"`
+	Cache CacheSettings `yaml:"cache,omitempty" jsonschema:",ref"`
 }
 
 // GetValue gets the value for this getter.  The return value will be either a string,
@@ -302,7 +336,7 @@ func (getter CustomGetter) getCustomValue(
 
 	// Resolve the executable to an absolute path.
 	executable := commandParts[0]
-	executable, err = fileutils.LookPathSafe(executable)
+	executable, err = context.LookPath(executable)
 	if err != nil {
 		return nil, fmt.Errorf("could not find executable: \"%s\": %w", commandParts[0], err)
 	}
@@ -5,6 +5,7 @@ package getters
 import (
 	"github.com/jwalton/kitsch/internal/cache"
 	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/toolversions"
 )
 
 // GetterContext is an interface used by a Getter to retrieve information from
@@ -19,8 +20,16 @@ type GetterContext interface {
 	// Getenv returns the value of the specified environment variable.
 	Getenv(key string) string
 
+	// LookPath returns the path to the specified executable on the PATH.
+	LookPath(file string) (string, error)
+
 	// GetValueCache returns the value cache.
 	GetValueCache() cache.Cache
+
+	// GetToolVersions returns the resolver for tool versions pinned in the
+	// working directory.  The same resolver is returned every time, so
+	// version files are only read once.
+	GetToolVersions() *toolversions.Resolver
 }
 
 // Getter retrieves a text value from the file system or environment.
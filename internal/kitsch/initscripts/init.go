@@ -5,6 +5,7 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/template"
 )
@@ -12,6 +13,14 @@ import (
 //go:embed templates/*init*
 var initTemplates embed.FS
 
+// shellAliases maps alternate names for shells to the name we use for them.
+var shellAliases = map[string]string{
+	// "pwsh" is the name of the PowerShell 6+ executable.
+	"pwsh": "powershell",
+	// On most systems, csh is tcsh.
+	"csh": "tcsh",
+}
+
 func getKitschCommand() string {
 	kitschCommand, err := os.Executable()
 	if err != nil {
@@ -22,27 +31,57 @@ func getKitschCommand() string {
 }
 
 // ShortInitScript returns the kitsch initialization script for the given shell type.
-func ShortInitScript(shell string, configFile string) (string, error) {
-	return getInitScript("init-short", shell, configFile)
+// If profile is not "", the prompt will always be rendered with the given profile.
+func ShortInitScript(shell string, configFile string, profile string) (string, error) {
+	return getInitScript("init-short", shell, configFile, profile, 0)
 }
 
 // InitScript returns the full kitsch initialization script for the given shell type.
-func InitScript(shell string, configFile string) (string, error) {
-	return getInitScript("init", shell, configFile)
+// If notifyThreshold is greater than 0, the script will run `kitsch notify`
+// when a command takes longer than notifyThreshold milliseconds.
+func InitScript(shell string, configFile string, profile string, notifyThreshold int64) (string, error) {
+	return getInitScript("init", shell, configFile, profile, notifyThreshold)
 }
 
-func getInitScript(filename string, shell string, configFile string) (string, error) {
+func getInitScript(filename string, shell string, configFile string, profile string, notifyThreshold int64) (string, error) {
 	kitschCommand := getKitschCommand()
 
+	if alias, ok := shellAliases[shell]; ok {
+		shell = alias
+	}
+
 	shellExt := shell
-	if shell == "powershell" {
+	switch shell {
+	case "powershell":
 		kitschCommand = `"` + kitschCommand + `"`
 		shellExt = "ps1"
+		// These are inserted into single quoted strings.
+		configFile = strings.ReplaceAll(configFile, "'", "''")
+		profile = strings.ReplaceAll(profile, "'", "''")
+	case "elvish":
+		// Elvish, like PowerShell, escapes a single quote inside a single
+		// quoted string by doubling it.
+		kitschCommand = strings.ReplaceAll(kitschCommand, "'", "''")
+		configFile = strings.ReplaceAll(configFile, "'", "''")
+		profile = strings.ReplaceAll(profile, "'", "''")
+	case "cmd":
+		// cmd.exe doesn't have its own init script - this is a Lua script for Clink.
+		shellExt = "lua"
+	case "xonsh":
+		// These are inserted into single quoted Python strings.
+		escaper := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+		kitschCommand = escaper.Replace(kitschCommand)
+		configFile = escaper.Replace(configFile)
+		profile = escaper.Replace(profile)
 	}
 
 	data := map[string]string{
 		"kitschCommand": kitschCommand,
 		"configFile":    configFile,
+		"profile":       profile,
+	}
+	if notifyThreshold > 0 {
+		data["notifyThreshold"] = strconv.FormatInt(notifyThreshold, 10)
 	}
 
 	initTemplate, err := initTemplates.ReadFile("templates/" + shell + "-" + filename + "." + shellExt)
@@ -0,0 +1,101 @@
+package initscripts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowershellInitScript(t *testing.T) {
+	script, err := ShortInitScript("pwsh", "C:\\Users\\o'brien\\kitsch.yaml", "work")
+	require.NoError(t, err)
+	assert.Contains(t, script, `--config 'C:\Users\o''brien\kitsch.yaml' --profile 'work' --print-full-init powershell`)
+
+	script, err = InitScript("powershell", "", "work", 0)
+	require.NoError(t, err)
+	assert.Contains(t, script, "'--profile=work'")
+	assert.NotContains(t, script, "--config")
+}
+
+func TestElvishInitScript(t *testing.T) {
+	script, err := InitScript("elvish", "/home/o'brien/kitsch.yaml", "", 0)
+	require.NoError(t, err)
+	assert.Contains(t, script, "--config '/home/o''brien/kitsch.yaml' --shell elvish")
+	assert.Contains(t, script, "set edit:after-command")
+	assert.Contains(t, script, "set edit:rprompt = { kitsch-prompt --right }")
+}
+
+func TestXonshInitScript(t *testing.T) {
+	script, err := ShortInitScript("xonsh", `C:\kitsch's.yaml`, "")
+	require.NoError(t, err)
+	assert.Contains(t, script, `--config 'C:\\kitsch\'s.yaml' --print-full-init xonsh`)
+}
+
+func TestCmdInitScript(t *testing.T) {
+	script, err := InitScript("cmd", `C:\Users\me\kitsch.yaml`, "", 0)
+	require.NoError(t, err)
+	assert.Contains(t, script, `local kitsch_args = [[ --config "C:\Users\me\kitsch.yaml"]]`)
+	assert.Contains(t, script, "clink.promptfilter")
+}
+
+func TestTcshInitScript(t *testing.T) {
+	script, err := InitScript("csh", "", "", 0)
+	require.NoError(t, err)
+	// The script is eval'd as a single line, so can't contain any comments.
+	assert.NotContains(t, script, "#")
+	assert.Contains(t, script, "--shell tcsh")
+}
+
+func TestInvalidShell(t *testing.T) {
+	_, err := InitScript("nushell", "", "", 0)
+	assert.Error(t, err)
+}
+
+func TestPipeStatus(t *testing.T) {
+	script, err := InitScript("bash", "", "", 0)
+	require.NoError(t, err)
+	assert.Contains(t, script, `--pipestatus="${KITSCH_PIPE_STATUS[*]}"`)
+
+	script, err = InitScript("zsh", "", "", 0)
+	require.NoError(t, err)
+	assert.Contains(t, script, `KITSCH_PIPE_STATUS=(${pipestatus[@]})`)
+	assert.Contains(t, script, `--pipestatus="${KITSCH_PIPE_STATUS[*]}"`)
+}
+
+func TestBackgroundQuery(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh"} {
+		script, err := InitScript(shell, "", "", 0)
+		require.NoError(t, err)
+		// Defined, and called once when the script is loaded, not from precmd.
+		assert.Equal(t, 2, strings.Count(script, "kitsch_query_background"), shell)
+		assert.Contains(t, script, "\nkitsch_query_background\n", shell)
+		// Accept replies terminated by either BEL or ST.
+		assert.Contains(t, script, `$char == $'\a' || $response == *$'\e\\'`, shell)
+	}
+}
+
+func TestPreviousCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "powershell", "elvish"} {
+		script, err := InitScript(shell, "", "", 0)
+		require.NoError(t, err)
+		assert.Contains(t, script, "--previous-command=", shell)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	for _, shell := range ValidShells() {
+		script, err := ShortInitScript(shell, "", "work")
+		require.NoError(t, err, shell)
+		assert.Regexp(t, `--profile\W+work`, script, shell)
+
+		script, err = InitScript(shell, "", "work", 0)
+		require.NoError(t, err, shell)
+		assert.Regexp(t, `--profile\W+work`, script, shell)
+
+		script, err = InitScript(shell, "", "", 0)
+		require.NoError(t, err, shell)
+		assert.NotContains(t, script, "--profile", shell)
+	}
+}
@@ -15,9 +15,34 @@ var code = gchalk.WithBold().BrightWhite
 var shellConfigFiles = map[string]string{
 	"bash":       "~/.bashrc",
 	"zsh":        "~/.zshrc",
+	"elvish":     "~/.config/elvish/rc.elv",
+	"xonsh":      "~/.xonshrc",
+	"tcsh":       "~/.tcshrc",
+	"cmd":        "kitsch.lua in your Clink profile folder (you can find this folder by running `clink info`)",
 	"powershell": "Microsoft.PowerShell_profile.ps1 (you can find the location of this file by running `echo $PROFILE`)",
 }
 
+// ShellConfigFiles returns the files that kitsch might be loaded from for the
+// given shell, or nil if we don't know where the shell's configuration lives.
+func ShellConfigFiles(shell string) []string {
+	if alias, ok := shellAliases[shell]; ok {
+		shell = alias
+	}
+
+	switch shell {
+	case "bash":
+		// macOS terminals start a login shell, which reads .bash_profile.
+		return []string{"~/.bashrc", "~/.bash_profile"}
+	case "cmd", "powershell":
+		return nil
+	}
+
+	if configFile, ok := shellConfigFiles[shell]; ok {
+		return []string{configFile}
+	}
+	return nil
+}
+
 // ValidShells returns a list of valid shell types.
 func ValidShells() []string {
 	result := []string{}
@@ -38,6 +63,9 @@ func ShowSetupInstructions(
 	shellSetupCommand := map[string]string{
 		"bash":       `eval "$(` + programName + ` init bash)"`,
 		"zsh":        `eval "$(` + programName + ` init zsh)"`,
+		"elvish":     `eval (` + programName + ` init elvish | slurp)`,
+		"xonsh":      `execx($(` + programName + ` init xonsh))`,
+		"tcsh":       "eval `" + programName + " init tcsh`",
 		"powershell": `Invoke-Expression (&` + programName + ` init powershell)`,
 	}
 
@@ -50,7 +78,23 @@ func ShowSetupInstructions(
 			if command -v ` + programName + ` > /dev/null; then
 			    eval "$(` + programName + ` init zsh)"
 			fi`),
+		"elvish": heredoc.Doc(`
+			if (has-external ` + programName + `) {
+			    eval (` + programName + ` init elvish | slurp)
+			}`),
+		"xonsh": heredoc.Doc(`
+			if !(which ` + programName + `):
+			    execx($(` + programName + ` init xonsh))`),
+		"tcsh": heredoc.Doc(`
+			if ( { which ` + programName + ` >& /dev/null } ) then
+			    eval ` + "`" + programName + ` init tcsh` + "`" + `
+			endif`),
 		"powershell": `Invoke-Expression (&` + programName + ` init powershell)`,
+		"cmd":        `load(io.popen('` + programName + ` init cmd'):read("*a"))()`,
+	}
+
+	if alias, ok := shellAliases[shell]; ok {
+		shell = alias
 	}
 
 	_, shellSupported := shellConfigFiles[shell]
@@ -67,8 +111,11 @@ func ShowSetupInstructions(
 		os.Exit(1)
 	}
 
-	fmt.Print(text("To try out " + programName + " in the current shell, run:\n\n"))
-	fmt.Print(code("    " + shellSetupCommand[shell] + "\n\n"))
+	// Some shells (i.e. cmd) can only be set up from a file.
+	if setupCommand, ok := shellSetupCommand[shell]; ok {
+		fmt.Print(text("To try out " + programName + " in the current shell, run:\n\n"))
+		fmt.Print(code("    " + setupCommand + "\n\n"))
+	}
 
 	fmt.Print(text(wordWrap(80, "To use "+programName+" by default for future shells, add "+
 		"the following to the end of your "+shellConfigFiles[shell]+":\n\n")))
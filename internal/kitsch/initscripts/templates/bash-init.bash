@@ -24,11 +24,38 @@ kitsch_preexec() {
     if [ "$KITSCH_PREEXEC_READY" = "true" ]; then
         KITSCH_PREEXEC_READY=false
         KITSCH_START_TIME=$({{ .kitschCommand }} time)
+        # Pressing enter on an empty line runs PROMPT_COMMAND, which also
+        # fires the DEBUG trap.
+        if [[ $BASH_COMMAND != kitsch_precmd ]]; then
+            KITSCH_PREVIOUS_COMMAND=$BASH_COMMAND
+        fi
     fi
 
     : "$PREV_LAST_ARG"
 }
 
+# Ask the terminal for its background color with an OSC 11 query, so kitsch can
+# choose between "colors" and "colorsLight".  We only ask once, when the shell
+# starts: asking before every prompt would swallow anything the user typed
+# while a command was running, and would slow down every prompt on terminals
+# that never reply.  Terminals end their reply with either BEL or ST ("\e\\").
+# Set KITSCH_BACKGROUND to "light" or "dark" to skip the query.
+kitsch_query_background() {
+    [[ -n $KITSCH_BACKGROUND || ! -t 0 || ! -t 1 ]] && return
+    local response="" char
+    printf '\e]11;?\a' > /dev/tty
+    while IFS= read -rs -n 1 -t 0.1 char; do
+        response+=$char
+        [[ $char == $'\a' || $response == *$'\e\\' ]] && break
+    done < /dev/tty
+    if [[ $response == *rgb:* ]]; then
+        response=${response#*rgb:}
+        response=${response%$'\a'}
+        export KITSCH_TERMINAL_BG="rgb:${response%$'\e\\'}"
+    fi
+}
+kitsch_query_background
+
 # Will be run before the prompt is drawn
 kitsch_precmd() {
     # Save the status, because commands in this pipeline will change $?
@@ -52,10 +79,15 @@ kitsch_precmd() {
     if [[ $KITSCH_START_TIME ]]; then
         KITSCH_END_TIME=$({{ .kitschCommand }} time)
         KITSCH_DURATION=$((KITSCH_END_TIME - KITSCH_START_TIME))
-        PS1="$({{ .kitschCommand }} prompt {{with .configFile}}--config {{.}} {{end}}--shell bash --terminal-width="$COLUMNS" --status=$KITSCH_CMD_STATUS --jobs="$NUM_JOBS" --cmd-duration=$KITSCH_DURATION)"
+{{- if .notifyThreshold }}
+        if ((KITSCH_DURATION >= {{ .notifyThreshold }})); then
+            {{ .kitschCommand }} notify {{with .configFile}}--config {{.}} {{end}}{{with .profile}}--profile {{.}} {{end}}--status=$KITSCH_CMD_STATUS --cmd-duration=$KITSCH_DURATION
+        fi
+{{- end }}
+        PS1="$({{ .kitschCommand }} prompt {{with .configFile}}--config {{.}} {{end}}{{with .profile}}--profile {{.}} {{end}}--shell bash --terminal-width="$COLUMNS" --status=$KITSCH_CMD_STATUS --pipestatus="${KITSCH_PIPE_STATUS[*]}" --previous-command="$KITSCH_PREVIOUS_COMMAND" --jobs="$NUM_JOBS" --cmd-duration=$KITSCH_DURATION)"
         unset KITSCH_START_TIME
     else
-        PS1="$({{ .kitschCommand }} prompt {{with .configFile}}--config {{.}} {{end}}--shell bash --terminal-width="$COLUMNS" --status=$KITSCH_CMD_STATUS --jobs="$NUM_JOBS")"
+        PS1="$({{ .kitschCommand }} prompt {{with .configFile}}--config {{.}} {{end}}{{with .profile}}--profile {{.}} {{end}}--shell bash --terminal-width="$COLUMNS" --status=$KITSCH_CMD_STATUS --pipestatus="${KITSCH_PIPE_STATUS[*]}" --previous-command="$KITSCH_PREVIOUS_COMMAND" --jobs="$NUM_JOBS")"
     fi
     KITSCH_PREEXEC_READY=true  # Signal that we can safely restart the timer
 }
@@ -63,8 +95,10 @@ kitsch_precmd() {
 # If the user appears to be using https://github.com/rcaloras/bash-preexec,
 # then hook our functions into their framework.
 if [[ "${__bp_imported:-}" == "defined" || $preexec_functions || $precmd_functions ]]; then
-    # bash-preexec needs a single function--wrap the args into a closure and pass
-    kitsch_preexec_all(){ kitsch_preexec "$_"; }
+    # bash-preexec needs a single function--wrap the args into a closure and pass.
+    # bash-preexec passes the full command line as $1, which is more useful than
+    # $BASH_COMMAND, which is only the first command in a pipeline.
+    kitsch_preexec_all(){ kitsch_preexec "$_"; KITSCH_PREVIOUS_COMMAND=$1; }
     preexec_functions+=(kitsch_preexec_all)
     precmd_functions+=(kitsch_precmd)
 else
@@ -0,0 +1 @@
+load(io.popen('"' .. [["{{ .kitschCommand }}" init{{with .configFile}} --config "{{.}}"{{end}}{{with .profile}} --profile "{{.}}"{{end}} --print-full-init cmd]] .. '"'):read("*a"))()
\ No newline at end of file
@@ -0,0 +1,62 @@
+-- Adapted from https://github.com/starship/starship/blob/master/src/init/starship.lua
+-- Copyright (c) 2019-2021, Starship Contributors
+
+-- This script is loaded by Clink (https://chrisant996.github.io/clink/), which
+-- adds a prompt filter API to cmd.exe.
+
+local kitsch_command = [[{{ .kitschCommand }}]]
+local kitsch_args = [[{{with .configFile}} --config "{{.}}"{{end}}{{with .profile}} --profile "{{.}}"{{end}}]]
+
+-- Clink's os.clock() returns the time in seconds since clink was loaded.
+local kitsch_start_time = os.clock()
+local kitsch_duration = nil
+local kitsch_line_empty = true
+
+clink.onendedit(function (line)
+    kitsch_start_time = os.clock()
+    kitsch_line_empty = string.match(line, "^%s*$") ~= nil
+end)
+
+clink.onbeginedit(function ()
+    -- Only report the duration once, so hitting enter on an empty line doesn't
+    -- show the same duration again.
+    if kitsch_line_empty then
+        kitsch_duration = nil
+    else
+        kitsch_duration = math.floor((os.clock() - kitsch_start_time) * 1000)
+    end
+end)
+
+-- Set up the session key that will be used to store logs
+math.randomseed(os.time())
+os.setenv("KITSCH_SESSION_KEY", string.format("%016d", math.random(0, 9999999999999999)))
+
+-- Disable virtualenv prompt, it breaks kitsch
+os.setenv("VIRTUAL_ENV_DISABLE_PROMPT", "1")
+
+local kitsch_prompt = clink.promptfilter(5)
+
+function kitsch_prompt:filter(prompt)
+    local keymap = rl.getvariable("keymap")
+    if keymap == "vi-command" then
+        keymap = "vicmd"
+    end
+
+    local command = '"' .. kitsch_command .. '" prompt' .. kitsch_args ..
+        " --shell cmd" ..
+        " --status=" .. os.geterrorlevel() ..
+        " --terminal-width=" .. console.getwidth() ..
+        " --keymap=" .. (keymap or "")
+    if kitsch_duration ~= nil then
+        command = command .. " --cmd-duration=" .. kitsch_duration
+    end
+
+    -- cmd.exe strips the first and last quote from the command line if there
+    -- are more than two, so wrap the whole command in an extra set of quotes.
+    local handle = io.popen('"' .. command .. '"')
+    local result = handle:read("*a")
+    handle:close()
+
+    -- Returning false stops lower priority prompt filters from running.
+    return result, false
+end
@@ -1 +1 @@
-Invoke-Expression (@(&{{ .kitschCommand }} init powershell --print-full-init) -join "`n")
\ No newline at end of file
+Invoke-Expression (@(&{{ .kitschCommand }} init {{with .configFile}}--config '{{.}}' {{end}}{{with .profile}}--profile '{{.}}' {{end}}--print-full-init powershell) -join "`n")
\ No newline at end of file
@@ -79,6 +79,12 @@ function global:prompt {
     $cwd = Get-Cwd
     $arguments = @(
         "prompt"
+{{- with .configFile}}
+        '--config={{.}}'
+{{- end}}
+{{- with .profile}}
+        '--profile={{.}}'
+{{- end}}
         "--shell=powershell",
         "--path=$($cwd.Path)",
         "--logical-path=$($cwd.LogicalPath)",
@@ -86,7 +92,7 @@ function global:prompt {
         "--jobs=$($jobs)"
     )
 
-    # Whe start from the premise that the command executed correctly, which covers also the fresh console.
+    # We start from the premise that the command executed correctly, which covers also the fresh console.
     $lastExitCodeForPrompt = 0
     if ($lastCmd = Get-History -Count 1) {
         # In case we have a False on the Dollar hook, we know there's an error.
@@ -97,9 +103,16 @@ function global:prompt {
             # it was an internal Powershell command, otherwise, there MUST be an error code.
             $lastExitCodeForPrompt = if ($null -ne $lastCmdletError -and $lastCmd.CommandLine -eq $lastCmdletError.Line) { 1 } else { $origLastExitCode }
         }
-        $duration = [math]::Round(($lastCmd.EndExecutionTime - $lastCmd.StartExecutionTime).TotalMilliseconds)
 
-        $arguments += "--cmd-duration=$($duration)"
+        $arguments += "--previous-command=$($lastCmd.CommandLine)"
+
+        # Only report the duration the first time we see a command, so hitting
+        # enter on an empty line doesn't show the same duration again.
+        if ($lastCmd.Id -ne $global:_KitschLastHistoryId) {
+            $global:_KitschLastHistoryId = $lastCmd.Id
+            $duration = [math]::Round(($lastCmd.EndExecutionTime - $lastCmd.StartExecutionTime).TotalMilliseconds)
+            $arguments += "--cmd-duration=$($duration)"
+        }
     }
 
     $arguments += "--status=$($lastExitCodeForPrompt)"
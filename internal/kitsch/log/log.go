@@ -1,51 +1,170 @@
 // Package log provides logging for kitsch prompt.
+//
+// All log output is written to stderr (and optionally to a log file), and
+// never to stdout, since anything written to stdout would end up as part of
+// the prompt.
 package log
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/jwalton/gchalk"
 )
 
-// If verbose is false, we'll only print the first warning that comes along, and
-// hide all "info" messages.
-var verbose = false
-var verboseMutex = sync.Mutex{}
+// Level is the severity of a log message.
+type Level int
+
+const (
+	// LevelError is for problems that stop kitsch from doing what was asked.
+	LevelError Level = iota
+	// LevelWarn is for problems the user should fix, like a broken template.
+	LevelWarn
+	// LevelInfo is for things that might be useful to know when figuring out
+	// why the prompt looks the way it does.
+	LevelInfo
+	// LevelDebug is for detailed information about what kitsch is doing.
+	LevelDebug
+)
+
+var levelNames = []string{"error", "warn", "info", "debug"}
+
+// String returns the name of the level.
+func (level Level) String() string {
+	if level < LevelError || level > LevelDebug {
+		return fmt.Sprintf("Level(%d)", int(level))
+	}
+	return levelNames[level]
+}
+
+// ParseLevel converts a level name ("error", "warn", "info", or "debug") to a
+// Level.
+func ParseLevel(name string) (Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		name = "warn"
+	}
+	for index, levelName := range levelNames {
+		if name == levelName {
+			return Level(index), nil
+		}
+	}
+	return LevelWarn, fmt.Errorf("unknown log level %q", name)
+}
 
+var mutex = sync.Mutex{}
+
+// level is the most verbose level that will be logged.
+var level = LevelWarn
+
+// If verbose is false, we'll only print the first warning that comes along.
+var verbose = false
 var warningShowed = false
 
+// stderr is where log messages are printed.  This is a variable so tests can
+// replace it.
+var stderr io.Writer = os.Stderr
+
+// logFile, if set, receives a copy of every log message.
+var logFile io.Writer
+
 // SetVerbose sets verbose logging.
 //
 // In non-verbose mode, most logging is hidden (we're trying to show a prompt
-// here, so we don't want to bombard the user with messages).  "Info"s are hidden,
-// the first "Warn" will be displayed but the rest will be hidden.  "Error"s
-// are shown but should be rare.
+// here, so we don't want to bombard the user with messages).  "Info"s and
+// "Debug"s are hidden, the first "Warn" will be displayed but the rest will be
+// hidden.  "Error"s are shown but should be rare.  Verbose mode shows
+// everything.
 func SetVerbose(v bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
 	verbose = v
+	if v {
+		level = LevelDebug
+	} else {
+		level = LevelWarn
+	}
+}
+
+// SetLevel sets the most verbose level that will be logged.
+func SetLevel(l Level) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	level = l
+}
+
+// SetLogFile makes all log messages also get appended to the given file.
+// Messages written to the file have timestamps and no colors, and warnings are
+// never suppressed.  Passing "" stops writing to the log file.
+func SetLogFile(path string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if closer, ok := logFile.(io.Closer); ok {
+		closer.Close()
+	}
+	logFile = nil
+
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	logFile = file
+	return nil
+}
+
+// Debug prints a debug-level message to stderr.
+func Debug(message ...interface{}) {
+	write(LevelDebug, gchalk.Stderr.BrightBlack("Debug:"), message)
 }
 
 // Info prints an info-level message to stderr.
 func Info(message ...interface{}) {
-	if verbose {
-		println(gchalk.Stderr.BrightCyan("Info: "), fmt.Sprint(message...))
-	}
+	write(LevelInfo, gchalk.Stderr.BrightCyan("Info: "), message)
 }
 
 // Warn prints a warn-level message to stderr.  If non-verbose mode, only the
 // first warning will be displayed.  Once the user fixes that warning, or the
 // user runs `check` or in verbose mode, we can show them more warnings.
 func Warn(message ...interface{}) {
-	verboseMutex.Lock()
-	defer verboseMutex.Unlock()
-
-	if verbose || !warningShowed {
-		warningShowed = true
-		println(gchalk.Stderr.BrightYellow("Warn : "), fmt.Sprint(message...))
-	}
+	write(LevelWarn, gchalk.Stderr.BrightYellow("Warn : "), message)
 }
 
 // Error prints an error message to stderr.
 func Error(message ...interface{}) {
-	println(gchalk.Stderr.BrightRed("Error: "), fmt.Sprint(message...))
+	write(LevelError, gchalk.Stderr.BrightRed("Error: "), message)
+}
+
+func write(messageLevel Level, prefix string, message []interface{}) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if messageLevel > level {
+		return
+	}
+
+	text := fmt.Sprint(message...)
+
+	if logFile != nil {
+		fmt.Fprintf(logFile, "%s %-5s %s\n", time.Now().Format(time.RFC3339), strings.ToUpper(messageLevel.String()), text)
+	}
+
+	if messageLevel == LevelWarn && !verbose {
+		if warningShowed {
+			return
+		}
+		warningShowed = true
+	}
+
+	fmt.Fprintln(stderr, prefix, text)
 }
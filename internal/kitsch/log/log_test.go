@@ -0,0 +1,114 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStderr replaces stderr and resets all logging state for a test.
+func captureStderr(t *testing.T) *bytes.Buffer {
+	buffer := &bytes.Buffer{}
+	stderr = buffer
+	level = LevelWarn
+	verbose = false
+	warningShowed = false
+	t.Cleanup(func() {
+		stderr = os.Stderr
+		level = LevelWarn
+		verbose = false
+		warningShowed = false
+		_ = SetLogFile("")
+	})
+	return buffer
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]Level{
+		"error":   LevelError,
+		"WARN":    LevelWarn,
+		"warning": LevelWarn,
+		" info ":  LevelInfo,
+		"debug":   LevelDebug,
+	} {
+		level, err := ParseLevel(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, level, name)
+	}
+
+	_, err := ParseLevel("loud")
+	assert.Error(t, err)
+}
+
+func TestDefaultLevel(t *testing.T) {
+	out := captureStderr(t)
+
+	Debug("debug message")
+	Info("info message")
+	Warn("first warning")
+	Warn("second warning")
+	Error("error message")
+
+	text := out.String()
+	assert.NotContains(t, text, "debug message")
+	assert.NotContains(t, text, "info message")
+	assert.Contains(t, text, "first warning")
+	assert.NotContains(t, text, "second warning")
+	assert.Contains(t, text, "error message")
+}
+
+func TestSetLevel(t *testing.T) {
+	out := captureStderr(t)
+
+	SetLevel(LevelError)
+	Warn("a warning")
+	Error("an error")
+	assert.NotContains(t, out.String(), "a warning")
+	assert.Contains(t, out.String(), "an error")
+
+	SetLevel(LevelInfo)
+	Info("info message")
+	Debug("debug message")
+	assert.Contains(t, out.String(), "info message")
+	assert.NotContains(t, out.String(), "debug message")
+}
+
+func TestVerbose(t *testing.T) {
+	out := captureStderr(t)
+
+	SetVerbose(true)
+	Debug("debug message")
+	Warn("first warning")
+	Warn("second warning")
+
+	text := out.String()
+	assert.Contains(t, text, "debug message")
+	assert.Contains(t, text, "first warning")
+	assert.Contains(t, text, "second warning")
+}
+
+func TestLogFile(t *testing.T) {
+	out := captureStderr(t)
+	logPath := filepath.Join(t.TempDir(), "kitsch.log")
+
+	require.NoError(t, SetLogFile(logPath))
+	Warn("first warning")
+	Warn("second warning")
+	Info("hidden")
+	require.NoError(t, SetLogFile(""))
+
+	contents, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasSuffix(lines[0], " WARN  first warning"), lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], " WARN  second warning"), lines[1])
+
+	// Only the first warning goes to stderr.
+	assert.NotContains(t, out.String(), "second warning")
+}
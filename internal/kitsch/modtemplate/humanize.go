@@ -0,0 +1,140 @@
+package modtemplate
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"text/template"
+)
+
+// humanizeFuncMap returns template functions for formatting durations and
+// numbers in a compact, human-friendly way.
+func humanizeFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"humanizeDuration": func(ms interface{}) (string, error) {
+			value, err := toFloat(ms)
+			if err != nil {
+				return "", err
+			}
+			return humanizeDuration(int64(value)), nil
+		},
+		"humanizeBytes": func(bytes interface{}) (string, error) {
+			value, err := toFloat(bytes)
+			if err != nil {
+				return "", err
+			}
+			return humanizeBytes(value), nil
+		},
+		"humanizeNumber": func(number interface{}) (string, error) {
+			value, err := toFloat(number)
+			if err != nil {
+				return "", err
+			}
+			return humanizeNumber(value), nil
+		},
+	}
+}
+
+// toFloat converts a number, or a string containing a number, to a float64.
+func toFloat(value interface{}) (float64, error) {
+	if str, ok := value.(string); ok {
+		return strconv.ParseFloat(str, 64)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", value)
+}
+
+// durationUnits are the units used by humanizeDuration, from largest to smallest.
+var durationUnits = []struct {
+	name string
+	ms   int64
+}{
+	{"d", 24 * 60 * 60 * 1000},
+	{"h", 60 * 60 * 1000},
+	{"m", 60 * 1000},
+	{"s", 1000},
+}
+
+// humanizeDuration formats a duration in milliseconds using at most two units,
+// for example "850ms", "4.2s", "3m5s", "1h2m", or "2d3h".
+func humanizeDuration(ms int64) string {
+	sign := ""
+	if ms < 0 {
+		sign = "-"
+		ms = -ms
+	}
+
+	if ms < 1000 {
+		return fmt.Sprintf("%s%dms", sign, ms)
+	}
+	if ms < 10000 {
+		return sign + formatCompact(float64(ms)/1000) + "s"
+	}
+
+	for index, unit := range durationUnits {
+		if ms < unit.ms {
+			continue
+		}
+		result := fmt.Sprintf("%s%d%s", sign, ms/unit.ms, unit.name)
+		if index+1 < len(durationUnits) {
+			next := durationUnits[index+1]
+			if remainder := (ms % unit.ms) / next.ms; remainder != 0 {
+				result += fmt.Sprintf("%d%s", remainder, next.name)
+			}
+		}
+		return result
+	}
+
+	// Unreachable, since ms >= 1000.
+	return ""
+}
+
+// humanizeBytes formats a byte count using binary units, for example "512B",
+// "1.5KiB", or "23GiB".
+func humanizeBytes(bytes float64) string {
+	return humanizeWithUnits(bytes, 1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"})
+}
+
+// humanizeNumber formats a large number, for example "999", "1.2k", "45k",
+// or "3.1M".
+func humanizeNumber(number float64) string {
+	return humanizeWithUnits(number, 1000, []string{"", "k", "M", "B", "T"})
+}
+
+func humanizeWithUnits(value float64, base float64, units []string) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	unit := 0
+	for unit < len(units)-1 && math.Round(value*10)/10 >= base {
+		value /= base
+		unit++
+	}
+
+	if unit == 0 {
+		return sign + strconv.FormatFloat(math.Round(value), 'f', -1, 64) + units[0]
+	}
+	return sign + formatCompact(value) + units[unit]
+}
+
+// formatCompact formats a value with one decimal place if it is less than 10,
+// and no decimal places otherwise.  Trailing ".0"s are removed.
+func formatCompact(value float64) string {
+	if value < 9.95 {
+		return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(math.Round(value), 'f', -1, 64)
+}
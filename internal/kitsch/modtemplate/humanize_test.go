@@ -0,0 +1,60 @@
+package modtemplate
+
+import (
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHumanizeDuration(t *testing.T) {
+	assert.Equal(t, "0ms", humanizeDuration(0))
+	assert.Equal(t, "850ms", humanizeDuration(850))
+	assert.Equal(t, "1s", humanizeDuration(1000))
+	assert.Equal(t, "4.2s", humanizeDuration(4230))
+	assert.Equal(t, "10s", humanizeDuration(9990))
+	assert.Equal(t, "42s", humanizeDuration(42100))
+	assert.Equal(t, "3m", humanizeDuration(3*60*1000))
+	assert.Equal(t, "3m5s", humanizeDuration(185000))
+	assert.Equal(t, "1h2m", humanizeDuration(62*60*1000+5000))
+	assert.Equal(t, "2d3h", humanizeDuration(51*60*60*1000))
+	assert.Equal(t, "-3m5s", humanizeDuration(-185000))
+}
+
+func TestHumanizeBytes(t *testing.T) {
+	assert.Equal(t, "0B", humanizeBytes(0))
+	assert.Equal(t, "512B", humanizeBytes(512))
+	assert.Equal(t, "1KiB", humanizeBytes(1023.99))
+	assert.Equal(t, "1.5KiB", humanizeBytes(1536))
+	assert.Equal(t, "15KiB", humanizeBytes(15*1024))
+	assert.Equal(t, "1.2GiB", humanizeBytes(1.2*1024*1024*1024))
+}
+
+func TestHumanizeNumber(t *testing.T) {
+	assert.Equal(t, "999", humanizeNumber(999))
+	assert.Equal(t, "1.2k", humanizeNumber(1234))
+	assert.Equal(t, "45k", humanizeNumber(45321))
+	assert.Equal(t, "1M", humanizeNumber(999999))
+	assert.Equal(t, "3.1M", humanizeNumber(3123456))
+	assert.Equal(t, "-1.2k", humanizeNumber(-1234))
+}
+
+func TestHumanizeTemplateFunctions(t *testing.T) {
+	tmpl, err := CompileTemplate(&styling.Registry{}, env.DummyEnv{}, nil, "test",
+		`{{ humanizeDuration .Duration }} {{ humanizeBytes .Bytes }} {{ humanizeNumber "12345" }}`)
+	require.NoError(t, err)
+
+	result, err := TemplateToString(tmpl, map[string]interface{}{
+		"Duration": int64(83500),
+		"Bytes":    uint64(2048),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1m23s 2KiB 12k", result)
+
+	tmpl, err = CompileTemplate(&styling.Registry{}, env.DummyEnv{}, nil, "test", `{{ humanizeNumber "abc" }}`)
+	require.NoError(t, err)
+	_, err = TemplateToString(tmpl, nil)
+	assert.Error(t, err)
+}
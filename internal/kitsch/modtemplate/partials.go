@@ -0,0 +1,68 @@
+package modtemplate
+
+import (
+	"fmt"
+	"sort"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+)
+
+// Partials is a collection of named templates, defined in the "templates"
+// section of the configuration, which can be used from any module's template
+// with `{{ template "name" . }}`.  Partials are parsed once, and the parsed
+// templates are shared by every template that uses them.
+type Partials struct {
+	trees map[string]*parse.Tree
+}
+
+// CompilePartials parses a collection of named templates.  If any of the
+// templates fail to parse, this returns an error for the first one, along with
+// all the templates that parsed successfully.
+func CompilePartials(templates map[string]string) (*Partials, error) {
+	partials := &Partials{trees: map[string]*parse.Tree{}}
+	var firstErr error
+
+	// Sort names, so we always report the same error.
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// The functions are only used to check function names while parsing,
+		// so it doesn't matter what styles or environment we use here.
+		tmpl, err := CompileTemplate(&styling.Registry{}, env.DummyEnv{}, nil, name, templates[name])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("invalid template %q: %w", name, err)
+			}
+			continue
+		}
+
+		// Include any templates the partial defines with `{{ define }}`.
+		for _, defined := range tmpl.Templates() {
+			if defined.Tree != nil {
+				partials.trees[defined.Name()] = defined.Tree
+			}
+		}
+	}
+
+	return partials, firstErr
+}
+
+// addTo adds all partials to the given template.
+func (partials *Partials) addTo(tmpl *template.Template) error {
+	if partials == nil {
+		return nil
+	}
+	for name, tree := range partials.trees {
+		if _, err := tmpl.AddParseTree(name, tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
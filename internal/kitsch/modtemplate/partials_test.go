@@ -0,0 +1,52 @@
+package modtemplate
+
+import (
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderWithPartials(t *testing.T, partials *Partials, templateString string, data interface{}) string {
+	tmpl, err := CompileTemplate(&styling.Registry{}, env.DummyEnv{}, partials, "test", templateString)
+	require.NoError(t, err)
+	result, err := TemplateToString(tmpl, data)
+	require.NoError(t, err)
+	return result
+}
+
+func TestPartials(t *testing.T) {
+	partials, err := CompilePartials(map[string]string{
+		"icon":    `{{ if .Icon }}{{ .Icon }} {{ end }}{{ .Text }}`,
+		"wrapped": `[{{ template "icon" . }}]`,
+		"defines": `{{ define "sep" }} | {{ end }}`,
+	})
+	require.NoError(t, err)
+
+	data := map[string]string{"Icon": "*", "Text": "hello"}
+	assert.Equal(t, "* hello", renderWithPartials(t, partials, `{{ template "icon" . }}`, data))
+	assert.Equal(t, "[* hello]", renderWithPartials(t, partials, `{{ template "wrapped" . }}`, data))
+	assert.Equal(t, "a | b", renderWithPartials(t, partials, `a{{ template "sep" }}b`, nil))
+	assert.Equal(t, "HELLO", renderWithPartials(t, partials, `{{ include "icon" (dict "Text" "hello") | upper }}`, nil))
+
+	// A template can override a partial.
+	assert.Equal(t, "a - b", renderWithPartials(t, partials, `{{ define "sep" }} - {{ end }}a{{ template "sep" }}b`, nil))
+
+	// Using the partials from a template doesn't change them for other templates.
+	assert.Equal(t, "a | b", renderWithPartials(t, partials, `a{{ template "sep" }}b`, nil))
+}
+
+func TestPartialsWithError(t *testing.T) {
+	partials, err := CompilePartials(map[string]string{
+		"good": `[{{ . }}]`,
+		"bad":  `{{ . `,
+	})
+	assert.EqualError(t, err, `invalid template "bad": template: bad:1: unclosed action`)
+	assert.Equal(t, "[x]", renderWithPartials(t, partials, `{{ template "good" . }}`, "x"))
+}
+
+func TestNilPartials(t *testing.T) {
+	assert.Equal(t, "x", renderWithPartials(t, nil, `{{ . }}`, "x"))
+}
@@ -0,0 +1,78 @@
+package modtemplate
+
+import (
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+)
+
+// pathFuncMap returns template functions for working with paths.
+func pathFuncMap(environment env.Env) template.FuncMap {
+	return template.FuncMap{
+		"pathBase":    filepath.Base,
+		"pathDir":     filepath.Dir,
+		"pathShorten": pathShorten,
+		"relPath": func(base string, path string) string {
+			rel, err := filepath.Rel(base, path)
+			if err != nil {
+				return path
+			}
+			return rel
+		},
+		"homeRel": func(path string) string {
+			home := environment.Getenv("HOME")
+			if home == "" {
+				home = environment.Getenv("USERPROFILE")
+			}
+			return homeRel(home, path)
+		},
+	}
+}
+
+// homeRel replaces the user's home directory at the start of `path` with "~".
+func homeRel(home string, path string) string {
+	home = strings.TrimSuffix(home, string(filepath.Separator))
+	if home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
+
+// pathShorten shortens every folder in `path` except for the last `keep`
+// folders to a single character, so "~/dev/kitsch/internal" with `keep` of 1
+// becomes "~/d/k/internal".  Hidden folders keep their leading ".".
+func pathShorten(keep int, path string) string {
+	separator := string(filepath.Separator)
+	parts := strings.Split(path, separator)
+	if separator != "/" && len(parts) == 1 {
+		separator = "/"
+		parts = strings.Split(path, separator)
+	}
+
+	for index := 0; index < len(parts)-keep; index++ {
+		parts[index] = shortenPathPart(parts[index])
+	}
+	return strings.Join(parts, separator)
+}
+
+func shortenPathPart(part string) string {
+	prefix := ""
+	if strings.HasPrefix(part, ".") && len(part) > 1 {
+		prefix = "."
+		part = part[1:]
+	}
+	if part == "" || part == "~" {
+		return prefix + part
+	}
+	_, size := utf8.DecodeRuneInString(part)
+	return prefix + part[:size]
+}
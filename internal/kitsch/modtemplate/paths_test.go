@@ -0,0 +1,42 @@
+package modtemplate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renderPathTemplate(t *testing.T, templateString string, data interface{}) string {
+	environment := env.DummyEnv{Env: map[string]string{"HOME": filepath.FromSlash("/Users/jwalton")}}
+	tmpl, err := CompileTemplate(&styling.Registry{}, environment, nil, "test", templateString)
+	require.NoError(t, err)
+	result, err := TemplateToString(tmpl, data)
+	require.NoError(t, err)
+	return filepath.ToSlash(result)
+}
+
+func TestPathFunctions(t *testing.T) {
+	cwd := filepath.FromSlash("/Users/jwalton/dev/kitsch/internal")
+	root := filepath.FromSlash("/Users/jwalton/dev/kitsch")
+
+	assert.Equal(t, "internal", renderPathTemplate(t, `{{ pathBase . }}`, cwd))
+	assert.Equal(t, "/Users/jwalton/dev/kitsch", renderPathTemplate(t, `{{ pathDir . }}`, cwd))
+	assert.Equal(t, "~/dev/kitsch/internal", renderPathTemplate(t, `{{ homeRel . }}`, cwd))
+	assert.Equal(t, "~", renderPathTemplate(t, `{{ homeRel . }}`, filepath.FromSlash("/Users/jwalton")))
+	assert.Equal(t, "/Users/jwaltonx", renderPathTemplate(t, `{{ homeRel . }}`, filepath.FromSlash("/Users/jwaltonx")))
+	assert.Equal(t, "~/d/k/internal", renderPathTemplate(t, `{{ . | homeRel | pathShorten 1 }}`, cwd))
+	assert.Equal(t, "/U/j/dev/kitsch/internal", renderPathTemplate(t, `{{ . | pathShorten 3 }}`, cwd))
+	assert.Equal(t, "internal", renderPathTemplate(t, `{{ . | relPath "`+filepath.ToSlash(root)+`" }}`, cwd))
+}
+
+func TestPathShorten(t *testing.T) {
+	sep := string(filepath.Separator)
+	assert.Equal(t, "~"+sep+".c"+sep+"kitsch", pathShorten(1, "~"+sep+".config"+sep+"kitsch"))
+	assert.Equal(t, "kitsch", pathShorten(1, "kitsch"))
+	assert.Equal(t, "k", pathShorten(0, "kitsch"))
+	assert.Equal(t, "~"+sep+"ü", pathShorten(0, "~"+sep+"über"))
+}
@@ -0,0 +1,46 @@
+package modtemplate
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// scaleFuncMap returns template functions for picking a value based on where
+// a number falls in a table of thresholds.
+func scaleFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"scale": scale,
+	}
+}
+
+// scale picks a value from a threshold table.  `table` is a list of threshold
+// and value pairs, and scale returns the value for the largest threshold that
+// is less than or equal to `number`.  For example,
+// `scale 12 0 "green" 10 "yellow" 30 "red"` returns "yellow".  If `number` is
+// below every threshold, this returns "".
+func scale(number interface{}, table ...interface{}) (interface{}, error) {
+	value, err := toFloat(number)
+	if err != nil {
+		return nil, fmt.Errorf("scale: %v", err)
+	}
+	if len(table)%2 != 0 {
+		return nil, fmt.Errorf("scale: expected pairs of thresholds and values, got %d arguments", len(table))
+	}
+
+	var result interface{} = ""
+	found := false
+	var best float64
+	for index := 0; index < len(table); index += 2 {
+		threshold, err := toFloat(table[index])
+		if err != nil {
+			return nil, fmt.Errorf("scale: threshold %d: %v", index/2+1, err)
+		}
+		if threshold <= value && (!found || threshold >= best) {
+			found = true
+			best = threshold
+			result = table[index+1]
+		}
+	}
+
+	return result, nil
+}
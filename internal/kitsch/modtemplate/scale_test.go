@@ -0,0 +1,54 @@
+package modtemplate
+
+import (
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScale(t *testing.T) {
+	table := []interface{}{0, "green", 10, "yellow", 30, "red"}
+
+	result, err := scale(5, table...)
+	require.NoError(t, err)
+	assert.Equal(t, "green", result)
+
+	result, err = scale(10, table...)
+	require.NoError(t, err)
+	assert.Equal(t, "yellow", result)
+
+	result, err = scale(int64(100), table...)
+	require.NoError(t, err)
+	assert.Equal(t, "red", result)
+
+	result, err = scale(-1, table...)
+	require.NoError(t, err)
+	assert.Equal(t, "", result)
+
+	// Thresholds don't need to be in order.
+	result, err = scale(25.5, 20, "orange", "0", "green", 10, "yellow")
+	require.NoError(t, err)
+	assert.Equal(t, "orange", result)
+
+	_, err = scale(5, 0, "green", 10)
+	assert.Error(t, err)
+
+	_, err = scale("banana", table...)
+	assert.Error(t, err)
+}
+
+func TestScaleTemplateFunction(t *testing.T) {
+	tmpl, err := CompileTemplate(&styling.Registry{}, env.DummyEnv{}, nil, "test",
+		`{{ scale .Percent 0 "" 20 "🪫" 80 "🔋" }} {{ .Duration | humanizeDuration | style (scale .Duration 0 "green" 10000 "red") }}`)
+	require.NoError(t, err)
+
+	result, err := TemplateToString(tmpl, map[string]interface{}{
+		"Percent":  15,
+		"Duration": int64(12000),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, " 12s", result)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"sync"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
@@ -16,32 +17,49 @@ const recursionMaxNums = 1000
 
 var sprigTemplateFunctions template.FuncMap
 
+// bufferPool holds buffers for TemplateToString to reuse.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
 func init() {
 	sprigTemplateFunctions = sprig.TxtFuncMap()
 	delete(sprigTemplateFunctions, "env")
 }
 
 // CompileTemplate compiles a module template and adds default template functions.
-func CompileTemplate(styles *styling.Registry, environment env.Env, name string, templateString string) (*template.Template, error) {
+// `partials` may be nil.  The returned template may be executed from multiple
+// goroutines at once.
+func CompileTemplate(
+	styles *styling.Registry,
+	environment env.Env,
+	partials *Partials,
+	name string,
+	templateString string,
+) (*template.Template, error) {
 	tmpl := template.New(name)
 
 	funcMap := template.FuncMap{}
 
 	// Borrowed from Helm.
 	// TODO: Make `data` optional.
+	var includedMutex sync.Mutex
 	includedNames := make(map[string]int) // Recursion guard.
 	funcMap["include"] = func(name string, data interface{}) (string, error) {
 		var buf strings.Builder
-		if v, ok := includedNames[name]; ok {
-			if v > recursionMaxNums {
-				return "", fmt.Errorf("rendering template has a nested reference name: %s", name)
-			}
-			includedNames[name]++
-		} else {
-			includedNames[name] = 1
+		includedMutex.Lock()
+		if includedNames[name] > recursionMaxNums {
+			includedMutex.Unlock()
+			return "", fmt.Errorf("rendering template has a nested reference name: %s", name)
 		}
+		includedNames[name]++
+		includedMutex.Unlock()
+
 		err := tmpl.ExecuteTemplate(&buf, name, data)
+
+		includedMutex.Lock()
 		includedNames[name]--
+		includedMutex.Unlock()
 		return buf.String(), err
 	}
 
@@ -49,12 +67,24 @@ func CompileTemplate(styles *styling.Registry, environment env.Env, name string,
 		return environment.Getenv(name)
 	}
 
-	tmpl, err := tmpl.
+	tmpl = tmpl.
 		Funcs(funcMap).
 		Funcs(sprigTemplateFunctions).
+		Funcs(pathFuncMap(environment)).
+		Funcs(humanizeFuncMap()).
+		Funcs(scaleFuncMap()).
+		Funcs(widthFuncMap()).
 		Funcs(styling.TxtFuncMap(styles)).
-		Funcs(powerline.TxtFuncMap(styles)).
-		Parse(templateString)
+		Funcs(powerline.TxtFuncMap(styles))
+
+	// Add partials before parsing, so a template can replace a partial with
+	// its own `{{ define }}`.
+	err := partials.addTo(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err = tmpl.Parse(templateString)
 	if err != nil {
 		return nil, err
 	}
@@ -63,8 +93,13 @@ func CompileTemplate(styles *styling.Registry, environment env.Env, name string,
 
 // TemplateToString renders a template to a string.
 func TemplateToString(template *template.Template, data interface{}) (string, error) {
-	var b bytes.Buffer
-	err := template.Execute(&b, data)
+	b := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		b.Reset()
+		bufferPool.Put(b)
+	}()
+
+	err := template.Execute(b, data)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,18 @@
+package modtemplate
+
+import (
+	"text/template"
+
+	"github.com/jwalton/kitsch/internal/textwidth"
+)
+
+// widthFuncMap returns template functions for measuring and truncating text
+// by the number of columns it occupies in the terminal.
+func widthFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"textWidth": textwidth.Width,
+		"truncateWidth": func(width int, str string) string {
+			return textwidth.Truncate(str, width, "…")
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package modtemplate
+
+import (
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWidthFunctions(t *testing.T) {
+	render := func(templateString string, data interface{}) string {
+		tmpl, err := CompileTemplate(&styling.Registry{}, env.DummyEnv{}, nil, "test", templateString)
+		require.NoError(t, err)
+		result, err := TemplateToString(tmpl, data)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.Equal(t, "6", render(`{{ textWidth . }}`, "日本語"))
+	assert.Equal(t, "feature/v…", render(`{{ . | truncateWidth 10 }}`, "feature/very-long-branch"))
+	assert.Equal(t, "main", render(`{{ . | truncateWidth 10 }}`, "main"))
+}
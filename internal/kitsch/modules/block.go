@@ -2,14 +2,16 @@ package modules
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 	"text/template"
 
-	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
 	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
 	"github.com/jwalton/kitsch/internal/kitsch/styling"
 	"github.com/jwalton/kitsch/internal/perf"
+	"github.com/jwalton/kitsch/internal/textwidth"
 	"gopkg.in/yaml.v3"
 )
 
@@ -32,8 +34,34 @@ type BlockModule struct {
 	// next module, and Index is the index of the current module in the modules
 	// array.
 	Join string
+	// MinWidth is the minimum width of the block, in columns.  If the block's
+	// output is narrower than this, it will be padded with spaces according
+	// to `Align`.  Blocks with no output are never padded.
+	MinWidth int `yaml:"minWidth"`
+	// MaxWidth is the maximum width of the block, in columns.  If the block's
+	// output is wider than this, it will be truncated and end with "…".  0
+	// means there is no maximum.
+	MaxWidth int `yaml:"maxWidth"`
+	// Align is how to align the block's output when it is narrower than
+	// `MinWidth`.  Defaults to "left".
+	Align textwidth.Align `yaml:"align" jsonschema:",enum=left:right:center"`
+	// Padding is the number of spaces to add to either side of the block's
+	// output.  These count towards `MinWidth` and `MaxWidth`.
+	Padding int `yaml:"padding"`
+	// FillLine, if true, extends the background color at the end of the block
+	// to the end of the terminal line.  This is handy for drawing a "header
+	// bar" across the top line of a two-line prompt.
+	FillLine bool `yaml:"fillLine"`
 }
 
+// eraseToEndOfLine is the escape sequence which clears from the cursor to the
+// end of the line.  The cleared part of the line is filled with the current
+// background color.
+const eraseToEndOfLine = "\u001b[K"
+
+// joinTemplateName is the name given to a block's join template.
+const joinTemplateName = "join"
+
 type blockModuleResult struct {
 	// Modules is a map of results from executing each child module, indexed by
 	// module ID.  Only modules that actually generated output will be included.
@@ -43,6 +71,10 @@ type blockModuleResult struct {
 	ModuleArray []ModuleWrapperResult
 }
 
+func (result blockModuleResult) childResults() map[string]ModuleWrapperResult {
+	return result.Modules
+}
+
 // Execute the block module.
 func (mod BlockModule) Execute(context *Context) ModuleResult {
 	resultsArray := make([]ModuleWrapperResult, 0, len(mod.Modules))
@@ -75,11 +107,13 @@ func (mod BlockModule) Execute(context *Context) ModuleResult {
 		resultsByID[id] = result
 	}
 
-	defaultText := mod.joinChildren(context, resultsArray)
+	defaultText, warnings := mod.joinChildren(context, resultsArray)
+	defaultText = mod.applyWidth(defaultText)
 
 	result := ModuleResult{
 		DefaultText: defaultText,
 		Performance: childDurations,
+		Warnings:    warnings,
 		Data: blockModuleResult{
 			Modules:     resultsByID,
 			ModuleArray: resultsArray,
@@ -98,9 +132,21 @@ func (mod BlockModule) Execute(context *Context) ModuleResult {
 		}
 	}
 
+	if mod.FillLine && result.DefaultText != "" {
+		result.DefaultText += fillLine(context, result.EndStyle.BG)
+	}
+
 	return result
 }
 
+func (mod BlockModule) childModules() []*ModuleWrapper {
+	children := make([]*ModuleWrapper, len(mod.Modules))
+	for index := range mod.Modules {
+		children[index] = &mod.Modules[index]
+	}
+	return children
+}
+
 // blockJoinData is the data passed to the join template.
 type blockJoinData struct {
 	// Globals are the global variables.
@@ -113,8 +159,9 @@ type blockJoinData struct {
 	Index int
 }
 
-func (mod BlockModule) joinChildren(context *Context, children []ModuleWrapperResult) string {
+func (mod BlockModule) joinChildren(context *Context, children []ModuleWrapperResult) (string, []string) {
 	out := strings.Builder{}
+	var warnings []string
 
 	var join *template.Template = nil
 
@@ -131,8 +178,9 @@ func (mod BlockModule) joinChildren(context *Context, children []ModuleWrapperRe
 		// Compile the join template
 		if mod.Join != "" {
 			var err error
-			join, err = modtemplate.CompileTemplate(context.Styles, context.Environment, "join", mod.Join)
+			join, err = context.compileTemplate(joinTemplateName, mod.Join)
 			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("Error compiling join template: %v", err))
 				join = nil
 			}
 		}
@@ -147,7 +195,7 @@ func (mod BlockModule) joinChildren(context *Context, children []ModuleWrapperRe
 					Index:      index,
 				})
 				if err != nil {
-					log.Warn(err.Error())
+					warnings = append(warnings, fmt.Sprintf("Error executing join template: %v", err))
 					joiner = " "
 				}
 				out.WriteString(joiner)
@@ -157,7 +205,45 @@ func (mod BlockModule) joinChildren(context *Context, children []ModuleWrapperRe
 		}
 	}
 
-	return out.String()
+	return out.String(), warnings
+}
+
+// applyWidth applies the `MinWidth`, `MaxWidth`, `Align`, and `Padding` options
+// to the block's output.
+func (mod BlockModule) applyWidth(text string) string {
+	if text == "" {
+		return text
+	}
+
+	if mod.MaxWidth > 0 {
+		contentWidth := mod.MaxWidth - 2*mod.Padding
+		if contentWidth < 0 {
+			contentWidth = 0
+		}
+		text = textwidth.Truncate(text, contentWidth, "…")
+	}
+
+	if mod.Padding > 0 {
+		padding := strings.Repeat(" ", mod.Padding)
+		text = padding + text + padding
+	}
+
+	if mod.MinWidth > 0 {
+		text = textwidth.Pad(text, mod.MinWidth, mod.Align)
+	}
+
+	return text
+}
+
+// fillLine returns the escape sequences required to fill the rest of the line
+// with the given background color.  If there is no background color, the line
+// is filled with whatever background color is active, which will be the
+// block's own background color if it has one.
+func fillLine(context *Context, bg string) string {
+	if bg == "" {
+		return eraseToEndOfLine
+	}
+	return context.GetStyle(bg).Apply(eraseToEndOfLine)
 }
 
 func init() {
@@ -177,29 +263,42 @@ func init() {
 // executeModules executes an array of modules in parallel.  It returns an array
 // of the same length as `modules`, where each value in the resulting array
 // contains the result of executing the corresponding module.
+//
+// Every module waits for one of the context's workers before it runs (see
+// acquireWorker), so no matter how deeply blocks are nested, at most
+// `context.Workers` modules are executed at once across the whole prompt.
 func executeModules(context *Context, modules []ModuleWrapper) []ModuleWrapperResult {
-	type chResult struct {
-		index int
-		value ModuleWrapperResult
-	}
-
-	// Create a channel to receive results from each module.
-	ch := make(chan chResult)
+	results := make([]ModuleWrapperResult, len(modules))
 
-	// Create a goroutine for each module.
-	executeModule := func(index int, module ModuleWrapper) {
-		ch <- chResult{index, module.Execute(context)}
-	}
-	for i, module := range modules {
-		go executeModule(i, module)
+	wg := sync.WaitGroup{}
+	wg.Add(len(modules))
+	for index := range modules {
+		go func(index int) {
+			defer wg.Done()
+			results[index] = modules[index].Execute(context)
+		}(index)
 	}
+	wg.Wait()
 
-	// Collect the results from the channel.
-	results := make([]ModuleWrapperResult, len(modules))
-	for i := 0; i < len(modules); i++ {
-		result := <-ch
-		results[result.index] = result.value
+	return results
+}
+
+// workerCount returns the maximum number of modules to execute at once.
+func (context *Context) workerCount() int {
+	if context.Workers > 0 {
+		return context.Workers
 	}
+	return runtime.NumCPU() * 2
+}
 
-	return results
+// acquireWorker blocks until fewer than `context.Workers` modules are
+// executing, and returns a function which must be called once the module is
+// done.  Modules which only render other modules never take a worker, so a
+// block waiting on its children can't stop those children from running.
+func (context *Context) acquireWorker() func() {
+	context.workersOnce.Do(func() {
+		context.workers = make(chan struct{}, context.workerCount())
+	})
+	context.workers <- struct{}{}
+	return func() { <-context.workers }
 }
@@ -1,7 +1,9 @@
 package modules
 
 import (
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/stretchr/testify/assert"
@@ -70,3 +72,229 @@ func TestBlockSubIDsNoData(t *testing.T) {
 	// in .Data.Modules, even though there was no output.
 	assert.Equal(t, "there is text", result.Text)
 }
+
+// TestBlockModules verifies that a block's template can combine data from
+// several child modules through `.Modules`.
+func TestBlockModules(t *testing.T) {
+	blockMod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		template: "{{ .Modules.user.Data.Username }}@{{ .Modules.hostname.Data.Hostname }} {{ .Modules.jobs.Text }}"
+		modules:
+		- type: username
+		  id: user
+		  showAlways: true
+		- type: hostname
+		  showAlways: true
+		- type: text
+		  id: jobs
+		  text: "2"
+    `))
+
+	result := blockMod.Execute(newTestContext("oriana"))
+
+	assert.Equal(t, "oriana@lucid 2", result.Text)
+}
+
+func TestModulesIsNilOutsideBlock(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: hello
+		template: "{{ if .Modules }}modules{{ else }}{{ .Text }}{{ end }}"
+    `))
+
+	result := mod.Execute(newTestContext("oriana"))
+
+	assert.Equal(t, "hello", result.Text)
+}
+
+func TestBlockWidth(t *testing.T) {
+	for _, test := range []struct {
+		options  string
+		expected string
+	}{
+		{"minWidth: 15", "hello world    "},
+		{"minWidth: 15\nalign: right", "    hello world"},
+		{"minWidth: 15\nalign: center", "  hello world  "},
+		{"maxWidth: 8", "hello w…"},
+		{"maxWidth: 11", "hello world"},
+		{"padding: 1", " hello world "},
+		{"padding: 1\nmaxWidth: 8", " hello… "},
+		{"padding: 1\nminWidth: 15\nalign: right", "   hello world "},
+	} {
+		blockMod := moduleWrapperFromYAML(heredoc.Doc(`
+			type: block
+			modules:
+			- type: text
+			  text: hello
+			- type: text
+			  text: world
+		`) + test.options)
+
+		result := blockMod.Execute(newTestContext("jwalton"))
+		assert.Equal(t, test.expected, result.Text, test.options)
+	}
+}
+
+func TestBlockWidthEmpty(t *testing.T) {
+	blockMod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		minWidth: 10
+		padding: 1
+		modules:
+		- type: text
+		  text: ""
+	`))
+
+	result := blockMod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.Text)
+}
+
+type panickingModule struct{}
+
+// Execute the module.
+func (mod panickingModule) Execute(context *Context) ModuleResult {
+	panic("oh no")
+}
+
+// concurrencyModule records how many instances of itself are running at once.
+type concurrencyModule struct {
+	running *int32
+	maximum *int32
+	delay   time.Duration
+}
+
+// Execute the module.
+func (mod concurrencyModule) Execute(context *Context) ModuleResult {
+	running := atomic.AddInt32(mod.running, 1)
+	for {
+		maximum := atomic.LoadInt32(mod.maximum)
+		if running <= maximum || atomic.CompareAndSwapInt32(mod.maximum, maximum, running) {
+			break
+		}
+	}
+	time.Sleep(mod.delay)
+	atomic.AddInt32(mod.running, -1)
+	return ModuleResult{DefaultText: "x"}
+}
+
+func TestBlockRecoversFromPanics(t *testing.T) {
+	block := ModuleWrapper{
+		config: CommonConfig{Type: "block"},
+		Module: BlockModule{
+			Join: " ",
+			Modules: []ModuleWrapper{
+				{config: CommonConfig{Type: "text"}, Module: TextModule{Text: "hello"}},
+				{config: CommonConfig{Type: "panicking"}, Module: panickingModule{}},
+				{config: CommonConfig{Type: "text"}, Module: TextModule{Text: "world"}},
+			},
+		},
+	}
+
+	context := newTestContext("jwalton")
+	result := block.Execute(context)
+	assert.Equal(t, "hello world", result.Text)
+	assert.Equal(t, []string{"Module panicking(0:0) panicked: oh no"}, context.Warnings())
+}
+
+func TestBlockLimitsWorkers(t *testing.T) {
+	var running, maximum int32
+	children := make([]ModuleWrapper, 6)
+	for index := range children {
+		children[index] = ModuleWrapper{
+			config: CommonConfig{Type: "concurrency"},
+			Module: concurrencyModule{running: &running, maximum: &maximum, delay: 20 * time.Millisecond},
+		}
+	}
+	block := ModuleWrapper{
+		config: CommonConfig{Type: "block"},
+		Module: BlockModule{Join: "", Modules: children},
+	}
+
+	context := newTestContext("jwalton")
+	context.Workers = 2
+	result := block.Execute(context)
+	assert.Equal(t, "xxxxxx", result.Text)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maximum))
+}
+
+func TestNestedBlocksShareWorkers(t *testing.T) {
+	var running, maximum int32
+	innerBlock := func() ModuleWrapper {
+		children := make([]ModuleWrapper, 3)
+		for index := range children {
+			children[index] = ModuleWrapper{
+				config: CommonConfig{Type: "concurrency"},
+				Module: concurrencyModule{running: &running, maximum: &maximum, delay: 20 * time.Millisecond},
+			}
+		}
+		return ModuleWrapper{
+			config: CommonConfig{Type: "block"},
+			Module: BlockModule{Join: "", Modules: children},
+		}
+	}
+	block := ModuleWrapper{
+		config: CommonConfig{Type: "block"},
+		Module: BlockModule{Join: "", Modules: []ModuleWrapper{innerBlock(), innerBlock()}},
+	}
+
+	// The limit applies to the whole prompt, not to each block.
+	context := newTestContext("jwalton")
+	context.Workers = 2
+	result := block.Execute(context)
+	assert.Equal(t, "xxxxxx", result.Text)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maximum))
+}
+
+func TestBlockChildTimeout(t *testing.T) {
+	var running, maximum int32
+	block := ModuleWrapper{
+		config: CommonConfig{Type: "block"},
+		Module: BlockModule{
+			Join: " ",
+			Modules: []ModuleWrapper{
+				{config: CommonConfig{Type: "text"}, Module: TextModule{Text: "hello"}},
+				{
+					config: CommonConfig{Type: "slow", Timeout: 10},
+					Module: concurrencyModule{running: &running, maximum: &maximum, delay: time.Second},
+				},
+				{config: CommonConfig{Type: "text"}, Module: TextModule{Text: "world"}},
+			},
+		},
+	}
+
+	// Even with a single worker, the slow module only holds up the block
+	// until it times out.
+	context := newTestContext("jwalton")
+	context.Workers = 1
+	start := time.Now()
+	result := block.Execute(context)
+	assert.Equal(t, "hello world", result.Text)
+	assert.Less(t, int64(time.Since(start)), int64(500*time.Millisecond))
+	assert.Equal(t, []string{"Module slow(0:0) timed out after 10ms"}, context.Warnings())
+}
+
+func TestBlockFillLine(t *testing.T) {
+	blockMod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		fillLine: true
+		modules:
+		- type: text
+		  style: bg:blue
+		  text: hello
+    `))
+
+	result := blockMod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "hello\u001b[K", result.Text)
+
+	// An empty block should not fill the line.
+	blockMod = moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		fillLine: true
+		modules:
+		- type: text
+		  text: ""
+    `))
+
+	result = blockMod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.Text)
+}
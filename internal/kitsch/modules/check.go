@@ -0,0 +1,223 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/jwalton/kitsch/internal/kitsch/suggest"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is a problem found in a configuration file.
+type ConfigError struct {
+	// Line is the line number of the problem, or 0 if unknown.
+	Line int
+	// Column is the column number of the problem, or 0 if unknown.
+	Column int
+	// Message is a description of the problem.
+	Message string
+}
+
+func (err ConfigError) Error() string {
+	if err.Line == 0 {
+		return err.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+// Checker checks module configuration for errors.  Unlike loading a module
+// from YAML, which stops at the first error, the Checker tries to find every
+// error in a configuration.
+type Checker struct {
+	// Styles is used to resolve styles.  Custom colors should be added to
+	// this registry before checking any modules.
+	Styles *styling.Registry
+	// Errors is a list of all errors found.
+	Errors []ConfigError
+}
+
+// moduleFields describes the fields a module accepts.
+type moduleFields struct {
+	// names is a sorted list of all valid field names.
+	names []string
+	// refs maps field names to the "$ref" for that field, if any.
+	refs map[string]string
+}
+
+var commonFields = parseModuleFields(schemas.CommonConfigJSONSchema)
+
+func parseModuleFields(jsonSchema string) moduleFields {
+	var schema struct {
+		Properties map[string]struct {
+			Ref string `json:"$ref"`
+		} `json:"properties"`
+	}
+	result := moduleFields{refs: map[string]string{}}
+	if err := json.Unmarshal([]byte(jsonSchema), &schema); err != nil {
+		return result
+	}
+	for name, property := range schema.Properties {
+		result.names = append(result.names, name)
+		if property.Ref != "" {
+			result.refs[name] = property.Ref
+		}
+	}
+	sort.Strings(result.names)
+	return result
+}
+
+// Errorf adds an error at the position of the given node.
+func (checker *Checker) Errorf(node *yaml.Node, format string, args ...interface{}) {
+	err := ConfigError{Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		err.Line = node.Line
+		err.Column = node.Column
+	}
+	checker.Errors = append(checker.Errors, err)
+}
+
+// CheckStyle checks that the given style string can be parsed.
+func (checker *Checker) CheckStyle(node *yaml.Node, style string) {
+	if _, err := checker.Styles.Get(style); err != nil {
+		checker.Errorf(node, "%v", err)
+	}
+}
+
+// CheckModule checks the module defined by the given YAML node, along with
+// any child modules.
+func (checker *Checker) CheckModule(node *yaml.Node) {
+	node = resolveAlias(node)
+	if node == nil {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		checker.Errorf(node, "expected a module, but found a %s", nodeKindName(node))
+		return
+	}
+
+	typeNode := mappingValue(node, "type")
+	if typeNode == nil || typeNode.Value == "" {
+		checker.Errorf(node, "module is missing type")
+		return
+	}
+
+	moduleType := typeNode.Value
+	mod, ok := registeredModules[moduleType]
+	if !ok {
+		checker.Errorf(typeNode, "unknown module type %q%s", moduleType,
+			suggest.DidYouMean(moduleType, RegisteredModuleTypes()))
+		return
+	}
+
+	fields := parseModuleFields(mod.jsonSchema)
+	validNames := append(append([]string{}, commonFields.names...), fields.names...)
+
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		keyNode := node.Content[index]
+		valueNode := resolveAlias(node.Content[index+1])
+		key := keyNode.Value
+
+		if key == "<<" {
+			continue
+		}
+
+		if !containsString(commonFields.names, key) && !containsString(fields.names, key) {
+			checker.Errorf(keyNode, "unknown field %q in %s module%s", key, moduleType,
+				suggest.DidYouMean(key, validNames))
+			continue
+		}
+
+		switch fields.refs[key] {
+		case "#/definitions/module":
+			checker.CheckModule(valueNode)
+			continue
+		case "#/definitions/ModulesList":
+			checker.checkModuleList(valueNode)
+			continue
+		}
+
+		if valueNode.Kind != yaml.ScalarNode {
+			continue
+		}
+		if isStyleField(key) {
+			checker.CheckStyle(valueNode, valueNode.Value)
+		} else if isTemplateField(moduleType, key) {
+			checker.CheckTemplate(valueNode, key)
+		}
+	}
+}
+
+func (checker *Checker) checkModuleList(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		checker.Errorf(node, "expected a list of modules, but found a %s", nodeKindName(node))
+		return
+	}
+	for _, child := range node.Content {
+		checker.CheckModule(child)
+	}
+}
+
+// CheckTemplate checks that the template in the given node compiles.  `field` is
+// the name of the field the template came from, used in the error message.
+func (checker *Checker) CheckTemplate(node *yaml.Node, field string) {
+	_, err := modtemplate.CompileTemplate(checker.Styles, env.DummyEnv{}, nil, field, node.Value)
+	if err != nil {
+		checker.Errorf(node, "invalid %s: %v", field, err)
+	}
+}
+
+// isStyleField returns true if the given field holds a style string.
+func isStyleField(field string) bool {
+	return field == "style" || strings.HasSuffix(field, "Style")
+}
+
+// isTemplateField returns true if the given field holds a template.
+func isTemplateField(moduleType string, field string) bool {
+	return field == "template" ||
+		strings.HasSuffix(field, "Template") ||
+		(moduleType == "block" && field == "join")
+}
+
+// mappingValue returns the value for the given key in a mapping node, or nil
+// if the key is not present.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if node.Content[index].Value == key {
+			return resolveAlias(node.Content[index+1])
+		}
+	}
+	return nil
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+func nodeKindName(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		return "list"
+	case yaml.MappingNode:
+		return "map"
+	default:
+		return "scalar"
+	}
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
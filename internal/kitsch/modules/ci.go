@@ -0,0 +1,90 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas CIModule
+
+// CIModule shows when the shell is running in a CI environment, and the name
+// of the current CI job.  This shows nothing outside of CI.
+//
+type CIModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=ci"`
+	// Symbol is the symbol to show before the CI provider.  Defaults to "⚙ ".
+	Symbol string `yaml:"symbol"`
+}
+
+type ciModuleResult struct {
+	// InCI is true if the shell is running in a CI environment.
+	InCI bool
+	// Provider is the CI provider (e.g. "github", "gitlab", "jenkins"), or
+	// "ci" if we can tell we're in CI but not which provider is being used.
+	Provider string
+	// Job is the name of the current CI job, if known.
+	Job string
+}
+
+// ciProviders is a list of CI providers, the environment variable that
+// identifies each, and the environment variable which holds the job name.
+// These are checked in order.
+var ciProviders = []struct {
+	provider string
+	detect   string
+	job      string
+}{
+	{"github", "GITHUB_ACTIONS", "GITHUB_JOB"},
+	{"gitlab", "GITLAB_CI", "CI_JOB_NAME"},
+	{"jenkins", "JENKINS_URL", "JOB_NAME"},
+	{"circleci", "CIRCLECI", "CIRCLE_JOB"},
+	{"buildkite", "BUILDKITE", "BUILDKITE_LABEL"},
+	{"travis", "TRAVIS", "TRAVIS_JOB_NAME"},
+	{"azure", "TF_BUILD", "SYSTEM_JOBDISPLAYNAME"},
+}
+
+// Execute the module.
+func (mod CIModule) Execute(context *Context) ModuleResult {
+	data := detectCI(context.Getenv)
+
+	text := ""
+	if data.InCI {
+		text = mod.Symbol + data.Provider
+		if data.Job != "" {
+			text += " " + data.Job
+		}
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// detectCI figures out if we're running in a CI environment.
+func detectCI(getenv func(string) string) ciModuleResult {
+	for _, entry := range ciProviders {
+		if getenv(entry.detect) != "" {
+			return ciModuleResult{InCI: true, Provider: entry.provider, Job: getenv(entry.job)}
+		}
+	}
+
+	// Most CI systems set CI, so check this last as a catch-all.
+	if ci := getenv("CI"); ci != "" && ci != "false" && ci != "0" {
+		return ciModuleResult{InCI: true, Provider: "ci"}
+	}
+
+	return ciModuleResult{}
+}
+
+func init() {
+	registerModule(
+		"ci",
+		registeredModule{
+			jsonSchema: schemas.CIModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := CIModule{Type: "ci", Symbol: "⚙ "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
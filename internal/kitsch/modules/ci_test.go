@@ -0,0 +1,57 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCI(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ci
+	`)).(*CIModule)
+
+	context := newTestContext("jwalton")
+	context.Environment = &env.DummyEnv{Env: map[string]string{
+		"CI":             "true",
+		"GITHUB_ACTIONS": "true",
+		"GITHUB_JOB":     "build",
+	}}
+	result := mod.Execute(context)
+
+	assert.Equal(t, "⚙ github build", result.DefaultText)
+	assert.Equal(t, ciModuleResult{InCI: true, Provider: "github", Job: "build"}, result.Data)
+}
+
+func TestDetectCI(t *testing.T) {
+	for _, test := range []struct {
+		env      map[string]string
+		expected ciModuleResult
+	}{
+		{
+			map[string]string{"GITLAB_CI": "true", "CI_JOB_NAME": "test"},
+			ciModuleResult{InCI: true, Provider: "gitlab", Job: "test"},
+		},
+		{
+			map[string]string{"JENKINS_URL": "https://ci.example.com/", "JOB_NAME": "deploy"},
+			ciModuleResult{InCI: true, Provider: "jenkins", Job: "deploy"},
+		},
+		{
+			map[string]string{"CI": "1"},
+			ciModuleResult{InCI: true, Provider: "ci"},
+		},
+		{
+			map[string]string{"CI": "false"},
+			ciModuleResult{},
+		},
+		{
+			map[string]string{},
+			ciModuleResult{},
+		},
+	} {
+		environment := &env.DummyEnv{Env: test.env}
+		assert.Equal(t, test.expected, detectCI(environment.Getenv), "%v", test.env)
+	}
+}
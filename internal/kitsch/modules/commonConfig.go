@@ -7,7 +7,7 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-//go:generate go run ../genSchema/main.go --pkg schemas CommonConfig
+//go:generate go run ../genSchema/main.go --pkg schemas CommonConfig ModuleCache
 
 // CommonConfig is common configuration for all modules.
 type CommonConfig struct {
@@ -20,8 +20,20 @@ type CommonConfig struct {
 	Style string `yaml:"style"`
 	// Template is a golang template to use to render the output of this module.
 	Template string `yaml:"template"`
+	// Prefix is text to show before the output of this module.  The prefix is
+	// only shown if the module produces some output.
+	Prefix string `yaml:"prefix"`
+	// PrefixStyle is the style to apply to the prefix.  Defaults to `Style`.
+	PrefixStyle string `yaml:"prefixStyle"`
+	// Suffix is text to show after the output of this module.  The suffix is
+	// only shown if the module produces some output.
+	Suffix string `yaml:"suffix"`
+	// SuffixStyle is the style to apply to the suffix.  Defaults to `Style`.
+	SuffixStyle string `yaml:"suffixStyle"`
 	// Conditions are conditions that must be met for this module to execute.
 	Conditions *condition.Conditions `yaml:"conditions,omitempty" jsonschema:",ref"`
+	// Cache configures caching of this module's output between prompts.
+	Cache *ModuleCache `yaml:"cache,omitempty" jsonschema:",ref"`
 	// Timeout is the maximum amount of time, in milliseconds, to wait for this
 	// module to execute.  If not specified, the default timeout for most modules
 	// will be 200ms, but for block modules it will be infinite.
@@ -32,6 +44,8 @@ type CommonConfig struct {
 // errors to the log if any are found.
 func (config *CommonConfig) Validate(context *Context, prefix string) {
 	context.GetStyle(config.Style)
+	context.GetStyle(config.PrefixStyle)
+	context.GetStyle(config.SuffixStyle)
 }
 
 func getCommonConfig(node *yaml.Node) (CommonConfig, error) {
@@ -45,9 +59,26 @@ func getCommonConfig(node *yaml.Node) (CommonConfig, error) {
 		return config, err
 	}
 
+	if config.Type == "" && mappingHasKey(node, "use") {
+		config.Type = "use"
+	}
+
 	if config.Type == "" {
 		return config, fmt.Errorf("object is missing type (%d:%d)", node.Line, node.Column)
 	}
 
 	return config, nil
 }
+
+// mappingHasKey returns true if the given mapping node has the given key.
+func mappingHasKey(node *yaml.Node, key string) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for index := 0; index+1 < len(node.Content); index += 2 {
+		if node.Content[index].Value == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package modules
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas ContainerModule
+
+// ContainerModule shows when the shell is running inside a container, such as
+// a Docker, Podman, or LXC container.  This shows nothing outside of a
+// container.
+//
+type ContainerModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=container"`
+	// Symbol is a symbol to show before the container name.  Defaults to "⬢ ".
+	Symbol string `yaml:"symbol"`
+	// fsys is the root file system.  This is used for unit testing.
+	fsys fs.FS
+}
+
+type containerModuleResult struct {
+	// InContainer is true if the shell is running inside a container.
+	InContainer bool
+	// Runtime is the container runtime (e.g. "docker", "podman", "lxc",
+	// "kubernetes"), or "container" if we can tell we're in a container but
+	// not which runtime is being used.
+	Runtime string
+	// Name is the name of the container, if known.
+	Name string
+	// Image is the image the container was created from, if known.
+	Image string
+}
+
+// cgroupRuntimes maps strings found in /proc/1/cgroup to container runtimes.
+// These are checked in order, since a pod's cgroup will mention both
+// "kubepods" and the runtime that runs the pod.
+var cgroupRuntimes = []struct {
+	match   string
+	runtime string
+}{
+	{"kubepods", "kubernetes"},
+	{"libpod", "podman"},
+	{"docker", "docker"},
+	{"lxc", "lxc"},
+	{"containerd", "containerd"},
+}
+
+// Execute the module.
+func (mod ContainerModule) Execute(context *Context) ModuleResult {
+	fsys := mod.fsys
+	if fsys == nil {
+		fsys = os.DirFS("/")
+	}
+
+	data := detectContainer(fsys, context.Getenv)
+
+	text := ""
+	if data.InContainer {
+		text = mod.Symbol + defaultString(data.Name, data.Runtime)
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// detectContainer figures out if we're running in a container.  `fsys` should
+// be rooted at "/".
+func detectContainer(fsys fs.FS, getenv func(string) string) containerModuleResult {
+	result := containerModuleResult{}
+
+	// Podman and toolbox write details about the container to /run/.containerenv.
+	if contents, err := fs.ReadFile(fsys, "run/.containerenv"); err == nil {
+		result.InContainer = true
+		result.Runtime = "podman"
+		values := parseKeyValues(contents)
+		result.Name = values["name"]
+		result.Image = values["image"]
+		if values["engine"] != "" && !strings.HasPrefix(values["engine"], "podman") {
+			result.Runtime = values["engine"]
+		}
+	}
+
+	// systemd-nspawn, podman, and LXC set the "container" variable in the
+	// container's environment.
+	if container := getenv("container"); container != "" {
+		result.InContainer = true
+		if result.Runtime == "" && container != "oci" {
+			result.Runtime = container
+		}
+	}
+
+	if !result.InContainer {
+		if _, err := fs.Stat(fsys, ".dockerenv"); err == nil {
+			result.InContainer = true
+			result.Runtime = "docker"
+		}
+	}
+
+	if result.Runtime == "" {
+		if cgroup, err := fs.ReadFile(fsys, "proc/1/cgroup"); err == nil {
+			for _, entry := range cgroupRuntimes {
+				if bytes.Contains(cgroup, []byte(entry.match)) {
+					result.InContainer = true
+					result.Runtime = entry.runtime
+					break
+				}
+			}
+		}
+	}
+
+	if !result.InContainer {
+		return containerModuleResult{}
+	}
+
+	if result.Runtime == "" {
+		result.Runtime = "container"
+	}
+
+	// Toolbox and distrobox set CONTAINER_ID to the name of the container.
+	if result.Name == "" {
+		result.Name = getenv("CONTAINER_ID")
+	}
+
+	return result
+}
+
+func init() {
+	registerModule(
+		"container",
+		registeredModule{
+			jsonSchema: schemas.ContainerModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := ContainerModule{Type: "container", Symbol: "⬢ "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,81 @@
+package modules
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func dummyGetenv(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestContainerNotInContainer(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: container
+	`)).(*ContainerModule)
+	mod.fsys = fstest.MapFS{
+		"proc/1/cgroup": &fstest.MapFile{Data: []byte("0::/init.scope\n")},
+	}
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, containerModuleResult{}, result.Data)
+}
+
+func TestContainerDocker(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: container
+	`)).(*ContainerModule)
+	mod.fsys = fstest.MapFS{
+		".dockerenv": &fstest.MapFile{},
+	}
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "⬢ docker", result.DefaultText)
+	assert.Equal(t, containerModuleResult{InContainer: true, Runtime: "docker"}, result.Data)
+}
+
+func TestDetectContainerPodman(t *testing.T) {
+	fsys := fstest.MapFS{
+		"run/.containerenv": &fstest.MapFile{Data: []byte(heredoc.Doc(`
+			engine="podman-4.2.0"
+			name="dev-box"
+			id="d5b1f6a4"
+			image="registry.fedoraproject.org/fedora:36"
+			rootless=1
+		`))},
+	}
+
+	result := detectContainer(fsys, dummyGetenv(map[string]string{"container": "oci"}))
+	assert.Equal(t, containerModuleResult{
+		InContainer: true,
+		Runtime:     "podman",
+		Name:        "dev-box",
+		Image:       "registry.fedoraproject.org/fedora:36",
+	}, result)
+}
+
+func TestDetectContainerFromCgroup(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/1/cgroup": &fstest.MapFile{Data: []byte(
+			"12:pids:/kubepods/besteffort/pod1234/docker-abcd\n",
+		)},
+	}
+
+	result := detectContainer(fsys, dummyGetenv(nil))
+	assert.Equal(t, containerModuleResult{InContainer: true, Runtime: "kubernetes"}, result)
+}
+
+func TestDetectContainerFromEnv(t *testing.T) {
+	result := detectContainer(fstest.MapFS{}, dummyGetenv(map[string]string{
+		"container":    "lxc",
+		"CONTAINER_ID": "ubuntu",
+	}))
+	assert.Equal(t, containerModuleResult{InContainer: true, Runtime: "lxc", Name: "ubuntu"}, result)
+
+	result = detectContainer(fstest.MapFS{}, dummyGetenv(map[string]string{"container": "oci"}))
+	assert.Equal(t, containerModuleResult{InContainer: true, Runtime: "container"}, result)
+}
@@ -1,26 +1,36 @@
 package modules
 
 import (
+	"encoding/json"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing/fstest"
 	"time"
 
 	"github.com/jwalton/kitsch/internal/cache"
 	"github.com/jwalton/kitsch/internal/fileutils"
 	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
 	"github.com/jwalton/kitsch/internal/kitsch/env"
 	"github.com/jwalton/kitsch/internal/kitsch/getters"
 	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
 	"github.com/jwalton/kitsch/internal/kitsch/projects"
 	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/jwalton/kitsch/internal/kitsch/toolversions"
 	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
 
 // Globals is a collection of "global" values that are passed to all modules.
 // These values are available to templates via the ".Globals" property.
+//
+// Values which are expensive to work out, like the hostname, are methods
+// instead of fields, and are only computed the first time they are used.
 type Globals struct {
 	// CWD is the current working directory.
 	CWD string `yaml:"cwd"`
@@ -30,14 +40,27 @@ type Globals struct {
 	Home string `yaml:"home"`
 	// IsRoot is true if this is a non-windows system, and the user is UID 0.
 	IsRoot bool `yaml:"isRoot"`
-	// Hostname is the name of the current machine.
-	Hostname string `yaml:"hostname"`
+	// hostname is the name of the current machine.
+	hostname *lazyString
+	// shortUsername is the current user's username, without any domain.
+	shortUsername *lazyString
+	// fullName is the current user's full name.
+	fullName *lazyString
+	// filesystemType is the type of the filesystem CWD is on.
+	filesystemType *lazyString
 	// Jobs is the number of jobs that the shell is currently running.
 	Jobs int `yaml:"jobs"`
 	// Status is the return status of the previous command.
 	Status int `yaml:"previousCommandStatus"`
+	// PipeStatus is the return status of each command in the previous
+	// pipeline.  If the shell doesn't report pipeline statuses, this will
+	// contain only Status.
+	PipeStatus []int `yaml:"pipeStatus"`
 	// PreviousCommandDuration is the duration of the previous command, in milliseconds.
 	PreviousCommandDuration int64 `yaml:"previousCommandDuration"`
+	// PreviousCommand is the command line of the previous command, or "" if
+	// the shell doesn't report it.
+	PreviousCommand string `yaml:"previousCommand"`
 	// Keymap is the zsh/fish keymap. This will be "" if vi mode is not enabled,
 	// "" or "main" in insert mode, and "vicmd" in normal mode.
 	Keymap string `yaml:"keymap"`
@@ -47,6 +70,15 @@ type Globals struct {
 	TerminalWidth int `yaml:"width"`
 	// PathSeparator is the path separator for the current system.
 	PathSeparator string `yaml:"pathSeparator"`
+	// IsWSL is true if we're running in the Windows Subsystem for Linux.
+	IsWSL bool `yaml:"isWSL"`
+	// IsTTY is true if kitsch is connected to a terminal.  This is false when
+	// the prompt is being rendered for something other than a shell, like a
+	// tmux status line or an editor.
+	IsTTY bool `yaml:"isTTY"`
+	// TermProgram is the name of the terminal emulator (e.g. "iTerm.app",
+	// "vscode", "WindowsTerminal"), or "" if it is unknown.
+	TermProgram string `yaml:"termProgram"`
 }
 
 // NewGlobals creates a new Globals object.
@@ -56,8 +88,10 @@ func NewGlobals(
 	logicalCWD string,
 	terminalWidth int,
 	status int,
+	pipeStatus []int,
 	jobs int,
 	previousCommandDuration int64,
+	previousCommand string,
 	keymap string,
 ) Globals {
 	var err error
@@ -74,11 +108,6 @@ func NewGlobals(
 		home = "~"
 	}
 
-	hostname, err := os.Hostname()
-	if err != nil {
-		hostname = ""
-	}
-
 	if terminalWidth <= 0 {
 		terminalWidth, _, err = term.GetSize(int(os.Stdout.Fd()))
 		if err != nil {
@@ -87,20 +116,237 @@ func NewGlobals(
 		}
 	}
 
+	if len(pipeStatus) == 0 {
+		pipeStatus = []int{status}
+	}
+
+	isWSL := false
+	if runtime.GOOS == "linux" {
+		procVersion, _ := os.ReadFile("/proc/version")
+		isWSL = detectWSL(os.Getenv, procVersion)
+	}
+
+	// stdout is captured by the shell, so check stdin and stderr instead.
+	isTTY := term.IsTerminal(int(os.Stdin.Fd())) || term.IsTerminal(int(os.Stderr.Fd()))
+
 	return Globals{
 		CWD:                     cwd,
 		logicalCWD:              logicalCWD,
 		Home:                    home,
 		IsRoot:                  os.Geteuid() == 0,
-		Hostname:                hostname,
+		hostname:                newLazyString(currentHostname),
+		shortUsername:           newLazyString(func() string { return shortUsername(currentUsername(os.Getenv)) }),
+		fullName:                newLazyString(currentUserFullName),
+		filesystemType:          newLazyString(func() string { return filesystemType(cwd) }),
 		Status:                  status,
+		PipeStatus:              pipeStatus,
 		Jobs:                    jobs,
 		PreviousCommandDuration: previousCommandDuration,
+		PreviousCommand:         previousCommand,
 		Keymap:                  keymap,
 		Shell:                   shell,
 		TerminalWidth:           terminalWidth,
 		PathSeparator:           string(os.PathSeparator),
+		IsWSL:                   isWSL,
+		IsTTY:                   isTTY,
+		TermProgram:             detectTermProgram(os.Getenv),
+	}
+}
+
+// detectTermProgram works out which terminal emulator we're running in from
+// environment variables.  Most terminals set TERM_PROGRAM, but some only set
+// a variable of their own.
+func detectTermProgram(getenv func(string) string) string {
+	if termProgram := getenv("TERM_PROGRAM"); termProgram != "" {
+		return termProgram
+	}
+
+	switch {
+	case getenv("WT_SESSION") != "":
+		return "WindowsTerminal"
+	case getenv("TERMINAL_EMULATOR") != "":
+		return getenv("TERMINAL_EMULATOR")
+	case getenv("KITTY_WINDOW_ID") != "":
+		return "kitty"
+	case getenv("ALACRITTY_WINDOW_ID") != "" || getenv("ALACRITTY_SOCKET") != "":
+		return "alacritty"
+	case getenv("KONSOLE_VERSION") != "":
+		return "konsole"
+	case getenv("VTE_VERSION") != "":
+		return "vte"
 	}
+
+	return ""
+}
+
+// lazyString is a string which is computed the first time it is needed.
+type lazyString struct {
+	once  sync.Once
+	get   func() string
+	value string
+	// done is set to 1 once value has been computed.
+	done uint32
+}
+
+func newLazyString(get func() string) *lazyString {
+	return &lazyString{get: get}
+}
+
+// staticString returns a lazyString which already has a value.
+func staticString(value string) *lazyString {
+	s := &lazyString{value: value, done: 1}
+	s.once.Do(func() {})
+	return s
+}
+
+// String returns the value of the lazyString, computing it if required.  This
+// is safe to call from multiple goroutines.
+func (s *lazyString) String() string {
+	if s == nil {
+		return ""
+	}
+	s.once.Do(func() {
+		s.value = s.get()
+		atomic.StoreUint32(&s.done, 1)
+	})
+	return s.value
+}
+
+// computed returns the value of the lazyString if it has already been
+// computed, or nil if it hasn't.  This never computes the value.
+func (s *lazyString) computed() *string {
+	if s == nil || atomic.LoadUint32(&s.done) == 0 {
+		return nil
+	}
+	return &s.value
+}
+
+// Hostname returns the name of the current machine.
+func (globals Globals) Hostname() string {
+	return globals.hostname.String()
+}
+
+// SetHostname sets the name of the current machine.
+func (globals *Globals) SetHostname(hostname string) {
+	globals.hostname = staticString(hostname)
+}
+
+// ShortUsername returns the current user's username, without any domain
+// (e.g. "jwalton" instead of "CORP\jwalton" on Windows).
+func (globals Globals) ShortUsername() string {
+	return globals.shortUsername.String()
+}
+
+// SetShortUsername sets the current user's short username.
+func (globals *Globals) SetShortUsername(username string) {
+	globals.shortUsername = staticString(username)
+}
+
+// FullName returns the current user's full name, or "" if it is unknown.
+func (globals Globals) FullName() string {
+	return globals.fullName.String()
+}
+
+// SetFullName sets the current user's full name.
+func (globals *Globals) SetFullName(fullName string) {
+	globals.fullName = staticString(fullName)
+}
+
+// FilesystemType returns the type of the filesystem the current directory is
+// on (e.g. "ext4", "apfs", or "nfs"), or "" if it is unknown.
+func (globals Globals) FilesystemType() string {
+	return globals.filesystemType.String()
+}
+
+// SetFilesystemType sets the type of the filesystem the current directory is on.
+func (globals *Globals) SetFilesystemType(fsType string) {
+	globals.filesystemType = staticString(fsType)
+}
+
+// IsNetworkFilesystem returns true if the current directory is on a network
+// filesystem (like NFS, SMB, or sshfs), or some other filesystem which is
+// known to be slow.  Modules which do a lot of work in the current directory
+// can use this to do less of it.
+func (globals Globals) IsNetworkFilesystem() bool {
+	return isNetworkFilesystemType(globals.FilesystemType())
+}
+
+// globalsYAML holds the Globals that can't be unmarshalled directly.
+type globalsYAML struct {
+	Hostname       *string `yaml:"hostname"`
+	ShortUsername  *string `yaml:"shortUsername"`
+	FullName       *string `yaml:"fullName"`
+	FilesystemType *string `yaml:"filesystemType"`
+}
+
+// UnmarshalYAML unmarshals Globals from a demo configuration.
+func (globals *Globals) UnmarshalYAML(node *yaml.Node) error {
+	type plainGlobals Globals
+	if err := node.Decode((*plainGlobals)(globals)); err != nil {
+		return err
+	}
+
+	values := globalsYAML{}
+	if err := node.Decode(&values); err != nil {
+		return err
+	}
+	if values.Hostname != nil {
+		globals.SetHostname(*values.Hostname)
+	}
+	if values.ShortUsername != nil {
+		globals.SetShortUsername(*values.ShortUsername)
+	}
+	if values.FullName != nil {
+		globals.SetFullName(*values.FullName)
+	}
+	if values.FilesystemType != nil {
+		globals.SetFilesystemType(*values.FilesystemType)
+	}
+	return nil
+}
+
+// MarshalJSON marshals Globals.  Values which are computed lazily are only
+// included if something has already asked for them, so marshalling Globals
+// never does the work the laziness is meant to avoid.  Call
+// computeLazyValues first to include all of them.
+func (globals Globals) MarshalJSON() ([]byte, error) {
+	type plainGlobals Globals
+	result := struct {
+		plainGlobals
+		Hostname            *string `json:",omitempty"`
+		ShortUsername       *string `json:",omitempty"`
+		FullName            *string `json:",omitempty"`
+		FilesystemType      *string `json:",omitempty"`
+		IsNetworkFilesystem *bool   `json:",omitempty"`
+	}{
+		plainGlobals:   plainGlobals(globals),
+		Hostname:       globals.hostname.computed(),
+		ShortUsername:  globals.shortUsername.computed(),
+		FullName:       globals.fullName.computed(),
+		FilesystemType: globals.filesystemType.computed(),
+	}
+	if result.FilesystemType != nil {
+		isNetworkFilesystem := isNetworkFilesystemType(*result.FilesystemType)
+		result.IsNetworkFilesystem = &isNetworkFilesystem
+	}
+	return json.Marshal(result)
+}
+
+// computeLazyValues computes every value in Globals which is computed lazily.
+func (globals Globals) computeLazyValues() {
+	globals.Hostname()
+	globals.ShortUsername()
+	globals.FullName()
+	globals.FilesystemType()
+}
+
+// currentHostname returns the name of the current machine.
+func currentHostname() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return hostname
 }
 
 // LogicalCWD returns the CWD to display in the directory module.
@@ -126,16 +372,54 @@ type Context struct {
 	ValueCache cache.Cache
 	// Styles is the style registry to use to create styles.
 	Styles *styling.Registry
+	// Partials are the named templates from the configuration, available to
+	// every module's template.
+	Partials *modtemplate.Partials
 	// DefaultTimeout is the default module timeout.
 	DefaultTimeout time.Duration
 	// FlexibleSpaceReplacement is a string to use to replace flexible spaces.
 	// If set, flexible spaces will be replaced with this sentinel value.
 	// See DemoConfig.FlexibleSpaceReplacement for details.
 	FlexibleSpaceReplacement string
+	// Explainer, if set, records the result of every module that is executed.
+	Explainer *Explainer
+	// DebugTemplates, if true, adds a dump of the data that was passed to a
+	// template to the warning when the template fails to execute.
+	DebugTemplates bool
+	// Clock returns the current time.  If nil, `time.Now` is used.
+	Clock func() time.Time
+	// Workers is the maximum number of modules which will be executed at the
+	// same time, across the whole prompt.  If 0, twice the number of CPUs is
+	// used.
+	Workers int
+	// FilesystemRules turn off modules or git in matching directories.
+	FilesystemRules []FilesystemRule
+	// DisabledModules is a list of module types or IDs which should not be
+	// executed.
+	DisabledModules []string
 
 	mutex          sync.Mutex
 	gitInitialized bool
 	git            gitutils.Git
+
+	warningsMutex sync.Mutex
+	warnings      []string
+
+	// sharedModules are modules referenced by "use" modules, indexed by ID.
+	sharedModules map[string]*sharedModule
+
+	templatesMutex sync.Mutex
+	templates      map[templateKey]*compiledTemplate
+
+	filesystemRulesOnce   sync.Once
+	filesystemRulesResult *filesystemRulesResult
+
+	toolVersionsOnce sync.Once
+	toolVersions     *toolversions.Resolver
+
+	// workers limits how many modules run at once.  See acquireWorker.
+	workersOnce sync.Once
+	workers     chan struct{}
 }
 
 // GetWorkingDirectory returns the current working directory.
@@ -158,22 +442,87 @@ func (context *Context) GetValueCache() cache.Cache {
 	return context.ValueCache
 }
 
+// GetToolVersions returns the resolver for tool versions pinned in the
+// current directory.  Every module shares the same resolver, so each version
+// file is only read once per prompt.
+func (context *Context) GetToolVersions() *toolversions.Resolver {
+	context.toolVersionsOnce.Do(func() {
+		context.toolVersions = toolversions.NewResolver(context.Directory)
+	})
+	return context.toolVersions
+}
+
+// HasCommand returns true if the specified executable is on the PATH.
+func (context *Context) HasCommand(command string) bool {
+	_, err := context.Environment.LookPath(command)
+	return err == nil
+}
+
+// LookPath returns the path to the specified executable on the PATH.
+func (context *Context) LookPath(file string) (string, error) {
+	return context.Environment.LookPath(file)
+}
+
+// OS returns the operating system we're running on (e.g. "linux").
+func (context *Context) OS() string {
+	return context.Environment.OS()
+}
+
+// IsInGitRepo returns true if the current working directory is inside a git repo.
+func (context *Context) IsInGitRepo() bool {
+	return context.Git() != nil
+}
+
+// Shell returns the type of the current shell.
+func (context *Context) Shell() string {
+	return context.Globals.Shell
+}
+
+// Now returns the current time.
+func (context *Context) Now() time.Time {
+	if context.Clock != nil {
+		return context.Clock()
+	}
+	return time.Now()
+}
+
 // Make sure that Context implements the GetterContext interface.
 var _ getters.GetterContext = (*Context)(nil)
 
+// Make sure that Context implements the condition.Environment interface.
+var _ condition.Environment = (*Context)(nil)
+
 // Git returns a git instance for the current repo, or nil if the current
 // working directory is not part of a git repo, or git is not installed.
 func (context *Context) Git() gitutils.Git {
 	context.mutex.Lock()
 	defer context.mutex.Unlock()
 
+	if !context.gitInitialized && context.isGitDisabled() {
+		context.gitInitialized = true
+	}
 	if !context.gitInitialized {
-		context.git = gitutils.NewCaching("git", context.Globals.CWD)
+		// Find the root of the repo with the directory, so the search is
+		// shared with every other module looking for files in our ancestors.
+		if gitFolder := context.Directory.FindFileInAncestors(".git"); gitFolder != "" {
+			context.git = gitutils.NewCaching("git", filepath.Dir(gitFolder))
+		}
 		context.gitInitialized = true
 	}
 	return context.git
 }
 
+// Warnings returns all the warnings generated by modules that have executed
+// so far.  Warnings are collected here instead of being printed as soon as
+// they happen, so the caller can report them once the prompt has been
+// rendered.
+func (context *Context) Warnings() []string {
+	context.warningsMutex.Lock()
+	defer context.warningsMutex.Unlock()
+
+	return append([]string(nil), context.warnings...)
+}
+
 // GetStyle returns the specified style, or logs a warning and returns an empty style
 // if the style string cannot be parsed.
 func (context *Context) GetStyle(styleString string) *styling.Style {
@@ -192,12 +541,13 @@ func NewContext(
 	projectTypes []projects.ProjectType,
 	defaultTimeout time.Duration,
 	scanTimeout time.Duration,
+	maxScanEntries int,
 	cacheDir string,
 	styles *styling.Registry,
 ) Context {
 	return Context{
 		Globals:        globals,
-		Directory:      fileutils.NewDirectory(globals.CWD, scanTimeout),
+		Directory:      fileutils.NewDirectory(globals.CWD, scanTimeout, maxScanEntries),
 		Environment:    env.New(),
 		ProjectTypes:   projectTypes,
 		ValueCache:     cache.NewFileCache(cacheDir),
@@ -216,6 +566,10 @@ type DemoConfig struct {
 	Env map[string]string `yaml:"env"`
 	// Git is the git instance to use.
 	Git gitutils.DemoGit `yaml:"git"`
+	// GitFiles are the contents of the ".git" folder of a git repo at the
+	// current working directory, as a map of paths relative to the ".git"
+	// folder to file contents.  This is ignored if Git is set.
+	GitFiles map[string]string `yaml:"gitFiles"`
 	// CWDIsReadOnly is true if the current working directory is read-only.
 	CWDIsReadOnly bool `yaml:"cwdIsReadOnly"`
 	// FlexibleSpaceReplacement is a string to use to replace flexible spaces.
@@ -223,23 +577,36 @@ type DemoConfig struct {
 	// with a sentinel value, and then in documentation generation we can use
 	// flexbox to make flexible spaces look good in the docs.
 	FlexibleSpaceReplacement string `yaml:"flexibleSpaceReplacement"`
+	// Files is a map of files in the current working directory, and their
+	// contents.
+	Files map[string]string `yaml:"files"`
+	// Time is the current time.  If this is the zero time, the real current
+	// time will be used.
+	Time time.Time `yaml:"time"`
+}
+
+// NewDemoConfig returns a DemoConfig with sensible defaults.
+func NewDemoConfig() DemoConfig {
+	return DemoConfig{
+		Globals: Globals{
+			CWD:           "/users/jwalton",
+			Home:          "/users/jwalton",
+			IsRoot:        false,
+			hostname:      staticString("orac"),
+			Shell:         "demo",
+			TerminalWidth: 80,
+			PathSeparator: "/",
+		},
+		Env: map[string]string{
+			"USER": "jwalton",
+		},
+	}
 }
 
 // Load will load the demo configuration from the specified file.
 func (demoConfig *DemoConfig) Load(filename string) error {
 	// Set sensible defaults.
-	demoConfig.Globals = Globals{
-		CWD:           "/users/jwalton",
-		Home:          "/users/jwalton",
-		IsRoot:        false,
-		Hostname:      "orac",
-		Shell:         "demo",
-		TerminalWidth: 80,
-		PathSeparator: "/",
-	}
-	demoConfig.Env = map[string]string{
-		"USER": "jwalton",
-	}
+	*demoConfig = NewDemoConfig()
 
 	yamlData, err := os.ReadFile(filename)
 	if err != nil {
@@ -264,7 +631,26 @@ func NewDemoContext(
 	}
 
 	demoFsys := fstest.MapFS{
-		".": {Mode: cwdMode},
+		".": {Mode: fs.ModeDir | cwdMode},
+	}
+	for name, contents := range config.Files {
+		demoFsys[name] = &fstest.MapFile{Data: []byte(contents), Mode: 0644}
+	}
+
+	// If no git repo was configured, then we're not in a git repo.
+	var git gitutils.Git
+	if config.Git != (gitutils.DemoGit{}) {
+		git = config.Git
+	} else if len(config.GitFiles) > 0 {
+		fakeGit, err := gitutils.NewFromFS(config.Globals.CWD, gitutils.GitFilesFS(config.GitFiles))
+		if err == nil {
+			git = fakeGit
+		}
+	}
+
+	var clock func() time.Time
+	if !config.Time.IsZero() {
+		clock = func() time.Time { return config.Time }
 	}
 
 	return Context{
@@ -275,9 +661,10 @@ func NewDemoContext(
 		ValueCache:               cache.NewMemoryCache(),
 		Styles:                   styles,
 		gitInitialized:           true,
-		git:                      config.Git,
+		git:                      git,
 		DefaultTimeout:           1000 * time.Millisecond,
 		FlexibleSpaceReplacement: config.FlexibleSpaceReplacement,
+		Clock:                    clock,
 	}
 }
 
@@ -293,7 +680,7 @@ func newTestContext(username string) *Context {
 			CWD:                     "/Users/" + username,
 			Home:                    "/Users/" + username,
 			IsRoot:                  false,
-			Hostname:                "lucid",
+			hostname:                staticString("lucid"),
 			Status:                  0,
 			Jobs:                    0,
 			PreviousCommandDuration: 0,
@@ -0,0 +1,107 @@
+package modules
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetectTermProgram(t *testing.T) {
+	getenv := func(env map[string]string) func(string) string {
+		return func(key string) string { return env[key] }
+	}
+
+	assert.Equal(t, "", detectTermProgram(getenv(nil)))
+	assert.Equal(t, "iTerm.app", detectTermProgram(getenv(map[string]string{
+		"TERM_PROGRAM": "iTerm.app",
+		"VTE_VERSION":  "6800",
+	})))
+	assert.Equal(t, "WindowsTerminal", detectTermProgram(getenv(map[string]string{
+		"WT_SESSION": "0fd2c3c4-6d5e-4f2a-9d0c-1b6b3c4f5a6e",
+	})))
+	assert.Equal(t, "JetBrains-JediTerm", detectTermProgram(getenv(map[string]string{
+		"TERMINAL_EMULATOR": "JetBrains-JediTerm",
+	})))
+	assert.Equal(t, "kitty", detectTermProgram(getenv(map[string]string{
+		"KITTY_WINDOW_ID": "1",
+	})))
+	assert.Equal(t, "vte", detectTermProgram(getenv(map[string]string{
+		"VTE_VERSION": "6800",
+	})))
+}
+
+func TestLazyString(t *testing.T) {
+	calls := 0
+	value := newLazyString(func() string {
+		calls++
+		return "orac"
+	})
+	assert.Equal(t, 0, calls)
+
+	assert.Equal(t, "orac", value.String())
+	assert.Equal(t, "orac", value.String())
+	assert.Equal(t, 1, calls)
+
+	var missing *lazyString
+	assert.Equal(t, "", missing.String())
+}
+
+func TestGlobalsLazyValues(t *testing.T) {
+	globals := Globals{CWD: "/Users/jwalton"}
+	assert.Equal(t, "", globals.Hostname())
+
+	globals.SetHostname("lucid")
+	globals.SetFullName("Jason Walton")
+	assert.Equal(t, "lucid", globals.Hostname())
+
+	data, err := json.Marshal(globals)
+	assert.NoError(t, err)
+	decoded := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "/Users/jwalton", decoded["CWD"])
+	assert.Equal(t, "lucid", decoded["Hostname"])
+	assert.Equal(t, "Jason Walton", decoded["FullName"])
+}
+
+func TestGlobalsMarshalJSONDoesNotComputeLazyValues(t *testing.T) {
+	calls := 0
+	globals := Globals{CWD: "/Users/jwalton"}
+	globals.hostname = newLazyString(func() string {
+		calls++
+		return "lucid"
+	})
+
+	data, err := json.Marshal(globals)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, calls)
+	assert.NotContains(t, string(data), "Hostname")
+	assert.NotContains(t, string(data), "IsNetworkFilesystem")
+
+	globals.computeLazyValues()
+	data, err = json.Marshal(globals)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, string(data), `"Hostname":"lucid"`)
+}
+
+func TestGlobalsUnmarshalYAML(t *testing.T) {
+	config := NewDemoConfig()
+	err := yaml.Unmarshal([]byte(heredoc.Doc(`
+		globals:
+		  cwd: /tmp
+		  hostname: webserver
+	`)), &config)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp", config.Globals.CWD)
+	assert.Equal(t, "/users/jwalton", config.Globals.Home)
+	assert.Equal(t, "webserver", config.Globals.Hostname())
+}
+
+func TestGetToolVersionsIsShared(t *testing.T) {
+	context := newTestContext("jwalton")
+	assert.NotNil(t, context.GetToolVersions())
+	assert.Same(t, context.GetToolVersions(), context.GetToolVersions())
+}
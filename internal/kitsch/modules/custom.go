@@ -1,10 +1,10 @@
 package modules
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/jwalton/kitsch/internal/kitsch/getters"
-	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
 	"gopkg.in/yaml.v3"
 )
@@ -50,9 +50,10 @@ func (mod CustomModule) Execute(context *Context) ModuleResult {
 		Cache: mod.Cache,
 	}
 
+	var warnings []string
 	value, err := getter.GetValue(context)
 	if err != nil {
-		log.Warn("Error executing custom module: ", err)
+		warnings = append(warnings, fmt.Sprint("Error executing custom module: ", err))
 		value = ""
 	}
 
@@ -64,7 +65,7 @@ func (mod CustomModule) Execute(context *Context) ModuleResult {
 		text = ""
 	}
 
-	return ModuleResult{DefaultText: text, Data: value}
+	return ModuleResult{DefaultText: text, Data: value, Warnings: warnings}
 }
 
 func init() {
@@ -0,0 +1,144 @@
+package modules
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas DevEnvironmentModule
+
+// DevEnvironmentModule shows when the current project has a Vagrantfile or a
+// dev container configuration, and whether that environment is running, so
+// you know if you need to `vagrant up` or reopen the project in a container.
+//
+type DevEnvironmentModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=dev_environment"`
+	// VagrantSymbol is the symbol to show before the state of the Vagrant
+	// machine.  Defaults to "⍱ ".
+	VagrantSymbol string `yaml:"vagrantSymbol"`
+	// DevContainerSymbol is the symbol to show before the state of the dev
+	// container.  Defaults to "⬡ ".
+	DevContainerSymbol string `yaml:"devContainerSymbol"`
+}
+
+type devEnvironmentModuleResult struct {
+	// Vagrantfile is the path to the project's Vagrantfile, or "" if there
+	// is none.
+	Vagrantfile string
+	// VagrantState is the state of the project's Vagrant machine, as reported
+	// by `vagrant status` (e.g. "running", "poweroff", "saved"), or
+	// "not_created" if the machine has not been created.
+	VagrantState string
+	// HasDevContainer is true if the project has a dev container
+	// configuration.
+	HasDevContainer bool
+	// InDevContainer is true if the shell is running inside a dev container.
+	InDevContainer bool
+}
+
+// vagrantMachineIndex is Vagrant's global index of machines, from
+// "~/.vagrant.d/data/machine-index/index".
+type vagrantMachineIndex struct {
+	Machines map[string]struct {
+		Name            string `json:"name"`
+		State           string `json:"state"`
+		VagrantfilePath string `json:"vagrantfile_path"`
+	} `json:"machines"`
+}
+
+// Execute the module.
+func (mod DevEnvironmentModule) Execute(context *Context) ModuleResult {
+	data := devEnvironmentModuleResult{}
+	directory := context.Directory
+
+	if directory.HasFile("Vagrantfile") {
+		data.Vagrantfile = filepath.Join(directory.Path(), "Vagrantfile")
+	} else {
+		data.Vagrantfile = directory.FindFileInAncestors("Vagrantfile")
+	}
+	if data.Vagrantfile != "" {
+		vagrantHome := context.Getenv("VAGRANT_HOME")
+		if vagrantHome == "" {
+			vagrantHome = filepath.Join(context.Globals.Home, ".vagrant.d")
+		}
+		data.VagrantState = vagrantMachineState(vagrantHome, filepath.Dir(data.Vagrantfile))
+	}
+
+	// VS Code sets REMOTE_CONTAINERS inside a dev container, and GitHub
+	// Codespaces sets CODESPACES.
+	data.InDevContainer = context.Getenv("REMOTE_CONTAINERS") == "true" ||
+		context.Getenv("CODESPACES") == "true"
+	data.HasDevContainer = data.InDevContainer ||
+		directory.HasFile(".devcontainer") ||
+		directory.HasFile(".devcontainer.json") ||
+		directory.FindFileInAncestors(".devcontainer") != "" ||
+		directory.FindFileInAncestors(".devcontainer.json") != ""
+
+	text := ""
+	if data.Vagrantfile != "" {
+		text = mod.VagrantSymbol + data.VagrantState
+	}
+	if data.HasDevContainer {
+		if text != "" {
+			text += " "
+		}
+		if data.InDevContainer {
+			text += mod.DevContainerSymbol + "in container"
+		} else {
+			text += mod.DevContainerSymbol + "not in container"
+		}
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// vagrantMachineState returns the state of the Vagrant machine for the
+// Vagrantfile in `projectDir` from Vagrant's machine index.  If there are
+// several machines, and any are running, this returns "running".
+func vagrantMachineState(vagrantHome string, projectDir string) string {
+	state := "not_created"
+
+	contents, err := os.ReadFile(filepath.Join(vagrantHome, "data", "machine-index", "index"))
+	if err != nil {
+		return state
+	}
+
+	index := vagrantMachineIndex{}
+	if err := json.Unmarshal(contents, &index); err != nil {
+		return state
+	}
+
+	for _, machine := range index.Machines {
+		if filepath.Clean(machine.VagrantfilePath) != filepath.Clean(projectDir) {
+			continue
+		}
+		if machine.State == "running" || state == "not_created" {
+			state = machine.State
+		}
+	}
+
+	return state
+}
+
+func init() {
+	registerModule(
+		"dev_environment",
+		registeredModule{
+			jsonSchema: schemas.DevEnvironmentModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := DevEnvironmentModule{
+					Type:               "dev_environment",
+					VagrantSymbol:      "⍱ ",
+					DevContainerSymbol: "⬡ ",
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
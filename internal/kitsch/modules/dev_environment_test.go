@@ -0,0 +1,97 @@
+package modules
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVagrantHome creates a VAGRANT_HOME folder with a running machine in
+// /Users/jwalton and a stopped one in /Users/jwalton/other.
+func fakeVagrantHome(t *testing.T) string {
+	vagrantHome := t.TempDir()
+	writeTestFile(t, filepath.Join(vagrantHome, "data", "machine-index", "index"), `{
+		"version": 1,
+		"machines": {
+			"0b5d1c3e": {
+				"local_data_path": "/Users/jwalton/.vagrant",
+				"name": "default",
+				"provider": "virtualbox",
+				"state": "running",
+				"vagrantfile_path": "/Users/jwalton"
+			},
+			"9f2c4a7d": {
+				"local_data_path": "/Users/jwalton/other/.vagrant",
+				"name": "default",
+				"provider": "virtualbox",
+				"state": "poweroff",
+				"vagrantfile_path": "/Users/jwalton/other"
+			}
+		}
+	}`)
+	return vagrantHome
+}
+
+func TestDevEnvironmentVagrant(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dev_environment
+	`)).(*DevEnvironmentModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"VAGRANT_HOME": fakeVagrantHome(t)}, fstest.MapFS{
+		"Vagrantfile": &fstest.MapFile{},
+	}))
+
+	assert.Equal(t, "⍱ running", result.DefaultText)
+	assert.Equal(t, devEnvironmentModuleResult{
+		Vagrantfile:  "/Users/jwalton/Vagrantfile",
+		VagrantState: "running",
+	}, result.Data)
+}
+
+func TestDevEnvironmentVagrantNotCreated(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dev_environment
+	`)).(*DevEnvironmentModule)
+
+	context := newTestContextWith(map[string]string{"VAGRANT_HOME": fakeVagrantHome(t)}, nil)
+	context.Directory = fileutils.NewDirectoryTestFS("/Users/jwalton/new", fstest.MapFS{
+		"Vagrantfile": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, "⍱ not_created", result.DefaultText)
+}
+
+func TestDevEnvironmentDevContainer(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dev_environment
+	`)).(*DevEnvironmentModule)
+
+	files := fstest.MapFS{
+		".devcontainer/devcontainer.json": &fstest.MapFile{},
+	}
+
+	result := mod.Execute(newTestContextWith(map[string]string{"VAGRANT_HOME": fakeVagrantHome(t)}, files))
+	assert.Equal(t, "⬡ not in container", result.DefaultText)
+	assert.Equal(t, devEnvironmentModuleResult{HasDevContainer: true}, result.Data)
+
+	result = mod.Execute(newTestContextWith(map[string]string{"VAGRANT_HOME": fakeVagrantHome(t), "REMOTE_CONTAINERS": "true"}, files))
+	assert.Equal(t, "⬡ in container", result.DefaultText)
+	assert.Equal(t, devEnvironmentModuleResult{HasDevContainer: true, InDevContainer: true}, result.Data)
+}
+
+func TestDevEnvironmentNone(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dev_environment
+	`)).(*DevEnvironmentModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"VAGRANT_HOME": fakeVagrantHome(t)}, fstest.MapFS{
+		"package.json": &fstest.MapFile{},
+	}))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, devEnvironmentModuleResult{}, result.Data)
+}
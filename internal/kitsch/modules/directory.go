@@ -13,10 +13,34 @@ const defaultHomeSymbol = "~"
 const defaultTruncationLength = 3
 const defaultTruncationSymbol = "…"
 
+// truncationModeUniquePrefix shortens each folder in the middle of the path to
+// the shortest prefix that is unique within its parent folder.
+const truncationModeUniquePrefix = "uniquePrefix"
+
+// truncationModeRepo shows the name of the git repo followed by the full path
+// inside the repo, without truncating it.
+const truncationModeRepo = "repo"
+
 func getVolumeName(path string) string {
 	return filepath.VolumeName(path)
 }
 
+// listFolders returns the names of all the folders inside the given folder.
+func listFolders(path string) []string {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Type()&os.ModeSymlink != 0 {
+			result = append(result, entry.Name())
+		}
+	}
+	return result
+}
+
 //go:generate go run ../genSchema/main.go --pkg schemas DirectoryModule
 
 // DirectoryModule shows the current working directory.
@@ -25,6 +49,8 @@ func getVolumeName(path string) string {
 //
 // • Path - The directory to show.
 //
+// • Components - The list of components in the path, each with a Name and Path.
+//
 type DirectoryModule struct {
 	// Type is the type of this module.
 	Type string `yaml:"type" jsonschema:",required,enum=directory"`
@@ -33,6 +59,9 @@ type DirectoryModule struct {
 	HomeSymbol string `yaml:"homeSymbol"`
 	// ReadOnlySymbol is the symbol to append to the directory if it is read-only.
 	ReadOnlySymbol string `yaml:"readOnlySymbol"`
+	// NetworkSymbol is the symbol to append to the directory if it is on a
+	// network filesystem.
+	NetworkSymbol string `yaml:"networkSymbol"`
 	// TruncateToRepo controls whether we truncate to the root directory of the
 	// git repo or not.  If this is true, and we are in a source code repository,
 	// we will replace everything up to the repo root directory with RepoSymbol.
@@ -45,8 +74,41 @@ type DirectoryModule struct {
 	// TruncationSymbol will be added to the start of the string in place of any
 	// paths that were removed.  Defaults to "…".
 	TruncationSymbol string `yaml:"truncationSymbol"`
+	// Substitutions is a map where keys are folders, and values are the symbol
+	// or name to show in place of that folder.  Keys may start with "~" to refer
+	// to the user's home directory.  If more than one key matches the current
+	// directory, the longest one is used.  Substitutions are applied before
+	// the path is truncated.
+	Substitutions map[string]string `yaml:"substitutions"`
+	// TruncationMode controls how folders in the middle of the path are shown.
+	// If this is "uniquePrefix", then every folder other than the first and the
+	// last will be shortened to the shortest prefix that is unique among the
+	// folders in its parent.  For example "~/dev/kitsch/internal" might be
+	// shown as "~/d/k/internal".  If this is "repo", then inside a git repo
+	// this will show the name of the repo followed by the full path inside the
+	// repo, regardless of TruncateToRepo and TruncationLength.  Outside of a git
+	// repo this behaves the same as "length".
+	TruncationMode string `yaml:"truncationMode" jsonschema:",enum=length:uniquePrefix:repo"`
+	// Separator, if set, is shown between each component of the path in place
+	// of the path separator.  Setting Separator, ComponentStyle,
+	// CurrentComponentStyle, or Hyperlinks renders each component of the path
+	// as a separate segment.
+	Separator string `yaml:"separator"`
+	// SeparatorStyle is the style to apply to the separator between
+	// components.
+	SeparatorStyle string `yaml:"separatorStyle"`
+	// ComponentStyle is the style to apply to each component of the path.
+	ComponentStyle string `yaml:"componentStyle"`
+	// CurrentComponentStyle is the style to apply to the last component of the
+	// path (the current directory).  Defaults to ComponentStyle.
+	CurrentComponentStyle string `yaml:"currentComponentStyle"`
+	// Hyperlinks, if true, makes each component of the path an OSC 8 hyperlink
+	// to that folder, in terminals that support it.
+	Hyperlinks bool `yaml:"hyperlinks"`
 
 	getVolumeName func(string) string
+	// listFolders returns the names of the folders inside a folder.
+	listFolders func(string) []string
 }
 
 type directoryModuleResult struct {
@@ -58,20 +120,82 @@ type directoryModuleResult struct {
 	ReadOnly bool
 	// ReadOnlySymbol is the same as ReadOnlySymbol from the module configuration.
 	ReadOnlySymbol string
+	// IsNetworkFilesystem is true if the current directory is on a network
+	// filesystem, or some other filesystem which is known to be slow.
+	IsNetworkFilesystem bool
+	// Components is the list of components in the path that will be shown to
+	// the user.
+	Components []directoryComponent
+	// RepoName is the name of the git repo we are in, or "" if we are not in a
+	// git repo.
+	RepoName string
+	// RepoSubpath is the path to the current directory relative to the root of
+	// the git repo, or "" if we are at the root or not in a git repo.
+	RepoSubpath string
 }
 
-// Removes `truncatePath` from the start of `path`.  The returned path will always
-// start with a path separator.
-func (mod DirectoryModule) truncateToFolder(path string, truncatePath string) string {
-	charsToStrip := len(truncatePath)
+// directoryComponent is a single component of the directory shown to the user.
+type directoryComponent struct {
+	// Name is the text to show for this component.  This will usually be the
+	// name of a folder, but could also be the HomeSymbol, the TruncationSymbol,
+	// or the name of the git repo.
+	Name string
+	// Path is the full path to the folder this component represents, or "" if
+	// this component is the TruncationSymbol.
+	Path string
+}
+
+// splitPath splits `path` into components.  `base` is the path to the folder
+// `path` is relative to.  If `path` starts with a path separator, the
+// returned components will not include an empty component for the separator.
+func splitPath(base string, path string, pathSeparator string) []directoryComponent {
+	path = strings.TrimPrefix(path, pathSeparator)
+	if path == "" {
+		return nil
+	}
+
+	names := strings.Split(path, pathSeparator)
+	result := make([]directoryComponent, len(names))
+	for index, name := range names {
+		if strings.HasSuffix(base, pathSeparator) || base == "" {
+			base += name
+		} else {
+			base += pathSeparator + name
+		}
+		result[index] = directoryComponent{Name: name, Path: base}
+	}
+	return result
+}
 
-	truncatePathEndsWithSeparator := len(truncatePath) > 0 && truncatePath[len(truncatePath)-1] == os.PathSeparator
-	if truncatePathEndsWithSeparator {
-		// Leave the separator
-		charsToStrip--
+// isInFolder returns true if `path` is `folder` or a descendant of `folder`.
+func isInFolder(path string, folder string, pathSeparator string) bool {
+	if folder == "" || !strings.HasPrefix(path, folder) {
+		return false
 	}
+	return len(path) == len(folder) ||
+		strings.HasSuffix(folder, pathSeparator) ||
+		strings.HasPrefix(path[len(folder):], pathSeparator)
+}
 
-	return path[charsToStrip:]
+// findSubstitution returns the longest folder in Substitutions which contains
+// `path`, along with the name to show in its place.
+func (mod DirectoryModule) findSubstitution(path string, home string, pathSeparator string) (folder string, name string, found bool) {
+	for key, value := range mod.Substitutions {
+		keyFolder := key
+		if key == "~" || strings.HasPrefix(key, "~/") || strings.HasPrefix(key, "~"+pathSeparator) {
+			keyFolder = home + key[1:]
+		}
+		if len(keyFolder) > 1 {
+			keyFolder = strings.TrimSuffix(keyFolder, pathSeparator)
+		}
+
+		if isInFolder(path, keyFolder, pathSeparator) && (!found || len(keyFolder) > len(folder)) {
+			folder = keyFolder
+			name = value
+			found = true
+		}
+	}
+	return folder, name, found
 }
 
 // Execute the directory module.
@@ -81,70 +205,229 @@ func (mod DirectoryModule) Execute(context *Context) ModuleResult {
 	pathSeparator := context.Globals.PathSeparator
 	path := context.Globals.LogicalCWD()
 	volumeName := mod.getVolumeName(path)
-	isHome := strings.HasPrefix(path, context.Globals.Home)
+	home := context.Globals.Home
+	isHome := isInFolder(path, home, pathSeparator)
 
-	prefix := ""
+	// `first` is the first component of the path, and `rest` is everything
+	// after it.  `keepFirst` is true if the first component should still be
+	// shown when the path is truncated.
+	var first directoryComponent
+	var rest []directoryComponent
+	keepFirst := false
+	// extraLength is added to the truncation length, if the first component
+	// shouldn't count towards the number of directories to show.
+	extraLength := 0
 
 	// TODO: Should add a timeout to figuring out if this is a git repo or not.
 	// This sometimes takes a long time, and we end up timing out the entire
 	// directory module.
 	git := context.Git()
-	if mod.TruncateToRepo && git != nil && strings.HasPrefix(path, git.RepoRoot()) {
-		// Truncate to root of git repo if we're in a git repo.
+	repoName, repoSubpath := "", ""
+	isInRepo := git != nil && isInFolder(path, git.RepoRoot(), pathSeparator)
+	if isInRepo {
 		gitRepoParts := strings.Split(git.RepoRoot(), pathSeparator)
-		prefix = mod.RepoSymbol + gitRepoParts[len(gitRepoParts)-1]
-		path = mod.truncateToFolder(path, git.RepoRoot())
-		isHome = false
+		repoName = gitRepoParts[len(gitRepoParts)-1]
+		repoSubpath = strings.TrimPrefix(strings.TrimPrefix(path, git.RepoRoot()), pathSeparator)
+	}
+	repoMode := mod.TruncationMode == truncationModeRepo
+	inRepo := isInRepo && (mod.TruncateToRepo || repoMode)
+	// showingFullRepoPath is true if we should show the full path inside the repo.
+	showingFullRepoPath := false
+	substitutionFolder, substitution, hasSubstitution := mod.findSubstitution(path, home, pathSeparator)
+
+	if hasSubstitution && (!inRepo || len(substitutionFolder) >= len(git.RepoRoot())) {
+		// Replace the folder with the substitution, unless we're in a git repo
+		// inside that folder.
+		first = directoryComponent{Name: substitution, Path: substitutionFolder}
+		rest = splitPath(substitutionFolder, strings.TrimPrefix(path, substitutionFolder), pathSeparator)
+		keepFirst = true
+		extraLength = 1
+	} else if inRepo {
+		// Truncate to root of git repo if we're in a git repo.
+		repoRoot := git.RepoRoot()
+		first = directoryComponent{Name: mod.RepoSymbol + repoName, Path: repoRoot}
+		rest = splitPath(repoRoot, repoSubpath, pathSeparator)
+		keepFirst = true
+		showingFullRepoPath = repoMode
+	} else if isHome && mod.TruncationLength > 0 {
+		// Truncate to the user's home directory, if we're in their home directory.
+		first = directoryComponent{Name: mod.HomeSymbol, Path: home}
+		rest = splitPath(home, strings.TrimPrefix(path, home), pathSeparator)
+		// There's no sense in replacing "~" with "...".
+		extraLength = 1
 	} else if volumeName != "" && !isHome {
-		// If the path starts with a volume name, remove it.
-		prefix = volumeName
-		path = path[len(volumeName):]
+		// If the path starts with a volume name, show it even if we truncate.
+		first = directoryComponent{Name: volumeName, Path: volumeName + pathSeparator}
+		rest = splitPath(volumeName+pathSeparator, path[len(volumeName):], pathSeparator)
+		if len(rest) == 0 {
+			// Make sure the root of the volume ends in a separator.
+			rest = []directoryComponent{{Name: "", Path: first.Path}}
+		}
+		keepFirst = true
+		extraLength = 1
+	} else {
+		names := strings.SplitN(path, pathSeparator, 2)
+		first = directoryComponent{Name: names[0], Path: names[0] + pathSeparator}
+		if len(names) > 1 {
+			rest = splitPath(first.Path, names[1], pathSeparator)
+			if len(rest) == 0 {
+				rest = []directoryComponent{{Name: "", Path: first.Path}}
+			}
+		}
 	}
 
+	components := append([]directoryComponent{first}, rest...)
+
 	// Truncate path `truncationLength`.
-	if mod.TruncationLength > 0 {
-		truncationLength := mod.TruncationLength
-		if isHome {
-			// Truncate to the user's home directory, if we're in their home directory.
-			path = mod.HomeSymbol + mod.truncateToFolder(path, context.Globals.Home)
-
-			// Add one to the truncation length, as there's no sense in replaceing
-			// "~" with "..."
-			truncationLength++
-		} else if volumeName != "" {
-			truncationLength++
-		}
-
-		parts := strings.Split(path, pathSeparator)
-
-		if len(parts) > truncationLength {
-			parts = parts[len(parts)-mod.TruncationLength:]
-			path = truncationSymbol + pathSeparator + strings.Join(parts, pathSeparator)
-			// If there's a prefix, add a leading path separator before the truncation symbol.
-			if prefix != "" {
-				path = pathSeparator + path
-			}
+	if mod.TruncationLength > 0 && !showingFullRepoPath && len(components) > mod.TruncationLength+extraLength {
+		truncated := []directoryComponent{}
+		if keepFirst {
+			truncated = append(truncated, first)
 		}
+		truncated = append(truncated, directoryComponent{Name: truncationSymbol})
+		components = append(truncated, rest[len(rest)-mod.TruncationLength:]...)
+	}
+
+	if mod.TruncationMode == truncationModeUniquePrefix {
+		mod.shortenToUniquePrefixes(components)
 	}
 
 	dirInfo, err := context.Directory.Stat(".")
 	readOnly := err == nil && dirInfo.Mode()&0200 == 0
+	// Don't work out the filesystem type unless we need it.
+	isNetwork := mod.NetworkSymbol != "" && context.Globals.IsNetworkFilesystem()
 
 	data := directoryModuleResult{
-		Path:           prefix + path,
-		PathSeparator:  pathSeparator,
-		ReadOnly:       readOnly,
-		ReadOnlySymbol: mod.ReadOnlySymbol,
+		Path:                joinComponents(components, pathSeparator),
+		PathSeparator:       pathSeparator,
+		ReadOnly:            readOnly,
+		ReadOnlySymbol:      mod.ReadOnlySymbol,
+		IsNetworkFilesystem: isNetwork,
+		Components:          components,
+		RepoName:            repoName,
+		RepoSubpath:         repoSubpath,
 	}
 
 	text := data.Path
+	if mod.isSegmented() {
+		text = mod.renderSegments(context, components)
+	}
 	if readOnly {
 		text += data.ReadOnlySymbol
 	}
+	if isNetwork {
+		text += mod.NetworkSymbol
+	}
 
 	return ModuleResult{DefaultText: text, Data: data}
 }
 
+// shortenToUniquePrefixes replaces the name of every component between the
+// first and the last with the shortest prefix that doesn't match any other
+// folder in the same parent.
+func (mod DirectoryModule) shortenToUniquePrefixes(components []directoryComponent) {
+	for index := 1; index < len(components)-1; index++ {
+		component := &components[index]
+		if component.Name == "" || component.Path == "" || !strings.HasSuffix(component.Path, component.Name) {
+			// Skip the truncation symbol, and anything else that isn't the
+			// name of a folder.
+			continue
+		}
+
+		parent := component.Path[:len(component.Path)-len(component.Name)]
+		component.Name = uniquePrefix(component.Name, mod.listFolders(parent))
+	}
+}
+
+// uniquePrefix returns the shortest prefix of `name` which is not also a prefix
+// of any of `siblings`.  Leading "."s don't count towards the length of the
+// prefix, so ".config" will be shortened to ".c" instead of ".".  If there is
+// no unique prefix, returns `name`.
+func uniquePrefix(name string, siblings []string) string {
+	runes := []rune(name)
+	length := 1
+	for length < len(runes) && runes[length-1] == '.' {
+		length++
+	}
+
+	for ; length < len(runes); length++ {
+		prefix := string(runes[:length])
+		unique := true
+		for _, sibling := range siblings {
+			if sibling != name && strings.HasPrefix(sibling, prefix) {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return prefix
+		}
+	}
+	return name
+}
+
+// joinComponents joins the names of the given components into a path.
+func joinComponents(components []directoryComponent, pathSeparator string) string {
+	var result strings.Builder
+	for index, component := range components {
+		if index > 0 {
+			result.WriteString(pathSeparator)
+		}
+		result.WriteString(component.Name)
+	}
+	return result.String()
+}
+
+// isSegmented returns true if each component of the path should be rendered
+// as a separate segment.
+func (mod DirectoryModule) isSegmented() bool {
+	return mod.Hyperlinks ||
+		mod.Separator != "" ||
+		mod.ComponentStyle != "" ||
+		mod.CurrentComponentStyle != ""
+}
+
+// renderSegments renders each component of the path separately, with its own
+// style and hyperlink, joined together with Separator.
+func (mod DirectoryModule) renderSegments(context *Context, components []directoryComponent) string {
+	separator := defaultString(mod.Separator, context.Globals.PathSeparator)
+	if mod.SeparatorStyle != "" {
+		separator = context.GetStyle(mod.SeparatorStyle).Apply(separator)
+	}
+
+	var result strings.Builder
+	count := 0
+	for index, component := range components {
+		name := component.Name
+		if name == "" {
+			if index != 0 {
+				// Skip the empty component at the root of a volume.
+				continue
+			}
+			// The root folder.
+			name = context.Globals.PathSeparator
+		}
+
+		style := mod.ComponentStyle
+		if index == len(components)-1 && mod.CurrentComponentStyle != "" {
+			style = mod.CurrentComponentStyle
+		}
+		if style != "" {
+			name = context.GetStyle(style).Apply(name)
+		}
+		if mod.Hyperlinks && component.Path != "" {
+			name = osc("8;;"+fileURL(context.Globals.Hostname(), component.Path)) + name + osc("8;;")
+		}
+
+		if count > 0 {
+			result.WriteString(separator)
+		}
+		result.WriteString(name)
+		count++
+	}
+	return result.String()
+}
+
 func init() {
 	registerModule(
 		"directory",
@@ -159,6 +442,7 @@ func init() {
 					TruncationLength: defaultTruncationLength,
 					TruncateToRepo:   true,
 					getVolumeName:    getVolumeName,
+					listFolders:      listFolders,
 				}
 				err := node.Decode(&module)
 				return &module, err
@@ -53,6 +53,11 @@ func TestDirectory(t *testing.T) {
 			PathSeparator:  "/",
 			ReadOnly:       false,
 			ReadOnlySymbol: "🔒",
+			Components: []directoryComponent{
+				{Name: "", Path: "/"},
+				{Name: "tmp", Path: "/tmp"},
+				{Name: "test", Path: "/tmp/test"},
+			},
 		},
 		DefaultText: "/tmp/test",
 	}, result)
@@ -72,6 +77,11 @@ func TestReadOnlyDirectory(t *testing.T) {
 			PathSeparator:  "/",
 			ReadOnly:       true,
 			ReadOnlySymbol: "🔒",
+			Components: []directoryComponent{
+				{Name: "", Path: "/"},
+				{Name: "tmp", Path: "/tmp"},
+				{Name: "test", Path: "/tmp/test"},
+			},
 		},
 		DefaultText: "/tmp/test🔒",
 	}, result)
@@ -186,3 +196,192 @@ func TestWindowLogicalCWD(t *testing.T) {
 
 	assert.Equal(t, "Env:\\", mod.Execute(context).DefaultText)
 }
+
+func TestDirectorySegments(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/Users/jwalton/foo/bar/baz/qux", "",
+		heredoc.Doc(`
+			type: directory
+			separator: " > "
+		`),
+	)
+	assert.Equal(t, "… > bar > baz > qux", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "/tmp/test"
+	assert.Equal(t, "/ > tmp > test", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "/"
+	assert.Equal(t, "/", mod.Execute(context).DefaultText)
+}
+
+func TestDirectorySegmentsWindows(t *testing.T) {
+	context, mod := makeTestDirectoryModule("\\", "D:\\", "",
+		heredoc.Doc(`
+			type: directory
+			separator: " > "
+		`),
+	)
+	assert.Equal(t, "D:", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "D:\\tmp\\foo\\bar\\baz\\qux"
+	assert.Equal(t, "D: > … > bar > baz > qux", mod.Execute(context).DefaultText)
+}
+
+func TestDirectorySegmentHyperlinks(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/Users/jwalton/foo", "",
+		heredoc.Doc(`
+			type: directory
+			hyperlinks: true
+		`),
+	)
+	context.Globals.SetHostname("lucid")
+
+	result := mod.Execute(context)
+	assert.Equal(t,
+		osc("8;;"+fileURL("lucid", "/Users/jwalton"))+"~"+osc("8;;")+
+			"/"+
+			osc("8;;"+fileURL("lucid", "/Users/jwalton/foo"))+"foo"+osc("8;;"),
+		result.DefaultText,
+	)
+	assert.Equal(t, "~/foo", result.Data.(directoryModuleResult).Path)
+	assert.Equal(t, []directoryComponent{
+		{Name: "~", Path: "/Users/jwalton"},
+		{Name: "foo", Path: "/Users/jwalton/foo"},
+	}, result.Data.(directoryModuleResult).Components)
+}
+
+func TestDirectorySegmentStyles(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/Users/jwalton/foo", "",
+		heredoc.Doc(`
+			type: directory
+			componentStyle: blue
+			currentComponentStyle: red
+			separatorStyle: brightBlack
+		`),
+	)
+
+	assert.Equal(t,
+		context.GetStyle("blue").Apply("~")+
+			context.GetStyle("brightBlack").Apply("/")+
+			context.GetStyle("red").Apply("foo"),
+		mod.Execute(context).DefaultText,
+	)
+}
+
+func TestDirectorySubstitutions(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/Users/jwalton/work/clientA", "",
+		heredoc.Doc(`
+			type: directory
+			substitutions:
+			  "~/work": work
+			  "~/work/clientA": " clientA"
+			  /mnt/c/Users: "C:"
+		`),
+	)
+	assert.Equal(t, " clientA", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "/Users/jwalton/work/clientA/src/foo"
+	assert.Equal(t, " clientA/src/foo", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "/Users/jwalton/work/clientB"
+	assert.Equal(t, "work/clientB", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "/Users/jwalton/work/clientAB"
+	assert.Equal(t, "work/clientAB", mod.Execute(context).DefaultText)
+
+	// Substitutions are applied before truncation.
+	context.Globals.CWD = "/mnt/c/Users/jwalton/foo/bar/baz"
+	assert.Equal(t, "C:/…/foo/bar/baz", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "/mnt/c/Users/jwalton/foo/bar"
+	assert.Equal(t, "C:/jwalton/foo/bar", mod.Execute(context).DefaultText)
+}
+
+func TestDirectorySubstitutionInGitRepo(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/Users/jwalton/dev/kitsch/src", "/Users/jwalton/dev/kitsch",
+		heredoc.Doc(`
+			type: directory
+			substitutions:
+			  "~/dev": dev
+		`),
+	)
+	assert.Equal(t, "kitsch/src", mod.Execute(context).DefaultText)
+
+	mod.Substitutions = map[string]string{"~/dev/kitsch": "k"}
+	assert.Equal(t, "k/src", mod.Execute(context).DefaultText)
+}
+
+func TestDirectoryUniquePrefix(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/Users/jwalton/dev/kitsch/internal", "",
+		heredoc.Doc(`
+			type: directory
+			truncationMode: uniquePrefix
+			truncationLength: 0
+		`),
+	)
+	folders := map[string][]string{
+		"/":                       {"Users", "tmp"},
+		"/Users/":                 {"jwalton", "jane", "Shared"},
+		"/Users/jwalton/":         {".config", ".cache", "dev", "Documents", "Desktop"},
+		"/Users/jwalton/dev/":     {"kitsch", "kit", "other"},
+		"/Users/jwalton/.config/": {"fish"},
+	}
+	mod.listFolders = func(path string) []string {
+		return folders[path]
+	}
+
+	// truncationLength is 0, so the home directory isn't replaced.
+	assert.Equal(t, "/U/jw/d/kits/internal", mod.Execute(context).DefaultText)
+
+	mod.TruncationLength = 3
+	assert.Equal(t, "~/d/kits/internal", mod.Execute(context).DefaultText)
+
+	context.Globals.CWD = "/Users/jwalton/.config/fish"
+	assert.Equal(t, "~/.co/fish", mod.Execute(context).DefaultText)
+
+	// Folders are shortened after the path is truncated.
+	context.Globals.CWD = "/Users/jwalton/dev/kitsch/internal/kitsch/modules"
+	assert.Equal(t, "…/i/k/modules", mod.Execute(context).DefaultText)
+}
+
+func TestUniquePrefix(t *testing.T) {
+	assert.Equal(t, "d", uniquePrefix("dev", []string{"dev", "Documents"}))
+	assert.Equal(t, "kits", uniquePrefix("kitsch", []string{"kitsch", "kit"}))
+	assert.Equal(t, "kit", uniquePrefix("kit", []string{"kitsch", "kit"}))
+	assert.Equal(t, ".c", uniquePrefix(".config", []string{".config"}))
+	assert.Equal(t, "é", uniquePrefix("été", nil))
+}
+
+func TestDirectoryRepoMode(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/Users/jwalton/dev/kitsch/src/foo/bar/baz/qux", "/Users/jwalton/dev/kitsch",
+		heredoc.Doc(`
+			type: directory
+			truncationMode: repo
+			truncateToRepo: false
+		`),
+	)
+	result := mod.Execute(context)
+	assert.Equal(t, "kitsch/src/foo/bar/baz/qux", result.DefaultText)
+	assert.Equal(t, "kitsch", result.Data.(directoryModuleResult).RepoName)
+	assert.Equal(t, "src/foo/bar/baz/qux", result.Data.(directoryModuleResult).RepoSubpath)
+
+	context.Globals.CWD = "/Users/jwalton/dev/kitsch"
+	result = mod.Execute(context)
+	assert.Equal(t, "kitsch", result.DefaultText)
+	assert.Equal(t, "", result.Data.(directoryModuleResult).RepoSubpath)
+
+	// Outside of a repo, we fall back to the truncated path.
+	context.Globals.CWD = "/Users/jwalton/dev/kitsch-other/foo/bar/baz"
+	result = mod.Execute(context)
+	assert.Equal(t, "…/foo/bar/baz", result.DefaultText)
+	assert.Equal(t, "", result.Data.(directoryModuleResult).RepoName)
+}
+
+func TestDirectoryNetworkSymbol(t *testing.T) {
+	context, mod := makeTestDirectoryModule("/", "/tmp/test", "", "{type: directory, networkSymbol: \"🌐\"}")
+	assert.Equal(t, "/tmp/test", mod.Execute(context).DefaultText)
+
+	context.Globals.SetFilesystemType("nfs")
+	result := mod.Execute(context)
+	assert.Equal(t, "/tmp/test🌐", result.DefaultText)
+	assert.True(t, result.Data.(directoryModuleResult).IsNetworkFilesystem)
+}
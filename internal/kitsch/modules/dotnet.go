@@ -0,0 +1,345 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas DotnetModule
+
+// DotnetModule shows the version of the .NET SDK in .NET projects.  If there
+// is a "global.json" which pins the SDK version, this works out which of the
+// installed SDKs `dotnet` would actually use, and warns if no installed SDK
+// satisfies the pin.
+//
+type DotnetModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=dotnet"`
+	// Symbol is the symbol to show before the version.  Defaults to ".NET ".
+	Symbol string `yaml:"symbol"`
+}
+
+type dotnetModuleResult struct {
+	// Version is the version of the .NET SDK that will be used in this folder,
+	// or "" if it could not be found.
+	Version string
+	// GlobalJSON is the path to the "global.json" file, or "" if there is none.
+	GlobalJSON string
+	// RequestedVersion is the SDK version from "global.json", or "" if no
+	// version is pinned.
+	RequestedVersion string
+	// RollForward is the roll forward policy used to pick an SDK.
+	RollForward string
+	// Missing is true if "global.json" pins an SDK version, and no installed
+	// SDK satisfies it.
+	Missing bool
+}
+
+// dotnetConditions are the conditions for the dotnet module to be shown.
+var dotnetConditions = condition.Conditions{
+	IfFiles:      []string{"global.json"},
+	IfExtensions: []string{"csproj", "fsproj", "vbproj"},
+}
+
+// dotnetVersionGetter is used to get the SDK version if we can't find the
+// folder the SDKs are installed in.
+var dotnetVersionGetter = getters.CustomGetter{
+	Type:  getters.TypeCustom,
+	From:  "dotnet --version",
+	Regex: `^(\d+\.\d+\.\S+)`,
+	Cache: getters.CacheSettings{Enabled: true},
+}
+
+// dotnetGlobalJSON is the contents of a "global.json" file.
+type dotnetGlobalJSON struct {
+	SDK struct {
+		Version         string `json:"version"`
+		RollForward     string `json:"rollForward"`
+		AllowPrerelease *bool  `json:"allowPrerelease"`
+	} `json:"sdk"`
+}
+
+// Execute the module.
+func (mod DotnetModule) Execute(context *Context) ModuleResult {
+	if !dotnetConditions.Matches(context.Directory, context) {
+		return ModuleResult{Data: dotnetModuleResult{}}
+	}
+
+	data := dotnetModuleResult{}
+	var warnings []string
+
+	globalJSON := dotnetGlobalJSON{}
+	if path, contents := readAncestorFile(context.Directory, "global.json"); path != "" {
+		data.GlobalJSON = path
+		if err := json.Unmarshal(contents, &globalJSON); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Error parsing %s: %v", path, err))
+		}
+	}
+	data.RequestedVersion = globalJSON.SDK.Version
+
+	installed, err := listDotnetSDKs(context.Environment)
+	if err != nil {
+		// We don't know which SDKs are installed, so ask `dotnet`.
+		if version, err := dotnetVersionGetter.GetValue(context); err == nil {
+			data.Version, _ = version.(string)
+		}
+	} else {
+		allowPrerelease := true
+		if globalJSON.SDK.AllowPrerelease != nil {
+			allowPrerelease = *globalJSON.SDK.AllowPrerelease
+		}
+		data.RollForward, data.Version = resolveDotnetSDK(
+			installed,
+			globalJSON.SDK.Version,
+			globalJSON.SDK.RollForward,
+			allowPrerelease,
+		)
+		if data.Version == "" && data.RequestedVersion != "" {
+			data.Missing = true
+			warnings = append(warnings, fmt.Sprintf(
+				"%s requires .NET SDK %s (rollForward: %s), which is not installed",
+				data.GlobalJSON,
+				data.RequestedVersion,
+				data.RollForward,
+			))
+		}
+	}
+
+	text := mod.Symbol + defaultString(data.Version, data.RequestedVersion)
+
+	return ModuleResult{DefaultText: text, Data: data, Warnings: warnings}
+}
+
+// listDotnetSDKs returns the versions of all installed .NET SDKs.
+func listDotnetSDKs(environment env.Env) ([]string, error) {
+	root := environment.Getenv("DOTNET_ROOT")
+	if root == "" {
+		dotnet, err := environment.LookPath("dotnet")
+		if err != nil {
+			return nil, err
+		}
+		dotnet, err = filepath.EvalSymlinks(dotnet)
+		if err != nil {
+			return nil, err
+		}
+		root = filepath.Dir(dotnet)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "sdk"))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() && parseDotnetVersion(entry.Name()).valid {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions, nil
+}
+
+// dotnetVersion is a parsed .NET SDK version, like "6.0.101" or
+// "7.0.100-preview.1.22110.4".  The hundreds digit of the patch number is the
+// "feature band".
+type dotnetVersion struct {
+	valid      bool
+	major      int
+	minor      int
+	patch      int
+	prerelease string
+}
+
+func parseDotnetVersion(version string) dotnetVersion {
+	result := dotnetVersion{}
+
+	core := version
+	if index := strings.Index(version, "-"); index != -1 {
+		core = version[:index]
+		result.prerelease = version[index+1:]
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return result
+	}
+
+	var err error
+	if result.major, err = strconv.Atoi(parts[0]); err != nil {
+		return result
+	}
+	if result.minor, err = strconv.Atoi(parts[1]); err != nil {
+		return result
+	}
+	if result.patch, err = strconv.Atoi(parts[2]); err != nil {
+		return result
+	}
+
+	result.valid = true
+	return result
+}
+
+func (v dotnetVersion) featureBand() int {
+	return v.patch / 100
+}
+
+// sameBand returns true if v and other are in the same major, minor, and
+// feature band.
+func (v dotnetVersion) sameBand(other dotnetVersion) bool {
+	return v.major == other.major && v.minor == other.minor && v.featureBand() == other.featureBand()
+}
+
+// compare returns -1 if v < other, 0 if v == other, or 1 if v > other.
+func (v dotnetVersion) compare(other dotnetVersion) int {
+	for _, diff := range []int{v.major - other.major, v.minor - other.minor, v.patch - other.patch} {
+		if diff < 0 {
+			return -1
+		} else if diff > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.prerelease == other.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case other.prerelease == "":
+		return -1
+	case v.prerelease < other.prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// resolveDotnetSDK picks the SDK that `dotnet` would use from the list of
+// installed SDKs, given the version and rollForward policy from a
+// "global.json".  Returns the policy that was applied, and the selected
+// version, or "" if no installed SDK is acceptable.
+//
+// See https://docs.microsoft.com/en-us/dotnet/core/tools/global-json#matching-rules.
+func resolveDotnetSDK(
+	installed []string,
+	requested string,
+	rollForward string,
+	allowPrerelease bool,
+) (string, string) {
+	if rollForward == "" {
+		if requested == "" {
+			rollForward = "latestMajor"
+		} else {
+			rollForward = "patch"
+		}
+	}
+
+	want := parseDotnetVersion(requested)
+	if requested != "" && !want.valid {
+		return rollForward, ""
+	}
+	if want.prerelease != "" {
+		// Asking for a prerelease SDK always allows prerelease SDKs.
+		allowPrerelease = true
+	}
+
+	// Sort candidates from newest to oldest.
+	candidates := []dotnetVersion{}
+	names := map[dotnetVersion]string{}
+	for _, name := range installed {
+		version := parseDotnetVersion(name)
+		if !version.valid ||
+			(version.prerelease != "" && !allowPrerelease) ||
+			(requested != "" && version.compare(want) < 0) {
+			continue
+		}
+		candidates = append(candidates, version)
+		names[version] = name
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].compare(candidates[j]) > 0
+	})
+
+	// latest returns the newest candidate that matches the filter.
+	latest := func(filter func(v dotnetVersion) bool) string {
+		for _, candidate := range candidates {
+			if filter(candidate) {
+				return names[candidate]
+			}
+		}
+		return ""
+	}
+
+	// rollToNextBand returns the newest patch in the lowest feature band
+	// which matches the filter.
+	rollToNextBand := func(filter func(v dotnetVersion) bool) string {
+		if version := latest(want.sameBand); version != "" {
+			return version
+		}
+		var band *dotnetVersion
+		for i := len(candidates) - 1; i >= 0; i-- {
+			if filter(candidates[i]) {
+				band = &candidates[i]
+				break
+			}
+		}
+		if band == nil {
+			return ""
+		}
+		return latest(band.sameBand)
+	}
+
+	sameMajor := func(v dotnetVersion) bool { return v.major == want.major }
+	sameMinor := func(v dotnetVersion) bool { return sameMajor(v) && v.minor == want.minor }
+	anyVersion := func(v dotnetVersion) bool { return true }
+
+	switch rollForward {
+	case "disable":
+		return rollForward, latest(func(v dotnetVersion) bool { return v.compare(want) == 0 })
+	case "patch":
+		if version := latest(func(v dotnetVersion) bool { return v.compare(want) == 0 }); version != "" {
+			return rollForward, version
+		}
+		return rollForward, latest(want.sameBand)
+	case "feature":
+		return rollForward, rollToNextBand(sameMinor)
+	case "minor":
+		return rollForward, rollToNextBand(sameMajor)
+	case "major":
+		return rollForward, rollToNextBand(anyVersion)
+	case "latestPatch":
+		return rollForward, latest(want.sameBand)
+	case "latestFeature":
+		return rollForward, latest(sameMinor)
+	case "latestMinor":
+		return rollForward, latest(sameMajor)
+	case "latestMajor":
+		return rollForward, latest(anyVersion)
+	default:
+		return rollForward, ""
+	}
+}
+
+func init() {
+	registerModule(
+		"dotnet",
+		registeredModule{
+			jsonSchema: schemas.DotnetModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := DotnetModule{Type: "dotnet", Symbol: ".NET "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
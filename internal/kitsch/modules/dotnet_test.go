@@ -0,0 +1,130 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDotnetRoot creates a DOTNET_ROOT folder with the given SDKs installed.
+func fakeDotnetRoot(t *testing.T, sdks ...string) string {
+	dotnetRoot := t.TempDir()
+	for _, sdk := range sdks {
+		require.NoError(t, os.MkdirAll(filepath.Join(dotnetRoot, "sdk", sdk), 0755))
+	}
+
+	return dotnetRoot
+}
+
+func TestDotnetLatestSDK(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dotnet
+	`)).(*DotnetModule)
+
+	context := newTestContextWith(map[string]string{"DOTNET_ROOT": fakeDotnetRoot(t, "5.0.408", "6.0.202")}, fstest.MapFS{
+		"app.csproj": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, ".NET 6.0.202", result.DefaultText)
+	assert.Equal(t, dotnetModuleResult{
+		Version:     "6.0.202",
+		RollForward: "latestMajor",
+	}, result.Data)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestDotnetGlobalJSON(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dotnet
+	`)).(*DotnetModule)
+
+	context := newTestContextWith(map[string]string{"DOTNET_ROOT": fakeDotnetRoot(t, "5.0.408", "6.0.101", "6.0.104", "6.0.202")}, fstest.MapFS{
+		"global.json": &fstest.MapFile{Data: []byte(`{"sdk": {"version": "6.0.100", "rollForward": "latestPatch"}}`)},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, ".NET 6.0.104", result.DefaultText)
+	assert.Equal(t, dotnetModuleResult{
+		Version:          "6.0.104",
+		GlobalJSON:       "/Users/jwalton/global.json",
+		RequestedVersion: "6.0.100",
+		RollForward:      "latestPatch",
+	}, result.Data)
+}
+
+func TestDotnetGlobalJSONMissingSDK(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dotnet
+	`)).(*DotnetModule)
+
+	context := newTestContextWith(map[string]string{"DOTNET_ROOT": fakeDotnetRoot(t, "6.0.202")}, fstest.MapFS{
+		"global.json": &fstest.MapFile{Data: []byte(`{"sdk": {"version": "6.0.100"}}`)},
+		"app.fsproj":  &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, ".NET 6.0.100", result.DefaultText)
+	assert.True(t, result.Data.(dotnetModuleResult).Missing)
+	assert.Equal(t, []string{
+		"/Users/jwalton/global.json requires .NET SDK 6.0.100 (rollForward: patch), which is not installed",
+	}, result.Warnings)
+}
+
+func TestDotnetNotADotnetProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: dotnet
+	`)).(*DotnetModule)
+
+	context := newTestContextWith(map[string]string{"DOTNET_ROOT": fakeDotnetRoot(t, "6.0.202")}, fstest.MapFS{"package.json": &fstest.MapFile{}})
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, dotnetModuleResult{}, result.Data)
+}
+
+func TestResolveDotnetSDK(t *testing.T) {
+	installed := []string{
+		"3.1.426",
+		"6.0.101",
+		"6.0.104",
+		"6.0.202",
+		"6.0.300",
+		"6.1.100",
+		"7.0.100-preview.4.22252.9",
+	}
+
+	for _, test := range []struct {
+		requested       string
+		rollForward     string
+		allowPrerelease bool
+		expected        string
+	}{
+		{"6.0.101", "", true, "6.0.101"},
+		{"6.0.102", "", true, "6.0.104"},
+		{"6.0.105", "", true, ""},
+		{"6.0.102", "disable", true, ""},
+		{"6.0.101", "disable", true, "6.0.101"},
+		{"6.0.101", "feature", true, "6.0.104"},
+		{"6.0.105", "feature", true, "6.0.202"},
+		{"6.0.301", "feature", true, ""},
+		{"6.0.301", "minor", true, "6.1.100"},
+		{"6.1.200", "minor", true, ""},
+		{"6.1.200", "major", true, "7.0.100-preview.4.22252.9"},
+		{"6.1.200", "major", false, ""},
+		{"6.0.100", "latestPatch", true, "6.0.104"},
+		{"6.0.100", "latestFeature", true, "6.0.300"},
+		{"6.0.100", "latestMinor", true, "6.1.100"},
+		{"3.1.100", "latestMajor", true, "7.0.100-preview.4.22252.9"},
+		{"3.1.100", "latestMajor", false, "6.1.100"},
+		{"", "", false, "6.1.100"},
+		{"6.0.100", "sideways", true, ""},
+	} {
+		_, actual := resolveDotnetSDK(installed, test.requested, test.rollForward, test.allowPrerelease)
+		assert.Equal(t, test.expected, actual, "%s %s %v", test.requested, test.rollForward, test.allowPrerelease)
+	}
+}
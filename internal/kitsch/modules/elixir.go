@@ -0,0 +1,185 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas ElixirModule
+
+// ElixirModule shows the version of Elixir and Erlang/OTP in Elixir projects.
+// Versions are read from the installed Elixir and Erlang, and only fall back
+// to running `elixir` and `erl` if they can't be found that way.
+//
+type ElixirModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=elixir"`
+	// Symbol is the symbol to show before the version.  Defaults to "💧 ".
+	Symbol string `yaml:"symbol"`
+	// installRoot returns the folder an executable is installed in.  This is
+	// used for unit testing.
+	installRoot func(executable string) string
+}
+
+type elixirModuleResult struct {
+	// Version is the version of Elixir, or "" if it could not be found.
+	Version string
+	// OTPVersion is the version of Erlang/OTP (e.g. "25.0.4"), or "" if it
+	// could not be found.
+	OTPVersion string
+	// OTPRelease is the major release of Erlang/OTP (e.g. "25").
+	OTPRelease string
+}
+
+// elixirConditions are the conditions for the elixir module to be shown.
+var elixirConditions = condition.Conditions{
+	IfFiles: []string{"mix.exs"},
+}
+
+// elixirAppVersionRegex finds the version in Elixir's "elixir.app" file.
+var elixirAppVersionRegex = regexp.MustCompile(`\{vsn,\s*"([^"]+)"\}`)
+
+// elixirVersionGetter is used to get the version of Elixir if we can't
+// find where Elixir is installed.
+var elixirVersionGetter = getters.CustomGetter{
+	Type:  getters.TypeCustom,
+	From:  "elixir --short-version",
+	Regex: `^(\S+)`,
+	Cache: getters.CacheSettings{Enabled: true},
+}
+
+// otpReleaseGetter is used to get the OTP release if we can't find where
+// Erlang is installed.
+var otpReleaseGetter = getters.CustomGetter{
+	Type:  getters.TypeCustom,
+	From:  `erl -noshell -eval "io:put_chars(erlang:system_info(otp_release)), halt()."`,
+	Regex: `^(\S+)`,
+	Cache: getters.CacheSettings{Enabled: true},
+}
+
+// Execute the module.
+func (mod ElixirModule) Execute(context *Context) ModuleResult {
+	if !elixirConditions.Matches(context.Directory, context) {
+		return ModuleResult{Data: elixirModuleResult{}}
+	}
+
+	installRoot := mod.installRoot
+	if installRoot == nil {
+		installRoot = func(executable string) string {
+			return executableInstallRoot(context.Environment, executable)
+		}
+	}
+
+	data := elixirModuleResult{}
+
+	if root := installRoot("elixir"); root != "" {
+		data.Version = readElixirVersion(root)
+	}
+	if data.Version == "" {
+		if version, err := elixirVersionGetter.GetValue(context); err == nil {
+			data.Version, _ = version.(string)
+		}
+	}
+
+	if root := installRoot("erl"); root != "" {
+		data.OTPRelease, data.OTPVersion = readOTPVersion(root)
+	}
+	if data.OTPRelease == "" {
+		if release, err := otpReleaseGetter.GetValue(context); err == nil {
+			data.OTPRelease, _ = release.(string)
+		}
+	}
+
+	text := mod.Symbol + data.Version
+	if data.OTPRelease != "" {
+		text += " (OTP " + data.OTPRelease + ")"
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// executableInstallRoot finds the given executable on the PATH, and returns
+// the parent of the "bin" folder it is in.
+func executableInstallRoot(environment env.Env, executable string) string {
+	file, err := environment.LookPath(executable)
+	if err != nil {
+		return ""
+	}
+	file, err = filepath.EvalSymlinks(file)
+	if err != nil {
+		return ""
+	}
+	return filepath.Dir(filepath.Dir(file))
+}
+
+// readElixirVersion reads the version of Elixir from the "elixir.app" file
+// in the Elixir installation.
+func readElixirVersion(elixirRoot string) string {
+	contents, err := os.ReadFile(filepath.Join(elixirRoot, "lib", "elixir", "ebin", "elixir.app"))
+	if err != nil {
+		return ""
+	}
+	match := elixirAppVersionRegex.FindSubmatch(contents)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
+// readOTPVersion reads the OTP release and full OTP version from the
+// "releases" folder in the Erlang installation.
+func readOTPVersion(erlangRoot string) (release string, version string) {
+	releasesDir := filepath.Join(erlangRoot, "releases")
+	entries, err := os.ReadDir(releasesDir)
+	if err != nil {
+		return "", ""
+	}
+
+	// There should only be one release, but if there are more, use the newest.
+	releases := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			releases = append(releases, entry.Name())
+		}
+	}
+	if len(releases) == 0 {
+		return "", ""
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		if len(releases[i]) != len(releases[j]) {
+			return len(releases[i]) < len(releases[j])
+		}
+		return releases[i] < releases[j]
+	})
+	release = releases[len(releases)-1]
+
+	contents, err := os.ReadFile(filepath.Join(releasesDir, release, "OTP_VERSION"))
+	if err == nil {
+		version = strings.TrimSpace(string(contents))
+	}
+
+	return release, version
+}
+
+func init() {
+	registerModule(
+		"elixir",
+		registeredModule{
+			jsonSchema: schemas.ElixirModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := ElixirModule{Type: "elixir", Symbol: "💧 "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
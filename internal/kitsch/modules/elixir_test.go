@@ -0,0 +1,68 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, path string, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+}
+
+func TestElixir(t *testing.T) {
+	elixirRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(elixirRoot, "lib", "elixir", "ebin", "elixir.app"), heredoc.Doc(`
+		{application,elixir,
+		             [{description,"elixir"},
+		              {vsn,"1.14.0"},
+		              {modules,[]}]}.
+	`))
+
+	erlangRoot := t.TempDir()
+	writeTestFile(t, filepath.Join(erlangRoot, "releases", "25", "OTP_VERSION"), "25.0.4\n")
+
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: elixir
+	`)).(*ElixirModule)
+	mod.installRoot = func(executable string) string {
+		if executable == "elixir" {
+			return elixirRoot
+		}
+		return erlangRoot
+	}
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, fstest.MapFS{
+		"mix.exs": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, "💧 1.14.0 (OTP 25)", result.DefaultText)
+	assert.Equal(t, elixirModuleResult{
+		Version:    "1.14.0",
+		OTPVersion: "25.0.4",
+		OTPRelease: "25",
+	}, result.Data)
+}
+
+func TestElixirNotAnElixirProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: elixir
+	`)).(*ElixirModule)
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, fstest.MapFS{
+		"package.json": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, elixirModuleResult{}, result.Data)
+}
@@ -0,0 +1,141 @@
+package modules
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"gopkg.in/yaml.v3"
+)
+
+// Explainer records what each module did while rendering the prompt.  Set
+// `Context.Explainer` to an Explainer before rendering, and then call
+// `Explain()` to find out what happened.
+type Explainer struct {
+	mutex   sync.Mutex
+	records map[*yaml.Node]*explainRecord
+}
+
+// explainRecord is the information we collect while a module is executing.
+type explainRecord struct {
+	executed bool
+	skipped  bool
+	timedOut bool
+	result   ModuleWrapperResult
+	warnings []string
+}
+
+// ModuleExplanation describes the result of executing a single module.
+type ModuleExplanation struct {
+	// Module is a description of the module, including its type, ID, and
+	// location in the configuration file.
+	Module string
+	// Type is the type of the module.
+	Type string
+	// ID is the ID of the module, or "" if it doesn't have one.
+	ID string
+	// Line is the line number of the module in the configuration file.
+	Line int
+	// Column is the column number of the module in the configuration file.
+	Column int
+	// Executed is false if this module was never run (for example, the
+	// unused modules in a vcs module).
+	Executed bool
+	// Skipped is true if the module's conditions didn't match.
+	Skipped bool
+	// TimedOut is true if the module took longer than its timeout.
+	TimedOut bool
+	// Text is the text the module produced, after applying the template and
+	// style.
+	Text string
+	// StartStyle is the color of the first character in Text.
+	StartStyle styling.CharacterColors
+	// EndStyle is the color of the last character in Text.
+	EndStyle styling.CharacterColors
+	// Duration is the time it took to execute the module.
+	Duration time.Duration
+	// Data is the `.Data` available to the module's template.
+	Data interface{}
+	// Warnings is a list of warnings generated by this module.
+	Warnings []string
+	// Children are explanations for any modules rendered by this module.
+	Children []ModuleExplanation
+}
+
+// NewExplainer creates a new Explainer.
+func NewExplainer() *Explainer {
+	return &Explainer{records: map[*yaml.Node]*explainRecord{}}
+}
+
+// record returns the record for the given module, creating it if necessary.
+// The caller must hold the mutex.
+func (explainer *Explainer) record(wrapper ModuleWrapper) *explainRecord {
+	record := explainer.records[wrapper.YamlNode]
+	if record == nil {
+		record = &explainRecord{}
+		explainer.records[wrapper.YamlNode] = record
+	}
+	return record
+}
+
+func (explainer *Explainer) addResult(wrapper ModuleWrapper, result ModuleWrapperResult, skipped bool, timedOut bool) {
+	if explainer == nil || wrapper.YamlNode == nil {
+		return
+	}
+
+	explainer.mutex.Lock()
+	defer explainer.mutex.Unlock()
+
+	record := explainer.record(wrapper)
+	record.executed = true
+	record.skipped = skipped
+	record.timedOut = timedOut
+	record.result = result
+}
+
+func (explainer *Explainer) addWarning(wrapper ModuleWrapper, warning string) {
+	if explainer == nil || wrapper.YamlNode == nil {
+		return
+	}
+
+	explainer.mutex.Lock()
+	defer explainer.mutex.Unlock()
+
+	record := explainer.record(wrapper)
+	record.warnings = append(record.warnings, warning)
+}
+
+// Explain returns an explanation of what happened when the given module, and
+// all of its children, were rendered.
+func (explainer *Explainer) Explain(root ModuleWrapper) ModuleExplanation {
+	explainer.mutex.Lock()
+	record := explainer.records[root.YamlNode]
+	explainer.mutex.Unlock()
+
+	explanation := ModuleExplanation{
+		Module: root.String(),
+		Type:   root.config.Type,
+		ID:     root.config.ID,
+		Line:   root.Line,
+		Column: root.Column,
+	}
+	if record != nil {
+		explanation.Executed = record.executed
+		explanation.Skipped = record.skipped
+		explanation.TimedOut = record.timedOut
+		explanation.Text = record.result.Text
+		explanation.StartStyle = record.result.StartStyle
+		explanation.EndStyle = record.result.EndStyle
+		explanation.Duration = record.result.Duration
+		explanation.Data = record.result.Data
+		explanation.Warnings = record.warnings
+	}
+
+	if parent, ok := root.Module.(parentModule); ok {
+		for _, child := range parent.childModules() {
+			explanation.Children = append(explanation.Children, explainer.Explain(*child))
+		}
+	}
+
+	return explanation
+}
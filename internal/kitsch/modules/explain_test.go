@@ -0,0 +1,49 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	blockMod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		- type: text
+		  text: hello
+		- type: text
+		  text: world
+		  template: "{{ .Foo }}"
+		- type: text
+		  text: skipped
+		  conditions:
+		    ifEnv: [NOT_SET]
+    `))
+
+	context := newTestContext("jwalton")
+	context.Explainer = NewExplainer()
+	blockMod.Execute(context)
+
+	explanation := context.Explainer.Explain(blockMod)
+	assert.True(t, explanation.Executed)
+	assert.Equal(t, "hello world", explanation.Text)
+	assert.Len(t, explanation.Children, 3)
+
+	hello := explanation.Children[0]
+	assert.Equal(t, "text(3:3)", hello.Module)
+	assert.Equal(t, "text", hello.Type)
+	assert.Equal(t, 3, hello.Line)
+	assert.Equal(t, "hello", hello.Text)
+	assert.Empty(t, hello.Warnings)
+
+	world := explanation.Children[1]
+	assert.Equal(t, "world", world.Text)
+	assert.Len(t, world.Warnings, 1)
+
+	skipped := explanation.Children[2]
+	assert.True(t, skipped.Executed)
+	assert.True(t, skipped.Skipped)
+	assert.Equal(t, "", skipped.Text)
+}
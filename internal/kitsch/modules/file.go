@@ -1,10 +1,10 @@
 package modules
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/jwalton/kitsch/internal/kitsch/getters"
-	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
 	"gopkg.in/yaml.v3"
 )
@@ -47,9 +47,10 @@ func (mod FileModule) Execute(context *Context) ModuleResult {
 		Regex: mod.Regex,
 	}
 
+	var warnings []string
 	value, err := getter.GetValue(context)
 	if err != nil {
-		log.Warn("Error executing file module: ", err)
+		warnings = append(warnings, fmt.Sprint("Error executing file module: ", err))
 		value = ""
 	}
 
@@ -61,7 +62,7 @@ func (mod FileModule) Execute(context *Context) ModuleResult {
 		text = ""
 	}
 
-	return ModuleResult{DefaultText: text, Data: value}
+	return ModuleResult{DefaultText: text, Data: value, Warnings: warnings}
 }
 
 func init() {
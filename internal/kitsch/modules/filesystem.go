@@ -0,0 +1,115 @@
+package modules
+
+import (
+	"strings"
+)
+
+// networkFilesystemTypes is the set of filesystem types which are on another
+// machine, or which are known to be slow to access.  Fuse filesystems are
+// reported as "fuse.<name>" on Linux.
+var networkFilesystemTypes = map[string]bool{
+	// Network filesystems.
+	"nfs":        true,
+	"nfs4":       true,
+	"cifs":       true,
+	"smb":        true,
+	"smb2":       true,
+	"smb3":       true,
+	"smbfs":      true,
+	"afpfs":      true,
+	"afs":        true,
+	"ncpfs":      true,
+	"webdav":     true,
+	"davfs":      true,
+	"ceph":       true,
+	"glusterfs":  true,
+	"lustre":     true,
+	"gpfs":       true,
+	"sshfs":      true,
+	"fuse.sshfs": true,
+	// Cloud storage mounted with fuse.
+	"fuse.davfs2":    true,
+	"fuse.rclone":    true,
+	"fuse.s3fs":      true,
+	"fuse.gcsfuse":   true,
+	"fuse.goofys":    true,
+	"fuse.onedriver": true,
+	// Windows drives mounted in WSL.
+	"9p":    true,
+	"drvfs": true,
+	// Windows network drives.
+	"remote": true,
+}
+
+// isNetworkFilesystemType returns true if the given filesystem type is a
+// network filesystem, or is otherwise known to be slow.
+func isNetworkFilesystemType(fsType string) bool {
+	return networkFilesystemTypes[strings.ToLower(fsType)]
+}
+
+// mountInfo is a single entry from /proc/self/mounts.
+type mountInfo struct {
+	mountPoint string
+	fsType     string
+}
+
+// parseMounts parses the contents of /proc/self/mounts.
+func parseMounts(mounts string) []mountInfo {
+	result := []mountInfo{}
+	for _, line := range strings.Split(mounts, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		result = append(result, mountInfo{
+			mountPoint: unescapeMountPoint(fields[1]),
+			fsType:     fields[2],
+		})
+	}
+	return result
+}
+
+// unescapeMountPoint replaces the octal escapes in a mount point from
+// /proc/self/mounts (e.g. "\040" for a space) with the characters they
+// represent.
+func unescapeMountPoint(mountPoint string) string {
+	if !strings.Contains(mountPoint, "\\") {
+		return mountPoint
+	}
+
+	var result strings.Builder
+	for index := 0; index < len(mountPoint); index++ {
+		if mountPoint[index] == '\\' && index+3 < len(mountPoint) && isOctal(mountPoint[index+1:index+4]) {
+			value := (mountPoint[index+1]-'0')*64 + (mountPoint[index+2]-'0')*8 + (mountPoint[index+3] - '0')
+			result.WriteByte(value)
+			index += 3
+		} else {
+			result.WriteByte(mountPoint[index])
+		}
+	}
+	return result.String()
+}
+
+func isOctal(digits string) bool {
+	for _, digit := range digits {
+		if digit < '0' || digit > '7' {
+			return false
+		}
+	}
+	return true
+}
+
+// filesystemTypeFromMounts returns the type of the filesystem `path` is on,
+// given the list of mounted filesystems.
+func filesystemTypeFromMounts(mounts []mountInfo, path string) string {
+	fsType := ""
+	longest := -1
+	for _, mount := range mounts {
+		// Later mounts hide earlier mounts at the same mount point.
+		if len(mount.mountPoint) >= longest && isInFolder(path, mount.mountPoint, "/") {
+			fsType = mount.fsType
+			longest = len(mount.mountPoint)
+		}
+	}
+	return fsType
+}
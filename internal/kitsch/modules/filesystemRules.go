@@ -0,0 +1,151 @@
+package modules
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// networkFilesystemType can be used in FilesystemRule.FilesystemTypes to match
+// any network filesystem.
+const networkFilesystemType = "network"
+
+// FilesystemRule turns off modules, or git, when the current directory matches
+// a set of paths or filesystem types.  This is used to keep the prompt fast in
+// places where reading files is slow, like network mounts or Windows drives in
+// WSL.
+type FilesystemRule struct {
+	// Paths is a list of glob patterns to match against the current directory.
+	// "**" matches any number of folders, so "/mnt/**" matches "/mnt" and
+	// everything under it.  Patterns may start with "~" to refer to the
+	// user's home directory.
+	Paths []string `yaml:"paths,omitempty"`
+	// FilesystemTypes is a list of filesystem types (e.g. "nfs", "autofs") to
+	// match against the filesystem the current directory is on.  The special
+	// type "network" matches any network filesystem.
+	FilesystemTypes []string `yaml:"filesystemTypes,omitempty"`
+	// DisableModules is a list of module types or IDs to turn off when this
+	// rule matches.
+	DisableModules []string `yaml:"disableModules,omitempty"`
+	// DisableGit, if true, stops kitsch from looking for a git repo when this
+	// rule matches, which turns off every git module.
+	DisableGit bool `yaml:"disableGit,omitempty"`
+}
+
+// matches returns true if this rule matches the current directory.  If both
+// Paths and FilesystemTypes are set, both must match.
+func (rule FilesystemRule) matches(globals *Globals) bool {
+	if len(rule.Paths) == 0 && len(rule.FilesystemTypes) == 0 {
+		return false
+	}
+
+	if len(rule.Paths) > 0 {
+		matched := false
+		for _, pattern := range rule.Paths {
+			if matchPathPattern(pattern, globals.CWD, globals.Home) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(rule.FilesystemTypes) > 0 {
+		fsType := globals.FilesystemType()
+		for _, ruleType := range rule.FilesystemTypes {
+			if ruleType == networkFilesystemType && isNetworkFilesystemType(fsType) {
+				return true
+			}
+			if fsType != "" && strings.EqualFold(ruleType, fsType) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+// matchPathPattern returns true if `pattern` matches `cwd`.
+func matchPathPattern(pattern string, cwd string, home string) bool {
+	if pattern == "~" || strings.HasPrefix(pattern, "~/") {
+		pattern = filepath.ToSlash(home) + pattern[1:]
+	}
+
+	patternParts := strings.Split(strings.Trim(filepath.ToSlash(pattern), "/"), "/")
+	pathParts := strings.Split(strings.Trim(filepath.ToSlash(cwd), "/"), "/")
+	return matchPathParts(patternParts, pathParts)
+}
+
+func matchPathParts(pattern []string, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// "**" matches zero or more folders.
+			for index := 0; index <= len(parts); index++ {
+				if matchPathParts(pattern[1:], parts[index:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		parts = parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// filesystemRulesResult is the combined effect of all the FilesystemRules that
+// match the current directory.
+type filesystemRulesResult struct {
+	disabledModules map[string]bool
+	gitDisabled     bool
+}
+
+// filesystemRules returns the combined effect of all the FilesystemRules which
+// match the current directory.  This is only worked out once per context.
+func (context *Context) filesystemRules() *filesystemRulesResult {
+	context.filesystemRulesOnce.Do(func() {
+		result := &filesystemRulesResult{disabledModules: map[string]bool{}}
+		for _, rule := range context.FilesystemRules {
+			if !rule.matches(&context.Globals) {
+				continue
+			}
+			for _, module := range rule.DisableModules {
+				result.disabledModules[module] = true
+			}
+			result.gitDisabled = result.gitDisabled || rule.DisableGit
+		}
+		context.filesystemRulesResult = result
+	})
+	return context.filesystemRulesResult
+}
+
+// isModuleDisabled returns true if the module with the given type or ID has
+// been turned off, either by DisabledModules or by a FilesystemRule.
+func (context *Context) isModuleDisabled(moduleType string, id string) bool {
+	for _, disabled := range context.DisabledModules {
+		if disabled == moduleType || (id != "" && disabled == id) {
+			return true
+		}
+	}
+
+	if len(context.FilesystemRules) == 0 {
+		return false
+	}
+	disabled := context.filesystemRules().disabledModules
+	return disabled[moduleType] || (id != "" && disabled[id])
+}
+
+// isGitDisabled returns true if a FilesystemRule has turned off git.
+func (context *Context) isGitDisabled() bool {
+	return len(context.FilesystemRules) > 0 && context.filesystemRules().gitDisabled
+}
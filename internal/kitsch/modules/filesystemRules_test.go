@@ -0,0 +1,96 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPathPattern(t *testing.T) {
+	home := "/Users/jwalton"
+
+	assert.True(t, matchPathPattern("/mnt/**", "/mnt", home))
+	assert.True(t, matchPathPattern("/mnt/**", "/mnt/c/Users", home))
+	assert.False(t, matchPathPattern("/mnt/**", "/mntc", home))
+	assert.True(t, matchPathPattern("/mnt/*", "/mnt/c", home))
+	assert.False(t, matchPathPattern("/mnt/*", "/mnt/c/Users", home))
+	assert.True(t, matchPathPattern("/net/*/projects/**", "/net/server/projects/kitsch", home))
+	assert.True(t, matchPathPattern("**/node_modules/**", "/Users/jwalton/dev/kitsch/node_modules/foo", home))
+	assert.True(t, matchPathPattern("~/remote/**", "/Users/jwalton/remote/src", home))
+	assert.False(t, matchPathPattern("~/remote/**", "/Users/jwalton/dev", home))
+	assert.True(t, matchPathPattern("~", "/Users/jwalton", home))
+}
+
+func TestFilesystemRuleMatches(t *testing.T) {
+	globals := Globals{CWD: "/mnt/c/Users", Home: "/home/jwalton"}
+	globals.SetFilesystemType("9p")
+
+	assert.False(t, FilesystemRule{}.matches(&globals))
+	assert.True(t, FilesystemRule{Paths: []string{"/tmp/**", "/mnt/**"}}.matches(&globals))
+	assert.False(t, FilesystemRule{Paths: []string{"/tmp/**"}}.matches(&globals))
+	assert.True(t, FilesystemRule{FilesystemTypes: []string{"9P"}}.matches(&globals))
+	assert.True(t, FilesystemRule{FilesystemTypes: []string{"network"}}.matches(&globals))
+	assert.False(t, FilesystemRule{FilesystemTypes: []string{"ext4"}}.matches(&globals))
+	assert.False(t, FilesystemRule{Paths: []string{"/mnt/**"}, FilesystemTypes: []string{"ext4"}}.matches(&globals))
+}
+
+func TestFilesystemRulesDisableModules(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.Globals.CWD = "/mnt/c/Users"
+	context.FilesystemRules = []FilesystemRule{
+		{Paths: []string{"/tmp/**"}, DisableModules: []string{"text"}},
+		{Paths: []string{"/mnt/**"}, DisableModules: []string{"slow"}},
+	}
+
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		  - type: text
+		    text: hello
+		  - type: text
+		    id: slow
+		    text: " world"
+	`))
+	_, text := RenderPrompt(context, root)
+	assert.Equal(t, "hello", text)
+}
+
+func TestFilesystemRulesDisableGit(t *testing.T) {
+	repo := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0755))
+
+	newContext := func(rules []FilesystemRule) *Context {
+		context := newTestContext("jwalton")
+		context.Globals.CWD = repo
+		context.Directory = fileutils.NewDirectory(repo, time.Second, 1000)
+		context.FilesystemRules = rules
+		context.gitInitialized = false
+		return context
+	}
+
+	assert.NotNil(t, newContext(nil).Git())
+	assert.Nil(t, newContext([]FilesystemRule{{Paths: []string{repo}, DisableGit: true}}).Git())
+	assert.NotNil(t, newContext([]FilesystemRule{{Paths: []string{"/mnt/**"}, DisableGit: true}}).Git())
+}
+
+func TestDisabledModules(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.DisabledModules = []string{"greeting"}
+
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		  - type: text
+		    id: greeting
+		    text: hello
+		  - type: text
+		    text: world
+	`))
+	_, text := RenderPrompt(context, root)
+	assert.Equal(t, "world", text)
+}
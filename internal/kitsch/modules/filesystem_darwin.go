@@ -0,0 +1,26 @@
+//go:build darwin
+// +build darwin
+
+package modules
+
+import (
+	"syscall"
+)
+
+// filesystemType returns the type of the filesystem `path` is on (e.g. "apfs"
+// or "smbfs"), or "" if it can't be determined.
+func filesystemType(path string) string {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return ""
+	}
+
+	name := make([]byte, 0, len(stat.Fstypename))
+	for _, char := range stat.Fstypename {
+		if char == 0 {
+			break
+		}
+		name = append(name, byte(char))
+	}
+	return string(name)
+}
@@ -0,0 +1,18 @@
+//go:build linux
+// +build linux
+
+package modules
+
+import (
+	"os"
+)
+
+// filesystemType returns the type of the filesystem `path` is on (e.g. "ext4"
+// or "nfs"), or "" if it can't be determined.
+func filesystemType(path string) string {
+	mounts, err := os.ReadFile("/proc/self/mounts")
+	if err != nil {
+		return ""
+	}
+	return filesystemTypeFromMounts(parseMounts(string(mounts)), path)
+}
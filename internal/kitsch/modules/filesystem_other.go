@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package modules
+
+func filesystemType(path string) string {
+	return ""
+}
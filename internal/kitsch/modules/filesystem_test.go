@@ -0,0 +1,47 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilesystemTypeFromMounts(t *testing.T) {
+	mounts := parseMounts(heredoc.Doc(`
+		/dev/sda1 / ext4 rw,relatime 0 0
+		proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0
+		server:/export/home /home nfs4 rw,relatime 0 0
+		/dev/sdb1 /home/jwalton/local ext4 rw 0 0
+		jwalton@host:/ /home/jwalton/remote\040drive fuse.sshfs rw 0 0
+		C:\134 /mnt/c 9p rw 0 0
+	`))
+
+	assert.Equal(t, "ext4", filesystemTypeFromMounts(mounts, "/"))
+	assert.Equal(t, "ext4", filesystemTypeFromMounts(mounts, "/tmp"))
+	assert.Equal(t, "nfs4", filesystemTypeFromMounts(mounts, "/home/jwalton"))
+	assert.Equal(t, "ext4", filesystemTypeFromMounts(mounts, "/home/jwalton/local/src"))
+	assert.Equal(t, "nfs4", filesystemTypeFromMounts(mounts, "/home/jwalton/localfoo"))
+	assert.Equal(t, "fuse.sshfs", filesystemTypeFromMounts(mounts, "/home/jwalton/remote drive/src"))
+	assert.Equal(t, "9p", filesystemTypeFromMounts(mounts, "/mnt/c/Users"))
+	assert.Equal(t, "", filesystemTypeFromMounts(nil, "/"))
+}
+
+func TestIsNetworkFilesystemType(t *testing.T) {
+	assert.True(t, isNetworkFilesystemType("nfs"))
+	assert.True(t, isNetworkFilesystemType("smbfs"))
+	assert.True(t, isNetworkFilesystemType("fuse.sshfs"))
+	assert.True(t, isNetworkFilesystemType("9p"))
+	assert.False(t, isNetworkFilesystemType("ext4"))
+	assert.False(t, isNetworkFilesystemType("apfs"))
+	assert.False(t, isNetworkFilesystemType(""))
+}
+
+func TestIsNetworkFilesystem(t *testing.T) {
+	globals := Globals{}
+	assert.False(t, globals.IsNetworkFilesystem())
+
+	globals.SetFilesystemType("smbfs")
+	assert.Equal(t, "smbfs", globals.FilesystemType())
+	assert.True(t, globals.IsNetworkFilesystem())
+}
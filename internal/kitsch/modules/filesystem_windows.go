@@ -0,0 +1,36 @@
+package modules
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const driveRemote = 4
+
+var procGetDriveType = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDriveTypeW")
+
+// filesystemType returns "remote" if `path` is on a network drive, or ""
+// otherwise.
+func filesystemType(path string) string {
+	if strings.HasPrefix(path, `\\`) {
+		// UNC path, like "\\server\share".
+		return "remote"
+	}
+
+	volume := filepath.VolumeName(path)
+	if volume == "" {
+		return ""
+	}
+	root, err := syscall.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return ""
+	}
+
+	driveType, _, _ := procGetDriveType.Call(uintptr(unsafe.Pointer(root)))
+	if driveType == driveRemote {
+		return "remote"
+	}
+	return ""
+}
@@ -3,9 +3,8 @@ package modules
 import (
 	"strings"
 
-	"github.com/jwalton/go-ansiparser"
 	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
-	"github.com/mattn/go-runewidth"
+	"github.com/jwalton/kitsch/internal/textwidth"
 	"gopkg.in/yaml.v3"
 )
 
@@ -77,7 +76,7 @@ func processFlexibleSpaces(terminalWidth int, renderedPrompt string, replacement
 
 			segmentsTotalLength := 0
 			for _, segment := range segments {
-				segmentsTotalLength += getPrintWidth(segment)
+				segmentsTotalLength += textwidth.Width(segment)
 			}
 
 			extraSpace := terminalWidth - segmentsTotalLength
@@ -107,22 +106,3 @@ func processFlexibleSpaces(terminalWidth int, renderedPrompt string, replacement
 
 	return result
 }
-
-func getPrintWidth(str string) int {
-	width := 0
-	tokenizer := ansiparser.NewStringTokenizer(str)
-
-	for tokenizer.Next() {
-		token := tokenizer.Token()
-
-		if token.Type == ansiparser.String {
-			if token.IsASCII {
-				width += len(token.Content)
-			} else {
-				width += runewidth.StringWidth(token.Content)
-			}
-		}
-	}
-
-	return width
-}
@@ -0,0 +1,136 @@
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas GcloudModule
+
+// GcloudModule shows the current Google Cloud project and account.  This reads
+// the gcloud configuration files directly instead of running `gcloud`, which
+// is very slow to start.  This shows nothing if no project is set.
+//
+type GcloudModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=gcloud"`
+	// Symbol is the symbol to show before the project.  Defaults to "☁ ".
+	Symbol string `yaml:"symbol"`
+}
+
+type gcloudModuleResult struct {
+	// Config is the name of the active gcloud configuration.
+	Config string
+	// Project is the current project, or "" if no project is set.
+	Project string
+	// Account is the current account.
+	Account string
+	// Region is the default compute region.
+	Region string
+}
+
+// Execute the module.
+func (mod GcloudModule) Execute(context *Context) ModuleResult {
+	data := readGcloudConfig(context.Getenv, context.Globals.Home)
+
+	text := ""
+	if data.Project != "" {
+		text = mod.Symbol + data.Project
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// gcloudConfigDir returns the folder gcloud stores its configuration in.
+func gcloudConfigDir(getenv func(string) string, home string) string {
+	if dir := getenv("CLOUDSDK_CONFIG"); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		if appData := getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "gcloud")
+		}
+	}
+	return filepath.Join(home, ".config", "gcloud")
+}
+
+// readGcloudConfig reads the active gcloud configuration.  Like gcloud,
+// environment variables override values from the configuration file.
+func readGcloudConfig(getenv func(string) string, home string) gcloudModuleResult {
+	configDir := gcloudConfigDir(getenv, home)
+
+	result := gcloudModuleResult{Config: getenv("CLOUDSDK_ACTIVE_CONFIG_NAME")}
+	if result.Config == "" {
+		if activeConfig, err := os.ReadFile(filepath.Join(configDir, "active_config")); err == nil {
+			result.Config = strings.TrimSpace(string(activeConfig))
+		}
+	}
+	if result.Config == "" {
+		result.Config = "default"
+	}
+
+	config := map[string]map[string]string{}
+	if contents, err := os.ReadFile(filepath.Join(configDir, "configurations", "config_"+result.Config)); err == nil {
+		config = parseINI(contents)
+	}
+
+	result.Project = defaultString(getenv("CLOUDSDK_CORE_PROJECT"), config["core"]["project"])
+	result.Account = defaultString(getenv("CLOUDSDK_CORE_ACCOUNT"), config["core"]["account"])
+	result.Region = defaultString(getenv("CLOUDSDK_COMPUTE_REGION"), config["compute"]["region"])
+
+	return result
+}
+
+// parseINI parses the contents of an INI file into a map of sections, where
+// each section is a map of keys to values.
+func parseINI(contents []byte) map[string]map[string]string {
+	result := map[string]map[string]string{}
+	section := map[string]string{}
+	result[""] = section
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if result[name] == nil {
+				result[name] = map[string]string{}
+			}
+			section = result[name]
+			continue
+		}
+
+		index := strings.IndexAny(line, "=:")
+		if index == -1 {
+			continue
+		}
+		section[strings.TrimSpace(line[:index])] = strings.TrimSpace(line[index+1:])
+	}
+
+	return result
+}
+
+func init() {
+	registerModule(
+		"gcloud",
+		registeredModule{
+			jsonSchema: schemas.GcloudModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := GcloudModule{Type: "gcloud", Symbol: "☁ "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
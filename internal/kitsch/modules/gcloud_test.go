@@ -0,0 +1,89 @@
+package modules
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGcloudConfig creates a CLOUDSDK_CONFIG folder where "work" is the
+// active configuration.
+func fakeGcloudConfig(t *testing.T) string {
+	configDir := t.TempDir()
+	writeTestFile(t, filepath.Join(configDir, "active_config"), "work\n")
+	writeTestFile(t, filepath.Join(configDir, "configurations", "config_work"), heredoc.Doc(`
+		[core]
+		account = jwalton@example.com
+		project = kitsch-prod
+
+		[compute]
+		region = us-east1
+	`))
+	writeTestFile(t, filepath.Join(configDir, "configurations", "config_default"), heredoc.Doc(`
+		[core]
+		account = jwalton@gmail.com
+	`))
+
+	return configDir
+}
+
+func TestGcloud(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: gcloud
+	`)).(*GcloudModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"CLOUDSDK_CONFIG": fakeGcloudConfig(t)}, nil))
+
+	assert.Equal(t, "☁ kitsch-prod", result.DefaultText)
+	assert.Equal(t, gcloudModuleResult{
+		Config:  "work",
+		Project: "kitsch-prod",
+		Account: "jwalton@example.com",
+		Region:  "us-east1",
+	}, result.Data)
+}
+
+func TestGcloudEnvironmentOverrides(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: gcloud
+	`)).(*GcloudModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{
+		"CLOUDSDK_CONFIG":             fakeGcloudConfig(t),
+		"CLOUDSDK_ACTIVE_CONFIG_NAME": "default",
+		"CLOUDSDK_CORE_PROJECT":       "kitsch-dev",
+	}, nil))
+
+	assert.Equal(t, "☁ kitsch-dev", result.DefaultText)
+	assert.Equal(t, gcloudModuleResult{
+		Config:  "default",
+		Project: "kitsch-dev",
+		Account: "jwalton@gmail.com",
+	}, result.Data)
+}
+
+func TestGcloudNoProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: gcloud
+	`)).(*GcloudModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{
+		"CLOUDSDK_CONFIG":             fakeGcloudConfig(t),
+		"CLOUDSDK_ACTIVE_CONFIG_NAME": "default",
+	}, nil))
+
+	assert.Equal(t, "", result.DefaultText)
+}
+
+func TestGcloudNotConfigured(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: gcloud
+	`)).(*GcloudModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"CLOUDSDK_CONFIG": t.TempDir()}, nil))
+
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, gcloudModuleResult{Config: "default"}, result.Data)
+}
@@ -3,7 +3,9 @@ package modules
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
 	"gopkg.in/yaml.v3"
 )
@@ -26,6 +28,16 @@ type GitDiverged struct {
 	UpToDateSymbol string `yaml:"upToDateSymbol"`
 	// NoUpstreamSymbol is the symbol to use when the current branch has no upstream.
 	NoUpstreamSymbol string `yaml:"noUpstreamSymbol"`
+	// Exact, if true, runs `git rev-list` to count exactly how many commits
+	// we are ahead and behind.  By default the counts come from a fast walk
+	// of the commit graph, which gives up and returns an estimate if the
+	// branches have diverged by more than 1000 commits.
+	Exact bool `yaml:"exact"`
+	// ExactTimeout is the maximum time to wait for `git rev-list`, in
+	// milliseconds, when `Exact` is true.  Defaults to 5000.  If the module
+	// has no `timeout`, it is given enough time to wait for git and then fall
+	// back to the estimate.
+	ExactTimeout int64 `yaml:"exactTimeout"`
 }
 
 type gitDivergedResult struct {
@@ -41,6 +53,8 @@ type gitDivergedResult struct {
 	// AheadBehind is "ahead" if we are ahead of the upstream branch, "behind"
 	// if we are behind, "diverged" if we are both, and "upToDate" otherwise.
 	AheadBehind string `json:"aheadBehind"`
+	// Exact is true if Ahead and Behind were counted by git.
+	Exact bool `json:"exact"`
 }
 
 // Execute runs a git module.
@@ -60,10 +74,11 @@ func (mod GitDiverged) Execute(context *Context) ModuleResult {
 
 	var ahead, behind int
 	var upstream string
+	exact := false
 	if !head.Detached {
 		upstream = git.GetUpstream(head.Description)
 		if upstream != "" {
-			ahead, behind, _ = git.GetAheadBehind("refs/heads/"+head.Description, "refs/remotes/"+upstream)
+			ahead, behind, exact = mod.aheadBehind(context, "refs/heads/"+head.Description, "refs/remotes/"+upstream)
 		}
 	}
 
@@ -91,6 +106,7 @@ func (mod GitDiverged) Execute(context *Context) ModuleResult {
 		Behind:      behind,
 		Symbol:      symbol,
 		AheadBehind: aheadBehind,
+		Exact:       exact,
 	}
 
 	return ModuleResult{
@@ -99,6 +115,34 @@ func (mod GitDiverged) Execute(context *Context) ModuleResult {
 	}
 }
 
+// minimumTimeout gives the module long enough to wait for `git rev-list` to
+// time out, and then fall back to walking the commit graph.
+func (mod GitDiverged) minimumTimeout(context *Context) time.Duration {
+	if !mod.Exact {
+		return 0
+	}
+	return time.Duration(mod.ExactTimeout)*time.Millisecond + context.DefaultTimeout
+}
+
+// aheadBehind counts how many commits localRef is ahead and behind remoteRef.
+// If `Exact` is set, this runs git, and falls back to the faster estimate if
+// git fails or takes too long.  `exact` is true if the counts came from git.
+func (mod GitDiverged) aheadBehind(context *Context, localRef string, remoteRef string) (ahead int, behind int, exact bool) {
+	git := context.Git()
+
+	if mod.Exact {
+		timeout := time.Duration(mod.ExactTimeout) * time.Millisecond
+		ahead, behind, err := git.CountAheadBehind(localRef, remoteRef, timeout, context.ValueCache)
+		if err == nil {
+			return ahead, behind, true
+		}
+		log.Debug("Failed to count commits with git rev-list: ", err)
+	}
+
+	ahead, behind, _ = git.GetAheadBehind(localRef, remoteRef)
+	return ahead, behind, false
+}
+
 func (mod GitDiverged) renderDefault(
 	context *Context,
 	symbol string,
@@ -132,6 +176,7 @@ func init() {
 					DivergedSymbol:   "↕",
 					UpToDateSymbol:   "≡",
 					NoUpstreamSymbol: "?",
+					ExactTimeout:     5000,
 				}
 				err := node.Decode(&module)
 				return &module, err
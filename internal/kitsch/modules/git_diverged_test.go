@@ -0,0 +1,93 @@
+package modules
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/cache"
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitDiverged(t *testing.T) {
+	context := NewDemoContext(
+		DemoConfig{
+			Git: gitutils.DemoGit{
+				HeadDescription:       "master",
+				CurrentBranchUpstream: "origin/master",
+				Ahead:                 2,
+				Behind:                3,
+			},
+		},
+		&styling.Registry{},
+	)
+
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_diverged
+	`))
+
+	result := mod.Execute(&context)
+	assert.Equal(t, "↓3 ↑2", result.Text)
+	assert.Equal(t, false, result.Data.(gitDivergedResult).Exact)
+
+	mod = moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_diverged
+		exact: true
+	`))
+
+	result = mod.Execute(&context)
+	assert.Equal(t, "↓3 ↑2", result.Text)
+	assert.Equal(t, "diverged", result.Data.(gitDivergedResult).AheadBehind)
+	assert.Equal(t, true, result.Data.(gitDivergedResult).Exact)
+}
+
+// slowRevListGit is a DemoGit where `git rev-list` takes `revListDuration`.
+type slowRevListGit struct {
+	gitutils.DemoGit
+	revListDuration time.Duration
+}
+
+func (git slowRevListGit) CountAheadBehind(
+	localRef string,
+	remoteRef string,
+	timeout time.Duration,
+	valueCache cache.Cache,
+) (ahead int, behind int, err error) {
+	if git.revListDuration > timeout {
+		time.Sleep(timeout)
+		return 0, 0, errors.New("git rev-list timed out")
+	}
+	time.Sleep(git.revListDuration)
+	return git.DemoGit.CountAheadBehind(localRef, remoteRef, timeout, valueCache)
+}
+
+func TestGitDivergedExactFallsBackWhenSlow(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.DefaultTimeout = 50 * time.Millisecond
+	context.gitInitialized = true
+	context.git = slowRevListGit{
+		DemoGit: gitutils.DemoGit{
+			HeadDescription:       "master",
+			CurrentBranchUpstream: "origin/master",
+			Ahead:                 2,
+			Behind:                3,
+		},
+		revListDuration: time.Second,
+	}
+
+	// git rev-list takes longer than both exactTimeout and the module's
+	// default timeout, so we should give up on git and use the estimate.
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_diverged
+		exact: true
+		exactTimeout: 100
+	`))
+
+	result := mod.Execute(context)
+	assert.Equal(t, "↓3 ↑2", result.Text)
+	assert.Equal(t, false, result.Data.(gitDivergedResult).Exact)
+	assert.Empty(t, context.Warnings())
+}
@@ -15,6 +15,9 @@ type GitHeadModule struct {
 	// MaxTagsToSearch is the maximum number of tags to search when checking to
 	// see if HEAD is a tagged release.  Defaults to 200.
 	MaxTagsToSearch int `yaml:"maxTagsToSearch"`
+	// DefaultBranchStyle is the style to use for the default output when we
+	// are on the repo's default branch (e.g. "main").
+	DefaultBranchStyle string `yaml:"defaultBranchStyle"`
 }
 
 type gitHeadResult struct {
@@ -33,6 +36,10 @@ type gitHeadResult struct {
 	// Upstream is the name of the upstream branch, or "" if there is no upstream,
 	// of if the Head is detached.
 	Upstream string
+	// DefaultBranch is the name of the repo's default branch (e.g. "main").
+	DefaultBranch string
+	// IsDefaultBranch is true if we are currently on the default branch.
+	IsDefaultBranch bool
 }
 
 // Execute runs a git module.
@@ -63,12 +70,22 @@ func (mod GitHeadModule) Execute(context *Context) ModuleResult {
 		shortHash = shortHash[0:7]
 	}
 
-	return ModuleResult{DefaultText: head.Description, Data: gitHeadResult{
-		Description: head.Description,
-		Detached:    head.Detached,
-		Hash:        head.Hash,
-		ShortHash:   shortHash,
-		Upstream:    upstream,
+	defaultBranch := git.DefaultBranch()
+	isDefaultBranch := !head.Detached && head.Description == defaultBranch
+
+	defaultText := head.Description
+	if isDefaultBranch {
+		defaultText = context.GetStyle(mod.DefaultBranchStyle).Apply(defaultText)
+	}
+
+	return ModuleResult{DefaultText: defaultText, Data: gitHeadResult{
+		Description:     head.Description,
+		Detached:        head.Detached,
+		Hash:            head.Hash,
+		ShortHash:       shortHash,
+		Upstream:        upstream,
+		DefaultBranch:   defaultBranch,
+		IsDefaultBranch: isDefaultBranch,
 	}}
 }
 
@@ -0,0 +1,47 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHeadDefaultBranch(t *testing.T) {
+	context := NewDemoContext(
+		DemoConfig{
+			Git: gitutils.DemoGit{
+				HeadDescription:   "main",
+				DefaultBranchName: "main",
+			},
+		},
+		&styling.Registry{},
+	)
+
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_head
+		defaultBranchStyle: red
+	`))
+
+	result := mod.Execute(&context)
+	assert.Equal(t, "main", result.Text)
+	assert.Equal(t, "main", result.Data.(gitHeadResult).DefaultBranch)
+	assert.Equal(t, true, result.Data.(gitHeadResult).IsDefaultBranch)
+
+	context = NewDemoContext(
+		DemoConfig{
+			Git: gitutils.DemoGit{
+				HeadDescription:   "feature/widgets",
+				DefaultBranchName: "main",
+			},
+		},
+		&styling.Registry{},
+	)
+
+	result = mod.Execute(&context)
+	assert.Equal(t, "feature/widgets", result.Text)
+	assert.Equal(t, "main", result.Data.(gitHeadResult).DefaultBranch)
+	assert.Equal(t, false, result.Data.(gitHeadResult).IsDefaultBranch)
+}
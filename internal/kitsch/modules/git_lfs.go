@@ -0,0 +1,73 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas GitLFSModule
+
+// GitLFSModule shows whether the current git repo uses Git LFS, and warns if
+// git-lfs is not installed.
+//
+type GitLFSModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=git_lfs"`
+	// Symbol is the text to show when the repo uses LFS and git-lfs is installed.
+	Symbol string `yaml:"symbol"`
+	// MissingSymbol is the text to show when the repo uses LFS, but git-lfs
+	// is not installed.
+	MissingSymbol string `yaml:"missingSymbol"`
+}
+
+type gitLFSResult struct {
+	// Enabled is true if the repo uses LFS.
+	Enabled bool
+	// Installed is true if git-lfs is installed.
+	Installed bool
+	// Missing is true if the repo uses LFS, but git-lfs is not installed.
+	Missing bool
+}
+
+// Execute runs a git_lfs module.
+func (mod GitLFSModule) Execute(context *Context) ModuleResult {
+	git := context.Git()
+
+	if git == nil {
+		return ModuleResult{DefaultText: "", Data: gitLFSResult{}}
+	}
+
+	lfs := git.LFS()
+	data := gitLFSResult{
+		Enabled:   lfs.Enabled,
+		Installed: lfs.Installed,
+		Missing:   lfs.Missing(),
+	}
+
+	text := ""
+	if data.Missing {
+		text = mod.MissingSymbol
+	} else if data.Enabled {
+		text = mod.Symbol
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+func init() {
+	registerModule(
+		"git_lfs",
+		registeredModule{
+			jsonSchema: schemas.GitLFSModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := GitLFSModule{
+					Type:          "git_lfs",
+					Symbol:        "",
+					MissingSymbol: "LFS?",
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
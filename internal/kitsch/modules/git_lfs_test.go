@@ -0,0 +1,37 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitLFS(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_lfs
+		symbol: LFS
+	`))
+
+	tests := []struct {
+		lfs      gitutils.LFSInfo
+		expected string
+	}{
+		{gitutils.LFSInfo{}, ""},
+		{gitutils.LFSInfo{Enabled: true, Installed: true}, "LFS"},
+		{gitutils.LFSInfo{Enabled: true, Installed: false}, "LFS?"},
+	}
+
+	for _, test := range tests {
+		context := NewDemoContext(
+			DemoConfig{Git: gitutils.DemoGit{HeadDescription: "master", CurrentLFS: test.lfs}},
+			&styling.Registry{},
+		)
+
+		result := mod.Execute(&context)
+		assert.Equal(t, test.expected, result.Text)
+		assert.Equal(t, test.lfs.Missing(), result.Data.(gitLFSResult).Missing)
+	}
+}
@@ -0,0 +1,63 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas GitSparseModule
+
+// GitSparseModule shows whether the current git repo is a sparse checkout.
+//
+type GitSparseModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=git_sparse"`
+	// Symbol is the text to show when the repo is a sparse checkout.
+	Symbol string `yaml:"symbol"`
+}
+
+type gitSparseResult struct {
+	// Enabled is true if the repo is a sparse checkout.
+	Enabled bool
+	// Cone is true if the sparse checkout is in cone mode.
+	Cone bool
+}
+
+// Execute runs a git_sparse module.
+func (mod GitSparseModule) Execute(context *Context) ModuleResult {
+	git := context.Git()
+
+	if git == nil {
+		return ModuleResult{DefaultText: "", Data: gitSparseResult{}}
+	}
+
+	sparse := git.SparseCheckout()
+	data := gitSparseResult{
+		Enabled: sparse.Enabled,
+		Cone:    sparse.Cone,
+	}
+
+	text := ""
+	if data.Enabled {
+		text = mod.Symbol
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+func init() {
+	registerModule(
+		"git_sparse",
+		registeredModule{
+			jsonSchema: schemas.GitSparseModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := GitSparseModule{
+					Type:   "git_sparse",
+					Symbol: "SPARSE",
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
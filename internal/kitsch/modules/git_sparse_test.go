@@ -0,0 +1,37 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitSparse(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_sparse
+	`))
+
+	context := NewDemoContext(
+		DemoConfig{Git: gitutils.DemoGit{HeadDescription: "master"}},
+		&styling.Registry{},
+	)
+
+	result := mod.Execute(&context)
+	assert.Equal(t, "", result.Text)
+	assert.Equal(t, gitSparseResult{}, result.Data)
+
+	context = NewDemoContext(
+		DemoConfig{Git: gitutils.DemoGit{
+			HeadDescription:       "master",
+			CurrentSparseCheckout: gitutils.SparseCheckoutInfo{Enabled: true, Cone: true},
+		}},
+		&styling.Registry{},
+	)
+
+	result = mod.Execute(&context)
+	assert.Equal(t, "SPARSE", result.Text)
+	assert.Equal(t, gitSparseResult{Enabled: true, Cone: true}, result.Data)
+}
@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/jwalton/kitsch/internal/gitutils"
-	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
 	"gopkg.in/yaml.v3"
 )
@@ -26,6 +25,10 @@ type GitStatusModule struct {
 	UnstagedStyle string `yaml:"unstagedStyle"`
 	// StashStyle is the style to use for the stash count.
 	StashStyle string `yaml:"stashStyle"`
+	// ScanNetworkFilesystems, if false, skips counting changed files when the
+	// current directory is on a network filesystem, since this has to read
+	// every file in the work tree.
+	ScanNetworkFilesystems bool `yaml:"scanNetworkFilesystems"`
 }
 
 type gitStatusModuleResult struct {
@@ -37,8 +40,13 @@ type gitStatusModuleResult struct {
 	Unstaged gitutils.GitFileStats
 	// Unmerged is the total number of unmerged paths in the git repo.
 	Unmerged int
+	// Conflicts breaks down Unmerged by the type of conflict.
+	Conflicts gitutils.GitConflictStats
 	// StashCount is the number of stashes in the git repo.
 	StashCount int
+	// Skipped is true if we didn't count changed files because the repo is on
+	// a network filesystem.
+	Skipped bool
 }
 
 // Execute runs a git module.
@@ -49,11 +57,17 @@ func (mod GitStatusModule) Execute(context *Context) ModuleResult {
 		return ModuleResult{DefaultText: "", Data: gitStatusModuleResult{}}
 	}
 
-	stats, _ := git.Stats()
+	var stats gitutils.GitStats
+	skipped := !mod.ScanNetworkFilesystems && context.Globals.IsNetworkFilesystem()
+	if !skipped {
+		stats, _ = git.Stats()
+	}
+
+	var warnings []string
 	stashCount, err := git.GetStashCount()
 	if err != nil {
 		stashCount = 0
-		log.Warn("Error getting stash count: ", err)
+		warnings = append(warnings, fmt.Sprint("Error getting stash count: ", err))
 	}
 
 	return ModuleResult{
@@ -62,8 +76,11 @@ func (mod GitStatusModule) Execute(context *Context) ModuleResult {
 			Index:      stats.Index,
 			Unstaged:   stats.Unstaged,
 			Unmerged:   stats.Unmerged,
+			Conflicts:  stats.Conflicts,
 			StashCount: stashCount,
+			Skipped:    skipped,
 		},
+		Warnings: warnings,
 	}
 }
 
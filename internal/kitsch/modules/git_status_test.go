@@ -60,6 +60,10 @@ func TestGitStatusUnmerged(t *testing.T) {
 			Git: gitutils.DemoGit{
 				CurrentStats: gitutils.GitStats{
 					Unmerged: 4,
+					Conflicts: gitutils.GitConflictStats{
+						BothModified:  3,
+						DeletedByThem: 1,
+					},
 				},
 			},
 		},
@@ -72,4 +76,46 @@ func TestGitStatusUnmerged(t *testing.T) {
 
 	result := mod.Execute(&context)
 	assert.Equal(t, "+0 ~0 -0 !4", result.Text)
+	assert.Equal(t,
+		gitutils.GitConflictStats{BothModified: 3, DeletedByThem: 1},
+		result.Data.(gitStatusModuleResult).Conflicts,
+	)
+
+	mod = moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_status
+		template: "{{ with .Data.Conflicts }}UU:{{ .BothModified }} UD:{{ .DeletedByThem }}{{ end }}"
+	`))
+
+	result = mod.Execute(&context)
+	assert.Equal(t, "UU:3 UD:1", result.Text)
+}
+
+func TestGitStatusNetworkFilesystem(t *testing.T) {
+	context := NewDemoContext(
+		DemoConfig{
+			Git: gitutils.DemoGit{
+				CurrentStats: gitutils.GitStats{
+					Unstaged: gitutils.GitFileStats{Modified: 2},
+				},
+				StashCount: 1,
+			},
+		},
+		&styling.Registry{},
+	)
+	context.Globals.SetFilesystemType("nfs4")
+
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_status
+	`))
+	result := mod.Execute(&context)
+	assert.Equal(t, "(1)", result.Text)
+	assert.True(t, result.Data.(gitStatusModuleResult).Skipped)
+
+	mod = moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_status
+		scanNetworkFilesystems: true
+	`))
+	result = mod.Execute(&context)
+	assert.Equal(t, "+0 ~2 -0 (1)", result.Text)
+	assert.False(t, result.Data.(gitStatusModuleResult).Skipped)
 }
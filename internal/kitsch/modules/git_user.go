@@ -0,0 +1,57 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas GitUserModule
+
+// GitUserModule shows the identity git will use for commits in the current repo.
+//
+type GitUserModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=git_user"`
+}
+
+type gitUserResult struct {
+	// Name is the effective `user.name` for the current repo.
+	Name string
+	// Email is the effective `user.email` for the current repo.
+	Email string
+}
+
+// Execute runs a git_user module.
+func (mod GitUserModule) Execute(context *Context) ModuleResult {
+	git := context.Git()
+
+	if git == nil {
+		return ModuleResult{DefaultText: "", Data: gitUserResult{}}
+	}
+
+	user, err := git.User()
+	if err != nil {
+		return ModuleResult{DefaultText: "", Data: gitUserResult{}}
+	}
+
+	return ModuleResult{DefaultText: user.Email, Data: gitUserResult{
+		Name:  user.Name,
+		Email: user.Email,
+	}}
+}
+
+func init() {
+	registerModule(
+		"git_user",
+		registeredModule{
+			jsonSchema: schemas.GitUserModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := GitUserModule{
+					Type: "git_user",
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
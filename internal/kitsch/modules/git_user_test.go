@@ -0,0 +1,41 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitUser(t *testing.T) {
+	context := NewDemoContext(
+		DemoConfig{
+			Git: gitutils.DemoGit{
+				HeadDescription: "master",
+				CurrentUser: gitutils.UserInfo{
+					Name:  "Jason Walton",
+					Email: "jwalton@example.com",
+				},
+			},
+		},
+		&styling.Registry{},
+	)
+
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_user
+	`))
+
+	result := mod.Execute(&context)
+	assert.Equal(t, "jwalton@example.com", result.Text)
+	assert.Equal(t, gitUserResult{Name: "Jason Walton", Email: "jwalton@example.com"}, result.Data)
+
+	mod = moduleWrapperFromYAML(heredoc.Doc(`
+		type: git_user
+		template: '{{ if hasSuffix "@example.com" .Data.Email }}personal email!{{ end }}'
+	`))
+
+	result = mod.Execute(&context)
+	assert.Equal(t, "personal email!", result.Text)
+}
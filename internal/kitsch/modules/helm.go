@@ -0,0 +1,128 @@
+package modules
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas HelmModule
+
+// HelmModule shows the name and version of the Helm chart in the current
+// folder, along with the Kubernetes context `helm install` would deploy it to.
+//
+type HelmModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=helm"`
+	// Symbol is the symbol to show before the chart name.  Defaults to "⎈ ".
+	Symbol string `yaml:"symbol"`
+	// ConfigFile is the path to the kubectl config file.  Defaults to the
+	// first file in KUBECONFIG, or "~/.kube/config".
+	ConfigFile string `yaml:"configFile"`
+	// configFileContents is the contents of the kubectl config file.  This is
+	// used for unit testing.
+	configFileContents []byte
+}
+
+type helmModuleResult struct {
+	// Chart is the name of the chart.
+	Chart string
+	// Version is the version of the chart.
+	Version string
+	// AppVersion is the version of the app in the chart.
+	AppVersion string
+	// ChartFile is the path to the "Chart.yaml" file.
+	ChartFile string
+	// KubeContext is the Kubernetes context the chart would be deployed to.
+	KubeContext string
+	// Namespace is the namespace the chart would be deployed to.
+	Namespace string
+}
+
+// helmChart is the contents of a "Chart.yaml" file.
+type helmChart struct {
+	Name       string `yaml:"name"`
+	Version    string `yaml:"version"`
+	AppVersion string `yaml:"appVersion"`
+}
+
+// Execute the module.
+func (mod HelmModule) Execute(context *Context) ModuleResult {
+	path, contents := readAncestorFile(context.Directory, "Chart.yaml")
+	if path == "" {
+		return ModuleResult{Data: helmModuleResult{}}
+	}
+
+	chart := helmChart{}
+	if err := yaml.Unmarshal(contents, &chart); err != nil {
+		return ModuleResult{
+			Data:     helmModuleResult{},
+			Warnings: []string{fmt.Sprintf("Could not parse %s: %v", path, err)},
+		}
+	}
+
+	data := helmModuleResult{
+		Chart:      chart.Name,
+		Version:    chart.Version,
+		AppVersion: chart.AppVersion,
+		ChartFile:  path,
+	}
+	data.KubeContext, data.Namespace = mod.deployTarget(context)
+
+	text := mod.Symbol + data.Chart
+	if data.Version != "" {
+		text += " " + data.Version
+	}
+	if data.KubeContext != "" {
+		text += " → " + data.KubeContext
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// deployTarget returns the Kubernetes context and namespace helm would use.
+// Like helm, HELM_KUBECONTEXT and HELM_NAMESPACE override the kubectl config.
+func (mod HelmModule) deployTarget(context *Context) (string, string) {
+	configFile := mod.ConfigFile
+	if configFile == "" {
+		if kubeconfig := filepath.SplitList(context.Getenv("KUBECONFIG")); len(kubeconfig) > 0 {
+			configFile = kubeconfig[0]
+		}
+	}
+
+	kubeContext := context.Getenv("HELM_KUBECONTEXT")
+	namespace := context.Getenv("HELM_NAMESPACE")
+
+	kubernetes := KubernetesModule{ConfigFile: configFile, configFileContents: mod.configFileContents}
+	if config := kubernetes.loadConfigFile(context.Globals.Home); config != nil {
+		if kubeContext == "" {
+			kubeContext = config.CurrentContext
+		}
+		if namespace == "" {
+			for _, c := range config.Contexts {
+				if c.Name == kubeContext {
+					namespace = c.Context.Namespace
+					break
+				}
+			}
+		}
+	}
+
+	return kubeContext, defaultString(namespace, "default")
+}
+
+func init() {
+	registerModule(
+		"helm",
+		registeredModule{
+			jsonSchema: schemas.HelmModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := HelmModule{Type: "helm", Symbol: "⎈ "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,78 @@
+package modules
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+const testHelmKubeConfig = `apiVersion: v1
+kind: Config
+contexts:
+  - name: prod
+    context:
+      cluster: prod-cluster
+      user: admin
+      namespace: web
+  - name: staging
+    context:
+      cluster: staging-cluster
+      user: admin
+current-context: prod
+`
+
+var testHelmChart = fstest.MapFS{
+	"Chart.yaml": &fstest.MapFile{Data: []byte(heredoc.Doc(`
+		apiVersion: v2
+		name: webapp
+		version: 1.2.0
+		appVersion: "3.4.1"
+	`))},
+}
+
+func TestHelm(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: helm
+	`)).(*HelmModule)
+	mod.configFileContents = []byte(testHelmKubeConfig)
+
+	result := mod.Execute(newTestContextWith(map[string]string{}, testHelmChart))
+
+	assert.Equal(t, "⎈ webapp 1.2.0 → prod", result.DefaultText)
+	assert.Equal(t, helmModuleResult{
+		Chart:       "webapp",
+		Version:     "1.2.0",
+		AppVersion:  "3.4.1",
+		ChartFile:   "/Users/jwalton/Chart.yaml",
+		KubeContext: "prod",
+		Namespace:   "web",
+	}, result.Data)
+}
+
+func TestHelmEnvironmentOverrides(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: helm
+	`)).(*HelmModule)
+	mod.configFileContents = []byte(testHelmKubeConfig)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"HELM_KUBECONTEXT": "staging"}, testHelmChart))
+	assert.Equal(t, "⎈ webapp 1.2.0 → staging", result.DefaultText)
+	assert.Equal(t, "default", result.Data.(helmModuleResult).Namespace)
+
+	result = mod.Execute(newTestContextWith(map[string]string{"HELM_NAMESPACE": "api"}, testHelmChart))
+	assert.Equal(t, "prod", result.Data.(helmModuleResult).KubeContext)
+	assert.Equal(t, "api", result.Data.(helmModuleResult).Namespace)
+}
+
+func TestHelmNoChart(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: helm
+	`)).(*HelmModule)
+	mod.configFileContents = []byte(testHelmKubeConfig)
+
+	result := mod.Execute(newTestContextWith(map[string]string{}, fstest.MapFS{}))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, helmModuleResult{}, result.Data)
+}
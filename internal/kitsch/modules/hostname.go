@@ -35,7 +35,7 @@ func (mod HostnameModule) Execute(context *Context) ModuleResult {
 	isSSH := context.Environment.HasSomeEnv("SSH_CLIENT", "SSH_CONNECTION", "SSH_TTY")
 	show := isSSH || mod.ShowAlways
 
-	hostname := context.Globals.Hostname
+	hostname := context.Globals.Hostname()
 
 	// If the hostname is a FQDM, just grab the first part of the hostname.
 	if strings.Contains(hostname, ".") {
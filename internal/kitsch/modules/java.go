@@ -0,0 +1,128 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas JavaModule
+
+// JavaModule shows the version of the JDK in Java projects, and which build
+// tool the project uses.  The version is read from the "release" file in the
+// JDK, so this never needs to run `java -version`, which can be slow.
+//
+type JavaModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=java"`
+	// Symbol is the symbol to show before the version.  Defaults to "☕ ".
+	Symbol string `yaml:"symbol"`
+}
+
+type javaModuleResult struct {
+	// Version is the version of the JDK, or "" if it could not be found.
+	Version string
+	// JavaHome is the folder the JDK is installed in.
+	JavaHome string
+	// BuildTool is the build tool used by the project: "gradle", "maven", or ""
+	// if neither was found.
+	BuildTool string
+}
+
+// javaConditions are the conditions for the java module to be shown.
+var javaConditions = condition.Conditions{
+	IfFiles: []string{
+		"pom.xml",
+		"build.gradle",
+		"build.gradle.kts",
+		"settings.gradle",
+		"settings.gradle.kts",
+	},
+	IfExtensions: []string{"java"},
+}
+
+// Execute the module.
+func (mod JavaModule) Execute(context *Context) ModuleResult {
+	if !javaConditions.Matches(context.Directory, context) {
+		return ModuleResult{Data: javaModuleResult{}}
+	}
+
+	data := javaModuleResult{
+		JavaHome:  findJavaHome(context.Environment),
+		BuildTool: javaBuildTool(context),
+	}
+	if data.JavaHome != "" {
+		data.Version = readJDKVersion(data.JavaHome)
+	}
+
+	text := mod.Symbol + data.Version
+	if data.BuildTool != "" {
+		if data.Version != "" {
+			text += " "
+		}
+		text += data.BuildTool
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// findJavaHome returns the JDK folder from JAVA_HOME, or from the `java`
+// executable on the PATH.
+func findJavaHome(environment env.Env) string {
+	if home := environment.Getenv("JAVA_HOME"); home != "" {
+		return home
+	}
+
+	java, err := environment.LookPath("java")
+	if err != nil {
+		return ""
+	}
+	java, err = filepath.EvalSymlinks(java)
+	if err != nil {
+		return ""
+	}
+
+	// The executable is in "$JAVA_HOME/bin/java".
+	return filepath.Dir(filepath.Dir(java))
+}
+
+// readJDKVersion reads the JAVA_VERSION from the "release" file in the JDK.
+func readJDKVersion(javaHome string) string {
+	contents, err := os.ReadFile(filepath.Join(javaHome, "release"))
+	if err != nil {
+		return ""
+	}
+	return parseKeyValues(contents)["JAVA_VERSION"]
+}
+
+// javaBuildTool returns the build tool used by the project in the current folder.
+func javaBuildTool(context *Context) string {
+	directory := context.Directory
+	for _, file := range []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts", "gradlew"} {
+		if directory.HasFile(file) {
+			return "gradle"
+		}
+	}
+	if directory.HasFile("pom.xml") || directory.HasFile("mvnw") {
+		return "maven"
+	}
+	return ""
+}
+
+func init() {
+	registerModule(
+		"java",
+		registeredModule{
+			jsonSchema: schemas.JavaModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := JavaModule{Type: "java", Symbol: "☕ "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,74 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJavaHome creates a JAVA_HOME folder for a JDK 17.0.2 install.
+func fakeJavaHome(t *testing.T) string {
+	javaHome := t.TempDir()
+	err := os.WriteFile(filepath.Join(javaHome, "release"), []byte(heredoc.Doc(`
+		IMPLEMENTOR="Eclipse Adoptium"
+		JAVA_VERSION="17.0.2"
+		JAVA_VERSION_DATE="2022-01-18"
+	`)), 0644)
+	require.NoError(t, err)
+
+	return javaHome
+}
+
+func TestJavaGradle(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: java
+	`)).(*JavaModule)
+
+	context := newTestContextWith(map[string]string{"JAVA_HOME": fakeJavaHome(t)}, fstest.MapFS{
+		"build.gradle.kts": &fstest.MapFile{},
+		"gradlew":          &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, "☕ 17.0.2 gradle", result.DefaultText)
+	data := result.Data.(javaModuleResult)
+	assert.Equal(t, "17.0.2", data.Version)
+	assert.Equal(t, "gradle", data.BuildTool)
+}
+
+func TestJavaMaven(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: java
+	`)).(*JavaModule)
+
+	context := newTestContextWith(map[string]string{"JAVA_HOME": fakeJavaHome(t)}, fstest.MapFS{"pom.xml": &fstest.MapFile{}})
+	result := mod.Execute(context)
+	assert.Equal(t, "☕ 17.0.2 maven", result.DefaultText)
+}
+
+func TestJavaSourceFiles(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: java
+	`)).(*JavaModule)
+
+	context := newTestContextWith(map[string]string{"JAVA_HOME": fakeJavaHome(t)}, fstest.MapFS{"Main.java": &fstest.MapFile{}})
+	result := mod.Execute(context)
+	assert.Equal(t, "☕ 17.0.2", result.DefaultText)
+	assert.Equal(t, "", result.Data.(javaModuleResult).BuildTool)
+}
+
+func TestJavaNotAJavaProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: java
+	`)).(*JavaModule)
+
+	context := newTestContextWith(map[string]string{"JAVA_HOME": fakeJavaHome(t)}, fstest.MapFS{"package.json": &fstest.MapFile{}})
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, javaModuleResult{}, result.Data)
+}
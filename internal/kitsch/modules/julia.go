@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas JuliaModule
+
+// JuliaModule shows the version of Julia in Julia projects.
+//
+type JuliaModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=julia"`
+	// Symbol is the symbol to show before the version.  Defaults to "ஃ ".
+	Symbol string `yaml:"symbol"`
+}
+
+type juliaModuleResult struct {
+	// Version is the version of Julia, or "" if it could not be found.
+	Version string
+}
+
+// juliaConditions are the conditions for the julia module to be shown.
+var juliaConditions = condition.Conditions{
+	IfFiles:      []string{"Project.toml", "Manifest.toml"},
+	IfExtensions: []string{"jl"},
+}
+
+// juliaVersionGetter gets the version of Julia.  Julia is slow to start, so
+// the result is cached until Julia is updated.
+var juliaVersionGetter = getters.CustomGetter{
+	Type:  getters.TypeCustom,
+	From:  "julia --version",
+	Regex: `^julia version (\S+)`,
+	Cache: getters.CacheSettings{Enabled: true},
+}
+
+// Execute the module.
+func (mod JuliaModule) Execute(context *Context) ModuleResult {
+	if !juliaConditions.Matches(context.Directory, context) {
+		return ModuleResult{Data: juliaModuleResult{}}
+	}
+
+	data := juliaModuleResult{}
+	if version, err := juliaVersionGetter.GetValue(context); err == nil {
+		data.Version, _ = version.(string)
+	}
+
+	return ModuleResult{DefaultText: mod.Symbol + data.Version, Data: data}
+}
+
+func init() {
+	registerModule(
+		"julia",
+		registeredModule{
+			jsonSchema: schemas.JuliaModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := JuliaModule{Type: "julia", Symbol: "ஃ "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
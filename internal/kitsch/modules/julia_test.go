@@ -0,0 +1,48 @@
+package modules
+
+import (
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJulia(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test executable is a shell script")
+	}
+
+	dir := t.TempDir()
+	executable := writeTestExecutable(t, dir, "julia", "echo 'julia version 1.8.0'\n")
+
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: julia
+	`)).(*JuliaModule)
+
+	context := newTestContext("jwalton")
+	addTestExecutable(context, executable)
+	context.Directory = fileutils.NewDirectoryTestFS(dir, fstest.MapFS{
+		"Project.toml": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, "ஃ 1.8.0", result.DefaultText)
+	assert.Equal(t, juliaModuleResult{Version: "1.8.0"}, result.Data)
+}
+
+func TestJuliaNotAJuliaProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: julia
+	`)).(*JuliaModule)
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, fstest.MapFS{
+		"pyproject.toml": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, juliaModuleResult{}, result.Data)
+}
@@ -0,0 +1,110 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas KeymapModule
+
+// KeymapModule shows which vi mode the shell is in, for shells with vi key
+// bindings turned on.  Nothing is shown if vi mode is not in use.
+//
+type KeymapModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=keymap"`
+	// Insert is the text to show in insert mode.  Defaults to "".
+	Insert string `yaml:"insert"`
+	// InsertStyle is the style to use in insert mode.
+	InsertStyle string `yaml:"insertStyle"`
+	// Normal is the text to show in normal (command) mode.  Defaults to "[N]".
+	Normal string `yaml:"normal"`
+	// NormalStyle is the style to use in normal mode.
+	NormalStyle string `yaml:"normalStyle"`
+	// Visual is the text to show in visual mode.  Defaults to "[V]".
+	Visual string `yaml:"visual"`
+	// VisualStyle is the style to use in visual mode.
+	VisualStyle string `yaml:"visualStyle"`
+	// Replace is the text to show in replace mode.  Defaults to "[R]".
+	Replace string `yaml:"replace"`
+	// ReplaceStyle is the style to use in replace mode.
+	ReplaceStyle string `yaml:"replaceStyle"`
+}
+
+type keymapModuleData struct {
+	// Mode is one of "insert", "normal", "visual", or "replace", or "" if
+	// vi mode is not in use.
+	Mode string
+	// Keymap is the name of the keymap, as reported by the shell.
+	Keymap string
+}
+
+// viMode converts the name of a keymap from zsh, fish, or clink into a vi mode
+// (one of "insert", "normal", "visual", or "replace"), or "" if the shell
+// isn't in vi mode.
+func viMode(keymap string) string {
+	switch keymap {
+	case "viins", "main", "insert":
+		return "insert"
+	case "vicmd", "viopp", "default":
+		// fish calls normal mode "default".
+		return "normal"
+	case "visual", "vivis":
+		return "visual"
+	case "replace", "replace_one":
+		return "replace"
+	default:
+		return ""
+	}
+}
+
+// Execute the module.
+func (mod KeymapModule) Execute(context *Context) ModuleResult {
+	mode := viMode(context.Globals.Keymap)
+
+	var text string
+	var style string
+
+	switch mode {
+	case "insert":
+		text = mod.Insert
+		style = mod.InsertStyle
+	case "normal":
+		text = mod.Normal
+		style = mod.NormalStyle
+	case "visual":
+		text = mod.Visual
+		style = mod.VisualStyle
+	case "replace":
+		text = mod.Replace
+		style = mod.ReplaceStyle
+	}
+
+	return ModuleResult{
+		DefaultText:   text,
+		StyleOverride: style,
+		Data: keymapModuleData{
+			Mode:   mode,
+			Keymap: context.Globals.Keymap,
+		},
+	}
+}
+
+func init() {
+	registerModule(
+		"keymap",
+		registeredModule{
+			jsonSchema: schemas.KeymapModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := KeymapModule{
+					Type:    "keymap",
+					Normal:  "[N]",
+					Visual:  "[V]",
+					Replace: "[R]",
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,42 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeymap(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: keymap
+		insert: "[I]"
+		normalStyle: red
+	`))
+
+	context := newTestContext("jwalton")
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.Text)
+	assert.Equal(t, keymapModuleData{Mode: "", Keymap: ""}, result.Data)
+
+	context.Globals.Keymap = "main"
+	result = mod.Execute(context)
+	assert.Equal(t, "[I]", result.Text)
+
+	context.Globals.Keymap = "vicmd"
+	result = mod.Execute(context)
+	assert.Equal(t, "[N]", result.Text)
+	assert.Equal(t, "red", result.StartStyle.FG)
+	assert.Equal(t, keymapModuleData{Mode: "normal", Keymap: "vicmd"}, result.Data)
+
+	// fish's visual mode.
+	context.Globals.Keymap = "visual"
+	result = mod.Execute(context)
+	assert.Equal(t, "[V]", result.Text)
+}
+
+func TestViMode(t *testing.T) {
+	assert.Equal(t, "normal", viMode("default"))
+	assert.Equal(t, "replace", viMode("replace_one"))
+	assert.Equal(t, "", viMode("emacs"))
+}
@@ -0,0 +1,123 @@
+package modules
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"gopkg.in/yaml.v3"
+)
+
+// ModuleCache configures caching of a module's output, so a slow module only
+// needs to run once every few seconds instead of on every prompt.
+type ModuleCache struct {
+	// TTL is how long to reuse the module's output for, in milliseconds.  If
+	// this is 0, the module's output is not cached.
+	TTL int64 `yaml:"ttl"`
+	// Key is a template used to generate the cache key.  Cached output is only
+	// used if the key is the same as when the output was cached.  The template
+	// is passed `{ Globals }`.  Defaults to the current working directory.
+	Key string `yaml:"key"`
+}
+
+// cachedModuleResult is the result of a module, as stored in the value cache.
+type cachedModuleResult struct {
+	// Time is when the result was cached, in nanoseconds since the epoch.
+	Time       int64                   `json:"time"`
+	Text       string                  `json:"text"`
+	Data       json.RawMessage         `json:"data,omitempty"`
+	StartStyle styling.CharacterColors `json:"startStyle"`
+	EndStyle   styling.CharacterColors `json:"endStyle"`
+}
+
+// moduleCacheKeyTemplateName is the name given to a module's cache key template.
+const moduleCacheKeyTemplateName = "cache-key"
+
+// cacheKey returns the key to use to cache this module's output.  The key is
+// made up of a hash of the module's configuration, so two different modules
+// never share a cache entry, and the result of the `cache.key` template.
+// Returns false if the module's output should not be cached.
+func (wrapper ModuleWrapper) cacheKey(context *Context) (string, bool) {
+	if wrapper.config.Cache == nil || wrapper.config.Cache.TTL <= 0 ||
+		wrapper.YamlNode == nil || context.ValueCache == nil {
+		return "", false
+	}
+
+	config, err := yaml.Marshal(wrapper.YamlNode)
+	if err != nil {
+		return "", false
+	}
+	hash := sha1.Sum(config)
+
+	key := context.Globals.CWD
+	if wrapper.config.Cache.Key != "" {
+		tmpl, err := context.compileTemplate(moduleCacheKeyTemplateName, wrapper.config.Cache.Key)
+		if err != nil {
+			return "", false
+		}
+		key, err = modtemplate.TemplateToString(tmpl, TemplateData{Globals: &context.Globals})
+		if err != nil {
+			return "", false
+		}
+	}
+
+	return "module-output:" + hex.EncodeToString(hash[:]) + ":" + key, true
+}
+
+// getCachedResult returns the cached output for this module, if there is
+// cached output which is still fresh.
+func (wrapper ModuleWrapper) getCachedResult(context *Context, key string) (ModuleWrapperResult, bool) {
+	value := context.ValueCache.Get(key)
+	if value == nil {
+		return ModuleWrapperResult{}, false
+	}
+
+	var cached cachedModuleResult
+	if err := json.Unmarshal(value, &cached); err != nil {
+		return ModuleWrapperResult{}, false
+	}
+
+	age := context.Now().Sub(time.Unix(0, cached.Time))
+	if age < 0 || age >= time.Duration(wrapper.config.Cache.TTL)*time.Millisecond {
+		return ModuleWrapperResult{}, false
+	}
+
+	result := ModuleWrapperResult{
+		Text:       cached.Text,
+		StartStyle: cached.StartStyle,
+		EndStyle:   cached.EndStyle,
+	}
+	if len(cached.Data) != 0 {
+		var data interface{}
+		if err := json.Unmarshal(cached.Data, &data); err == nil {
+			result.Data = data
+		}
+	}
+	return result, true
+}
+
+// setCachedResult stores the output of this module in the value cache.
+func (wrapper ModuleWrapper) setCachedResult(context *Context, key string, result ModuleWrapperResult) {
+	cached := cachedModuleResult{
+		Time:       context.Now().UnixNano(),
+		Text:       result.Text,
+		StartStyle: result.StartStyle,
+		EndStyle:   result.EndStyle,
+	}
+	if result.Data != nil {
+		data, err := json.Marshal(result.Data)
+		if err != nil {
+			return
+		}
+		cached.Data = data
+	}
+
+	value, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	context.ValueCache.Set(key, value)
+}
@@ -0,0 +1,77 @@
+package modules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModuleCache(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	context := newTestContext("jwalton")
+	context.Clock = func() time.Time { return now }
+
+	wrapper := moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: first
+		cache:
+		  ttl: 5000
+	`))
+
+	result := wrapper.Execute(context)
+	assert.Equal(t, "first", result.Text)
+
+	// Swap out the module, so we can tell if the module was run again.
+	wrapper.Module = &TextModule{Type: "text", Text: "second"}
+
+	now = now.Add(4 * time.Second)
+	result = wrapper.Execute(context)
+	assert.Equal(t, "first", result.Text)
+	assert.Equal(t, map[string]interface{}{"Text": "first"}, result.Data)
+
+	// Output should not be shared between directories.
+	context.Globals.CWD = "/tmp"
+	result = wrapper.Execute(context)
+	assert.Equal(t, "second", result.Text)
+
+	// Output should expire after the TTL.
+	context.Globals.CWD = "/Users/jwalton"
+	now = now.Add(2 * time.Second)
+	result = wrapper.Execute(context)
+	assert.Equal(t, "second", result.Text)
+}
+
+func TestModuleCacheKeyTemplate(t *testing.T) {
+	context := newTestContext("jwalton")
+
+	wrapper := moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: first
+		cache:
+		  ttl: 60000
+		  key: "{{ .Globals.ShortUsername }}"
+	`))
+
+	result := wrapper.Execute(context)
+	assert.Equal(t, "first", result.Text)
+
+	wrapper.Module = &TextModule{Type: "text", Text: "second"}
+
+	// The key doesn't include the directory, so the output should be shared.
+	context.Globals.CWD = "/tmp"
+	result = wrapper.Execute(context)
+	assert.Equal(t, "first", result.Text)
+
+	// Modules with different configuration never share output.
+	other := moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: other
+		cache:
+		  ttl: 60000
+		  key: "{{ .Globals.ShortUsername }}"
+	`))
+	result = other.Execute(context)
+	assert.Equal(t, "other", result.Text)
+}
@@ -1,7 +1,9 @@
 package modules
 
 import (
+	"encoding/json"
 	"fmt"
+	"runtime/debug"
 	"text/template"
 	"time"
 
@@ -50,6 +52,9 @@ type ModuleWrapperResult struct {
 	Duration time.Duration
 	// Performance is an array of execution times for children of this module.
 	Performance *perf.Performance
+	// Warnings is a list of warnings generated by this module.  This does not
+	// include warnings from children of this module.
+	Warnings []string
 }
 
 // UnmarshalYAML converts a YAML node into a ModuleWrapper.
@@ -86,6 +91,15 @@ func (wrapper *ModuleWrapper) UnmarshalYAML(node *yaml.Node) error {
 	return nil
 }
 
+// MarshalYAML converts a ModuleWrapper back into YAML.  This returns the node
+// the module was read from, so the result will match the original configuration.
+func (wrapper ModuleWrapper) MarshalYAML() (interface{}, error) {
+	if wrapper.YamlNode == nil {
+		return nil, fmt.Errorf("cannot marshal module %s that was not loaded from YAML", wrapper.String())
+	}
+	return wrapper.YamlNode, nil
+}
+
 func (wrapper ModuleWrapper) String() string {
 	name := wrapper.config.Type
 	if wrapper.config.ID != "" {
@@ -102,15 +116,58 @@ func (wrapper ModuleWrapper) String() string {
 // Execute executes this module.  This will run the underlying Module, and then
 // apply styling and the template from the CommonConfig.
 func (wrapper ModuleWrapper) Execute(context *Context) ModuleWrapperResult {
-	if !wrapper.config.Conditions.IsEmpty() && !wrapper.config.Conditions.Matches(context.Directory) {
+	if wrapper.config.ID != "" && wrapper.YamlNode != nil {
+		// If this module is used elsewhere in the prompt, make sure it only
+		// runs once.
+		if shared := context.sharedModules[wrapper.config.ID]; shared != nil &&
+			shared.wrapper != nil &&
+			shared.wrapper.YamlNode == wrapper.YamlNode {
+			return shared.execute(context, wrapper)
+		}
+	}
+
+	return wrapper.execute(context)
+}
+
+func (wrapper ModuleWrapper) execute(context *Context) ModuleWrapperResult {
+	if !wrapper.config.Conditions.IsEmpty() && !wrapper.config.Conditions.Matches(context.Directory, context) {
 		// If the item has conditions, and they don't match, return an empty result.
+		context.Explainer.addResult(wrapper, ModuleWrapperResult{}, true, false)
 		return ModuleWrapperResult{}
 	}
 
-	// If the module has no timeout, use the default timeout.
+	if context.isModuleDisabled(wrapper.config.Type, wrapper.config.ID) {
+		// The module was turned off in the configuration.
+		context.Explainer.addResult(wrapper, ModuleWrapperResult{}, true, false)
+		return ModuleWrapperResult{}
+	}
+
+	cacheKey, useCache := wrapper.cacheKey(context)
+	if useCache {
+		if result, ok := wrapper.getCachedResult(context, cacheKey); ok {
+			context.Explainer.addResult(wrapper, result, false, false)
+			return result
+		}
+	}
+
+	// If the module has no timeout, use the default timeout.  Modules that
+	// only render other modules are left alone, as their children will time
+	// out on their own.
 	timeout := time.Duration(wrapper.config.Timeout) * time.Millisecond
-	if timeout == 0 && wrapper.config.Type != "block" {
+	if timeout == 0 && !rendersOtherModules(wrapper.config.Type) {
 		timeout = context.DefaultTimeout
+		if slow, ok := wrapper.Module.(slowModule); ok {
+			if minimum := slow.minimumTimeout(context); minimum > timeout {
+				timeout = minimum
+			}
+		}
+	}
+
+	if !rendersOtherModules(wrapper.config.Type) {
+		// The worker is given back as soon as we stop waiting for the module,
+		// so a module that times out doesn't hold up the rest of the prompt.
+		release := context.acquireWorker()
+		defer release()
 	}
 
 	start := time.Now()
@@ -118,11 +175,22 @@ func (wrapper ModuleWrapper) Execute(context *Context) ModuleWrapperResult {
 	// Run the module in a goroutine, so we can time it out.
 	ch := make(chan ModuleWrapperResult, 1)
 	go func() {
+		// A bug in one module shouldn't take down the whole prompt.
+		defer func() {
+			if r := recover(); r != nil {
+				log.Debug("Module ", wrapper.String(), " panicked: ", r, "\n", string(debug.Stack()))
+				ch <- ModuleWrapperResult{
+					Warnings: []string{fmt.Sprint("Module ", wrapper.String(), " panicked: ", r)},
+				}
+			}
+		}()
+
 		moduleResult := wrapper.Module.Execute(context)
 		ch <- processModuleResult(context, wrapper, moduleResult)
 	}()
 
 	var result ModuleWrapperResult
+	timedOut := false
 	if timeout <= 0 {
 		result = <-ch
 	} else {
@@ -131,18 +199,95 @@ func (wrapper ModuleWrapper) Execute(context *Context) ModuleWrapperResult {
 		case result = <-ch:
 		case <-time.After(timeout):
 			// Module timed out!
-			// TODO: Record a list of which modules timed out in the context,
-			// so we can display a list of them in a warning.
-			log.Warn("Module ", wrapper.String(), " timed out after ", timeout)
-			result = ModuleWrapperResult{}
+			result = ModuleWrapperResult{
+				Warnings: []string{fmt.Sprint("Module ", wrapper.String(), " timed out after ", timeout)},
+			}
+			timedOut = true
 		}
 	}
 
 	result.Duration = time.Since(start)
+	if useCache && !timedOut && len(result.Warnings) == 0 {
+		// Don't cache failures, so the module gets another chance next time.
+		wrapper.setCachedResult(context, cacheKey, result)
+	}
+	context.addWarnings(wrapper, result.Warnings)
+	context.Explainer.addResult(wrapper, result, false, timedOut)
 
 	return result
 }
 
+// addWarnings records warnings generated by the given module.
+func (context *Context) addWarnings(wrapper ModuleWrapper, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	context.warningsMutex.Lock()
+	context.warnings = append(context.warnings, warnings...)
+	context.warningsMutex.Unlock()
+
+	for _, warning := range warnings {
+		context.Explainer.addWarning(wrapper, warning)
+	}
+}
+
+// rendersOtherModules returns true if modules of the given type only render
+// other modules.
+func rendersOtherModules(moduleType string) bool {
+	switch moduleType {
+	case "block", "vcs", "use", "switch":
+		return true
+	}
+	return false
+}
+
+// slowModule is implemented by modules which, depending on their
+// configuration, may need longer than the default timeout.
+type slowModule interface {
+	// minimumTimeout returns the shortest timeout this module should be given
+	// when the configuration doesn't set one, or 0 to use the default.
+	minimumTimeout(context *Context) time.Duration
+}
+
+// parentModule is implemented by modules that render other modules.
+type parentModule interface {
+	childModules() []*ModuleWrapper
+}
+
+// FindModule searches `root` and all of its descendants for a module with the
+// given ID.  If there is no such module, this returns the first module with
+// the given type.
+func FindModule(root ModuleWrapper, name string) (ModuleWrapper, bool) {
+	var byType *ModuleWrapper
+
+	var search func(wrapper *ModuleWrapper) *ModuleWrapper
+	search = func(wrapper *ModuleWrapper) *ModuleWrapper {
+		if wrapper.config.ID == name {
+			return wrapper
+		}
+		if byType == nil && wrapper.config.Type == name {
+			byType = wrapper
+		}
+		if parent, ok := wrapper.Module.(parentModule); ok {
+			for _, child := range parent.childModules() {
+				if found := search(child); found != nil {
+					return found
+				}
+			}
+		}
+		return nil
+	}
+
+	if found := search(&root); found != nil {
+		return *found, true
+	}
+	if byType != nil {
+		return *byType, true
+	}
+	return ModuleWrapper{}, false
+}
+
 // TemplateData is the common data structure passed to a template when it is executed.
 type TemplateData struct {
 	// Text is the default text produced by this module
@@ -151,10 +296,24 @@ type TemplateData struct {
 	Data interface{}
 	// Global is the global data.
 	Globals *Globals
+	// Modules is a map of results from child modules, indexed by module ID,
+	// for modules that render other modules (e.g. the block module).  This is
+	// nil for other modules.
+	Modules map[string]ModuleWrapperResult `json:",omitempty"`
+}
+
+// childResultsData is implemented by the Data of modules that render other
+// modules, to make the results of their children available to templates as
+// `.Modules`.
+type childResultsData interface {
+	childResults() map[string]ModuleWrapperResult
 }
 
+// moduleTemplateName is the name given to a module's template.
+const moduleTemplateName = "module-template"
+
 func compileModuleTemplate(context *Context, tmpl string) (*template.Template, error) {
-	return modtemplate.CompileTemplate(context.Styles, context.Environment, "module-template", tmpl)
+	return context.compileTemplate(moduleTemplateName, tmpl)
 }
 
 // executeModule is called to execute a module.  This handles "common" stuff that
@@ -173,26 +332,34 @@ func processModuleResult(
 	text := moduleResult.DefaultText
 	startStyle := moduleResult.StartStyle
 	endStyle := moduleResult.EndStyle
+	warnings := moduleResult.Warnings
 
 	if moduleWrapper.config.Template != "" {
 		tmpl, err := compileModuleTemplate(context, moduleWrapper.config.Template)
 		if err != nil {
-			log.Warn(fmt.Sprintf("Error compiling template in %s: %v", moduleWrapper.String(), err))
+			warnings = append(warnings, fmt.Sprintf("Error compiling template in %s: %v", moduleWrapper.String(), err))
 		} else {
 			templateData := TemplateData{
 				Data:    moduleResult.Data,
 				Globals: &context.Globals,
 				Text:    moduleResult.DefaultText,
 			}
+			if data, ok := moduleResult.Data.(childResultsData); ok {
+				templateData.Modules = data.childResults()
+			}
 
 			text, err = modtemplate.TemplateToString(tmpl, templateData)
 			if err != nil {
-				log.Warn(fmt.Sprintf(
+				message := fmt.Sprintf(
 					"Error executing template in %s:\n%s\n%v",
 					moduleWrapper.String(),
 					moduleWrapper.config.Template,
 					err,
-				))
+				)
+				if context.DebugTemplates {
+					message += "\nTemplate data:\n" + dumpTemplateData(templateData)
+				}
+				warnings = append(warnings, message)
 				text = moduleResult.DefaultText
 			}
 		}
@@ -202,17 +369,64 @@ func processModuleResult(
 		text, startStyle, endStyle = style.ApplyGetColors(text)
 	}
 
+	if text != "" {
+		text, startStyle, endStyle = addPrefixAndSuffix(context, moduleWrapper.config, styleStr, text, startStyle, endStyle)
+	}
+
 	return ModuleWrapperResult{
 		Text:        text,
 		Data:        moduleResult.Data,
 		StartStyle:  startStyle,
 		EndStyle:    endStyle,
 		Performance: moduleResult.Performance,
+		Warnings:    warnings,
+	}
+}
+
+// addPrefixAndSuffix adds the module's prefix and suffix to the module's
+// styled output, and updates the start and end styles to match.  `styleStr`
+// is the style applied to the module, which is used for the prefix and suffix
+// if they have no style of their own.
+func addPrefixAndSuffix(
+	context *Context,
+	config CommonConfig,
+	styleStr string,
+	text string,
+	startStyle styling.CharacterColors,
+	endStyle styling.CharacterColors,
+) (string, styling.CharacterColors, styling.CharacterColors) {
+	if config.Prefix != "" {
+		style := context.GetStyle(defaultString(config.PrefixStyle, styleStr))
+		var prefix string
+		prefix, startStyle, _ = style.ApplyGetColors(config.Prefix)
+		text = prefix + text
+	}
+
+	if config.Suffix != "" {
+		style := context.GetStyle(defaultString(config.SuffixStyle, styleStr))
+		var suffix string
+		suffix, _, endStyle = style.ApplyGetColors(config.Suffix)
+		text = text + suffix
+	}
+
+	return text, startStyle, endStyle
+}
+
+// dumpTemplateData returns a pretty-printed copy of the data passed to a
+// template.  This is JSON, since the field names in JSON match the names used
+// in the template.
+func dumpTemplateData(data TemplateData) string {
+	result, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%+v", data)
 	}
+	return string(result)
 }
 
 // RenderPrompt renders the top-level module in a prompt.
 func RenderPrompt(context *Context, root ModuleWrapper) (ModuleWrapperResult, string) {
+	context.ShareModules(root)
+	context.PrecompileTemplates(root)
 	result := root.Execute(context)
 	return result, processFlexibleSpaces(context.Globals.TerminalWidth, result.Text, context.FlexibleSpaceReplacement)
 }
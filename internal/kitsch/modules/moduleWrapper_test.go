@@ -7,8 +7,11 @@ import (
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
 	"github.com/jwalton/kitsch/internal/kitsch/styling"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestModuleWrapperExecute(t *testing.T) {
@@ -53,6 +56,50 @@ func TestExecuteModuleWrapperWithTemplate(t *testing.T) {
 	)
 }
 
+func TestExecuteModuleWrapperWithPrefixAndSuffix(t *testing.T) {
+	module := moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: "main"
+		style: blue
+		prefix: "["
+		prefixStyle: red bg:white
+		suffix: "]"
+	`))
+
+	result := module.Execute(newTestContext("jwalton"))
+
+	assert.Equal(t, "[main]", result.Text)
+	assert.Equal(t, styling.CharacterColors{FG: "red", BG: "bg:white"}, result.StartStyle)
+	assert.Equal(t, styling.CharacterColors{FG: "blue"}, result.EndStyle)
+
+	// The prefix and suffix should not be shown if the module has no output.
+	module = moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: ""
+		prefix: "["
+		suffix: "]"
+	`))
+
+	result = module.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.Text)
+}
+
+func TestExecuteModuleWrapperWithPartial(t *testing.T) {
+	module := moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: "Text"
+		template: '{{ template "wrap" .Text }}'
+	`))
+
+	context := newTestContext("jwalton")
+	partials, err := modtemplate.CompilePartials(map[string]string{"wrap": "[{{ . }}]"})
+	require.NoError(t, err)
+	context.Partials = partials
+
+	result := module.Execute(context)
+	assert.Equal(t, "[Text]", result.Text)
+}
+
 func TestExecuteModuleWithConditions(t *testing.T) {
 	mod := moduleWrapperFromYAML(heredoc.Doc(`
 		type: text
@@ -114,3 +161,122 @@ func TestExecuteModuleWithTimeout(t *testing.T) {
 	// Should have no output, because it should have timed out.
 	assert.Equal(t, "", result.Text)
 }
+
+type countingModule struct {
+	count *int
+}
+
+// Execute the module.
+func (mod countingModule) Execute(context *Context) ModuleResult {
+	*mod.count++
+	return ModuleResult{DefaultText: "counted"}
+}
+
+func TestExecuteModuleSkipsExecuteWhenConditionsFail(t *testing.T) {
+	count := 0
+	mod := ModuleWrapper{
+		config: CommonConfig{
+			Type: "counting",
+			Conditions: &condition.Conditions{
+				IfFiles: []string{"k8s/", "Chart.yaml"},
+			},
+		},
+		Module: countingModule{count: &count},
+	}
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS("/foo/bar", fstest.MapFS{
+		"k8s": &fstest.MapFile{Data: []byte("not a directory")},
+	})
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.Text)
+	assert.Equal(t, 0, count)
+
+	context.Directory = fileutils.NewDirectoryTestFS("/foo/bar", fstest.MapFS{
+		"k8s/deployment.yaml": &fstest.MapFile{Data: []byte("kind: Deployment")},
+	})
+	result = mod.Execute(context)
+	assert.Equal(t, "counted", result.Text)
+	assert.Equal(t, 1, count)
+}
+
+func TestFindModule(t *testing.T) {
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		- type: text
+		  text: first
+		- type: vcs
+		  git:
+		    type: text
+		    id: gitText
+		    text: git
+	`))
+
+	found, ok := FindModule(root, "gitText")
+	require.True(t, ok)
+	assert.Equal(t, "git", found.Execute(newTestContext("jwalton")).Text)
+
+	found, ok = FindModule(root, "text")
+	require.True(t, ok)
+	assert.Equal(t, "first", found.Execute(newTestContext("jwalton")).Text)
+
+	_, ok = FindModule(root, "directory")
+	assert.False(t, ok)
+}
+
+func TestExecuteModuleDebugTemplates(t *testing.T) {
+	module := moduleWrapperFromYAML(heredoc.Doc(`
+		type: text
+		text: "hello"
+		template: "{{ .Data.Missing }}"
+	`))
+
+	context := newTestContext("jwalton")
+	context.Explainer = NewExplainer()
+	module.Execute(context)
+	warnings := context.Explainer.Explain(module).Warnings
+	require.Len(t, warnings, 1)
+	assert.NotContains(t, warnings[0], "Template data:")
+
+	context = newTestContext("jwalton")
+	context.Explainer = NewExplainer()
+	context.DebugTemplates = true
+	result := module.Execute(context)
+	assert.Equal(t, "hello", result.Text)
+
+	warnings = context.Explainer.Explain(module).Warnings
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "can't evaluate field Missing")
+	assert.Contains(t, warnings[0], "Template data:\n{\n  \"Text\": \"hello\",\n  \"Data\": {\n    \"Text\": \"hello\"\n  },")
+	assert.Contains(t, warnings[0], `"Hostname": "lucid"`)
+}
+
+func TestExecuteModuleCollectsWarnings(t *testing.T) {
+	module := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		join: "{{ .Missing.Field }}"
+		modules:
+		  - type: text
+		    text: "a"
+		    template: "{{ .Data.Missing }}"
+		  - type: text
+		    text: "b"
+	`))
+
+	context := newTestContext("jwalton")
+	result := module.Execute(context)
+
+	// Template errors fall back to the default text.
+	assert.Equal(t, "a b", result.Text)
+
+	// The block's own result only includes warnings from the block.
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "Error executing join template")
+
+	// The context has warnings from every module.
+	warnings := context.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "Error executing template in text(4:5)")
+	assert.Equal(t, result.Warnings[0], warnings[1])
+}
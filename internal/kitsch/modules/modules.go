@@ -18,6 +18,15 @@
 package modules
 
 import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
 	"github.com/jwalton/kitsch/internal/kitsch/styling"
 	"github.com/jwalton/kitsch/internal/perf"
 )
@@ -43,6 +52,10 @@ type ModuleResult struct {
 	// EndStyle is similar to StartStyle, but contains the colors of the last
 	// character in Text.
 	EndStyle styling.CharacterColors
+	// Warnings is a list of problems the module ran into.  Modules should
+	// return warnings here rather than logging them, so they can be reported
+	// after the prompt is rendered.
+	Warnings []string
 }
 
 // Module represents a module that generates some output to show in the prompt.
@@ -58,3 +71,47 @@ func defaultString(value string, def string) string {
 	}
 	return def
 }
+
+// parseKeyValues parses a file made up of `KEY="value"` lines, like the
+// "release" file in a JDK, or /run/.containerenv.  Values may be quoted or
+// unquoted.
+func parseKeyValues(contents []byte) map[string]string {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		index := strings.Index(line, "=")
+		if index <= 0 {
+			continue
+		}
+
+		key := line[:index]
+		value := line[index+1:]
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		values[key] = value
+	}
+
+	return values
+}
+
+// readAncestorFile reads the named file from the current folder, or from the
+// nearest ancestor folder that has it.  This returns the path to the file and
+// its contents, or "" and nil if the file can't be found.
+func readAncestorFile(directory fileutils.Directory, name string) (string, []byte) {
+	if contents, err := fs.ReadFile(directory.FileSystem(), name); err == nil {
+		return filepath.Join(directory.Path(), name), contents
+	}
+
+	path := directory.FindFileInAncestors(name)
+	if path == "" {
+		return "", nil
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil
+	}
+	return path, contents
+}
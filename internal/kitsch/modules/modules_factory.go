@@ -32,6 +32,17 @@ func registerModule(name string, mod registeredModule) {
 	registeredModules[name] = mod
 }
 
+// RegisteredModuleTypes returns a sorted list of the names of all registered
+// module types.
+func RegisteredModuleTypes() []string {
+	keys := make([]string, 0, len(registeredModules))
+	for name := range registeredModules {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // JSONSchemaForModule returns the JSON schema for a module.
 func JSONSchemaForModule(typeName string) string {
 	mod, ok := registeredModules[typeName]
@@ -47,27 +58,31 @@ func JSONSchemaDefinitions() string {
 	var moduleRefs []string
 
 	definitions = append(definitions, fmt.Sprintf("\"CommonConfig\": %s", schemas.CommonConfigJSONSchema))
+	definitions = append(definitions, fmt.Sprintf("\"ModuleCache\": %s", schemas.ModuleCacheJSONSchema))
 
-	keys := make([]string, 0, len(registeredModules))
-	for name := range registeredModules {
-		keys = append(keys, name)
-	}
-	sort.Strings(keys)
-
-	for _, name := range keys {
+	for _, name := range RegisteredModuleTypes() {
 		mod := registeredModules[name]
 		definitions = append(definitions, fmt.Sprintf("\"%s\": %s", name, mod.jsonSchema))
 		moduleRefs = append(moduleRefs, fmt.Sprintf("{ \"$ref\": \"#/definitions/%s\" }", name))
 	}
 
-	// Add a "module" definition, which can be any module.
+	// Add a "module" definition, which can be any module.  A module which
+	// extends a definition doesn't need a type, since it can get it from the
+	// definition, and neither does a module which uses another module.
 	moduleDefinition := `"module": {
     "type": "object",
-    "required": [ "type" ],
+    "properties": {
+      "extends": { "type": "string", "description": "The name of a definition from the definitions section to inherit from." }
+    },
     "allOf": [
-      { "$ref": "#/definitions/CommonConfig" },
-      { "oneOf": [` + strings.Join(moduleRefs, ", ") + `] }
-    ]
+      { "$ref": "#/definitions/CommonConfig" }
+    ],
+    "if": { "anyOf": [ { "required": [ "extends" ] }, { "required": [ "use" ] } ] },
+    "then": {},
+    "else": {
+      "required": [ "type" ],
+      "oneOf": [` + strings.Join(moduleRefs, ", ") + `]
+    }
 }`
 	definitions = append(definitions, moduleDefinition)
 
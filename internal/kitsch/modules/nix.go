@@ -0,0 +1,101 @@
+package modules
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas NixModule
+
+// NixModule shows when the shell is inside a `nix-shell` or `nix develop`
+// environment.  Outside of a nix shell, this shows just the symbol if the
+// current folder has a "flake.nix", "shell.nix", or "default.nix", to let you
+// know a nix environment is available.
+//
+type NixModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=nix"`
+	// Symbol is the symbol to show before the shell name.  Defaults to "❄ ".
+	Symbol string `yaml:"symbol"`
+	// Heuristic, if true, will also detect `nix shell`, which doesn't set any
+	// environment variables, by looking for "/nix/store" in the PATH.  This
+	// will give false positives on NixOS.
+	Heuristic bool `yaml:"heuristic"`
+}
+
+type nixModuleResult struct {
+	// InShell is true if the shell is inside a nix shell.
+	InShell bool
+	// Pure is true if the nix shell is a pure shell.
+	Pure bool
+	// Name is the name of the nix shell, from the `name` environment variable.
+	Name string
+	// Flake is the name of the folder containing the nearest "flake.nix", or
+	// "" if there is no flake.
+	Flake string
+	// HasNixFile is true if the current folder contains a "flake.nix",
+	// "shell.nix", or "default.nix".
+	HasNixFile bool
+}
+
+// Execute the module.
+func (mod NixModule) Execute(context *Context) ModuleResult {
+	data := nixModuleResult{}
+
+	switch context.Getenv("IN_NIX_SHELL") {
+	case "":
+		if mod.Heuristic && strings.Contains(context.Getenv("PATH"), "/nix/store/") {
+			data.InShell = true
+		}
+	case "pure":
+		data.InShell = true
+		data.Pure = true
+	default:
+		data.InShell = true
+	}
+
+	if data.InShell {
+		data.Name = context.Getenv("name")
+	}
+
+	directory := context.Directory
+	data.HasNixFile = directory.HasFile("flake.nix") || directory.HasFile("shell.nix") || directory.HasFile("default.nix")
+	if directory.HasFile("flake.nix") {
+		data.Flake = filepath.Base(directory.Path())
+	} else if flake := directory.FindFileInAncestors("flake.nix"); flake != "" {
+		data.Flake = filepath.Base(filepath.Dir(flake))
+	}
+
+	text := ""
+	if data.InShell {
+		name := data.Name
+		if name == "" || name == "nix-shell" {
+			name = defaultString(data.Flake, "nix-shell")
+		}
+		text = mod.Symbol + name
+		if data.Pure {
+			text += " (pure)"
+		}
+	} else if data.HasNixFile {
+		text = strings.TrimSpace(mod.Symbol)
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+func init() {
+	registerModule(
+		"nix",
+		registeredModule{
+			jsonSchema: schemas.NixModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := NixModule{Type: "nix", Symbol: "❄ "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,69 @@
+package modules
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNixShell(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: nix
+	`)).(*NixModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{
+		"IN_NIX_SHELL": "impure",
+		"name":         "devshell",
+	}, nil))
+	assert.Equal(t, "❄ devshell", result.DefaultText)
+	assert.Equal(t, nixModuleResult{InShell: true, Name: "devshell"}, result.Data)
+
+	result = mod.Execute(newTestContextWith(map[string]string{
+		"IN_NIX_SHELL": "pure",
+		"name":         "nix-shell",
+	}, nil))
+	assert.Equal(t, "❄ nix-shell (pure)", result.DefaultText)
+}
+
+func TestNixFlake(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: nix
+	`)).(*NixModule)
+
+	files := fstest.MapFS{"flake.nix": &fstest.MapFile{}}
+
+	// In a `nix develop` shell, use the name of the flake.
+	result := mod.Execute(newTestContextWith(map[string]string{
+		"IN_NIX_SHELL": "impure",
+		"name":         "nix-shell",
+	}, files))
+	assert.Equal(t, "❄ jwalton", result.DefaultText)
+	assert.Equal(t, nixModuleResult{
+		InShell:    true,
+		Name:       "nix-shell",
+		Flake:      "jwalton",
+		HasNixFile: true,
+	}, result.Data)
+
+	// Outside of a shell, just show the symbol.
+	result = mod.Execute(newTestContextWith(map[string]string{}, files))
+	assert.Equal(t, "❄", result.DefaultText)
+}
+
+func TestNixNotInShell(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: nix
+	`)).(*NixModule)
+
+	environment := map[string]string{"PATH": "/nix/store/abc-hello/bin:/usr/bin"}
+
+	result := mod.Execute(newTestContextWith(environment, nil))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, nixModuleResult{}, result.Data)
+
+	mod.Heuristic = true
+	result = mod.Execute(newTestContextWith(environment, nil))
+	assert.Equal(t, "❄ nix-shell", result.DefaultText)
+}
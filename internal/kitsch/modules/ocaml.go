@@ -0,0 +1,118 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas OcamlModule
+
+// OcamlModule shows the active opam switch and the version of the OCaml
+// compiler in OCaml projects.
+//
+type OcamlModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=ocaml"`
+	// Symbol is the symbol to show before the version.  Defaults to "🐫 ".
+	Symbol string `yaml:"symbol"`
+}
+
+type ocamlModuleResult struct {
+	// Version is the version of the OCaml compiler, or "" if it could not be
+	// found.
+	Version string
+	// Switch is the name of the active opam switch, or "" if there is no
+	// active switch.  For a local switch, this is the name of the folder
+	// containing the "_opam" folder.
+	Switch string
+	// SwitchPrefix is the folder the active opam switch is installed in.
+	SwitchPrefix string
+	// LocalSwitch is true if the active switch is a local switch.
+	LocalSwitch bool
+}
+
+// ocamlConditions are the conditions for the ocaml module to be shown.
+var ocamlConditions = condition.Conditions{
+	IfFiles:      []string{"dune-project"},
+	IfExtensions: []string{"opam"},
+}
+
+// opamCompilerRegex finds the compiler version in an opam "switch-state" file,
+// which will have a line like:
+//
+//     compiler: ["ocaml-base-compiler.4.14.0" "ocaml.4.14.0"]
+//
+var opamCompilerRegex = regexp.MustCompile(`(?m)^compiler:\s*\[[^\]]*"(?:ocaml-base-compiler|ocaml-variants|ocaml-system)\.([^"]+)"`)
+
+// ocamlVersionGetter is used to get the version of OCaml if there is no
+// active opam switch.
+var ocamlVersionGetter = getters.CustomGetter{
+	Type:  getters.TypeCustom,
+	From:  "ocaml -vnum",
+	Regex: `^(\S+)`,
+	Cache: getters.CacheSettings{Enabled: true},
+}
+
+// Execute the module.
+func (mod OcamlModule) Execute(context *Context) ModuleResult {
+	if !ocamlConditions.Matches(context.Directory, context) {
+		return ModuleResult{Data: ocamlModuleResult{}}
+	}
+
+	data := ocamlModuleResult{}
+	var switchState []byte
+
+	if prefix := context.Getenv("OPAM_SWITCH_PREFIX"); prefix != "" {
+		data.SwitchPrefix = prefix
+		data.Switch, data.LocalSwitch = opamSwitchName(prefix)
+		switchState, _ = os.ReadFile(filepath.Join(prefix, ".opam-switch", "switch-state"))
+	} else if path, contents := readAncestorFile(context.Directory, "_opam/.opam-switch/switch-state"); path != "" {
+		// A local switch which hasn't been activated with `eval $(opam env)`.
+		data.SwitchPrefix = filepath.Dir(filepath.Dir(path))
+		data.Switch, data.LocalSwitch = opamSwitchName(data.SwitchPrefix)
+		switchState = contents
+	}
+
+	if match := opamCompilerRegex.FindSubmatch(switchState); match != nil {
+		data.Version = string(match[1])
+	} else if version, err := ocamlVersionGetter.GetValue(context); err == nil {
+		data.Version, _ = version.(string)
+	}
+
+	text := mod.Symbol + data.Version
+	if data.Switch != "" {
+		text += " (" + data.Switch + ")"
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// opamSwitchName returns the name of the opam switch installed in the given
+// prefix, and true if it is a local switch.  Global switches are installed in
+// "~/.opam/<name>", and local switches in "<project>/_opam".
+func opamSwitchName(prefix string) (string, bool) {
+	if filepath.Base(prefix) == "_opam" {
+		return filepath.Base(filepath.Dir(prefix)), true
+	}
+	return filepath.Base(prefix), false
+}
+
+func init() {
+	registerModule(
+		"ocaml",
+		registeredModule{
+			jsonSchema: schemas.OcamlModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := OcamlModule{Type: "ocaml", Symbol: "🐫 "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/stretchr/testify/assert"
+)
+
+const testOpamSwitchState = `opam-version: "2.0"
+compiler: ["ocaml-base-compiler.4.14.0" "ocaml.4.14.0"]
+roots: ["dune.3.4.1" "ocaml-base-compiler.4.14.0"]
+`
+
+func TestOcamlGlobalSwitch(t *testing.T) {
+	prefix := filepath.Join(t.TempDir(), ".opam", "default")
+	writeTestFile(t, filepath.Join(prefix, ".opam-switch", "switch-state"), testOpamSwitchState)
+
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ocaml
+	`)).(*OcamlModule)
+
+	context := newTestContext("jwalton")
+	context.Environment = &env.DummyEnv{Env: map[string]string{"OPAM_SWITCH_PREFIX": prefix}}
+	context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, fstest.MapFS{
+		"dune-project": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, "🐫 4.14.0 (default)", result.DefaultText)
+	assert.Equal(t, ocamlModuleResult{
+		Version:      "4.14.0",
+		Switch:       "default",
+		SwitchPrefix: prefix,
+	}, result.Data)
+}
+
+func TestOcamlLocalSwitch(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ocaml
+	`)).(*OcamlModule)
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, fstest.MapFS{
+		"myapp.opam":                      &fstest.MapFile{},
+		"_opam/.opam-switch/switch-state": &fstest.MapFile{Data: []byte(testOpamSwitchState)},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, "🐫 4.14.0 (jwalton)", result.DefaultText)
+	assert.Equal(t, ocamlModuleResult{
+		Version:      "4.14.0",
+		Switch:       "jwalton",
+		SwitchPrefix: "/Users/jwalton/_opam",
+		LocalSwitch:  true,
+	}, result.Data)
+}
+
+func TestOcamlNotAnOcamlProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ocaml
+	`)).(*OcamlModule)
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, fstest.MapFS{
+		"package.json": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, ocamlModuleResult{}, result.Data)
+}
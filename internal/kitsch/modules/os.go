@@ -0,0 +1,113 @@
+package modules
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas OSModule
+
+// OSModule shows the current operating system.  On Linux running under the
+// Windows Subsystem for Linux (WSL), this shows "WSL" instead of "Linux".
+//
+type OSModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=os"`
+	// Symbols is a map of operating systems to the text to show for each.  Keys
+	// are Go operating system names (e.g. "linux", "darwin", "windows"), or
+	// "wsl" for the Windows Subsystem for Linux.
+	Symbols map[string]string `yaml:"symbols"`
+	// ShowDistro, if true, will show the name of the WSL distribution after
+	// the symbol when running in WSL.
+	ShowDistro bool `yaml:"showDistro"`
+}
+
+type osModuleResult struct {
+	// OS is the Go name of the current operating system (e.g. "linux").
+	OS string
+	// Arch is the Go name of the current architecture (e.g. "amd64").
+	Arch string
+	// IsWSL is true if we're running in the Windows Subsystem for Linux.
+	IsWSL bool
+	// Distro is the name of the WSL distribution, or "" if not running in WSL.
+	Distro string
+	// OnWindowsDrive is true if we're running in WSL, and the current directory
+	// is on a Windows drive (e.g. "/mnt/c").  Git is much slower on these
+	// drives.
+	OnWindowsDrive bool
+}
+
+// defaultOSSymbols is the text to show for each operating system, if not
+// specified in Symbols.
+var defaultOSSymbols = map[string]string{
+	"darwin":  "macOS",
+	"freebsd": "FreeBSD",
+	"linux":   "Linux",
+	"windows": "Windows",
+	"wsl":     "WSL",
+}
+
+// Execute the module.
+func (mod OSModule) Execute(context *Context) ModuleResult {
+	data := osModuleResult{
+		OS:    context.Environment.OS(),
+		Arch:  context.Environment.Arch(),
+		IsWSL: context.Globals.IsWSL,
+	}
+
+	key := data.OS
+	if data.IsWSL {
+		key = "wsl"
+		data.Distro = context.Getenv("WSL_DISTRO_NAME")
+		data.OnWindowsDrive = isWindowsDrivePath(context.Globals.CWD)
+	}
+
+	text, ok := mod.Symbols[key]
+	if !ok {
+		text = defaultString(defaultOSSymbols[key], key)
+	}
+	if mod.ShowDistro && data.Distro != "" {
+		text += " " + data.Distro
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// detectWSL returns true if we are running in the Windows Subsystem for Linux.
+// `procVersion` should be the contents of /proc/version.
+func detectWSL(getenv func(string) string, procVersion []byte) bool {
+	if getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	return bytes.Contains(bytes.ToLower(procVersion), []byte("microsoft"))
+}
+
+// isWindowsDrivePath returns true if the given path is on a Windows drive
+// mounted in WSL, like "/mnt/c/Users".
+func isWindowsDrivePath(path string) bool {
+	if !strings.HasPrefix(path, "/mnt/") {
+		return false
+	}
+	drive := strings.TrimPrefix(path, "/mnt/")
+	if len(drive) == 0 || !(drive[0] >= 'a' && drive[0] <= 'z') {
+		return false
+	}
+	return len(drive) == 1 || drive[1] == '/'
+}
+
+func init() {
+	registerModule(
+		"os",
+		registeredModule{
+			jsonSchema: schemas.OSModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := OSModule{Type: "os"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
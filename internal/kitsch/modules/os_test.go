@@ -0,0 +1,82 @@
+package modules
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOSModuleWSL(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: os
+		showDistro: true
+	`)).(*OSModule)
+
+	context := newTestContext("jwalton")
+	context.Globals.IsWSL = true
+	context.Globals.CWD = "/mnt/c/Users/jwalton"
+	context.Environment = &env.DummyEnv{Env: map[string]string{"WSL_DISTRO_NAME": "Ubuntu"}}
+
+	result := mod.Execute(context)
+	assert.Equal(t, "WSL Ubuntu", result.DefaultText)
+	assert.Equal(t, osModuleResult{
+		OS:             runtime.GOOS,
+		Arch:           runtime.GOARCH,
+		IsWSL:          true,
+		Distro:         "Ubuntu",
+		OnWindowsDrive: true,
+	}, result.Data)
+}
+
+func TestOSModuleSymbols(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: os
+		symbols:
+		  ` + runtime.GOOS + `: "os!"
+	`)).(*OSModule)
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "os!", result.DefaultText)
+	assert.Equal(t, osModuleResult{OS: runtime.GOOS, Arch: runtime.GOARCH}, result.Data)
+}
+
+func TestOSModuleFromEnvironment(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: os
+	`)).(*OSModule)
+
+	context := newTestContext("jwalton")
+	context.Environment = &env.DummyEnv{GOOS: "darwin", GOARCH: "arm64"}
+
+	result := mod.Execute(context)
+	assert.Equal(t, "macOS", result.DefaultText)
+	assert.Equal(t, osModuleResult{OS: "darwin", Arch: "arm64"}, result.Data)
+}
+
+func TestDetectWSL(t *testing.T) {
+	noEnv := func(string) string { return "" }
+
+	assert.True(t, detectWSL(noEnv, []byte("Linux version 5.10.102.1-microsoft-standard-WSL2 (gcc version 9.3.0)")))
+	assert.True(t, detectWSL(noEnv, []byte("Linux version 4.4.0-19041-Microsoft (Microsoft@Microsoft.com)")))
+	assert.False(t, detectWSL(noEnv, []byte("Linux version 5.15.0-48-generic (buildd@lcy02-amd64-080)")))
+	assert.False(t, detectWSL(noEnv, nil))
+
+	withDistro := func(key string) string {
+		if key == "WSL_DISTRO_NAME" {
+			return "Debian"
+		}
+		return ""
+	}
+	assert.True(t, detectWSL(withDistro, nil))
+}
+
+func TestIsWindowsDrivePath(t *testing.T) {
+	assert.True(t, isWindowsDrivePath("/mnt/c"))
+	assert.True(t, isWindowsDrivePath("/mnt/d/dev/kitsch"))
+	assert.False(t, isWindowsDrivePath("/mnt/wsl"))
+	assert.False(t, isWindowsDrivePath("/mnt/"))
+	assert.False(t, isWindowsDrivePath("/home/jwalton"))
+}
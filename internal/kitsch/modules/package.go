@@ -0,0 +1,125 @@
+package modules
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"github.com/jwalton/kitsch/internal/kitsch/projects"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas PackageModule
+
+// PackageModule shows the version of the package in the current folder.
+//
+// The project type of the current folder is worked out using the same project
+// types as the project module, and then the version is read from that project
+// type's manifest (e.g. package.json for node, or Cargo.toml for rust).  This
+// only ever reads files; it never runs an external command.
+//
+type PackageModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=package"`
+}
+
+type packageModuleData struct {
+	// Name is the name of the package, or "" if unavailable.
+	Name string
+	// Version is the version of the package.
+	Version string
+	// ProjectType is the name of the project type of the current folder, or ""
+	// if the project type is unknown.
+	ProjectType string
+	// File is the file the version was read from.
+	File string
+}
+
+// Execute the module.
+func (mod PackageModule) Execute(context *Context) ModuleResult {
+	projectTypeName := ""
+	projectType := projects.MatchProjectType(context.ProjectTypes, context.Directory, context)
+	if projectType != nil {
+		projectTypeName = projectType.Name
+	}
+
+	var manifest projects.PackageManifest
+	var err error
+	if projectTypeName == "go" {
+		manifest, err = mod.goManifest(context)
+	} else {
+		manifest, err = projects.ReadPackageManifest(projectTypeName, context.Directory.FileSystem())
+	}
+	if err != nil || manifest.Version == "" {
+		return ModuleResult{}
+	}
+
+	return ModuleResult{
+		DefaultText: manifest.Version,
+		Data: packageModuleData{
+			Name:        manifest.Name,
+			Version:     manifest.Version,
+			ProjectType: projectTypeName,
+			File:        manifest.File,
+		},
+	}
+}
+
+var goModuleRegex = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+// goManifest returns the version of a go module.  Go modules don't record their
+// own version, so if HEAD is tagged we use the tag, and otherwise we generate
+// a pseudo-version from the HEAD commit.
+func (mod PackageModule) goManifest(context *Context) (projects.PackageManifest, error) {
+	contents, err := fs.ReadFile(context.Directory.FileSystem(), "go.mod")
+	if err != nil {
+		return projects.PackageManifest{}, err
+	}
+
+	manifest := projects.PackageManifest{File: "go.mod"}
+	if matches := goModuleRegex.FindSubmatch(contents); matches != nil {
+		manifest.Name = string(matches[1])
+	}
+
+	git := context.Git()
+	if git == nil {
+		return manifest, projects.ErrNoManifest
+	}
+
+	head, err := git.Head(100)
+	if err != nil {
+		return manifest, err
+	}
+
+	if head.IsTag {
+		manifest.Version = strings.TrimSuffix(strings.TrimPrefix(head.Description, "("), ")")
+		return manifest, nil
+	}
+
+	if len(head.Hash) < 12 {
+		return manifest, projects.ErrNoManifest
+	}
+	commitTime, err := git.CommitTime(head.Hash)
+	if err != nil {
+		return manifest, err
+	}
+
+	manifest.Version = fmt.Sprintf("v0.0.0-%s-%s", commitTime.UTC().Format("20060102150405"), head.Hash[:12])
+	return manifest, nil
+}
+
+func init() {
+	registerModule(
+		"package",
+		registeredModule{
+			jsonSchema: schemas.PackageModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := PackageModule{Type: "package"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,102 @@
+package modules
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageNode(t *testing.T) {
+	context := newTestContextWith(nil, fstest.MapFS{
+		"package.json": &fstest.MapFile{Data: []byte(`{"name": "my-package", "version": "1.2.3"}`)},
+	})
+
+	result := moduleWrapperFromYAML("type: package").Execute(context)
+	assert.Equal(t, "1.2.3", result.Text)
+	assert.Equal(t, packageModuleData{
+		Name:        "my-package",
+		Version:     "1.2.3",
+		ProjectType: "node",
+		File:        "package.json",
+	}, result.Data)
+}
+
+func TestPackageCargoWorkspace(t *testing.T) {
+	context := newTestContextWith(nil, fstest.MapFS{
+		"Cargo.toml": &fstest.MapFile{Data: []byte(heredoc.Doc(`
+			[package]
+			name = "crate"
+			version.workspace = true
+
+			[workspace.package]
+			version = "0.4.0"
+		`))},
+	})
+
+	result := moduleWrapperFromYAML("type: package").Execute(context)
+	assert.Equal(t, "0.4.0", result.Text)
+}
+
+func TestPackagePoetry(t *testing.T) {
+	context := newTestContextWith(nil, fstest.MapFS{
+		"pyproject.toml": &fstest.MapFile{Data: []byte(heredoc.Doc(`
+			[tool.poetry]
+			name = "example"
+			version = "2.1.0"
+		`))},
+	})
+
+	result := moduleWrapperFromYAML("type: package").Execute(context)
+	assert.Equal(t, "2.1.0", result.Text)
+}
+
+func TestPackageVersionFile(t *testing.T) {
+	context := newTestContextWith(nil, fstest.MapFS{
+		"VERSION": &fstest.MapFile{Data: []byte("3.0.0-beta.1\n")},
+	})
+
+	result := moduleWrapperFromYAML("type: package").Execute(context)
+	assert.Equal(t, "3.0.0-beta.1", result.Text)
+	assert.Equal(t, "", result.Data.(packageModuleData).ProjectType)
+}
+
+func TestPackageNoManifest(t *testing.T) {
+	context := newTestContextWith(nil, fstest.MapFS{
+		"main.py": &fstest.MapFile{},
+	})
+
+	result := moduleWrapperFromYAML("type: package").Execute(context)
+	assert.Equal(t, "", result.Text)
+}
+
+func TestPackageGoPseudoVersion(t *testing.T) {
+	context := newTestContextWith(nil, fstest.MapFS{
+		"go.mod": &fstest.MapFile{Data: []byte("module github.com/jwalton/kitsch\n\ngo 1.16\n")},
+	})
+	context.git = gitutils.DemoGit{
+		HeadDescription: "7c088a39dcd2dcda89f4dee1fd3eb41c1d34ea2f",
+		HeadCommitTime:  time.Date(2022, 1, 20, 19, 3, 12, 0, time.UTC),
+	}
+
+	result := moduleWrapperFromYAML("type: package").Execute(context)
+	assert.Equal(t, "v0.0.0-20220120190312-7c088a39dcd2", result.Text)
+	assert.Equal(t, "github.com/jwalton/kitsch", result.Data.(packageModuleData).Name)
+}
+
+func TestPackageGoTag(t *testing.T) {
+	context := newTestContextWith(nil, fstest.MapFS{
+		"go.mod": &fstest.MapFile{Data: []byte("module github.com/jwalton/kitsch\n")},
+	})
+	context.git = gitutils.DemoGit{
+		HeadDescription: "v1.1.0",
+		IsDetached:      true,
+		IsTag:           true,
+	}
+
+	result := moduleWrapperFromYAML("type: package").Execute(context)
+	assert.Equal(t, "v1.1.0", result.Text)
+}
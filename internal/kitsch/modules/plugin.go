@@ -0,0 +1,204 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"github.com/mattn/go-shellwords"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas PluginModule
+
+// PluginExecutablePrefix is the prefix for the name of plugin executables.  A
+// plugin named "weather" is run from an executable called "kitsch-module-weather".
+const PluginExecutablePrefix = "kitsch-module-"
+
+// PluginProtocolVersion is the version of the plugin protocol.
+const PluginProtocolVersion = 1
+
+// PluginModule runs an external program to render a module.  This lets you
+// write a module in any language.
+//
+// The program is run in the current working directory.  Kitsch writes a JSON
+// object to the program's stdin with the protocol `version`, the `globals`,
+// and the `options` from the configuration.  The program should write a JSON
+// object to stdout with the `text` to show, and optionally `data` to make
+// available to templates, and a `style` to use in place of the module's style.
+type PluginModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=plugin"`
+	// Name is the name of the plugin.  The plugin will be run from an executable
+	// named "kitsch-module-[name]" on the PATH.
+	Name string `yaml:"name"`
+	// Command is the command to run the plugin.  If this is specified, `name`
+	// is ignored.
+	Command string `yaml:"command"`
+	// Options are passed to the plugin.
+	Options map[string]string `yaml:"options"`
+}
+
+// pluginRequest is the JSON object written to the plugin's stdin.
+type pluginRequest struct {
+	Version int               `json:"version"`
+	Globals Globals           `json:"globals"`
+	Options map[string]string `json:"options"`
+}
+
+// pluginResponse is the JSON object the plugin writes to stdout.
+type pluginResponse struct {
+	Text  string      `json:"text"`
+	Data  interface{} `json:"data"`
+	Style string      `json:"style"`
+}
+
+// Execute the module.
+func (mod PluginModule) Execute(context *Context) ModuleResult {
+	response, err := mod.run(context)
+	if err != nil {
+		return ModuleResult{
+			Warnings: []string{fmt.Sprintf("Error running plugin %s: %v", mod.description(), err)},
+		}
+	}
+
+	return ModuleResult{
+		DefaultText:   response.Text,
+		StyleOverride: response.Style,
+		Data:          response.Data,
+	}
+}
+
+func (mod PluginModule) description() string {
+	if mod.Command != "" {
+		return mod.Command
+	}
+	return mod.Name
+}
+
+func (mod PluginModule) run(context *Context) (pluginResponse, error) {
+	var response pluginResponse
+
+	var commandParts []string
+	if mod.Command != "" {
+		var err error
+		commandParts, err = shellwords.Parse(mod.Command)
+		if err != nil {
+			return response, fmt.Errorf("invalid command: %w", err)
+		}
+	} else if mod.Name != "" {
+		commandParts = []string{PluginExecutablePrefix + mod.Name}
+	}
+	if len(commandParts) == 0 {
+		return response, fmt.Errorf("one of name or command is required")
+	}
+
+	executable, err := context.LookPath(commandParts[0])
+	if err != nil {
+		return response, fmt.Errorf("could not find executable %q: %w", commandParts[0], err)
+	}
+
+	// Plugins can't ask for values as they need them, so send all of them.
+	context.Globals.computeLazyValues()
+	request, err := json.Marshal(pluginRequest{
+		Version: PluginProtocolVersion,
+		Globals: context.Globals,
+		Options: mod.Options,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(executable, commandParts[1:]...)
+	cmd.Dir = context.Globals.CWD
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return response, fmt.Errorf("%w: %s", err, message)
+		}
+		return response, err
+	}
+
+	err = json.Unmarshal(output, &response)
+	if err != nil {
+		return response, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return response, nil
+}
+
+// PluginInfo describes a plugin executable found on the PATH.
+type PluginInfo struct {
+	// Name is the name of the plugin, as used in the `name` field of a plugin
+	// module.
+	Name string
+	// Path is the full path to the plugin executable.
+	Path string
+}
+
+// DiscoverPlugins returns a list of all plugin executables on the PATH,
+// sorted by name.
+func DiscoverPlugins() []PluginInfo {
+	found := map[string]bool{}
+	plugins := []PluginInfo{}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || dir == "." {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			filename := entry.Name()
+			if entry.IsDir() || !strings.HasPrefix(filename, PluginExecutablePrefix) {
+				continue
+			}
+
+			// On Windows, remove the ".exe" or similar.
+			name := strings.TrimSuffix(filename[len(PluginExecutablePrefix):], filepath.Ext(filename))
+			if name == "" || found[name] {
+				continue
+			}
+
+			// Make sure the plugin would actually be run from this folder.
+			path, err := fileutils.LookPathSafe(PluginExecutablePrefix + name)
+			if err != nil || filepath.Dir(path) != filepath.Clean(dir) {
+				continue
+			}
+
+			found[name] = true
+			plugins = append(plugins, PluginInfo{Name: name, Path: path})
+		}
+	}
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+func init() {
+	registerModule(
+		"plugin",
+		registeredModule{
+			jsonSchema: schemas.PluginModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := PluginModule{Type: "plugin"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
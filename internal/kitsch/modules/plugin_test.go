@@ -0,0 +1,104 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestPlugin writes a plugin which echoes back the request it was sent
+// as its data.
+func writeTestPlugin(t *testing.T, dir string, name string) {
+	script := heredoc.Doc(`
+		#!/bin/sh
+		printf '{"text": "hello", "style": "blue", "data": '
+		cat
+		printf '}'
+	`)
+	err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755)
+	require.NoError(t, err)
+}
+
+func TestPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin is a shell script")
+	}
+
+	dir := t.TempDir()
+	writeTestPlugin(t, dir, "kitsch-module-test")
+
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: plugin
+		name: test
+		options:
+		  units: metric
+	`))
+	context := newTestContext("jwalton")
+	addTestExecutable(context, filepath.Join(dir, "kitsch-module-test"))
+	context.Globals.CWD = dir
+
+	result := mod.Execute(context)
+	assert.Equal(t, "hello", result.Text)
+	assert.Equal(t, "blue", result.StartStyle.FG)
+
+	data := result.Data.(map[string]interface{})
+	assert.Equal(t, float64(PluginProtocolVersion), data["version"])
+	assert.Equal(t, map[string]interface{}{"units": "metric"}, data["options"])
+	assert.Equal(t, "lucid", data["globals"].(map[string]interface{})["Hostname"])
+}
+
+func TestPluginCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin is a shell script")
+	}
+
+	dir := t.TempDir()
+	writeTestPlugin(t, dir, "my-plugin")
+
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: plugin
+		command: ` + filepath.Join(dir, "my-plugin") + `
+		template: '{{ .Text }} {{ .Data.globals.Shell }}'
+	`))
+	context := newTestContext("jwalton")
+	addTestExecutable(context, filepath.Join(dir, "my-plugin"))
+	context.Globals.CWD = dir
+
+	result := mod.Execute(context)
+	assert.Equal(t, "hello bash", result.Text)
+}
+
+func TestPluginMissing(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: plugin
+		name: missing
+	`))
+	context := newTestContext("jwalton")
+
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.Text)
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin is a shell script")
+	}
+
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	writeTestPlugin(t, dir1, "kitsch-module-weather")
+	writeTestPlugin(t, dir2, "kitsch-module-weather")
+	writeTestPlugin(t, dir2, "kitsch-module-aws")
+	writeTestPlugin(t, dir2, "not-a-plugin")
+	t.Setenv("PATH", dir1+string(os.PathListSeparator)+dir2)
+
+	assert.Equal(t, []PluginInfo{
+		{Name: "aws", Path: filepath.Join(dir2, "kitsch-module-aws")},
+		{Name: "weather", Path: filepath.Join(dir1, "kitsch-module-weather")},
+	}, DiscoverPlugins())
+}
@@ -15,6 +15,8 @@ type ProjectConfig struct {
 	Style string `yaml:"style"`
 	// ToolSymbol is the symbol to show for this project's build tool.
 	ToolSymbol string `yaml:"toolSymbol"`
+	// Icon is the icon to show for this project.
+	Icon string `yaml:"icon"`
 	// PackageManagerSymbol is the symbol to show for this project's package manager.
 	PackageManagerSymbol string `yaml:"packageManagerSymbol"`
 }
@@ -26,6 +28,8 @@ type projectModuleData struct {
 	Name string
 	// ToolSymbol is the symbol for this project's build tool.
 	ToolSymbol string
+	// Icon is the icon for this project type, or "" if none.
+	Icon string
 	// ToolVersion is the version of this project's build tool.
 	ToolVersion string
 	// PackageManagerSymbol is the symbol for this project's package manager, or "" if unavailable.
@@ -44,6 +48,30 @@ func (p projectModuleData) PackageVersion() string {
 	return p.projectInfo.PackageVersion()
 }
 
+// WorkspaceName returns the name of the current package if it is a member of a
+// workspace in a monorepo, or "" otherwise.
+func (p projectModuleData) WorkspaceName() string {
+	return p.projectInfo.WorkspaceName()
+}
+
+// PinnedVersion returns the tool version pinned by a file like `.tool-versions`
+// or `.nvmrc`, or "" if no version is pinned.
+func (p projectModuleData) PinnedVersion() string {
+	return p.projectInfo.PinnedVersion()
+}
+
+// PinnedVersionFile returns the path of the file that pinned the tool version,
+// or "" if no version is pinned.
+func (p projectModuleData) PinnedVersionFile() string {
+	return p.projectInfo.PinnedVersionFile()
+}
+
+// VersionMismatch returns true if the installed tool version does not match
+// the pinned version.
+func (p projectModuleData) VersionMismatch() bool {
+	return p.projectInfo.VersionMismatch()
+}
+
 // ProjectModule prints information about the project in the current folder.
 //
 type ProjectModule struct {
@@ -81,6 +109,7 @@ func (mod ProjectModule) Execute(context *Context) ModuleResult {
 		projectInfo:          *projectInfo,
 		Name:                 projectInfo.Name,
 		ToolSymbol:           defaultString(overrides.ToolSymbol, projectInfo.ToolSymbol),
+		Icon:                 defaultString(overrides.Icon, projectInfo.Icon),
 		ToolVersion:          projectInfo.ToolVersion,
 		PackageManagerSymbol: defaultString(overrides.PackageManagerSymbol, projectInfo.PackageManagerSymbol),
 		ProjectStyle:         projectStyleString,
@@ -88,7 +117,11 @@ func (mod ProjectModule) Execute(context *Context) ModuleResult {
 
 	text := ""
 	if data.ToolVersion != "" {
-		text = "w/" + projectStyle.Apply(data.ToolSymbol+"@"+data.ToolVersion)
+		icon := ""
+		if data.Icon != "" {
+			icon = data.Icon + " "
+		}
+		text = "w/" + projectStyle.Apply(icon+data.ToolSymbol+"@"+data.ToolVersion)
 	}
 
 	return ModuleResult{DefaultText: text, Data: data}
@@ -1,6 +1,8 @@
 package modules
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"testing/fstest"
 
@@ -10,6 +12,7 @@ import (
 	"github.com/jwalton/kitsch/internal/kitsch/getters"
 	"github.com/jwalton/kitsch/internal/kitsch/projects"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 func TestProject(t *testing.T) {
@@ -49,3 +52,60 @@ func TestProject(t *testing.T) {
 
 	assert.Equal(t, "v1.0.0 / v2.0.0 / v3.0.0", result.Text)
 }
+
+func TestProjectPinnedVersion(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "tool.txt"), []byte("1.2.3\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, ".tool-versions"), []byte("txt 1.4\n"), 0644)
+	assert.NoError(t, err)
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectory(dir, 0, 0)
+	context.ProjectTypes = []projects.ProjectType{
+		{
+			Name:        "txt",
+			Conditions:  &condition.Conditions{IfExtensions: []string{"txt"}},
+			ToolSymbol:  "txt",
+			ToolVersion: []getters.Getter{getters.CustomGetter{Type: getters.TypeFile, From: "tool.txt"}},
+			PinnedTool:  "txt",
+		},
+	}
+
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: project
+		template: "{{ .Data.ToolVersion }} / {{ .Data.PinnedVersion }} / {{ .Data.VersionMismatch }}"
+	`))
+
+	result := mod.Execute(context)
+
+	assert.Equal(t, "1.2.3 / 1.4 / true", result.Text)
+}
+
+func TestProjectCustomProjectType(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "example.widget"), []byte(""), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, ".widget-version"), []byte("widget-tools 4.2.0 (stable)\n"), 0644)
+	assert.NoError(t, err)
+
+	var projectTypes []projects.ProjectType
+	err = yaml.Unmarshal([]byte(heredoc.Doc(`
+		- name: widget
+		  conditions:
+		    ifExtensions: [widget]
+		  toolSymbol: widget
+		  icon: "W"
+		  versionFile: .widget-version
+		  versionRegex: 'widget-tools (\S+)'
+	`)), &projectTypes)
+	assert.NoError(t, err)
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectory(dir, 0, 0)
+	context.ProjectTypes = projectTypes
+
+	result := moduleWrapperFromYAML("type: project").Execute(context)
+
+	assert.Equal(t, "w/W widget@4.2.0", result.Text)
+}
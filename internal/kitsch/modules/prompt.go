@@ -44,7 +44,7 @@ func (mod PromptModule) Execute(context *Context) ModuleResult {
 	var text string
 	var style string
 
-	viCmdMode := context.Globals.Keymap == "vicmd"
+	viCmdMode := viMode(context.Globals.Keymap) == "normal"
 
 	if viCmdMode {
 		text = mod.VicmdPrompt
@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas RModule
+
+// RModule shows the version of R in R projects.
+//
+type RModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=r"`
+	// Symbol is the symbol to show before the version.  Defaults to "📐 ".
+	Symbol string `yaml:"symbol"`
+}
+
+type rModuleResult struct {
+	// Version is the version of R, or "" if it could not be found.
+	Version string
+}
+
+// rConditions are the conditions for the r module to be shown.
+var rConditions = condition.Conditions{
+	IfFiles:      []string{"DESCRIPTION", ".Rprofile"},
+	IfExtensions: []string{"R", "r", "Rmd", "Rproj"},
+}
+
+// rVersionGetter gets the version of R.  The result is cached until R is
+// updated.
+var rVersionGetter = getters.CustomGetter{
+	Type:  getters.TypeCustom,
+	From:  "R --version",
+	Regex: `(?m)^R version (\S+)`,
+	Cache: getters.CacheSettings{Enabled: true},
+}
+
+// Execute the module.
+func (mod RModule) Execute(context *Context) ModuleResult {
+	if !rConditions.Matches(context.Directory, context) {
+		return ModuleResult{Data: rModuleResult{}}
+	}
+
+	data := rModuleResult{}
+	if version, err := rVersionGetter.GetValue(context); err == nil {
+		data.Version, _ = version.(string)
+	}
+
+	return ModuleResult{DefaultText: mod.Symbol + data.Version, Data: data}
+}
+
+func init() {
+	registerModule(
+		"r",
+		registeredModule{
+			jsonSchema: schemas.RModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := RModule{Type: "r", Symbol: "📐 "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
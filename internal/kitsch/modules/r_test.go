@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestExecutable writes a shell script to the given folder, and returns
+// the path to the script.
+func writeTestExecutable(t *testing.T, dir string, name string, script string) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755)
+	require.NoError(t, err)
+	return path
+}
+
+// addTestExecutable puts the executable at `path` on the PATH of the test
+// context's environment, which must be a DummyEnv.
+func addTestExecutable(context *Context, path string) {
+	environment := context.Environment.(*env.DummyEnv)
+	if environment.Executables == nil {
+		environment.Executables = map[string]string{}
+	}
+	environment.Executables[filepath.Base(path)] = path
+	environment.Executables[path] = path
+}
+
+func TestR(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test executable is a shell script")
+	}
+
+	dir := t.TempDir()
+	executable := writeTestExecutable(t, dir, "R", heredoc.Doc(`
+		echo 'R version 4.2.1 (2022-06-23) -- "Funny-Looking Kid"'
+		echo 'Copyright (C) 2022 The R Foundation for Statistical Computing'
+	`))
+
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: r
+	`)).(*RModule)
+
+	context := newTestContext("jwalton")
+	addTestExecutable(context, executable)
+	context.Directory = fileutils.NewDirectoryTestFS(dir, fstest.MapFS{
+		"analysis.R": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+
+	assert.Equal(t, "📐 4.2.1", result.DefaultText)
+	assert.Equal(t, rModuleResult{Version: "4.2.1"}, result.Data)
+}
+
+func TestRNotAnRProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: r
+	`)).(*RModule)
+
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, fstest.MapFS{
+		"main.c": &fstest.MapFile{},
+	})
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, rModuleResult{}, result.Data)
+}
@@ -0,0 +1,146 @@
+package modules
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas RubyModule
+
+// RubyModule shows the version of ruby in ruby projects, and the current
+// gemset, if there is one.  This honors the same files and environment
+// variables as rbenv, chruby, and rvm, so it only needs to run `ruby` if no
+// version has been selected.
+//
+type RubyModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=ruby"`
+	// Symbol is the symbol to show before the version.  Defaults to "💎 ".
+	Symbol string `yaml:"symbol"`
+}
+
+type rubyModuleResult struct {
+	// Version is the version of ruby, or "" if it could not be found.
+	Version string
+	// VersionSource is where the version came from.  This is either the name
+	// of an environment variable, the path to a file like ".ruby-version", or
+	// "ruby" if the version came from running `ruby --version`.
+	VersionSource string
+	// Gemset is the name of the current gemset, or "" if none.
+	Gemset string
+}
+
+// rubyConditions are the conditions for the ruby module to be shown.
+var rubyConditions = condition.Conditions{
+	IfFiles:      []string{"Gemfile", ".ruby-version", ".rvmrc"},
+	IfExtensions: []string{"rb"},
+}
+
+// rvmrcRegex matches a version and gemset in a ".rvmrc" file, for example
+// "rvm use ruby-3.1.2@myapp --create".
+var rvmrcRegex = regexp.MustCompile(`(?m)^\s*rvm\s+(?:use\s+)?(?:--\S+\s+)*([^\s@]+)(?:@(\S+))?`)
+
+// rubyVersionGetter is used to get the version of ruby if no version is
+// selected any other way.
+var rubyVersionGetter = getters.CustomGetter{
+	Type:  getters.TypeCustom,
+	From:  "ruby --version",
+	Regex: `^ruby (\d+\.\d+\.[0-9a-zA-Z]+)`,
+	Cache: getters.CacheSettings{Enabled: true},
+}
+
+// Execute the module.
+func (mod RubyModule) Execute(context *Context) ModuleResult {
+	if !rubyConditions.Matches(context.Directory, context) {
+		return ModuleResult{Data: rubyModuleResult{}}
+	}
+
+	data := resolveRubyVersion(context)
+
+	text := mod.Symbol + data.Version
+	if data.Gemset != "" {
+		text += "@" + data.Gemset
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// resolveRubyVersion works out which version of ruby will be used in the
+// current folder.
+func resolveRubyVersion(context *Context) rubyModuleResult {
+	data := rubyModuleResult{}
+	rvmrcGemset := ""
+
+	if version := context.Getenv("RBENV_VERSION"); version != "" {
+		// Set by `rbenv shell`, which overrides any local version.
+		data.Version = version
+		data.VersionSource = "RBENV_VERSION"
+	} else if path, contents := readAncestorFile(context.Directory, ".ruby-version"); path != "" {
+		data.Version = strings.TrimSpace(string(contents))
+		data.VersionSource = path
+	} else if path, contents := readAncestorFile(context.Directory, ".rvmrc"); path != "" {
+		if match := rvmrcRegex.FindSubmatch(contents); match != nil {
+			data.Version = string(match[1])
+			data.VersionSource = path
+			rvmrcGemset = string(match[2])
+		}
+	}
+
+	if data.Version == "" {
+		// chruby and rvm set RUBY_VERSION to the active ruby.
+		if version := context.Getenv("RUBY_VERSION"); version != "" {
+			data.Version = version
+			data.VersionSource = "RUBY_VERSION"
+		}
+	}
+
+	if data.Version == "" {
+		if version, err := rubyVersionGetter.GetValue(context); err == nil {
+			data.Version, _ = version.(string)
+			data.VersionSource = "ruby"
+		}
+	}
+
+	data.Version = strings.TrimPrefix(data.Version, "ruby-")
+	data.Gemset = resolveRubyGemset(context, rvmrcGemset)
+
+	return data
+}
+
+// resolveRubyGemset returns the name of the current gemset.
+func resolveRubyGemset(context *Context, rvmrcGemset string) string {
+	if _, contents := readAncestorFile(context.Directory, ".ruby-gemset"); contents != nil {
+		return strings.TrimSpace(string(contents))
+	}
+	if rvmrcGemset != "" {
+		return rvmrcGemset
+	}
+
+	// rvm sets GEM_HOME to something like "~/.rvm/gems/ruby-3.1.2@myapp".
+	gemHome := filepath.Base(context.Getenv("GEM_HOME"))
+	if index := strings.Index(gemHome, "@"); index != -1 {
+		return gemHome[index+1:]
+	}
+
+	return ""
+}
+
+func init() {
+	registerModule(
+		"ruby",
+		registeredModule{
+			jsonSchema: schemas.RubyModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := RubyModule{Type: "ruby", Symbol: "💎 "}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
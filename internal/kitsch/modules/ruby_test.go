@@ -0,0 +1,83 @@
+package modules
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRubyVersionFile(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ruby
+	`)).(*RubyModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"RUBY_VERSION": "2.7.0"}, fstest.MapFS{
+		"Gemfile":       &fstest.MapFile{},
+		".ruby-version": &fstest.MapFile{Data: []byte("ruby-3.1.2\n")},
+		".ruby-gemset":  &fstest.MapFile{Data: []byte("myapp\n")},
+	}))
+
+	assert.Equal(t, "💎 3.1.2@myapp", result.DefaultText)
+	assert.Equal(t, rubyModuleResult{
+		Version:       "3.1.2",
+		VersionSource: "/Users/jwalton/.ruby-version",
+		Gemset:        "myapp",
+	}, result.Data)
+}
+
+func TestRubyRbenvShell(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ruby
+	`)).(*RubyModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"RBENV_VERSION": "3.2.0"}, fstest.MapFS{
+		"app.rb":        &fstest.MapFile{},
+		".ruby-version": &fstest.MapFile{Data: []byte("3.1.2\n")},
+	}))
+
+	assert.Equal(t, "💎 3.2.0", result.DefaultText)
+	assert.Equal(t, "RBENV_VERSION", result.Data.(rubyModuleResult).VersionSource)
+}
+
+func TestRubyRvmrc(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ruby
+	`)).(*RubyModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{}, fstest.MapFS{
+		".rvmrc": &fstest.MapFile{Data: []byte("rvm use --create ruby-2.7.4@legacy\n")},
+	}))
+
+	assert.Equal(t, "💎 2.7.4@legacy", result.DefaultText)
+}
+
+func TestRubyChruby(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ruby
+	`)).(*RubyModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{
+		"RUBY_VERSION": "3.0.1",
+		"GEM_HOME":     "/Users/jwalton/.rvm/gems/ruby-3.0.1@tools",
+	}, fstest.MapFS{
+		"Gemfile": &fstest.MapFile{},
+	}))
+
+	assert.Equal(t, "💎 3.0.1@tools", result.DefaultText)
+	assert.Equal(t, rubyModuleResult{
+		Version:       "3.0.1",
+		VersionSource: "RUBY_VERSION",
+		Gemset:        "tools",
+	}, result.Data)
+}
+
+func TestRubyNotARubyProject(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: ruby
+	`)).(*RubyModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"RUBY_VERSION": "3.0.1"}, fstest.MapFS{}))
+	assert.Equal(t, "", result.DefaultText)
+}
@@ -0,0 +1,68 @@
+package modules
+
+import (
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"github.com/jwalton/kitsch/internal/textwidth"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas RuleModule
+
+// RuleModule draws a horizontal line by repeating a character, for example
+// to separate a two-line prompt from the output of the previous command.
+//
+type RuleModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=rule"`
+	// Char is the character to repeat.  This can be more than one character,
+	// in which case the whole string is repeated, and cut off at the end of
+	// the line.  Defaults to "─".
+	Char string `yaml:"char"`
+	// Width is the width of the line, in columns.  If this is 0, the line
+	// will be as wide as the terminal.  If this is negative, the line will be
+	// this many columns narrower than the terminal.
+	Width int `yaml:"width"`
+}
+
+type ruleModuleResult struct {
+	// Char is the character that was repeated.
+	Char string
+	// Width is the width of the line, in columns.
+	Width int
+}
+
+// Execute the module.
+func (mod RuleModule) Execute(context *Context) ModuleResult {
+	width := mod.Width
+	if width <= 0 {
+		width += context.Globals.TerminalWidth
+	}
+
+	text := ""
+	charWidth := textwidth.Width(mod.Char)
+	if width > 0 && charWidth > 0 {
+		text = strings.Repeat(mod.Char, (width+charWidth-1)/charWidth)
+		text = textwidth.Truncate(text, width, "")
+	}
+
+	return ModuleResult{
+		DefaultText: text,
+		Data:        ruleModuleResult{Char: mod.Char, Width: textwidth.Width(text)},
+	}
+}
+
+func init() {
+	registerModule(
+		"rule",
+		registeredModule{
+			jsonSchema: schemas.RuleModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := RuleModule{Type: "rule", Char: "─"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
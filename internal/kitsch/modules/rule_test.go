@@ -0,0 +1,49 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleFillsTerminal(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.Globals.TerminalWidth = 10
+
+	mod := moduleFromYAML("type: rule").(*RuleModule)
+	result := mod.Execute(context)
+	assert.Equal(t, "──────────", result.DefaultText)
+	assert.Equal(t, ruleModuleResult{Char: "─", Width: 10}, result.Data)
+}
+
+func TestRuleWidth(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.Globals.TerminalWidth = 10
+
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: rule
+		char: "-="
+		width: 5
+	`)).(*RuleModule)
+	assert.Equal(t, "-=-=-", mod.Execute(context).DefaultText)
+
+	mod.Width = -4
+	assert.Equal(t, "-=-=-=", mod.Execute(context).DefaultText)
+
+	mod.Width = -10
+	assert.Equal(t, "", mod.Execute(context).DefaultText)
+}
+
+func TestRuleWideCharacter(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.Globals.TerminalWidth = 5
+
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: rule
+		char: "🟦"
+	`)).(*RuleModule)
+	result := mod.Execute(context)
+	assert.Equal(t, "🟦🟦", result.DefaultText)
+	assert.Equal(t, 4, result.Data.(ruleModuleResult).Width)
+}
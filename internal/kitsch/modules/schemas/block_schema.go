@@ -8,7 +8,12 @@ var BlockModuleJSONSchema = `{
   "properties": {
     "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["block"]},
     "modules": {"$ref": "#/definitions/ModulesList"},
-    "join": {"type": "string", "description": "Join is a template to use to join together modules.  Defaults to \" \". This will be executed with template data of the form ` + "`" + `{ PrevColors, NextColors, Index }` + "`" + `, where PrevColors is the FG and BG color of last character of the previous module, NextColors is the FG and BG color of the first character of the next module, and Index is the index of the current module in the modules array."}
+    "join": {"type": "string", "description": "Join is a template to use to join together modules.  Defaults to \" \". This will be executed with template data of the form ` + "`" + `{ PrevColors, NextColors, Index }` + "`" + `, where PrevColors is the FG and BG color of last character of the previous module, NextColors is the FG and BG color of the first character of the next module, and Index is the index of the current module in the modules array."},
+    "minWidth": {"type": "integer", "description": "MinWidth is the minimum width of the block, in columns.  If the block's output is narrower than this, it will be padded with spaces according to ` + "`" + `Align` + "`" + `.  Blocks with no output are never padded."},
+    "maxWidth": {"type": "integer", "description": "MaxWidth is the maximum width of the block, in columns.  If the block's output is wider than this, it will be truncated and end with \"…\".  0 means there is no maximum."},
+    "align": {"type": "string", "description": "Align is how to align the block's output when it is narrower than ` + "`" + `MinWidth` + "`" + `.  Defaults to \"left\".", "enum": ["left", "right", "center"]},
+    "padding": {"type": "integer", "description": "Padding is the number of spaces to add to either side of the block's output.  These count towards ` + "`" + `MinWidth` + "`" + ` and ` + "`" + `MaxWidth` + "`" + `."},
+    "fillLine": {"type": "boolean", "description": "FillLine, if true, extends the background color at the end of the block to the end of the terminal line.  This is handy for drawing a \"header bar\" across the top line of a two-line prompt."}
   },
   "required": ["type", "modules"]}`
 
@@ -0,0 +1,13 @@
+// Code generated by "genSchema --pkg schemas CIModule"; DO NOT EDIT.
+
+package schemas
+
+// CIModuleJSONSchema is the JSON schema for the CIModule struct.
+var CIModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["ci"]},
+    "symbol": {"type": "string", "description": "Symbol is the symbol to show before the CI provider.  Defaults to \"⚙ \"."}
+  },
+  "required": ["type"]}`
+
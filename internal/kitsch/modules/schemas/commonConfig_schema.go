@@ -1,4 +1,4 @@
-// Code generated by "genSchema --pkg schemas CommonConfig"; DO NOT EDIT.
+// Code generated by "genSchema --pkg schemas CommonConfig ModuleCache"; DO NOT EDIT.
 
 package schemas
 
@@ -10,7 +10,20 @@ var CommonConfigJSONSchema = `{
     "id": {"type": "string", "description": "ID is a unique identifier for this module.  IDs are unique only within the parent block."},
     "style": {"type": "string", "description": "Style is the style to apply to this module."},
     "template": {"type": "string", "description": "Template is a golang template to use to render the output of this module."},
+    "prefix": {"type": "string", "description": "Prefix is text to show before the output of this module.  The prefix is only shown if the module produces some output."},
+    "prefixStyle": {"type": "string", "description": "PrefixStyle is the style to apply to the prefix.  Defaults to ` + "`" + `Style` + "`" + `."},
+    "suffix": {"type": "string", "description": "Suffix is text to show after the output of this module.  The suffix is only shown if the module produces some output."},
+    "suffixStyle": {"type": "string", "description": "SuffixStyle is the style to apply to the suffix.  Defaults to ` + "`" + `Style` + "`" + `."},
     "conditions": {"$ref": "#/definitions/Conditions"},
+    "cache": {"$ref": "#/definitions/ModuleCache"},
     "timeout": {"type": "integer", "description": "Timeout is the maximum amount of time, in milliseconds, to wait for this module to execute.  If not specified, the default timeout for most modules will be 200ms, but for block modules it will be infinite."}
   }}`
 
+// ModuleCacheJSONSchema is the JSON schema for the ModuleCache struct.
+var ModuleCacheJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "ttl": {"type": "integer", "description": "TTL is how long to reuse the module's output for, in milliseconds.  If this is 0, the module's output is not cached."},
+    "key": {"type": "string", "description": "Key is a template used to generate the cache key.  Cached output is only used if the key is the same as when the output was cached.  The template is passed ` + "`" + `{ Globals }` + "`" + `.  Defaults to the current working directory."}
+  }}`
+
@@ -0,0 +1,13 @@
+// Code generated by "genSchema --pkg schemas ContainerModule"; DO NOT EDIT.
+
+package schemas
+
+// ContainerModuleJSONSchema is the JSON schema for the ContainerModule struct.
+var ContainerModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["container"]},
+    "symbol": {"type": "string", "description": "Symbol is a symbol to show before the container name.  Defaults to \"⬢ \"."}
+  },
+  "required": ["type"]}`
+
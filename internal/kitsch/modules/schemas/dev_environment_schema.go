@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas DevEnvironmentModule"; DO NOT EDIT.
+
+package schemas
+
+// DevEnvironmentModuleJSONSchema is the JSON schema for the DevEnvironmentModule struct.
+var DevEnvironmentModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["dev_environment"]},
+    "vagrantSymbol": {"type": "string", "description": "VagrantSymbol is the symbol to show before the state of the Vagrant machine.  Defaults to \"⍱ \"."},
+    "devContainerSymbol": {"type": "string", "description": "DevContainerSymbol is the symbol to show before the state of the dev container.  Defaults to \"⬡ \"."}
+  },
+  "required": ["type"]}`
+
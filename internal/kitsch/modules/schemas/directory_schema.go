@@ -9,10 +9,18 @@ var DirectoryModuleJSONSchema = `{
     "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["directory"]},
     "homeSymbol": {"type": "string", "description": "HomeSymbol is the symbol to replace the home directory with in directory strings.  Defaults to \"~\"."},
     "readOnlySymbol": {"type": "string", "description": "ReadOnlySymbol is the symbol to append to the directory if it is read-only."},
+    "networkSymbol": {"type": "string", "description": "NetworkSymbol is the symbol to append to the directory if it is on a network filesystem."},
     "truncateToRepo": {"type": "boolean", "description": "TruncateToRepo controls whether we truncate to the root directory of the git repo or not.  If this is true, and we are in a source code repository, we will replace everything up to the repo root directory with RepoSymbol."},
     "repoSymbol": {"type": "string", "description": "RepoSymbol is a string that will be added as a prefix when we truncate to a repo."},
     "truncationLength": {"type": "integer", "description": "TruncationLength is the maximum number of directories to show. If 0, truncation will be disabled."},
-    "truncationSymbol": {"type": "string", "description": "TruncationSymbol will be added to the start of the string in place of any paths that were removed.  Defaults to \"…\"."}
+    "truncationSymbol": {"type": "string", "description": "TruncationSymbol will be added to the start of the string in place of any paths that were removed.  Defaults to \"…\"."},
+    "substitutions": {"type": "object", "description": "Substitutions is a map where keys are folders, and values are the symbol or name to show in place of that folder.  Keys may start with \"~\" to refer to the user's home directory.  If more than one key matches the current directory, the longest one is used.  Substitutions are applied before the path is truncated.", "additionalProperties": {"type": "string", "description": ""}},
+    "truncationMode": {"type": "string", "description": "TruncationMode controls how folders in the middle of the path are shown. If this is \"uniquePrefix\", then every folder other than the first and the last will be shortened to the shortest prefix that is unique among the folders in its parent.  For example \"~/dev/kitsch/internal\" might be shown as \"~/d/k/internal\".  If this is \"repo\", then inside a git repo this will show the name of the repo followed by the full path inside the repo, regardless of TruncateToRepo and TruncationLength.  Outside of a git repo this behaves the same as \"length\".", "enum": ["length", "uniquePrefix", "repo"]},
+    "separator": {"type": "string", "description": "Separator, if set, is shown between each component of the path in place of the path separator.  Setting Separator, ComponentStyle, CurrentComponentStyle, or Hyperlinks renders each component of the path as a separate segment."},
+    "separatorStyle": {"type": "string", "description": "SeparatorStyle is the style to apply to the separator between components."},
+    "componentStyle": {"type": "string", "description": "ComponentStyle is the style to apply to each component of the path."},
+    "currentComponentStyle": {"type": "string", "description": "CurrentComponentStyle is the style to apply to the last component of the path (the current directory).  Defaults to ComponentStyle."},
+    "hyperlinks": {"type": "boolean", "description": "Hyperlinks, if true, makes each component of the path an OSC 8 hyperlink to that folder, in terminals that support it."}
   },
   "required": ["type"]}`
 
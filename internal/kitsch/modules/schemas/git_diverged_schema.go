@@ -11,7 +11,9 @@ var GitDivergedJSONSchema = `{
     "behindSymbol": {"type": "string", "description": "BehindSymbol is the symbol to use when the current branch is behind its upstream."},
     "divergedSymbol": {"type": "string", "description": "DivergedSymbol is the symbol to use when the current branch has diverged from its upstream."},
     "upToDateSymbol": {"type": "string", "description": "UpToDateSymbol is the symbol to use when the current branch is up to date with its upstream."},
-    "noUpstreamSymbol": {"type": "string", "description": "NoUpstreamSymbol is the symbol to use when the current branch has no upstream."}
+    "noUpstreamSymbol": {"type": "string", "description": "NoUpstreamSymbol is the symbol to use when the current branch has no upstream."},
+    "exact": {"type": "boolean", "description": "Exact, if true, runs ` + "`" + `git rev-list` + "`" + ` to count exactly how many commits we are ahead and behind.  By default the counts come from a fast walk of the commit graph, which gives up and returns an estimate if the branches have diverged by more than 1000 commits."},
+    "exactTimeout": {"type": "integer", "description": "ExactTimeout is the maximum time to wait for ` + "`" + `git rev-list` + "`" + `, in milliseconds, when ` + "`" + `Exact` + "`" + ` is true.  Defaults to 5000."}
   },
   "required": ["type"]}`
 
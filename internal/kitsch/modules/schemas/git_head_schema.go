@@ -7,7 +7,8 @@ var GitHeadModuleJSONSchema = `{
   "type": "object",
   "properties": {
     "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["git_head"]},
-    "maxTagsToSearch": {"type": "integer", "description": "MaxTagsToSearch is the maximum number of tags to search when checking to see if HEAD is a tagged release.  Defaults to 200."}
+    "maxTagsToSearch": {"type": "integer", "description": "MaxTagsToSearch is the maximum number of tags to search when checking to see if HEAD is a tagged release.  Defaults to 200."},
+    "defaultBranchStyle": {"type": "string", "description": "DefaultBranchStyle is the style to use for the default output when we are on the repo's default branch (e.g. \"main\")."}
   },
   "required": ["type"]}`
 
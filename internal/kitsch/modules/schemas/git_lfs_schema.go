@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas GitLFSModule"; DO NOT EDIT.
+
+package schemas
+
+// GitLFSModuleJSONSchema is the JSON schema for the GitLFSModule struct.
+var GitLFSModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["git_lfs"]},
+    "symbol": {"type": "string", "description": "Symbol is the text to show when the repo uses LFS and git-lfs is installed."},
+    "missingSymbol": {"type": "string", "description": "MissingSymbol is the text to show when the repo uses LFS, but git-lfs is not installed."}
+  },
+  "required": ["type"]}`
+
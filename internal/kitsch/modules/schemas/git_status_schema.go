@@ -9,7 +9,8 @@ var GitStatusModuleJSONSchema = `{
     "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["git_status"]},
     "indexStyle": {"type": "string", "description": "IndexStyle is the style to use for the index status."},
     "unstagedStyle": {"type": "string", "description": "UnstagedStyle is the style to use for the unstaged file status."},
-    "stashStyle": {"type": "string", "description": "StashStyle is the style to use for the stash count."}
+    "stashStyle": {"type": "string", "description": "StashStyle is the style to use for the stash count."},
+    "scanNetworkFilesystems": {"type": "boolean", "description": "ScanNetworkFilesystems, if false, skips counting changed files when the current directory is on a network filesystem, since this has to read every file in the work tree."}
   },
   "required": ["type"]}`
 
@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas HelmModule"; DO NOT EDIT.
+
+package schemas
+
+// HelmModuleJSONSchema is the JSON schema for the HelmModule struct.
+var HelmModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["helm"]},
+    "symbol": {"type": "string", "description": "Symbol is the symbol to show before the chart name.  Defaults to \"⎈ \"."},
+    "configFile": {"type": "string", "description": "ConfigFile is the path to the kubectl config file.  Defaults to the first file in KUBECONFIG, or \"~/.kube/config\"."}
+  },
+  "required": ["type"]}`
+
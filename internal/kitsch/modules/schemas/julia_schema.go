@@ -0,0 +1,13 @@
+// Code generated by "genSchema --pkg schemas JuliaModule"; DO NOT EDIT.
+
+package schemas
+
+// JuliaModuleJSONSchema is the JSON schema for the JuliaModule struct.
+var JuliaModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["julia"]},
+    "symbol": {"type": "string", "description": "Symbol is the symbol to show before the version.  Defaults to \"ஃ \"."}
+  },
+  "required": ["type"]}`
+
@@ -0,0 +1,20 @@
+// Code generated by "genSchema --pkg schemas KeymapModule"; DO NOT EDIT.
+
+package schemas
+
+// KeymapModuleJSONSchema is the JSON schema for the KeymapModule struct.
+var KeymapModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["keymap"]},
+    "insert": {"type": "string", "description": "Insert is the text to show in insert mode.  Defaults to \"\"."},
+    "insertStyle": {"type": "string", "description": "InsertStyle is the style to use in insert mode."},
+    "normal": {"type": "string", "description": "Normal is the text to show in normal (command) mode.  Defaults to \"[N]\"."},
+    "normalStyle": {"type": "string", "description": "NormalStyle is the style to use in normal mode."},
+    "visual": {"type": "string", "description": "Visual is the text to show in visual mode.  Defaults to \"[V]\"."},
+    "visualStyle": {"type": "string", "description": "VisualStyle is the style to use in visual mode."},
+    "replace": {"type": "string", "description": "Replace is the text to show in replace mode.  Defaults to \"[R]\"."},
+    "replaceStyle": {"type": "string", "description": "ReplaceStyle is the style to use in replace mode."}
+  },
+  "required": ["type"]}`
+
@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas NixModule"; DO NOT EDIT.
+
+package schemas
+
+// NixModuleJSONSchema is the JSON schema for the NixModule struct.
+var NixModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["nix"]},
+    "symbol": {"type": "string", "description": "Symbol is the symbol to show before the shell name.  Defaults to \"❄ \"."},
+    "heuristic": {"type": "boolean", "description": "Heuristic, if true, will also detect ` + "`" + `nix shell` + "`" + `, which doesn't set any environment variables, by looking for \"/nix/store\" in the PATH.  This will give false positives on NixOS."}
+  },
+  "required": ["type"]}`
+
@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas OSModule"; DO NOT EDIT.
+
+package schemas
+
+// OSModuleJSONSchema is the JSON schema for the OSModule struct.
+var OSModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["os"]},
+    "symbols": {"type": "object", "description": "Symbols is a map of operating systems to the text to show for each.  Keys are Go operating system names (e.g. \"linux\", \"darwin\", \"windows\"), or \"wsl\" for the Windows Subsystem for Linux.", "additionalProperties": {"type": "string", "description": ""}},
+    "showDistro": {"type": "boolean", "description": "ShowDistro, if true, will show the name of the WSL distribution after the symbol when running in WSL."}
+  },
+  "required": ["type"]}`
+
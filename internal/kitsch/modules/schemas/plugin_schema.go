@@ -0,0 +1,15 @@
+// Code generated by "genSchema --pkg schemas PluginModule"; DO NOT EDIT.
+
+package schemas
+
+// PluginModuleJSONSchema is the JSON schema for the PluginModule struct.
+var PluginModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["plugin"]},
+    "name": {"type": "string", "description": "Name is the name of the plugin.  The plugin will be run from an executable named \"kitsch-module-[name]\" on the PATH."},
+    "command": {"type": "string", "description": "Command is the command to run the plugin.  If this is specified, ` + "`" + `name` + "`" + ` is ignored."},
+    "options": {"type": "object", "description": "Options are passed to the plugin.", "additionalProperties": {"type": "string", "description": ""}}
+  },
+  "required": ["type"]}`
+
@@ -12,6 +12,7 @@ var ProjectModuleJSONSchema = `{
       "properties": {
         "style": {"type": "string", "description": "Style is the style to apply to this project."},
         "toolSymbol": {"type": "string", "description": "ToolSymbol is the symbol to show for this project's build tool."},
+        "icon": {"type": "string", "description": "Icon is the icon to show for this project."},
         "packageManagerSymbol": {"type": "string", "description": "PackageManagerSymbol is the symbol to show for this project's package manager."}
       },
       "additionalProperties": false}},
@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas RuleModule"; DO NOT EDIT.
+
+package schemas
+
+// RuleModuleJSONSchema is the JSON schema for the RuleModule struct.
+var RuleModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["rule"]},
+    "char": {"type": "string", "description": "Char is the character to repeat.  This can be more than one character, in which case the whole string is repeated, and cut off at the end of the line.  Defaults to \"─\"."},
+    "width": {"type": "integer", "description": "Width is the width of the line, in columns.  If this is 0, the line will be as wide as the terminal.  If this is negative, the line will be this many columns narrower than the terminal."}
+  },
+  "required": ["type"]}`
+
@@ -0,0 +1,13 @@
+// Code generated by "genSchema --pkg schemas ScriptModule"; DO NOT EDIT.
+
+package schemas
+
+// ScriptModuleJSONSchema is the JSON schema for the ScriptModule struct.
+var ScriptModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["script"]},
+    "script": {"type": "string", "description": "Script is the expression to evaluate."}
+  },
+  "required": ["type", "script"]}`
+
@@ -0,0 +1,16 @@
+// Code generated by "genSchema --pkg schemas ShlvlModule"; DO NOT EDIT.
+
+package schemas
+
+// ShlvlModuleJSONSchema is the JSON schema for the ShlvlModule struct.
+var ShlvlModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["shlvl"]},
+    "symbol": {"type": "string", "description": "Symbol is the symbol to show before the shell level.  Defaults to \"↕ \"."},
+    "threshold": {"type": "integer", "description": "Threshold is the minimum shell level to show.  Defaults to 2."},
+    "repeat": {"type": "boolean", "description": "Repeat, if true, will show the symbol once for each level, instead of showing the symbol followed by the level (e.g. \"❯❯❯\" instead of \"❯3\")."},
+    "repeatOffset": {"type": "integer", "description": "RepeatOffset is subtracted from the level when repeating the symbol. For example, if this is 1, a level 3 shell will show the symbol twice."}
+  },
+  "required": ["type"]}`
+
@@ -0,0 +1,15 @@
+// Code generated by "genSchema --pkg schemas SudoModule"; DO NOT EDIT.
+
+package schemas
+
+// SudoModuleJSONSchema is the JSON schema for the SudoModule struct.
+var SudoModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["sudo"]},
+    "symbol": {"type": "string", "description": "Symbol is the text to show when sudo credentials are cached.  Defaults to \"⚠ \"."},
+    "checkTimeout": {"type": "integer", "description": "CheckTimeout is the maximum time to wait for sudo to respond, in milliseconds.  Defaults to 500."},
+    "cacheDuration": {"type": "integer", "description": "CacheDuration is how long to remember the result, in milliseconds, so we don't need to run sudo every time the prompt is shown.  Defaults to 5000. Set to 0 to check every time."}
+  },
+  "required": ["type"]}`
+
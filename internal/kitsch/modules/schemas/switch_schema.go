@@ -0,0 +1,13 @@
+// Code generated by "genSchema --pkg schemas SwitchModule"; DO NOT EDIT.
+
+package schemas
+
+// SwitchModuleJSONSchema is the JSON schema for the SwitchModule struct.
+var SwitchModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["switch"]},
+    "modules": {"$ref": "#/definitions/ModulesList"}
+  },
+  "required": ["type", "modules"]}`
+
@@ -0,0 +1,16 @@
+// Code generated by "genSchema --pkg schemas SysinfoModule"; DO NOT EDIT.
+
+package schemas
+
+// SysinfoModuleJSONSchema is the JSON schema for the SysinfoModule struct.
+var SysinfoModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["sysinfo"]},
+    "memorySymbol": {"type": "string", "description": "MemorySymbol is shown before the memory usage.  Defaults to \"mem \"."},
+    "memoryThreshold": {"type": "number", "description": "MemoryThreshold is the memory usage percentage at or above which memory usage will be shown.  Defaults to 90."},
+    "loadSymbol": {"type": "string", "description": "LoadSymbol is shown before the load average.  Defaults to \"load \"."},
+    "loadThreshold": {"type": "number", "description": "LoadThreshold is the one minute load average at or above which the load average will be shown.  Defaults to the number of CPUs."}
+  },
+  "required": ["type"]}`
+
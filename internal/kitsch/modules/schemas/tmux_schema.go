@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas TmuxModule"; DO NOT EDIT.
+
+package schemas
+
+// TmuxModuleJSONSchema is the JSON schema for the TmuxModule struct.
+var TmuxModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["tmux"]},
+    "symbol": {"type": "string", "description": "Symbol is the symbol to show before the session name.  Defaults to \"▣ \"."},
+    "cacheDuration": {"type": "integer", "description": "CacheDuration is how long to remember the session name and window index, in milliseconds, so we don't need to run ` + "`" + `tmux` + "`" + ` every time the prompt is shown.  Defaults to 5000.  Set to 0 to check every time."}
+  },
+  "required": ["type"]}`
+
@@ -0,0 +1,14 @@
+// Code generated by "genSchema --pkg schemas UmaskModule"; DO NOT EDIT.
+
+package schemas
+
+// UmaskModuleJSONSchema is the JSON schema for the UmaskModule struct.
+var UmaskModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["umask"]},
+    "symbol": {"type": "string", "description": "Symbol is the symbol to show before the umask.  Defaults to \"umask \"."},
+    "expected": {"type": "string", "description": "Expected is the expected umask, in octal.  Nothing is shown when the umask matches this value.  Defaults to \"022\"."}
+  },
+  "required": ["type"]}`
+
@@ -8,7 +8,8 @@ var UsernameModuleJSONSchema = `{
   "properties": {
     "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["username"]},
     "showAlways": {"type": "boolean", "description": "ShowAlways will cause the username to always be shown.  If false (the default), then the username will only be shown if the user is root, or the current session is an SSH session."},
-    "rootStyle": {"type": "string", "description": "RootStyle will be used in place of ` + "`" + `Style` + "`" + ` if the current user is root. If this style is empty, will fall back to ` + "`" + `Style` + "`" + `."}
+    "rootStyle": {"type": "string", "description": "RootStyle will be used in place of ` + "`" + `Style` + "`" + ` if the current user is root. If this style is empty, will fall back to ` + "`" + `Style` + "`" + `."},
+    "short": {"type": "boolean", "description": "Short will show the username without a domain, so on Windows only the part after the domain name is shown, and \"user@domain\" becomes \"user\"."}
   },
   "required": ["type"]}`
 
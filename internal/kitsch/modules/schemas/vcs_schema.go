@@ -0,0 +1,16 @@
+// Code generated by "genSchema --pkg schemas VCSModule"; DO NOT EDIT.
+
+package schemas
+
+// VCSModuleJSONSchema is the JSON schema for the VCSModule struct.
+var VCSModuleJSONSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "description": "Type is the type of this module.", "enum": ["vcs"]},
+    "git": {"$ref": "#/definitions/module"},
+    "hg": {"$ref": "#/definitions/module"},
+    "svn": {"$ref": "#/definitions/module"},
+    "jj": {"$ref": "#/definitions/module"}
+  },
+  "required": ["type"]}`
+
@@ -0,0 +1,128 @@
+package modules
+
+import (
+	"fmt"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"github.com/jwalton/kitsch/internal/kitsch/script"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas ScriptModule
+
+// ScriptModule evaluates a small expression, and shows the result.  Scripts
+// can do string manipulation and conditionals over environment variables,
+// globals, and git data, without having to run an external command.
+//
+type ScriptModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=script"`
+	// Script is the expression to evaluate.
+	Script string `yaml:"script" jsonschema:",required"`
+
+	program *script.Program
+}
+
+type scriptModuleResult struct {
+	// Value is the value the script returned.
+	Value interface{}
+}
+
+// scriptGitInfo is the `git` variable available to scripts.
+type scriptGitInfo struct {
+	// Root is the root folder of the repo.
+	Root string
+	// Branch is the name of the current branch, or the tag or short hash if
+	// the HEAD is detached.
+	Branch string
+	// Detached is true if the HEAD is detached.
+	Detached bool
+	// Hash is the hash of the HEAD commit.
+	Hash string
+	// Upstream is the upstream of the current branch, or "" if there is none.
+	Upstream string
+	// State is the current state of the repo (e.g. "MERGING").
+	State string
+}
+
+// Execute the module.
+func (mod ScriptModule) Execute(context *Context) ModuleResult {
+	if mod.program == nil {
+		return ModuleResult{}
+	}
+
+	value, err := mod.program.Run(script.Environment{
+		Variables: map[string]interface{}{
+			"globals": context.Globals,
+			"git":     script.LazyValue(func() interface{} { return getScriptGitInfo(context) }),
+		},
+		Functions: map[string]script.Function{
+			"env": func(args []interface{}) (interface{}, error) {
+				if len(args) != 1 {
+					return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+				}
+				name, ok := args[0].(string)
+				if !ok {
+					return nil, fmt.Errorf("argument 1 must be a string")
+				}
+				return context.Getenv(name), nil
+			},
+		},
+	})
+	if err != nil {
+		return ModuleResult{Warnings: []string{fmt.Sprint("Error executing script module: ", err)}}
+	}
+
+	return ModuleResult{
+		DefaultText: script.ToString(value),
+		Data:        scriptModuleResult{Value: value},
+	}
+}
+
+// getScriptGitInfo returns information about the current git repo, or nil if
+// the current folder is not in a git repo.
+func getScriptGitInfo(context *Context) interface{} {
+	git := context.Git()
+	if git == nil {
+		return nil
+	}
+
+	info := scriptGitInfo{
+		Root:  git.RepoRoot(),
+		State: string(git.State().State),
+	}
+
+	head, err := git.Head(0)
+	if err == nil {
+		info.Branch = head.Description
+		info.Detached = head.Detached
+		info.Hash = head.Hash
+		if !head.Detached {
+			info.Upstream = git.GetUpstream(head.Description)
+		}
+	}
+
+	return info
+}
+
+func init() {
+	registerModule(
+		"script",
+		registeredModule{
+			jsonSchema: schemas.ScriptModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := ScriptModule{Type: "script"}
+				err := node.Decode(&module)
+				if err != nil {
+					return &module, err
+				}
+
+				module.program, err = script.Compile(module.Script)
+				if err != nil {
+					return &module, fmt.Errorf("invalid script (%d:%d): %w", node.Line, node.Column, err)
+				}
+				return &module, nil
+			},
+		},
+	)
+}
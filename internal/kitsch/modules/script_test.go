@@ -0,0 +1,66 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/gitutils"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestScript(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: script
+		script: 'env("USER") == "jwalton" ? upper(globals.hostname) : "?"'
+	`))
+	context := newTestContext("jwalton")
+
+	result := mod.Execute(context)
+	assert.Equal(t, "LUCID", result.Text)
+	assert.Equal(t, scriptModuleResult{Value: "LUCID"}, result.Data)
+}
+
+func TestScriptGit(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: script
+		script: 'git ? trimPrefix(git.branch, "feature/") + (git.state ? "|" + git.state : "") : "no repo"'
+	`))
+
+	context := NewDemoContext(
+		DemoConfig{
+			Git: gitutils.DemoGit{
+				HeadDescription: "feature/widgets",
+				CurrentState:    gitutils.StateMerging,
+			},
+		},
+		&styling.Registry{},
+	)
+	result := mod.Execute(&context)
+	assert.Equal(t, "widgets|MERGING", result.Text)
+
+	context = NewDemoContext(DemoConfig{}, &styling.Registry{})
+	result = mod.Execute(&context)
+	assert.Equal(t, "no repo", result.Text)
+}
+
+func TestScriptRuntimeError(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: script
+		script: 'upper(globals.jobs)'
+	`))
+	context := newTestContext("jwalton")
+
+	result := mod.Execute(context)
+	assert.Equal(t, "", result.Text)
+}
+
+func TestScriptCompileError(t *testing.T) {
+	var wrapper ModuleWrapper
+	err := yaml.Unmarshal([]byte(heredoc.Doc(`
+		type: script
+		script: '1 +'
+	`)), &wrapper)
+	assert.EqualError(t, err, "invalid script (1:1): 4: unexpected end of script")
+}
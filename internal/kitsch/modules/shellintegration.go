@@ -0,0 +1,88 @@
+package modules
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
+)
+
+// ShellIntegration configures escape sequences that kitsch writes before the
+// prompt to tell the terminal about the state of the shell.  Terminals like
+// iTerm2 and WezTerm use these for features like opening a new tab in the same
+// folder, "reveal in Finder", and switching profiles based on the current
+// host.  Shells normally send these from their own integration scripts, which
+// kitsch replaces.
+type ShellIntegration struct {
+	// Enabled turns on OSC 7 and OSC 1337 "CurrentDir" and "RemoteHost"
+	// sequences.
+	Enabled bool `yaml:"enabled"`
+	// UserVars is a map of user variables to set with OSC 1337 "SetUserVar".
+	// Values are templates, which are executed with the same data as a
+	// module template, except with no `.Data`.
+	UserVars map[string]string `yaml:"userVars,omitempty"`
+}
+
+// Render returns the escape sequences to write before the prompt.
+func (integration *ShellIntegration) Render(context *Context) string {
+	if integration == nil || !integration.Enabled {
+		return ""
+	}
+
+	var result strings.Builder
+
+	cwd := context.Globals.CWD
+	hostname := context.Globals.Hostname()
+
+	result.WriteString(osc("7;" + fileURL(hostname, cwd)))
+	result.WriteString(osc("1337;CurrentDir=" + cwd))
+	if username := (usernameModuleData{username: context.Environment.Getenv("USER")}).Username(); username != "" {
+		result.WriteString(osc("1337;RemoteHost=" + username + "@" + hostname))
+	}
+
+	names := make([]string, 0, len(integration.UserVars))
+	for name := range integration.UserVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, err := integration.renderUserVar(context, integration.UserVars[name])
+		if err != nil {
+			log.Warn(fmt.Sprintf("Error in shellIntegration user variable %q: %v", name, err))
+			continue
+		}
+		result.WriteString(osc("1337;SetUserVar=" + name + "=" + base64.StdEncoding.EncodeToString([]byte(value))))
+	}
+
+	return result.String()
+}
+
+func (integration *ShellIntegration) renderUserVar(context *Context, templateString string) (string, error) {
+	tmpl, err := modtemplate.CompileTemplate(context.Styles, context.Environment, context.Partials, "user-var", templateString)
+	if err != nil {
+		return "", err
+	}
+	return modtemplate.TemplateToString(tmpl, TemplateData{Globals: &context.Globals})
+}
+
+// osc returns an "operating system command" escape sequence.
+func osc(command string) string {
+	return "\x1b]" + command + "\a"
+}
+
+// fileURL returns a "file://" URL for the given path on the given host.
+func fileURL(hostname string, path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths, like "C:/Users".
+		path = "/" + path
+	}
+	fileURL := url.URL{Scheme: "file", Host: hostname, Path: path}
+	return fileURL.String()
+}
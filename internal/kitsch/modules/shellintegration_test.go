@@ -0,0 +1,41 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShellIntegration(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.Globals.CWD = "/Users/jwalton/my project"
+
+	integration := &ShellIntegration{
+		Enabled: true,
+		UserVars: map[string]string{
+			"shell": "{{ .Globals.Shell }}",
+		},
+	}
+
+	assert.Equal(t,
+		"\x1b]7;file://lucid/Users/jwalton/my%20project\a"+
+			"\x1b]1337;CurrentDir=/Users/jwalton/my project\a"+
+			"\x1b]1337;RemoteHost=jwalton@lucid\a"+
+			"\x1b]1337;SetUserVar=shell=YmFzaA==\a",
+		integration.Render(context),
+	)
+}
+
+func TestShellIntegrationDisabled(t *testing.T) {
+	context := newTestContext("jwalton")
+
+	var integration *ShellIntegration
+	assert.Equal(t, "", integration.Render(context))
+
+	integration = &ShellIntegration{UserVars: map[string]string{"shell": "bash"}}
+	assert.Equal(t, "", integration.Render(context))
+}
+
+func TestFileURL(t *testing.T) {
+	assert.Equal(t, "file://lucid/C:/Users/jwalton", fileURL("lucid", "C:/Users/jwalton"))
+}
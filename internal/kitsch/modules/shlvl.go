@@ -0,0 +1,83 @@
+package modules
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas ShlvlModule
+
+// ShlvlModule shows how deeply nested the current shell is, based on the
+// SHLVL environment variable.  Nothing is shown unless SHLVL is greater than
+// or equal to "Threshold".
+//
+type ShlvlModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=shlvl"`
+	// Symbol is the symbol to show before the shell level.  Defaults to "↕ ".
+	Symbol string `yaml:"symbol"`
+	// Threshold is the minimum shell level to show.  Defaults to 2.
+	Threshold int `yaml:"threshold"`
+	// Repeat, if true, will show the symbol once for each level, instead of
+	// showing the symbol followed by the level (e.g. "❯❯❯" instead of "❯3").
+	Repeat bool `yaml:"repeat"`
+	// RepeatOffset is subtracted from the level when repeating the symbol.
+	// For example, if this is 1, a level 3 shell will show the symbol twice.
+	RepeatOffset int `yaml:"repeatOffset"`
+}
+
+type shlvlModuleResult struct {
+	// Level is the current shell level, or 0 if SHLVL is not set.
+	Level int
+	// Show is true if the level is greater than or equal to the threshold.
+	Show bool
+}
+
+// Execute the module.
+func (mod ShlvlModule) Execute(context *Context) ModuleResult {
+	level, err := strconv.Atoi(strings.TrimSpace(context.Getenv("SHLVL")))
+	if err != nil {
+		level = 0
+	}
+	show := level > 0 && level >= mod.Threshold
+
+	defaultText := ""
+	if show {
+		if mod.Repeat {
+			if count := level - mod.RepeatOffset; count > 0 {
+				defaultText = strings.Repeat(mod.Symbol, count)
+			}
+		} else {
+			defaultText = mod.Symbol + strconv.Itoa(level)
+		}
+	}
+
+	return ModuleResult{
+		DefaultText: defaultText,
+		Data: shlvlModuleResult{
+			Level: level,
+			Show:  show,
+		},
+	}
+}
+
+func init() {
+	registerModule(
+		"shlvl",
+		registeredModule{
+			jsonSchema: schemas.ShlvlModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := ShlvlModule{
+					Type:      "shlvl",
+					Symbol:    "↕ ",
+					Threshold: 2,
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,39 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShlvl(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: shlvl
+	`)).(*ShlvlModule)
+
+	result := mod.Execute(newTestContextWith(map[string]string{"SHLVL": "1"}, nil))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, shlvlModuleResult{Level: 1, Show: false}, result.Data)
+
+	result = mod.Execute(newTestContextWith(map[string]string{"SHLVL": "3"}, nil))
+	assert.Equal(t, "↕ 3", result.DefaultText)
+	assert.Equal(t, shlvlModuleResult{Level: 3, Show: true}, result.Data)
+
+	result = mod.Execute(newTestContextWith(map[string]string{"SHLVL": ""}, nil))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, shlvlModuleResult{Level: 0, Show: false}, result.Data)
+}
+
+func TestShlvlRepeat(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: shlvl
+		symbol: "❯"
+		repeat: true
+	`)).(*ShlvlModule)
+
+	assert.Equal(t, "❯❯❯", mod.Execute(newTestContextWith(map[string]string{"SHLVL": "3"}, nil)).DefaultText)
+
+	mod.RepeatOffset = 1
+	assert.Equal(t, "❯❯", mod.Execute(newTestContextWith(map[string]string{"SHLVL": "3"}, nil)).DefaultText)
+}
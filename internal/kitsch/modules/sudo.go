@@ -0,0 +1,159 @@
+package modules
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas SudoModule
+
+// SudoModule shows a warning when sudo has cached credentials, so the next
+// `sudo` command would run without asking for a password.
+//
+type SudoModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=sudo"`
+	// Symbol is the text to show when sudo credentials are cached.  Defaults
+	// to "⚠ ".
+	Symbol string `yaml:"symbol"`
+	// CheckTimeout is the maximum time to wait for sudo to respond, in
+	// milliseconds.  Defaults to 500.
+	CheckTimeout int `yaml:"checkTimeout"`
+	// CacheDuration is how long to remember the result, in milliseconds, so we
+	// don't need to run sudo every time the prompt is shown.  Defaults to 5000.
+	// Set to 0 to check every time.
+	CacheDuration int `yaml:"cacheDuration"`
+	// checkCredentials is used to check if credentials are cached.  This is
+	// used for unit testing.
+	checkCredentials func(timeout time.Duration) bool
+	// now returns the current time.  This is used for unit testing.
+	now func() time.Time
+}
+
+type sudoModuleResult struct {
+	// Cached is true if sudo credentials are currently cached.
+	Cached bool
+}
+
+// Execute the module.
+func (mod SudoModule) Execute(context *Context) ModuleResult {
+	cached := mod.credentialsCached(context)
+
+	text := ""
+	if cached {
+		text = mod.Symbol
+	}
+
+	return ModuleResult{DefaultText: text, Data: sudoModuleResult{Cached: cached}}
+}
+
+// credentialsCached returns true if sudo credentials are cached, using a
+// previous result from the value cache if it is recent enough.
+func (mod SudoModule) credentialsCached(context *Context) bool {
+	now := time.Now
+	if mod.now != nil {
+		now = mod.now
+	}
+
+	cacheDuration := time.Duration(mod.CacheDuration) * time.Millisecond
+
+	// sudo normally caches credentials per terminal, so cache the result per
+	// shell session.
+	cacheKey := "sudo-cached:" + context.Getenv("KITSCH_SESSION_KEY")
+	valueCache := context.ValueCache
+	if cacheDuration > 0 && valueCache != nil {
+		if checkedAt, cached, ok := parseSudoCacheValue(valueCache.Get(cacheKey)); ok &&
+			now().Sub(checkedAt) < cacheDuration {
+			return cached
+		}
+	}
+
+	check := mod.checkCredentials
+	if check == nil {
+		check = func(timeout time.Duration) bool {
+			return sudoCredentialsCached(context.Environment, timeout)
+		}
+	}
+	cached := check(time.Duration(mod.CheckTimeout) * time.Millisecond)
+
+	if cacheDuration > 0 && valueCache != nil {
+		valueCache.Set(cacheKey, []byte(strconv.FormatInt(now().UnixNano(), 10)+" "+strconv.FormatBool(cached)))
+	}
+
+	return cached
+}
+
+// parseSudoCacheValue parses a value written to the cache by credentialsCached.
+func parseSudoCacheValue(value []byte) (checkedAt time.Time, cached bool, ok bool) {
+	parts := strings.Fields(string(value))
+	if len(parts) != 2 {
+		return checkedAt, false, false
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return checkedAt, false, false
+	}
+	cached, err = strconv.ParseBool(parts[1])
+	if err != nil {
+		return checkedAt, false, false
+	}
+
+	return time.Unix(0, nanos), cached, true
+}
+
+// sudoCredentialsCached runs `sudo -n true`, which will succeed without
+// prompting if credentials are cached, and fail otherwise.
+func sudoCredentialsCached(environment env.Env, timeout time.Duration) bool {
+	if environment.OS() == "windows" {
+		return false
+	}
+
+	sudo, err := environment.LookPath("sudo")
+	if err != nil {
+		return false
+	}
+
+	cmd := exec.Command(sudo, "-n", "true")
+	if err := cmd.Start(); err != nil {
+		return false
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err == nil
+	case <-time.After(timeout):
+		_ = cmd.Process.Kill()
+		return false
+	}
+}
+
+func init() {
+	registerModule(
+		"sudo",
+		registeredModule{
+			jsonSchema: schemas.SudoModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := SudoModule{
+					Type:          "sudo",
+					Symbol:        "⚠ ",
+					CheckTimeout:  500,
+					CacheDuration: 5000,
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,83 @@
+package modules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSudo(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: sudo
+	`)).(*SudoModule)
+
+	checks := 0
+	mod.checkCredentials = func(timeout time.Duration) bool {
+		checks++
+		assert.Equal(t, 500*time.Millisecond, timeout)
+		return true
+	}
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "⚠ ", result.DefaultText)
+	assert.Equal(t, sudoModuleResult{Cached: true}, result.Data)
+	assert.Equal(t, 1, checks)
+}
+
+func TestSudoNotCached(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: sudo
+	`)).(*SudoModule)
+	mod.checkCredentials = func(timeout time.Duration) bool { return false }
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, sudoModuleResult{Cached: false}, result.Data)
+}
+
+func TestSudoCachesResult(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: sudo
+		cacheDuration: 1000
+	`)).(*SudoModule)
+
+	now := time.Unix(1000, 0)
+	mod.now = func() time.Time { return now }
+
+	cachedCredentials := true
+	checks := 0
+	mod.checkCredentials = func(timeout time.Duration) bool {
+		checks++
+		return cachedCredentials
+	}
+
+	context := newTestContext("jwalton")
+
+	// First check runs sudo.
+	assert.Equal(t, sudoModuleResult{Cached: true}, mod.Execute(context).Data)
+	assert.Equal(t, 1, checks)
+
+	// Within the cache duration, the previous result is used.
+	cachedCredentials = false
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, sudoModuleResult{Cached: true}, mod.Execute(context).Data)
+	assert.Equal(t, 1, checks)
+
+	// Once the cache expires, we check again.
+	now = now.Add(time.Second)
+	assert.Equal(t, sudoModuleResult{Cached: false}, mod.Execute(context).Data)
+	assert.Equal(t, 2, checks)
+}
+
+func TestSudoCredentialsCachedOnWindows(t *testing.T) {
+	environment := env.NewRecordingEnv(&env.DummyEnv{
+		GOOS:        "windows",
+		Executables: map[string]string{"sudo": "/usr/bin/sudo"},
+	})
+
+	assert.False(t, sudoCredentialsCached(environment, time.Second))
+	assert.False(t, environment.Called("LookPath"))
+}
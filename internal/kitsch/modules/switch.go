@@ -0,0 +1,77 @@
+package modules
+
+import (
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"github.com/jwalton/kitsch/internal/perf"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas SwitchModule
+
+// SwitchModule renders the first of its child modules that produces any
+// output.  Children are run one at a time, in order, and once one produces
+// output the rest are never run.  A child whose conditions don't match
+// produces no output, so conditions can be used to pick which child is shown.
+//
+type SwitchModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=switch"`
+	// Modules is a list of child modules to try, in order.
+	Modules []ModuleWrapper `yaml:"modules" jsonschema:",required,ref=ModulesList"`
+}
+
+type switchModuleResult struct {
+	// Index is the index of the child module that was shown, or -1 if no
+	// child produced any output.
+	Index int
+	// Module is the result of the child module that was shown.
+	Module ModuleWrapperResult
+}
+
+// Execute the module.
+func (mod SwitchModule) Execute(context *Context) ModuleResult {
+	performance := perf.New(len(mod.Modules))
+
+	for index := range mod.Modules {
+		child := &mod.Modules[index]
+		childResult := child.Execute(context)
+		performance.Add(child.String(), childResult.Duration, childResult.Performance)
+
+		if childResult.Text != "" {
+			return ModuleResult{
+				DefaultText: childResult.Text,
+				Data:        switchModuleResult{Index: index, Module: childResult},
+				Performance: performance,
+				StartStyle:  childResult.StartStyle,
+				EndStyle:    childResult.EndStyle,
+			}
+		}
+	}
+
+	return ModuleResult{
+		Data:        switchModuleResult{Index: -1},
+		Performance: performance,
+	}
+}
+
+func (mod SwitchModule) childModules() []*ModuleWrapper {
+	children := make([]*ModuleWrapper, len(mod.Modules))
+	for index := range mod.Modules {
+		children[index] = &mod.Modules[index]
+	}
+	return children
+}
+
+func init() {
+	registerModule(
+		"switch",
+		registeredModule{
+			jsonSchema: schemas.SwitchModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := SwitchModule{Type: "switch"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,63 @@
+package modules
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var switchTestConfig = heredoc.Doc(`
+	type: switch
+	modules:
+	- type: text
+	  text: ""
+	- type: text
+	  text: go
+	  conditions:
+	    ifFiles: ["go.mod"]
+	- type: text
+	  text: fallback
+	- type: text
+	  text: never
+`)
+
+func TestSwitchFirstMatch(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.Directory = fileutils.NewDirectoryTestFS("/Users/jwalton", fstest.MapFS{
+		"go.mod": &fstest.MapFile{Data: []byte("module example\n")},
+	})
+
+	result := moduleWrapperFromYAML(switchTestConfig).Execute(context)
+	assert.Equal(t, "go", result.Text)
+	assert.Equal(t, 1, result.Data.(switchModuleResult).Index)
+}
+
+func TestSwitchFallback(t *testing.T) {
+	result := moduleWrapperFromYAML(switchTestConfig).Execute(newTestContext("jwalton"))
+	assert.Equal(t, "fallback", result.Text)
+	assert.Equal(t, 2, result.Data.(switchModuleResult).Index)
+}
+
+func TestSwitchSkipsRemainingModules(t *testing.T) {
+	count := 0
+	mod := moduleFromYAML(switchTestConfig).(*SwitchModule)
+	mod.Modules[3].Module = countingModule{count: &count}
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "fallback", result.DefaultText)
+	assert.Equal(t, 0, count)
+}
+
+func TestSwitchNoOutput(t *testing.T) {
+	result := moduleWrapperFromYAML(heredoc.Doc(`
+		type: switch
+		modules:
+		- type: text
+		  text: ""
+	`)).Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.Text)
+	assert.Equal(t, -1, result.Data.(switchModuleResult).Index)
+}
@@ -0,0 +1,191 @@
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas SysinfoModule
+
+// SysinfoModule shows memory usage and load average.  By default, this only
+// shows something when the machine is under pressure.  This is supported on
+// Linux and macOS.
+//
+type SysinfoModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=sysinfo"`
+	// MemorySymbol is shown before the memory usage.  Defaults to "mem ".
+	MemorySymbol string `yaml:"memorySymbol"`
+	// MemoryThreshold is the memory usage percentage at or above which memory
+	// usage will be shown.  Defaults to 90.
+	MemoryThreshold float64 `yaml:"memoryThreshold"`
+	// LoadSymbol is shown before the load average.  Defaults to "load ".
+	LoadSymbol string `yaml:"loadSymbol"`
+	// LoadThreshold is the one minute load average at or above which the load
+	// average will be shown.  Defaults to the number of CPUs.
+	LoadThreshold float64 `yaml:"loadThreshold"`
+	// readInfo is used to read system information.  This is used for unit testing.
+	readInfo func() (systemInfo, error)
+}
+
+// systemInfo is the information read from the operating system.
+type systemInfo struct {
+	// MemoryTotal is the total amount of memory, in bytes.
+	MemoryTotal uint64
+	// MemoryAvailable is the amount of memory available to start new
+	// programs, in bytes.
+	MemoryAvailable uint64
+	// Load is the 1, 5, and 15 minute load average.
+	Load [3]float64
+}
+
+type sysinfoModuleResult struct {
+	// MemoryUsedPercent is the percentage of memory in use.
+	MemoryUsedPercent float64
+	// MemoryTotal is the total amount of memory, in bytes.
+	MemoryTotal uint64
+	// MemoryAvailable is the amount of memory available, in bytes.
+	MemoryAvailable uint64
+	// Load1 is the one minute load average.
+	Load1 float64
+	// Load5 is the five minute load average.
+	Load5 float64
+	// Load15 is the fifteen minute load average.
+	Load15 float64
+	// CPUs is the number of CPUs.
+	CPUs int
+	// ShowMemory is true if memory usage is at or above the threshold.
+	ShowMemory bool
+	// ShowLoad is true if the load average is at or above the threshold.
+	ShowLoad bool
+}
+
+// errSysinfoUnsupported is returned by readSystemInfo on platforms we can't
+// read system information from.
+var errSysinfoUnsupported = errors.New("sysinfo is not supported on " + runtime.GOOS)
+
+// Execute the module.
+func (mod SysinfoModule) Execute(context *Context) ModuleResult {
+	readInfo := mod.readInfo
+	if readInfo == nil {
+		readInfo = readSystemInfo
+	}
+
+	info, err := readInfo()
+	if err == errSysinfoUnsupported {
+		return ModuleResult{Data: sysinfoModuleResult{}}
+	} else if err != nil {
+		return ModuleResult{
+			Data:     sysinfoModuleResult{},
+			Warnings: []string{fmt.Sprint("Error reading system information: ", err)},
+		}
+	}
+
+	data := sysinfoModuleResult{
+		MemoryTotal:     info.MemoryTotal,
+		MemoryAvailable: info.MemoryAvailable,
+		Load1:           info.Load[0],
+		Load5:           info.Load[1],
+		Load15:          info.Load[2],
+		CPUs:            runtime.NumCPU(),
+	}
+	if info.MemoryTotal > 0 && info.MemoryAvailable <= info.MemoryTotal {
+		data.MemoryUsedPercent = float64(info.MemoryTotal-info.MemoryAvailable) * 100 / float64(info.MemoryTotal)
+	}
+	data.ShowMemory = data.MemoryTotal > 0 && data.MemoryUsedPercent >= mod.MemoryThreshold
+	data.ShowLoad = data.Load1 >= mod.LoadThreshold
+
+	parts := []string{}
+	if data.ShowMemory {
+		parts = append(parts, mod.MemorySymbol+strconv.FormatFloat(data.MemoryUsedPercent, 'f', 0, 64)+"%")
+	}
+	if data.ShowLoad {
+		parts = append(parts, mod.LoadSymbol+strconv.FormatFloat(data.Load1, 'f', 2, 64))
+	}
+
+	return ModuleResult{DefaultText: strings.Join(parts, " "), Data: data}
+}
+
+// parseMeminfo parses the contents of /proc/meminfo on Linux.
+func parseMeminfo(contents []byte) (total uint64, available uint64, err error) {
+	values := map[string]uint64{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 2 && fields[2] == "kB" {
+			value *= 1024
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = value
+	}
+
+	total, ok := values["MemTotal"]
+	if !ok {
+		return 0, 0, errors.New("MemTotal missing from /proc/meminfo")
+	}
+
+	available, ok = values["MemAvailable"]
+	if !ok {
+		// Older kernels don't have MemAvailable.
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+
+	return total, available, nil
+}
+
+// parseLoadavg parses the load averages from the output of /proc/loadavg on
+// Linux (e.g. "0.52 0.58 0.59 1/467 12345"), or `sysctl -n vm.loadavg` on
+// macOS (e.g. "{ 1.79 2.04 2.13 }").
+func parseLoadavg(contents string) ([3]float64, error) {
+	var load [3]float64
+
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(contents), "{}"))
+	if len(fields) < 3 {
+		return load, fmt.Errorf("invalid load average %q", contents)
+	}
+
+	for index := range load {
+		value, err := strconv.ParseFloat(fields[index], 64)
+		if err != nil {
+			return load, fmt.Errorf("invalid load average %q", contents)
+		}
+		load[index] = value
+	}
+
+	return load, nil
+}
+
+func init() {
+	registerModule(
+		"sysinfo",
+		registeredModule{
+			jsonSchema: schemas.SysinfoModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := SysinfoModule{
+					Type:            "sysinfo",
+					MemorySymbol:    "mem ",
+					MemoryThreshold: 90,
+					LoadSymbol:      "load ",
+					LoadThreshold:   float64(runtime.NumCPU()),
+				}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
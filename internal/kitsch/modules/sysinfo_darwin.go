@@ -0,0 +1,41 @@
+//go:build darwin
+// +build darwin
+
+package modules
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// readSystemInfo reads memory and load information with `sysctl`.
+func readSystemInfo() (systemInfo, error) {
+	info := systemInfo{}
+
+	output, err := exec.Command("/usr/sbin/sysctl", "-n", "hw.memsize", "kern.memorystatus_level", "vm.loadavg").Output()
+	if err != nil {
+		return info, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) != 3 {
+		return info, fmt.Errorf("unexpected output from sysctl: %q", output)
+	}
+
+	info.MemoryTotal, err = strconv.ParseUint(strings.TrimSpace(lines[0]), 10, 64)
+	if err != nil {
+		return info, err
+	}
+
+	// kern.memorystatus_level is the percentage of memory that is available.
+	level, err := strconv.ParseUint(strings.TrimSpace(lines[1]), 10, 64)
+	if err != nil {
+		return info, err
+	}
+	info.MemoryAvailable = info.MemoryTotal / 100 * level
+
+	info.Load, err = parseLoadavg(lines[2])
+	return info, err
+}
@@ -0,0 +1,29 @@
+//go:build linux
+// +build linux
+
+package modules
+
+import (
+	"os"
+)
+
+// readSystemInfo reads memory and load information from /proc.
+func readSystemInfo() (systemInfo, error) {
+	info := systemInfo{}
+
+	meminfo, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return info, err
+	}
+	info.MemoryTotal, info.MemoryAvailable, err = parseMeminfo(meminfo)
+	if err != nil {
+		return info, err
+	}
+
+	loadavg, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return info, err
+	}
+	info.Load, err = parseLoadavg(string(loadavg))
+	return info, err
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package modules
+
+func readSystemInfo() (systemInfo, error) {
+	return systemInfo{}, errSysinfoUnsupported
+}
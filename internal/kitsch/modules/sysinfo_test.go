@@ -0,0 +1,108 @@
+package modules
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSysinfo(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: sysinfo
+		loadThreshold: 4
+	`)).(*SysinfoModule)
+
+	mod.readInfo = func() (systemInfo, error) {
+		return systemInfo{
+			MemoryTotal:     16 * 1024 * 1024 * 1024,
+			MemoryAvailable: 1 * 1024 * 1024 * 1024,
+			Load:            [3]float64{4.5, 3.25, 2},
+		}, nil
+	}
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "mem 94% load 4.50", result.DefaultText)
+	assert.Equal(t, sysinfoModuleResult{
+		MemoryUsedPercent: 93.75,
+		MemoryTotal:       16 * 1024 * 1024 * 1024,
+		MemoryAvailable:   1 * 1024 * 1024 * 1024,
+		Load1:             4.5,
+		Load5:             3.25,
+		Load15:            2,
+		CPUs:              runtime.NumCPU(),
+		ShowMemory:        true,
+		ShowLoad:          true,
+	}, result.Data)
+}
+
+func TestSysinfoBelowThreshold(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: sysinfo
+		loadThreshold: 4
+	`)).(*SysinfoModule)
+
+	mod.readInfo = func() (systemInfo, error) {
+		return systemInfo{MemoryTotal: 100, MemoryAvailable: 50, Load: [3]float64{1, 1, 1}}, nil
+	}
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, float64(50), result.Data.(sysinfoModuleResult).MemoryUsedPercent)
+}
+
+func TestSysinfoErrors(t *testing.T) {
+	mod := moduleFromYAML(`type: sysinfo`).(*SysinfoModule)
+
+	mod.readInfo = func() (systemInfo, error) { return systemInfo{}, errSysinfoUnsupported }
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Empty(t, result.Warnings)
+
+	mod.readInfo = func() (systemInfo, error) { return systemInfo{}, errors.New("boom") }
+	result = mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, []string{"Error reading system information: boom"}, result.Warnings)
+}
+
+func TestParseMeminfo(t *testing.T) {
+	total, available, err := parseMeminfo([]byte(heredoc.Doc(`
+		MemTotal:       16318424 kB
+		MemFree:         1234567 kB
+		MemAvailable:    8159212 kB
+		Buffers:          123456 kB
+	`)))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(16318424*1024), total)
+	assert.Equal(t, uint64(8159212*1024), available)
+
+	// Older kernels have no MemAvailable.
+	total, available, err = parseMeminfo([]byte(heredoc.Doc(`
+		MemTotal:       1000 kB
+		MemFree:         100 kB
+		Buffers:          20 kB
+		Cached:          300 kB
+	`)))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1000*1024), total)
+	assert.Equal(t, uint64(420*1024), available)
+
+	_, _, err = parseMeminfo([]byte("MemFree: 100 kB\n"))
+	assert.Error(t, err)
+}
+
+func TestParseLoadavg(t *testing.T) {
+	load, err := parseLoadavg("0.52 0.58 0.59 1/467 12345\n")
+	require.NoError(t, err)
+	assert.Equal(t, [3]float64{0.52, 0.58, 0.59}, load)
+
+	load, err = parseLoadavg("{ 1.79 2.04 2.13 }\n")
+	require.NoError(t, err)
+	assert.Equal(t, [3]float64{1.79, 2.04, 2.13}, load)
+
+	_, err = parseLoadavg("{ 1.79 }")
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package modules
+
+import (
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
+)
+
+// compiledTemplate is a template which is compiled at most once per context.
+type compiledTemplate struct {
+	once sync.Once
+	tmpl *template.Template
+	err  error
+}
+
+// templateKey identifies a template in the context's template cache.
+type templateKey struct {
+	name   string
+	source string
+}
+
+// compileTemplate returns the compiled version of the given template.  Each
+// template is only compiled once per context, no matter how many modules use
+// it or how many times those modules are executed.
+func (context *Context) compileTemplate(name string, source string) (*template.Template, error) {
+	key := templateKey{name: name, source: source}
+
+	context.templatesMutex.Lock()
+	if context.templates == nil {
+		context.templates = map[templateKey]*compiledTemplate{}
+	}
+	compiled := context.templates[key]
+	if compiled == nil {
+		compiled = &compiledTemplate{}
+		context.templates[key] = compiled
+	}
+	context.templatesMutex.Unlock()
+
+	compiled.once.Do(func() {
+		compiled.tmpl, compiled.err = modtemplate.CompileTemplate(
+			context.Styles,
+			context.Environment,
+			context.Partials,
+			name,
+			source,
+		)
+	})
+	return compiled.tmpl, compiled.err
+}
+
+// PrecompileTemplates starts compiling every template in `root` and its
+// descendants in the background, so they are ready by the time the modules
+// that use them finish executing.  This returns immediately; a module which
+// needs a template before it has been compiled will wait for it.  This is
+// called by RenderPrompt, so only needs to be called when executing part of a
+// prompt on its own.
+func (context *Context) PrecompileTemplates(root ModuleWrapper) {
+	var keys []templateKey
+	seen := map[templateKey]bool{}
+	add := func(name string, source string) {
+		key := templateKey{name: name, source: source}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	var search func(wrapper *ModuleWrapper)
+	search = func(wrapper *ModuleWrapper) {
+		if wrapper.config.Template != "" {
+			add(moduleTemplateName, wrapper.config.Template)
+		}
+		if block, ok := wrapper.Module.(*BlockModule); ok && strings.Contains(block.Join, "{{") {
+			add(joinTemplateName, block.Join)
+		}
+		if parent, ok := wrapper.Module.(parentModule); ok {
+			for _, child := range parent.childModules() {
+				search(child)
+			}
+		}
+	}
+	search(&root)
+
+	if len(keys) == 0 {
+		return
+	}
+
+	indexes := make(chan int, len(keys))
+	for index := range keys {
+		indexes <- index
+	}
+	close(indexes)
+
+	workers := context.workerCount()
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+	for worker := 0; worker < workers; worker++ {
+		go func() {
+			for index := range indexes {
+				_, _ = context.compileTemplate(keys[index].name, keys[index].source)
+			}
+		}()
+	}
+}
@@ -0,0 +1,52 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileTemplateOnce(t *testing.T) {
+	context := newTestContext("jwalton")
+
+	first, err := context.compileTemplate(moduleTemplateName, "{{ .Text }}!")
+	assert.NoError(t, err)
+	second, err := context.compileTemplate(moduleTemplateName, "{{ .Text }}!")
+	assert.NoError(t, err)
+	assert.Same(t, first, second)
+
+	other, err := context.compileTemplate(joinTemplateName, "{{ .Text }}!")
+	assert.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	_, err = context.compileTemplate(moduleTemplateName, "{{ .Text ")
+	assert.Error(t, err)
+	_, err = context.compileTemplate(moduleTemplateName, "{{ .Text ")
+	assert.Error(t, err)
+}
+
+func TestPrecompileTemplates(t *testing.T) {
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		join: "{{ if .Index }}|{{ end }}"
+		modules:
+		- type: text
+		  text: hello
+		  template: "{{ .Text | upper }}"
+		- type: text
+		  text: world
+		  template: "{{ .Text | upper }}"
+		- type: text
+		  text: "!"
+	`))
+
+	context := newTestContext("jwalton")
+	_, text := RenderPrompt(context, root)
+	assert.Equal(t, "HELLO|WORLD|!", text)
+	assert.Empty(t, context.Warnings())
+
+	context.templatesMutex.Lock()
+	defer context.templatesMutex.Unlock()
+	assert.Len(t, context.templates, 2)
+}
@@ -1,6 +1,10 @@
 package modules
 
 import (
+	"testing/fstest"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,3 +20,18 @@ func moduleWrapperFromYAML(data string) ModuleWrapper {
 	}
 	return moduleWrapper
 }
+
+// newTestContextWith returns a test context for "jwalton" where `environment`
+// is the only set of environment variables, and `files` are the contents of
+// the current directory.  If either is nil, the defaults from newTestContext
+// are used.
+func newTestContextWith(environment map[string]string, files fstest.MapFS) *Context {
+	context := newTestContext("jwalton")
+	if environment != nil {
+		context.Environment = &env.DummyEnv{Env: environment}
+	}
+	if files != nil {
+		context.Directory = fileutils.NewDirectoryTestFS(context.Globals.CWD, files)
+	}
+	return context
+}
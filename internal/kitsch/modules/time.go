@@ -38,7 +38,7 @@ type timeModuleData struct {
 
 // Execute the time module.
 func (mod TimeModule) Execute(context *Context) ModuleResult {
-	now := time.Now()
+	now := context.Now()
 
 	layout := mod.Layout
 	if layout == "" {
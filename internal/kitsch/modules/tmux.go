@@ -0,0 +1,153 @@
+package modules
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas TmuxModule
+
+// TmuxModule shows the name of the current tmux or screen session, and the
+// index of the current window.  This shows nothing if the shell isn't running
+// inside tmux or screen.
+//
+type TmuxModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=tmux"`
+	// Symbol is the symbol to show before the session name.  Defaults to "▣ ".
+	Symbol string `yaml:"symbol"`
+	// CacheDuration is how long to remember the session name and window
+	// index, in milliseconds, so we don't need to run `tmux` every time the
+	// prompt is shown.  Defaults to 5000.  Set to 0 to check every time.
+	CacheDuration int `yaml:"cacheDuration"`
+	// displayMessage runs `tmux display-message` for the given pane.  This is
+	// used for unit testing.
+	displayMessage func(pane string) (string, error)
+	// now returns the current time.  This is used for unit testing.
+	now func() time.Time
+}
+
+type tmuxModuleResult struct {
+	// Multiplexer is "tmux" or "screen", or "" if the shell is not running
+	// in a terminal multiplexer.
+	Multiplexer string
+	// Session is the name of the current session.
+	Session string
+	// Window is the index of the current window.
+	Window string
+}
+
+// Execute the module.
+func (mod TmuxModule) Execute(context *Context) ModuleResult {
+	data := tmuxModuleResult{}
+
+	if context.Getenv("TMUX") != "" {
+		data.Multiplexer = "tmux"
+		data.Session, data.Window = mod.tmuxSession(context)
+	} else if sty := context.Getenv("STY"); sty != "" {
+		// STY is "<pid>.<session name>".
+		data.Multiplexer = "screen"
+		data.Session = sty
+		if index := strings.Index(sty, "."); index != -1 {
+			data.Session = sty[index+1:]
+		}
+		data.Window = context.Getenv("WINDOW")
+	}
+
+	text := ""
+	if data.Multiplexer != "" {
+		text = mod.Symbol + data.Session
+		if data.Window != "" {
+			text += ":" + data.Window
+		}
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+// tmuxSession returns the session name and window index of the current tmux
+// pane, using a previous result from the value cache if it is recent enough.
+func (mod TmuxModule) tmuxSession(context *Context) (string, string) {
+	now := time.Now
+	if mod.now != nil {
+		now = mod.now
+	}
+
+	cacheDuration := time.Duration(mod.CacheDuration) * time.Millisecond
+
+	pane := context.Getenv("TMUX_PANE")
+	cacheKey := "tmux-session:" + context.Getenv("TMUX") + ":" + pane
+	valueCache := context.ValueCache
+	if cacheDuration > 0 && valueCache != nil {
+		parts := strings.SplitN(string(valueCache.Get(cacheKey)), "\t", 3)
+		if len(parts) == 3 {
+			if nanos, err := strconv.ParseInt(parts[0], 10, 64); err == nil &&
+				now().Sub(time.Unix(0, nanos)) < cacheDuration {
+				return parts[1], parts[2]
+			}
+		}
+	}
+
+	displayMessage := mod.displayMessage
+	if displayMessage == nil {
+		displayMessage = func(pane string) (string, error) {
+			return tmuxDisplayMessage(context.Environment, pane)
+		}
+	}
+	output, err := displayMessage(pane)
+	if err != nil {
+		return "", ""
+	}
+
+	session := strings.TrimSpace(output)
+	window := ""
+	if index := strings.LastIndex(session, "\t"); index != -1 {
+		window = session[index+1:]
+		session = session[:index]
+	}
+
+	if cacheDuration > 0 && valueCache != nil {
+		value := strconv.FormatInt(now().UnixNano(), 10) + "\t" + session + "\t" + window
+		valueCache.Set(cacheKey, []byte(value))
+	}
+
+	return session, window
+}
+
+// tmuxDisplayMessage asks tmux for the session name and window index of the
+// given pane, separated by a tab.
+func tmuxDisplayMessage(environment env.Env, pane string) (string, error) {
+	tmux, err := environment.LookPath("tmux")
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"display-message", "-p"}
+	if pane != "" {
+		args = append(args, "-t", pane)
+	}
+	args = append(args, "#S\t#I")
+
+	output, err := exec.Command(tmux, args...).Output()
+	return string(output), err
+}
+
+func init() {
+	registerModule(
+		"tmux",
+		registeredModule{
+			jsonSchema: schemas.TmuxModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := TmuxModule{Type: "tmux", Symbol: "▣ ", CacheDuration: 5000}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
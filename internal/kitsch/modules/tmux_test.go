@@ -0,0 +1,73 @@
+package modules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTmux(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: tmux
+	`)).(*TmuxModule)
+
+	calls := 0
+	mod.displayMessage = func(pane string) (string, error) {
+		calls++
+		assert.Equal(t, "%3", pane)
+		return "work\t2\n", nil
+	}
+	now := time.Unix(1000, 0)
+	mod.now = func() time.Time { return now }
+
+	context := newTestContext("jwalton")
+	context.Environment = &env.DummyEnv{Env: map[string]string{
+		"TMUX":      "/tmp/tmux-501/default,8112,0",
+		"TMUX_PANE": "%3",
+	}}
+
+	result := mod.Execute(context)
+	assert.Equal(t, "▣ work:2", result.DefaultText)
+	assert.Equal(t, tmuxModuleResult{Multiplexer: "tmux", Session: "work", Window: "2"}, result.Data)
+	assert.Equal(t, 1, calls)
+
+	// Should use the cached value.
+	now = now.Add(time.Second)
+	result = mod.Execute(context)
+	assert.Equal(t, "▣ work:2", result.DefaultText)
+	assert.Equal(t, 1, calls)
+
+	// Cached value should expire.
+	now = now.Add(5 * time.Second)
+	mod.Execute(context)
+	assert.Equal(t, 2, calls)
+}
+
+func TestScreen(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: tmux
+	`)).(*TmuxModule)
+
+	context := newTestContext("jwalton")
+	context.Environment = &env.DummyEnv{Env: map[string]string{
+		"STY":    "12345.pts-0.lucid",
+		"WINDOW": "1",
+	}}
+
+	result := mod.Execute(context)
+	assert.Equal(t, "▣ pts-0.lucid:1", result.DefaultText)
+	assert.Equal(t, tmuxModuleResult{Multiplexer: "screen", Session: "pts-0.lucid", Window: "1"}, result.Data)
+}
+
+func TestTmuxNotMultiplexed(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: tmux
+	`)).(*TmuxModule)
+
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, tmuxModuleResult{}, result.Data)
+}
@@ -0,0 +1,95 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas UmaskModule
+
+// UmaskModule shows the current umask when it is different from the expected
+// umask, so you notice before you create a pile of world-writable files.
+//
+type UmaskModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=umask"`
+	// Symbol is the symbol to show before the umask.  Defaults to "umask ".
+	Symbol string `yaml:"symbol"`
+	// Expected is the expected umask, in octal.  Nothing is shown when the
+	// umask matches this value.  Defaults to "022".
+	Expected string `yaml:"expected"`
+	// readUmask is used to read the current umask.  This is used for unit
+	// testing.
+	readUmask func() (int, error)
+}
+
+type umaskModuleResult struct {
+	// Umask is the current umask, in octal (e.g. "022").
+	Umask string
+	// Expected is the expected umask, in octal.
+	Expected string
+	// Unexpected is true if the umask differs from the expected umask.
+	Unexpected bool
+}
+
+// errUmaskUnsupported is returned by readUmask on platforms which don't have
+// a umask.
+var errUmaskUnsupported = errors.New("umask is not supported on " + runtime.GOOS)
+
+// Execute the module.
+func (mod UmaskModule) Execute(context *Context) ModuleResult {
+	expected, err := strconv.ParseUint(mod.Expected, 8, 32)
+	if err != nil {
+		return ModuleResult{
+			Data:     umaskModuleResult{},
+			Warnings: []string{fmt.Sprintf("Invalid expected umask %q: must be an octal number", mod.Expected)},
+		}
+	}
+
+	read := mod.readUmask
+	if read == nil {
+		read = readUmask
+	}
+
+	umask, err := read()
+	if err == errUmaskUnsupported {
+		return ModuleResult{Data: umaskModuleResult{}}
+	} else if err != nil {
+		return ModuleResult{
+			Data:     umaskModuleResult{},
+			Warnings: []string{fmt.Sprintf("Error reading umask: %v", err)},
+		}
+	}
+
+	data := umaskModuleResult{
+		Umask:      fmt.Sprintf("%03o", umask),
+		Expected:   fmt.Sprintf("%03o", expected),
+		Unexpected: uint64(umask) != expected,
+	}
+
+	text := ""
+	if data.Unexpected {
+		text = mod.Symbol + data.Umask
+	}
+
+	return ModuleResult{DefaultText: text, Data: data}
+}
+
+func init() {
+	registerModule(
+		"umask",
+		registeredModule{
+			jsonSchema: schemas.UmaskModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := UmaskModule{Type: "umask", Symbol: "umask ", Expected: "022"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
@@ -0,0 +1,60 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUmask(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: umask
+	`)).(*UmaskModule)
+
+	mod.readUmask = func() (int, error) { return 0022, nil }
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, umaskModuleResult{Umask: "022", Expected: "022"}, result.Data)
+
+	mod.readUmask = func() (int, error) { return 0, nil }
+	result = mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "umask 000", result.DefaultText)
+	assert.Equal(t, umaskModuleResult{Umask: "000", Expected: "022", Unexpected: true}, result.Data)
+}
+
+func TestUmaskExpected(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: umask
+		expected: "077"
+	`)).(*UmaskModule)
+
+	mod.readUmask = func() (int, error) { return 0077, nil }
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+
+	mod.readUmask = func() (int, error) { return 0022, nil }
+	result = mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "umask 022", result.DefaultText)
+}
+
+func TestUmaskInvalidExpected(t *testing.T) {
+	mod := moduleFromYAML(heredoc.Doc(`
+		type: umask
+		expected: "999"
+	`)).(*UmaskModule)
+
+	mod.readUmask = func() (int, error) { return 0022, nil }
+	result := mod.Execute(newTestContext("jwalton"))
+	assert.Equal(t, "", result.DefaultText)
+	assert.Equal(t, []string{`Invalid expected umask "999": must be an octal number`}, result.Warnings)
+}
+
+func TestReadUmask(t *testing.T) {
+	umask, err := readUmask()
+	if err == errUmaskUnsupported {
+		t.Skip("umask not supported")
+	}
+	assert.NoError(t, err)
+	assert.True(t, umask >= 0 && umask <= 0777)
+}
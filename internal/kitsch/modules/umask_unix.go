@@ -0,0 +1,36 @@
+//go:build !windows
+// +build !windows
+
+package modules
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func readUmask() (int, error) {
+	// On Linux, we can read the umask without changing it.
+	if file, err := os.Open("/proc/self/status"); err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "Umask:") {
+				umask, err := strconv.ParseInt(strings.TrimSpace(line[len("Umask:"):]), 8, 32)
+				if err == nil {
+					return int(umask), nil
+				}
+			}
+		}
+	}
+
+	// Otherwise the only way to read the umask is to set it.  We set it right
+	// back, but any file created in the meantime would get a umask of 0, so
+	// use the most restrictive umask possible while we do this.
+	umask := syscall.Umask(0777)
+	syscall.Umask(umask)
+	return umask, nil
+}
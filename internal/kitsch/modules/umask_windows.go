@@ -0,0 +1,5 @@
+package modules
+
+func readUmask() (int, error) {
+	return 0, errUmaskUnsupported
+}
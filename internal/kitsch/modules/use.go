@@ -0,0 +1,189 @@
+package modules
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas UseModule
+
+// UseModule shows the output of another module in the prompt, referenced by
+// its ID.  The referenced module is only executed once per render, no matter
+// how many places it is used.
+//
+// A "use" module doesn't need a type; `- use: git` is the same as
+// `- type: use\n  use: git`.
+//
+type UseModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",enum=use"`
+	// Use is the ID of the module to show.
+	Use string `yaml:"use" jsonschema:",required"`
+}
+
+// sharedModule is a module which is referenced by a "use" module.  A shared
+// module is executed at most once per render, and every place the module
+// appears gets the same result.
+type sharedModule struct {
+	// wrapper is the module being shared, or nil if the reference is invalid.
+	wrapper *ModuleWrapper
+	// err is the reason the reference is invalid.
+	err    string
+	once   sync.Once
+	result ModuleWrapperResult
+}
+
+// execute runs the shared module, or returns the result from a previous run.
+func (shared *sharedModule) execute(context *Context, wrapper ModuleWrapper) ModuleWrapperResult {
+	shared.once.Do(func() {
+		shared.result = wrapper.execute(context)
+	})
+	return shared.result
+}
+
+// Execute the module.
+func (mod UseModule) Execute(context *Context) ModuleResult {
+	shared := context.sharedModules[mod.Use]
+	if shared == nil {
+		return ModuleResult{Warnings: []string{fmt.Sprintf("No module with id %q", mod.Use)}}
+	}
+	if shared.wrapper == nil {
+		return ModuleResult{Warnings: []string{shared.err}}
+	}
+
+	result := shared.wrapper.Execute(context)
+
+	return ModuleResult{
+		DefaultText: result.Text,
+		Data:        result.Data,
+		StartStyle:  result.StartStyle,
+		EndStyle:    result.EndStyle,
+		Performance: result.Performance,
+	}
+}
+
+// ShareModules finds every module in `root` which is referenced by a "use"
+// module, so those modules can be used while executing modules with this
+// context.  This is called by RenderPrompt, so only needs to be called when
+// executing part of a prompt on its own.
+func (context *Context) ShareModules(root ModuleWrapper) {
+	context.sharedModules = findSharedModules(&root)
+}
+
+// findSharedModules finds every module in the prompt which is referenced by a
+// "use" module.
+func findSharedModules(root *ModuleWrapper) map[string]*sharedModule {
+	result := map[string]*sharedModule{}
+
+	// usedWithin maps each module (by YAML node) to the IDs used by "use"
+	// modules inside of it.
+	usedWithin := map[*yaml.Node][]string{}
+
+	var search func(wrapper *ModuleWrapper, ancestors []*ModuleWrapper)
+	search = func(wrapper *ModuleWrapper, ancestors []*ModuleWrapper) {
+		if use, ok := wrapper.Module.(*UseModule); ok {
+			if _, found := result[use.Use]; !found {
+				result[use.Use] = resolveUse(root, use.Use)
+			}
+			for _, ancestor := range ancestors {
+				if ancestor.YamlNode != nil {
+					usedWithin[ancestor.YamlNode] = append(usedWithin[ancestor.YamlNode], use.Use)
+				}
+			}
+			return
+		}
+
+		if parent, ok := wrapper.Module.(parentModule); ok {
+			ancestors = append(ancestors, wrapper)
+			for _, child := range parent.childModules() {
+				search(child, ancestors)
+			}
+		}
+	}
+	search(root, nil)
+
+	// A shared module which uses itself, directly or through some other shared
+	// module, would wait forever for its own result.
+	inLoop := map[string]bool{}
+	visited := map[string]bool{}
+	var stack []string
+	var visit func(id string)
+	visit = func(id string) {
+		for index, onStack := range stack {
+			if onStack == id {
+				for _, loopID := range stack[index:] {
+					inLoop[loopID] = true
+				}
+				return
+			}
+		}
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+
+		if shared := result[id]; shared != nil && shared.wrapper != nil {
+			stack = append(stack, id)
+			for _, dependency := range usedWithin[shared.wrapper.YamlNode] {
+				visit(dependency)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for id := range result {
+		visit(id)
+	}
+	for id := range inLoop {
+		result[id] = &sharedModule{err: fmt.Sprintf("Module %q is used inside itself", id)}
+	}
+
+	return result
+}
+
+// resolveUse finds the module with the given ID.
+func resolveUse(root *ModuleWrapper, id string) *sharedModule {
+	var find func(wrapper *ModuleWrapper) *ModuleWrapper
+	find = func(wrapper *ModuleWrapper) *ModuleWrapper {
+		if _, isUse := wrapper.Module.(*UseModule); isUse {
+			return nil
+		}
+		if wrapper.config.ID == id {
+			return wrapper
+		}
+		if parent, ok := wrapper.Module.(parentModule); ok {
+			for _, child := range parent.childModules() {
+				if found := find(child); found != nil {
+					return found
+				}
+			}
+		}
+		return nil
+	}
+
+	target := find(root)
+	if target == nil {
+		return &sharedModule{err: fmt.Sprintf("No module with id %q", id)}
+	}
+	if target.YamlNode == nil {
+		return &sharedModule{err: fmt.Sprintf("Module %s was not loaded from configuration and cannot be used", target)}
+	}
+
+	return &sharedModule{wrapper: target}
+}
+
+func init() {
+	registerModule(
+		"use",
+		registeredModule{
+			jsonSchema: schemas.UseModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := UseModule{Type: "use"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
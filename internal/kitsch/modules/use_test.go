@@ -0,0 +1,95 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUse(t *testing.T) {
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		- type: text
+		  id: greeting
+		  text: hello
+		- use: greeting
+		- use: greeting
+		  template: "{{ .Text | upper }}"
+	`))
+
+	context := newTestContext("jwalton")
+	_, text := RenderPrompt(context, root)
+	assert.Equal(t, "hello hello HELLO", text)
+	assert.Empty(t, context.Warnings())
+}
+
+func TestUseExecutesOnce(t *testing.T) {
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		- type: text
+		  id: counter
+		- type: block
+		  modules:
+		  - use: counter
+		  - use: counter
+	`))
+	count := 0
+	root.Module.(*BlockModule).Modules[0].Module = countingModule{count: &count}
+
+	_, text := RenderPrompt(newTestContext("jwalton"), root)
+	assert.Equal(t, "counted counted counted", text)
+	assert.Equal(t, 1, count)
+}
+
+func TestUseMissingModule(t *testing.T) {
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		- type: text
+		  text: hello
+		- use: missing
+	`))
+
+	context := newTestContext("jwalton")
+	_, text := RenderPrompt(context, root)
+	assert.Equal(t, "hello", text)
+	assert.Equal(t, []string{`No module with id "missing"`}, context.Warnings())
+}
+
+func TestUseLoop(t *testing.T) {
+	root := moduleWrapperFromYAML(heredoc.Doc(`
+		type: block
+		modules:
+		- type: block
+		  id: a
+		  modules:
+		  - type: text
+		    text: a
+		  - use: b
+		- type: block
+		  id: b
+		  modules:
+		  - type: text
+		    text: b
+		  - use: a
+		- type: block
+		  id: c
+		  modules:
+		  - type: text
+		    text: c
+		  - use: a
+		- use: c
+	`))
+
+	context := newTestContext("jwalton")
+	_, text := RenderPrompt(context, root)
+	assert.Equal(t, "a b c c", text)
+	assert.ElementsMatch(t, []string{
+		`Module "a" is used inside itself`,
+		`Module "b" is used inside itself`,
+		`Module "a" is used inside itself`,
+	}, context.Warnings())
+}
@@ -2,6 +2,8 @@ package modules
 
 import (
 	"os/user"
+	"runtime"
+	"strings"
 
 	"github.com/jwalton/kitsch/internal/kitsch/log"
 	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
@@ -19,6 +21,8 @@ import (
 //
 // • Username - The current user's username.
 //
+// • ShortUsername - The current user's username, without any domain.
+//
 // • IsRoot - True if the user is root, false otherwise.
 //
 // • IsSSH - True if this is an SSH session, false otherwise.
@@ -35,6 +39,9 @@ type UsernameModule struct {
 	// RootStyle will be used in place of `Style` if the current user is root.
 	// If this style is empty, will fall back to `Style`.
 	RootStyle string `yaml:"rootStyle"`
+	// Short will show the username without a domain, so on Windows only the
+	// part after the domain name is shown, and "user@domain" becomes "user".
+	Short bool `yaml:"short"`
 }
 
 type usernameModuleData struct {
@@ -64,6 +71,11 @@ func (data usernameModuleData) Username() string {
 	return user.Username
 }
 
+// ShortUsername is the current user's username, without any domain.
+func (data usernameModuleData) ShortUsername() string {
+	return shortUsername(data.Username())
+}
+
 // Execute the username module.
 func (mod UsernameModule) Execute(context *Context) ModuleResult {
 	isRoot := context.Globals.IsRoot
@@ -80,7 +92,11 @@ func (mod UsernameModule) Execute(context *Context) ModuleResult {
 	style := ""
 
 	if show {
-		defaultText = data.Username()
+		if mod.Short {
+			defaultText = data.ShortUsername()
+		} else {
+			defaultText = data.Username()
+		}
 		if isRoot && mod.RootStyle != "" {
 			style = mod.RootStyle
 		}
@@ -93,6 +109,51 @@ func (mod UsernameModule) Execute(context *Context) ModuleResult {
 	}
 }
 
+// currentUsername returns the current user's username.  This tries the
+// environment first, since asking the OS is slow.
+func currentUsername(getenv func(string) string) string {
+	if username := getenv("USER"); username != "" {
+		return username
+	}
+	if runtime.GOOS == "windows" {
+		if username := getenv("USERNAME"); username != "" {
+			return username
+		}
+	}
+
+	user, err := user.Current()
+	if err != nil {
+		log.Info("Unable to get current user: " + err.Error())
+		return ""
+	}
+	// On macOS this is the account's short name, and on Windows this is
+	// "DOMAIN\user".
+	return user.Username
+}
+
+// currentUserFullName returns the current user's full name.  This asks the
+// OS, so is slow.
+func currentUserFullName() string {
+	user, err := user.Current()
+	if err != nil {
+		log.Info("Unable to get current user: " + err.Error())
+		return ""
+	}
+	return user.Name
+}
+
+// shortUsername removes the domain from a username, so "DOMAIN\user" or
+// "user@domain" becomes "user".
+func shortUsername(username string) string {
+	if index := strings.LastIndex(username, "\\"); index != -1 {
+		username = username[index+1:]
+	}
+	if index := strings.Index(username, "@"); index > 0 {
+		username = username[:index]
+	}
+	return username
+}
+
 func init() {
 	registerModule(
 		"username",
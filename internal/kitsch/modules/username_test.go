@@ -56,3 +56,29 @@ func TestUsername(t *testing.T) {
 	)
 	assert.Equal(t, "jwalton", result.Data.(usernameModuleData).Username())
 }
+
+func TestUsernameShort(t *testing.T) {
+	mod := moduleWrapperFromYAML(heredoc.Doc(`
+		type: username
+		showAlways: true
+		short: true
+	`))
+
+	context := newTestContext("jwalton")
+	context.Environment = &env.DummyEnv{
+		Env: map[string]string{
+			"USER": `CORP\jwalton`,
+		},
+	}
+
+	result := mod.Execute(context)
+	assert.Equal(t, "jwalton", result.Text)
+	assert.Equal(t, `CORP\jwalton`, result.Data.(usernameModuleData).Username())
+}
+
+func TestShortUsername(t *testing.T) {
+	assert.Equal(t, "jwalton", shortUsername("jwalton"))
+	assert.Equal(t, "jwalton", shortUsername(`CORP\jwalton`))
+	assert.Equal(t, "jwalton", shortUsername("jwalton@corp.example.com"))
+	assert.Equal(t, "", shortUsername(""))
+}
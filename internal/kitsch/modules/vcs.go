@@ -0,0 +1,126 @@
+package modules
+
+import (
+	"path/filepath"
+
+	"github.com/jwalton/kitsch/internal/kitsch/modules/schemas"
+	"github.com/jwalton/kitsch/internal/perf"
+	"gopkg.in/yaml.v3"
+)
+
+//go:generate go run ../genSchema/main.go --pkg schemas VCSModule
+
+// VCSModule detects which version control system the current directory belongs
+// to, and renders the module configured for that version control system.
+//
+// The repository closest to the current directory wins, so a git repo checked
+// out inside an svn working copy will render the `git` module.  When a jj repo
+// is colocated with a git repo, `jj` is preferred.  Version control systems
+// that have no module configured are not detected.
+//
+type VCSModule struct {
+	// Type is the type of this module.
+	Type string `yaml:"type" jsonschema:",required,enum=vcs"`
+	// Git is the module to render when the current directory is in a git repo.
+	Git *ModuleWrapper `yaml:"git" jsonschema:",ref=module"`
+	// Hg is the module to render when the current directory is in a mercurial repo.
+	Hg *ModuleWrapper `yaml:"hg" jsonschema:",ref=module"`
+	// Svn is the module to render when the current directory is in a subversion working copy.
+	Svn *ModuleWrapper `yaml:"svn" jsonschema:",ref=module"`
+	// Jj is the module to render when the current directory is in a jj repo.
+	Jj *ModuleWrapper `yaml:"jj" jsonschema:",ref=module"`
+}
+
+type vcsModuleData struct {
+	// VCS is the detected version control system; one of "git", "hg", "svn",
+	// or "jj".
+	VCS string
+	// Root is the root folder of the repository.
+	Root string
+	// Module is the result of the module that was rendered for this VCS.
+	Module ModuleWrapperResult
+}
+
+// vcsCandidate is a version control system we can detect, along with the
+// name of the folder that marks the root of a repository.
+type vcsCandidate struct {
+	name   string
+	marker string
+	module *ModuleWrapper
+}
+
+// Execute the module.
+func (mod VCSModule) Execute(context *Context) ModuleResult {
+	// Order matters here - if two candidates are found in the same folder,
+	// the first one wins.
+	candidates := []vcsCandidate{
+		{name: "jj", marker: ".jj", module: mod.Jj},
+		{name: "git", marker: ".git", module: mod.Git},
+		{name: "hg", marker: ".hg", module: mod.Hg},
+		{name: "svn", marker: ".svn", module: mod.Svn},
+	}
+
+	var found *vcsCandidate
+	root := ""
+	for index := range candidates {
+		candidate := &candidates[index]
+		if candidate.module == nil {
+			continue
+		}
+
+		markerPath := context.Directory.FindFileInAncestors(candidate.marker)
+		if markerPath == "" {
+			continue
+		}
+
+		candidateRoot := filepath.Dir(markerPath)
+		if found == nil || len(candidateRoot) > len(root) {
+			found = candidate
+			root = candidateRoot
+		}
+	}
+
+	if found == nil {
+		return ModuleResult{}
+	}
+
+	childResult := found.module.Execute(context)
+	performance := perf.New(1)
+	performance.Add(found.module.String(), childResult.Duration, childResult.Performance)
+
+	return ModuleResult{
+		DefaultText: childResult.Text,
+		Data: vcsModuleData{
+			VCS:    found.name,
+			Root:   root,
+			Module: childResult,
+		},
+		Performance: performance,
+		StartStyle:  childResult.StartStyle,
+		EndStyle:    childResult.EndStyle,
+	}
+}
+
+func (mod VCSModule) childModules() []*ModuleWrapper {
+	children := []*ModuleWrapper{}
+	for _, child := range []*ModuleWrapper{mod.Git, mod.Hg, mod.Svn, mod.Jj} {
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func init() {
+	registerModule(
+		"vcs",
+		registeredModule{
+			jsonSchema: schemas.VCSModuleJSONSchema,
+			factory: func(node *yaml.Node) (Module, error) {
+				module := VCSModule{Type: "vcs"}
+				err := node.Decode(&module)
+				return &module, err
+			},
+		},
+	)
+}
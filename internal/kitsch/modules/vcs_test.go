@@ -0,0 +1,76 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+var vcsTestConfig = heredoc.Doc(`
+	type: vcs
+	git:
+	  type: text
+	  text: git
+	hg:
+	  type: text
+	  text: hg
+	jj:
+	  type: text
+	  text: jj
+`)
+
+func vcsTestContext(t *testing.T, markers ...string) (*Context, string) {
+	root := t.TempDir()
+	for _, marker := range markers {
+		err := os.MkdirAll(filepath.Join(root, marker), 0755)
+		assert.NoError(t, err)
+	}
+
+	cwd := filepath.Join(root, "a", "b")
+	err := os.MkdirAll(cwd, 0755)
+	assert.NoError(t, err)
+
+	context := newTestContext("jwalton")
+	context.Globals.CWD = cwd
+	context.Directory = fileutils.NewDirectory(cwd, 0, 0)
+	return context, root
+}
+
+func TestVCSNoRepo(t *testing.T) {
+	context, _ := vcsTestContext(t)
+	result := moduleWrapperFromYAML(vcsTestConfig).Execute(context)
+	assert.Equal(t, "", result.Text)
+}
+
+func TestVCSGit(t *testing.T) {
+	context, root := vcsTestContext(t, ".git")
+	result := moduleWrapperFromYAML(vcsTestConfig).Execute(context)
+	assert.Equal(t, "git", result.Text)
+
+	data := result.Data.(vcsModuleData)
+	assert.Equal(t, "git", data.VCS)
+	assert.Equal(t, root, data.Root)
+}
+
+func TestVCSNearestRepoWins(t *testing.T) {
+	context, root := vcsTestContext(t, ".hg", "a/.git")
+	result := moduleWrapperFromYAML(vcsTestConfig).Execute(context)
+	assert.Equal(t, "git", result.Text)
+	assert.Equal(t, filepath.Join(root, "a"), result.Data.(vcsModuleData).Root)
+}
+
+func TestVCSColocatedJJ(t *testing.T) {
+	context, _ := vcsTestContext(t, ".git", ".jj")
+	result := moduleWrapperFromYAML(vcsTestConfig).Execute(context)
+	assert.Equal(t, "jj", result.Text)
+}
+
+func TestVCSUnconfiguredIgnored(t *testing.T) {
+	context, _ := vcsTestContext(t, ".svn")
+	result := moduleWrapperFromYAML(vcsTestConfig).Execute(context)
+	assert.Equal(t, "", result.Text)
+}
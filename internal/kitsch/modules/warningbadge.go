@@ -0,0 +1,89 @@
+package modules
+
+import (
+	"fmt"
+
+	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
+)
+
+const defaultWarningBadgeTemplate = "⚠ kitsch"
+const defaultWarningBadgeStyle = "yellow"
+
+// WarningBadge configures a badge which is added to the prompt when any module
+// reports a warning while the prompt is rendered.  When the badge is enabled,
+// warnings are not written to stderr, so they can't end up in the middle of
+// the prompt; run `kitsch explain` to see them instead.
+type WarningBadge struct {
+	// Enabled turns on the badge.
+	Enabled bool `yaml:"enabled"`
+	// Template is the template for the badge.  This is executed with the same
+	// data as a module template, where `.Data` is a `{ Count, Warnings }`
+	// object.  Defaults to "⚠ kitsch".
+	Template string `yaml:"template,omitempty"`
+	// Style is the style to apply to the badge.  Defaults to "yellow".
+	Style string `yaml:"style,omitempty"`
+	// Position is where to put the badge; either "start" or "end" of the
+	// prompt.  Defaults to "start".
+	Position string `yaml:"position,omitempty"`
+	// Link, if set, turns the badge into an OSC 8 hyperlink to this URL.
+	Link string `yaml:"link,omitempty"`
+}
+
+type warningBadgeData struct {
+	// Count is the number of warnings.
+	Count int
+	// Warnings is the list of warnings.
+	Warnings []string
+}
+
+// IsEnabled returns true if the badge is enabled.
+func (badge *WarningBadge) IsEnabled() bool {
+	return badge != nil && badge.Enabled
+}
+
+// Apply adds the badge to the given prompt, if there were any warnings while
+// rendering the prompt.
+func (badge *WarningBadge) Apply(context *Context, prompt string) string {
+	if !badge.IsEnabled() {
+		return prompt
+	}
+
+	warnings := context.Warnings()
+	if len(warnings) == 0 {
+		return prompt
+	}
+
+	text, err := badge.render(context, warningBadgeData{Count: len(warnings), Warnings: warnings})
+	if err != nil {
+		text = defaultWarningBadgeTemplate
+		log.Warn(fmt.Sprintf("Error in warningBadge template: %v", err))
+	}
+	if text == "" {
+		return prompt
+	}
+
+	text = context.GetStyle(defaultString(badge.Style, defaultWarningBadgeStyle)).Apply(text)
+	if badge.Link != "" {
+		text = osc("8;;"+badge.Link) + text + osc("8;;")
+	}
+
+	if badge.Position == "end" {
+		return prompt + text + " "
+	}
+	return text + " " + prompt
+}
+
+func (badge *WarningBadge) render(context *Context, data warningBadgeData) (string, error) {
+	tmpl, err := modtemplate.CompileTemplate(
+		context.Styles,
+		context.Environment,
+		context.Partials,
+		"warning-badge",
+		defaultString(badge.Template, defaultWarningBadgeTemplate),
+	)
+	if err != nil {
+		return "", err
+	}
+	return modtemplate.TemplateToString(tmpl, TemplateData{Globals: &context.Globals, Data: data})
+}
@@ -0,0 +1,36 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarningBadge(t *testing.T) {
+	context := newTestContext("jwalton")
+	badge := &WarningBadge{Enabled: true}
+
+	// No warnings, no badge.
+	assert.Equal(t, "$ ", badge.Apply(context, "$ "))
+
+	context.addWarnings(ModuleWrapper{}, []string{"oops", "uh oh"})
+	assert.Equal(t, "⚠ kitsch $ ", badge.Apply(context, "$ "))
+
+	badge = &WarningBadge{Enabled: true, Template: "{{ .Data.Count }} warnings", Position: "end"}
+	assert.Equal(t, "$ 2 warnings ", badge.Apply(context, "$ "))
+
+	badge = &WarningBadge{Enabled: true, Link: "https://example.com"}
+	assert.Equal(t, "\x1b]8;;https://example.com\a⚠ kitsch\x1b]8;;\a $ ", badge.Apply(context, "$ "))
+}
+
+func TestWarningBadgeDisabled(t *testing.T) {
+	context := newTestContext("jwalton")
+	context.addWarnings(ModuleWrapper{}, []string{"oops"})
+
+	var badge *WarningBadge
+	assert.False(t, badge.IsEnabled())
+	assert.Equal(t, "$ ", badge.Apply(context, "$ "))
+
+	badge = &WarningBadge{}
+	assert.Equal(t, "$ ", badge.Apply(context, "$ "))
+}
@@ -0,0 +1,132 @@
+// Package notify sends desktop notifications when a long running command
+// finishes.
+//
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/env"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+	"github.com/mattn/go-shellwords"
+)
+
+const defaultMessage = `Command finished in {{ .PrettyDuration }}{{ if .Status }} (exit status {{ .Status }}){{ end }}`
+
+// Config configures notifications for long running commands.
+type Config struct {
+	// Threshold is the minimum duration of a command, in milliseconds, before
+	// we send a notification.  If this is 0, notifications are disabled.
+	Threshold int64 `yaml:"threshold"`
+	// Method is how to send the notification.  "osc9" (the default) and
+	// "osc777" write an escape sequence to the terminal, and the terminal
+	// shows the notification.  "command" runs Command.
+	Method string `yaml:"method,omitempty"`
+	// Message is a template for the text of the notification.
+	Message string `yaml:"message,omitempty"`
+	// Command is a template for the command to run when Method is "command".
+	Command string `yaml:"command,omitempty"`
+}
+
+// Data is the data passed to the Message and Command templates.
+type Data struct {
+	// Duration is the duration of the command, in milliseconds.
+	Duration int64
+	// PrettyDuration is the duration of the command in a human-readable format.
+	PrettyDuration string
+	// Status is the exit status of the command.
+	Status int
+	// Message is the rendered message.  This is only available to Command.
+	Message string
+}
+
+// Enabled returns true if notifications are turned on.
+func (config *Config) Enabled() bool {
+	return config != nil && config.Threshold > 0
+}
+
+// Notify sends a notification for a command that took `duration`
+// milliseconds and exited with `status`, if the duration is over the
+// threshold.  Escape sequences are written to `terminal`.
+func (config *Config) Notify(terminal io.Writer, duration int64, status int) error {
+	if !config.Enabled() || duration < config.Threshold {
+		return nil
+	}
+
+	data := Data{
+		Duration:       duration,
+		PrettyDuration: (time.Duration(duration) * time.Millisecond).Round(time.Second).String(),
+		Status:         status,
+	}
+
+	message := config.Message
+	if message == "" {
+		message = defaultMessage
+	}
+	message, err := renderTemplate("message", message, data)
+	if err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	data.Message = message
+
+	switch config.Method {
+	case "", "osc9":
+		_, err = io.WriteString(terminal, "\x1b]9;"+sanitize(message)+"\a")
+	case "osc777":
+		_, err = io.WriteString(terminal, "\x1b]777;notify;kitsch;"+strings.ReplaceAll(sanitize(message), ";", ",")+"\a")
+	case "command":
+		err = config.runCommand(data)
+	default:
+		err = fmt.Errorf("unknown notification method %q", config.Method)
+	}
+	return err
+}
+
+// runCommand starts the notification command.  We don't wait for the command
+// to finish, so a slow command won't hold up the prompt.
+func (config *Config) runCommand(data Data) error {
+	command, err := renderTemplate("command", config.Command, data)
+	if err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	commandParts, err := shellwords.Parse(command)
+	if err != nil {
+		return fmt.Errorf("invalid command: \"%s\": %w", command, err)
+	}
+	if len(commandParts) == 0 {
+		return fmt.Errorf("invalid command: \"%s\"", command)
+	}
+
+	executable, err := fileutils.LookPathSafe(commandParts[0])
+	if err != nil {
+		return fmt.Errorf("could not find executable: \"%s\": %w", commandParts[0], err)
+	}
+
+	return exec.Command(executable, commandParts[1:]...).Start()
+}
+
+func renderTemplate(name string, templateString string, data Data) (string, error) {
+	tmpl, err := modtemplate.CompileTemplate(&styling.Registry{}, env.New(), nil, name, templateString)
+	if err != nil {
+		return "", err
+	}
+	return modtemplate.TemplateToString(tmpl, data)
+}
+
+// sanitize removes any control characters from the message, so it can't end
+// the escape sequence early.
+func sanitize(message string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, message)
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotify(t *testing.T) {
+	config := &Config{Threshold: 10000}
+
+	var out bytes.Buffer
+	assert.NoError(t, config.Notify(&out, 9999, 0))
+	assert.Equal(t, "", out.String())
+
+	assert.NoError(t, config.Notify(&out, 12400, 1))
+	assert.Equal(t, "\x1b]9;Command finished in 12s (exit status 1)\a", out.String())
+}
+
+func TestNotifyOSC777(t *testing.T) {
+	config := &Config{
+		Threshold: 1000,
+		Method:    "osc777",
+		Message:   "done; took {{ .Duration }}ms\n",
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, config.Notify(&out, 1500, 0))
+	assert.Equal(t, "\x1b]777;notify;kitsch;done, took 1500ms\a", out.String())
+}
+
+func TestNotifyDisabled(t *testing.T) {
+	var config *Config
+	var out bytes.Buffer
+	assert.NoError(t, config.Notify(&out, 100000, 0))
+	assert.Equal(t, "", out.String())
+
+	config = &Config{Threshold: 1000, Method: "smoke-signals"}
+	assert.EqualError(t, config.Notify(&out, 100000, 0), `unknown notification method "smoke-signals"`)
+}
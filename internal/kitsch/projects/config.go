@@ -97,8 +97,16 @@ func mergeProjectType(to ProjectType, from ProjectType) ProjectType {
 	if to.ToolSymbol == "" {
 		to.ToolSymbol = from.ToolSymbol
 	}
-	if to.ToolVersion == nil {
+	if to.Icon == "" {
+		to.Icon = from.Icon
+	}
+	if to.ToolVersion == nil && to.VersionCommand == "" && to.VersionFile == "" {
 		to.ToolVersion = from.ToolVersion
+		to.VersionCommand = from.VersionCommand
+		to.VersionFile = from.VersionFile
+	}
+	if to.VersionRegex == "" {
+		to.VersionRegex = from.VersionRegex
 	}
 	if to.PackageManagerSymbol == "" {
 		to.PackageManagerSymbol = from.PackageManagerSymbol
@@ -111,6 +119,12 @@ func mergeProjectType(to ProjectType, from ProjectType) ProjectType {
 	if to.PackageVersion == nil {
 		to.PackageVersion = from.PackageVersion
 	}
+	if to.PinnedTool == "" {
+		to.PinnedTool = from.PinnedTool
+	}
+	if to.PackageManagerDetection == "" {
+		to.PackageManagerDetection = from.PackageManagerDetection
+	}
 
 	return to
 }
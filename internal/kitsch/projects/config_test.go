@@ -160,3 +160,25 @@ func TestMergeAlteredProjectTypes(t *testing.T) {
 		to,
 	)
 }
+
+func TestMergeVersionCommandOverridesToolVersion(t *testing.T) {
+	to := projectTypesFromYAML(`
+  - name: node
+    versionCommand: "fnm current"
+    icon: "⬢"
+`)
+
+	to = MergeProjectTypes(to, from, false)
+	assert.Nil(t, to[0].ToolVersion)
+	assert.Equal(t, "fnm current", to[0].VersionCommand)
+	assert.Equal(t, "⬢", to[0].Icon)
+	assert.Equal(t, "Node", to[0].ToolSymbol)
+	assert.Equal(t,
+		[]getters.Getter{getters.CustomGetter{
+			Type:  getters.TypeCustom,
+			From:  "fnm current",
+			Cache: getters.CacheSettings{Enabled: true},
+		}},
+		to[0].toolVersionGetters(),
+	)
+}
@@ -14,6 +14,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfExtensions: []string{"java"},
 		},
 		ToolSymbol: "java",
+		PinnedTool: "java",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type: getters.TypeCustom,
@@ -32,6 +33,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfExtensions: []string{"go"},
 		},
 		ToolSymbol: "go",
+		PinnedTool: "go",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -49,6 +51,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfExtensions: []string{"rs"},
 		},
 		ToolSymbol: "rustc",
+		PinnedTool: "rust",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -73,6 +76,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfFiles: []string{"yarn.lock"},
 		},
 		ToolSymbol: "node",
+		PinnedTool: "node",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -90,7 +94,8 @@ var DefaultProjectTypes = []ProjectType{
 				Regex: `v(.*)`,
 			},
 		},
-		PackageManagerSymbol: "yarn",
+		PackageManagerSymbol:    "yarn",
+		PackageManagerDetection: DetectionJavaScript,
 		PackageManagerVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -123,6 +128,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfFiles: []string{"package.json"},
 		},
 		ToolSymbol: "node",
+		PinnedTool: "node",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -140,7 +146,8 @@ var DefaultProjectTypes = []ProjectType{
 				Regex: `v(.*)`,
 			},
 		},
-		PackageManagerSymbol: "npm",
+		PackageManagerSymbol:    "npm",
+		PackageManagerDetection: DetectionJavaScript,
 		PackageManagerVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -173,6 +180,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfFiles: []string{"mod.ts"},
 		},
 		ToolSymbol: "deno",
+		PinnedTool: "deno",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -190,6 +198,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfExtensions: []string{"py"},
 		},
 		ToolSymbol: "python",
+		PinnedTool: "python",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -219,6 +228,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfExtensions: []string{"php"},
 		},
 		ToolSymbol: "php",
+		PinnedTool: "php",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -236,6 +246,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfExtensions: []string{"rb"},
 		},
 		ToolSymbol: "ruby",
+		PinnedTool: "ruby",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
@@ -253,6 +264,201 @@ var DefaultProjectTypes = []ProjectType{
 			},
 		},
 	},
+	{
+		Name:  "elixir",
+		Style: "magenta",
+		Conditions: &condition.Conditions{
+			IfFiles:      []string{"mix.exs"},
+			IfExtensions: []string{"ex", "exs"},
+		},
+		ToolSymbol: "elixir",
+		PinnedTool: "elixir",
+		ToolVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type: getters.TypeCustom,
+				// Prints the Erlang/OTP version first, then "Elixir 1.15.7 (compiled with Erlang/OTP 26)".
+				From:  "elixir --version",
+				Regex: `Elixir (\d+\.\d+\.\d+)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+		PackageManagerSymbol: "mix",
+		PackageVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeFile,
+				From:  "mix.exs",
+				Regex: `version:\s*"([^"]+)"`,
+			},
+		},
+	},
+	{
+		Name:  "zig",
+		Style: "brightYellow",
+		Conditions: &condition.Conditions{
+			IfFiles:      []string{"build.zig", "build.zig.zon"},
+			IfExtensions: []string{"zig"},
+		},
+		ToolSymbol: "zig",
+		PinnedTool: "zig",
+		ToolVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  "zig version",
+				Regex: `^(\d+\.\d+\.\d+\S*)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+		PackageVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeFile,
+				From:  "build.zig.zon",
+				Regex: `\.version\s*=\s*"([^"]+)"`,
+			},
+		},
+	},
+	{
+		Name:  "haskell",
+		Style: "brightMagenta",
+		Conditions: &condition.Conditions{
+			IfFiles:      []string{"stack.yaml", "cabal.project"},
+			IfExtensions: []string{"cabal", "hs"},
+		},
+		ToolSymbol: "ghc",
+		PinnedTool: "ghc",
+		ToolVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  "ghc --numeric-version",
+				Regex: `^(\d+\.\d+\.\d+)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+		PackageManagerSymbol: "stack",
+		PackageManagerVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  "stack --numeric-version",
+				Regex: `^(\d+\.\d+\.\d+)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+		PackageVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:          getters.TypeFile,
+				From:          "package.yaml",
+				As:            getters.AsYAML,
+				ValueTemplate: "{{ .version }}",
+			},
+		},
+	},
+	{
+		Name:  "swift",
+		Style: "brightRed",
+		Conditions: &condition.Conditions{
+			IfFiles:      []string{"Package.swift"},
+			IfExtensions: []string{"swift"},
+		},
+		ToolSymbol: "swift",
+		PinnedTool: "swift",
+		ToolVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type: getters.TypeCustom,
+				// e.g. "Apple Swift version 5.9.2 (swiftlang-...)" or
+				// "Swift version 5.9.2 (swift-5.9.2-RELEASE)".
+				From:  "swift --version",
+				Regex: `Swift version (\d+\.\d+(?:\.\d+)?)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+	},
+	{
+		Name:  "dart",
+		Style: "brightBlue",
+		Conditions: &condition.Conditions{
+			IfFiles:      []string{"pubspec.yaml", "pubspec.yml"},
+			IfExtensions: []string{"dart"},
+		},
+		ToolSymbol: "dart",
+		PinnedTool: "dart",
+		ToolVersion: []getters.Getter{
+			getters.CustomGetter{
+				// Flutter ships with its own dart SDK, so this works for
+				// Flutter projects too.
+				Type:  getters.TypeCustom,
+				From:  "dart --version",
+				Regex: `Dart SDK version: (\d+\.\d+\.\d+)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+		PackageManagerSymbol: "pub",
+		PackageVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:          getters.TypeFile,
+				From:          "pubspec.yaml",
+				As:            getters.AsYAML,
+				ValueTemplate: "{{ .version }}",
+			},
+		},
+	},
+	{
+		Name:  "julia",
+		Style: "magenta",
+		Conditions: &condition.Conditions{
+			IfFiles:      []string{"Project.toml", "JuliaProject.toml", "Manifest.toml"},
+			IfExtensions: []string{"jl"},
+		},
+		ToolSymbol: "julia",
+		PinnedTool: "julia",
+		ToolVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  "julia --version",
+				Regex: `julia version (\d+\.\d+\.\d+)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+		PackageVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:          getters.TypeFile,
+				From:          "Project.toml",
+				As:            getters.AsTOML,
+				ValueTemplate: "{{ .version }}",
+			},
+		},
+	},
+	{
+		Name:  "lua",
+		Style: "blue",
+		Conditions: &condition.Conditions{
+			IfFiles:      []string{".lua-version", ".luarc.json", "lua_modules"},
+			IfExtensions: []string{"lua", "rockspec"},
+		},
+		ToolSymbol: "lua",
+		PinnedTool: "lua",
+		ToolVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  "lua -v",
+				Regex: `^Lua (\d+\.\d+\.\d+)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  "luajit -v",
+				Regex: `^LuaJIT (\d+\.\d+\.\d+\S*)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+		PackageManagerSymbol: "luarocks",
+		PackageManagerVersion: []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  "luarocks --version",
+				Regex: `luarocks (\d+\.\d+\.\d+)`,
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		},
+	},
 	{
 		Name:  "helm",
 		Style: "brightWhite",
@@ -260,6 +466,7 @@ var DefaultProjectTypes = []ProjectType{
 			IfFiles: []string{"Chart.yaml"},
 		},
 		ToolSymbol: "helm",
+		PinnedTool: "helm",
 		ToolVersion: []getters.Getter{
 			getters.CustomGetter{
 				Type:  getters.TypeCustom,
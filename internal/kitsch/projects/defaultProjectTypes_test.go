@@ -0,0 +1,94 @@
+package projects
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+)
+
+func getDefaultProjectType(name string) ProjectType {
+	for _, projectType := range DefaultProjectTypes {
+		if projectType.Name == name {
+			return projectType
+		}
+	}
+	panic("No such project type: " + name)
+}
+
+func TestDefaultProjectTypePackageVersions(t *testing.T) {
+	tests := []struct {
+		projectType string
+		file        string
+		contents    string
+		expected    string
+		otherFiles  []string
+	}{
+		{
+			projectType: "elixir",
+			file:        "mix.exs",
+			contents: heredoc.Doc(`
+				defmodule Example.MixProject do
+				  use Mix.Project
+
+				  def project do
+				    [
+				      app: :example,
+				      version: "0.3.1",
+				      elixir: "~> 1.15",
+				    ]
+				  end
+				end
+			`),
+			expected: "0.3.1",
+		},
+		{
+			projectType: "zig",
+			file:        "build.zig.zon",
+			contents: heredoc.Doc(`
+				.{
+				    .name = "example",
+				    .version = "0.1.0",
+				}
+			`),
+			expected: "0.1.0",
+		},
+		{
+			projectType: "haskell",
+			file:        "package.yaml",
+			contents:    "name: example\nversion: 1.2.0.0\n",
+			expected:    "1.2.0.0",
+			otherFiles:  []string{"stack.yaml"},
+		},
+		{
+			projectType: "dart",
+			file:        "pubspec.yaml",
+			contents:    "name: example\nversion: 2.0.0+4\n",
+			expected:    "2.0.0+4",
+		},
+		{
+			projectType: "julia",
+			file:        "Project.toml",
+			contents:    "name = \"Example\"\nversion = \"0.5.0\"\n",
+			expected:    "0.5.0",
+		},
+	}
+
+	for _, test := range tests {
+		projectType := getDefaultProjectType(test.projectType)
+		fsys := fstest.MapFS{
+			test.file: &fstest.MapFile{Data: []byte(test.contents)},
+		}
+		for _, file := range test.otherFiles {
+			fsys[file] = &fstest.MapFile{}
+		}
+		context := makeTestGetterContext(fsys)
+
+		assert.True(t, projectType.Conditions.Matches(context.GetWorkingDirectory(), nil), test.projectType)
+
+		version, err := getStringValue(projectType.PackageVersion, context)
+		assert.NoError(t, err, test.projectType)
+		assert.Equal(t, test.expected, version, test.projectType)
+	}
+}
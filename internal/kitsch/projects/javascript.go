@@ -0,0 +1,230 @@
+package projects
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"gopkg.in/yaml.v3"
+)
+
+// DetectionJavaScript is the value for ProjectType.PackageManagerDetection
+// which detects the JavaScript package manager from lockfiles and the
+// "packageManager" field in package.json.
+const DetectionJavaScript = "javascript"
+
+// JSWorkspace is information about the JavaScript package in a folder, and the
+// workspace it belongs to.
+type JSWorkspace struct {
+	// PackageDir is the folder containing the nearest package.json.
+	PackageDir string
+	// PackageName is the name of the nearest package.
+	PackageName string
+	// Root is the root of the workspace, or PackageDir if the package is not
+	// part of a workspace.
+	Root string
+	// IsMember is true if the package is a member of a workspace (and is not
+	// the workspace root itself).
+	IsMember bool
+	// PackageManager is the detected package manager; one of "npm", "yarn",
+	// "pnpm", or "bun", or "" if no package manager could be detected.
+	PackageManager string
+	// PackageManagerVersion is the version of the package manager from the
+	// "packageManager" field in package.json, or "" if unspecified.
+	PackageManagerVersion string
+}
+
+// jsLockfiles maps lockfiles to the package manager that writes them, in
+// priority order.
+var jsLockfiles = []struct {
+	file           string
+	packageManager string
+}{
+	{"pnpm-lock.yaml", "pnpm"},
+	{"bun.lockb", "bun"},
+	{"bun.lock", "bun"},
+	{"yarn.lock", "yarn"},
+	{"package-lock.json", "npm"},
+	{"npm-shrinkwrap.json", "npm"},
+}
+
+type packageJSON struct {
+	Name           string          `json:"name"`
+	PackageManager string          `json:"packageManager"`
+	Workspaces     json.RawMessage `json:"workspaces"`
+}
+
+// workspacePatterns returns the workspace globs from the "workspaces" field,
+// which is either an array, or an object with a "packages" array.
+func (pkg packageJSON) workspacePatterns() []string {
+	if len(pkg.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(pkg.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(pkg.Workspaces, &object); err == nil {
+		return object.Packages
+	}
+	return nil
+}
+
+func readPackageJSON(dir fileutils.Directory, file string) (packageJSON, bool) {
+	var pkg packageJSON
+	contents, err := dir.ReadFile(file)
+	if err != nil {
+		return pkg, false
+	}
+	if err := json.Unmarshal(contents, &pkg); err != nil {
+		return pkg, false
+	}
+	return pkg, true
+}
+
+func readPnpmWorkspace(dir fileutils.Directory, file string) ([]string, bool) {
+	contents, err := dir.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	var workspace struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(contents, &workspace); err != nil {
+		return nil, false
+	}
+	return workspace.Packages, true
+}
+
+// FindJSWorkspace returns information about the JavaScript package containing
+// the given directory.  Returns nil if there is no package.json in the
+// directory or any of its ancestors.
+func FindJSWorkspace(dir fileutils.Directory) *JSWorkspace {
+	// Only folders with a package.json or a pnpm-workspace.yaml can be a
+	// package or a workspace root.
+	packageFiles := filesByFolder(dir.FindAllInAncestors("package.json"))
+	pnpmFiles := filesByFolder(dir.FindAllInAncestors("pnpm-workspace.yaml"))
+	folders := make([]string, 0, len(packageFiles)+len(pnpmFiles))
+	for folder := range packageFiles {
+		folders = append(folders, folder)
+	}
+	for folder := range pnpmFiles {
+		if _, exists := packageFiles[folder]; !exists {
+			folders = append(folders, folder)
+		}
+	}
+	// Every folder is an ancestor of dir, so longer paths are closer.
+	sort.Slice(folders, func(i, j int) bool { return len(folders[i]) > len(folders[j]) })
+
+	var result *JSWorkspace
+	var rootPackage packageJSON
+
+	for _, folder := range folders {
+		var pkg packageJSON
+		hasPackage := false
+		if file, ok := packageFiles[folder]; ok {
+			pkg, hasPackage = readPackageJSON(dir, file)
+		}
+
+		if result == nil && hasPackage {
+			result = &JSWorkspace{
+				PackageDir:  folder,
+				PackageName: pkg.Name,
+				Root:        folder,
+			}
+			rootPackage = pkg
+		}
+
+		if result != nil && !result.IsMember && folder != result.PackageDir {
+			patterns := pkg.workspacePatterns()
+			if file, ok := pnpmFiles[folder]; ok {
+				if pnpmPatterns, ok := readPnpmWorkspace(dir, file); ok {
+					patterns = append(patterns, pnpmPatterns...)
+				}
+			}
+			if matchesWorkspace(patterns, folder, result.PackageDir) {
+				result.Root = folder
+				result.IsMember = true
+				rootPackage = pkg
+				break
+			}
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if rootPackage.PackageManager != "" {
+		// e.g. "pnpm@8.6.0+sha256.abc..."
+		parts := strings.SplitN(rootPackage.PackageManager, "@", 2)
+		result.PackageManager = parts[0]
+		if len(parts) > 1 {
+			result.PackageManagerVersion = strings.SplitN(parts[1], "+", 2)[0]
+		}
+	} else {
+		result.PackageManager = detectJSLockfile(dir, result.Root)
+	}
+
+	return result
+}
+
+// filesByFolder maps each file's folder to the file.
+func filesByFolder(files []string) map[string]string {
+	result := make(map[string]string, len(files))
+	for _, file := range files {
+		result[filepath.Dir(file)] = file
+	}
+	return result
+}
+
+// detectJSLockfile returns the package manager for the lockfile in `root`,
+// which must be `dir` or one of its ancestors.
+func detectJSLockfile(dir fileutils.Directory, root string) string {
+	for _, lockfile := range jsLockfiles {
+		for _, file := range dir.FindAllInAncestors(lockfile.file) {
+			if filepath.Dir(file) == root {
+				return lockfile.packageManager
+			}
+		}
+	}
+	return ""
+}
+
+// matchesWorkspace returns true if packageDir is matched by one of the
+// workspace patterns from the workspace in rootDir.
+func matchesWorkspace(patterns []string, rootDir string, packageDir string) bool {
+	rel, err := filepath.Rel(rootDir, packageDir)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	matched := false
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "./"), "/")
+
+		if matchesWorkspacePattern(pattern, rel) {
+			matched = !negate
+		}
+	}
+	return matched
+}
+
+func matchesWorkspacePattern(pattern string, rel string) bool {
+	if index := strings.Index(pattern, "**"); index != -1 {
+		prefix := pattern[:index]
+		return strings.HasPrefix(rel, prefix)
+	}
+	matched, err := path.Match(pattern, rel)
+	return err == nil && matched
+}
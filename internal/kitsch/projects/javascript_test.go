@@ -0,0 +1,123 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	for name, contents := range files {
+		filename := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filename), 0755))
+		assert.NoError(t, os.WriteFile(filename, []byte(contents), 0644))
+	}
+}
+
+func TestFindJSWorkspaceNoPackage(t *testing.T) {
+	assert.Nil(t, FindJSWorkspace(fileutils.NewDirectory(t.TempDir(), 0, 0)))
+}
+
+func TestFindJSWorkspaceStandalonePackage(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"package.json":      `{"name": "standalone"}`,
+		"package-lock.json": `{}`,
+	})
+
+	workspace := FindJSWorkspace(fileutils.NewDirectory(filepath.Join(root), 0, 0))
+	assert.Equal(t, &JSWorkspace{
+		PackageDir:     root,
+		PackageName:    "standalone",
+		Root:           root,
+		IsMember:       false,
+		PackageManager: "npm",
+	}, workspace)
+}
+
+func TestFindJSWorkspaceYarnMember(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"package.json":              `{"name": "monorepo", "workspaces": ["packages/*"]}`,
+		"yarn.lock":                 ``,
+		"packages/api/package.json": `{"name": "@monorepo/api"}`,
+		"packages/api/src/index.js": ``,
+	})
+
+	workspace := FindJSWorkspace(fileutils.NewDirectory(filepath.Join(root, "packages", "api", "src"), 0, 0))
+	assert.Equal(t, &JSWorkspace{
+		PackageDir:     filepath.Join(root, "packages", "api"),
+		PackageName:    "@monorepo/api",
+		Root:           root,
+		IsMember:       true,
+		PackageManager: "yarn",
+	}, workspace)
+}
+
+func TestFindJSWorkspacePnpm(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"package.json":              `{"name": "monorepo"}`,
+		"pnpm-workspace.yaml":       "packages:\n  - 'apps/**'\n  - '!apps/ignored'\n",
+		"pnpm-lock.yaml":            ``,
+		"apps/web/package.json":     `{"name": "web"}`,
+		"apps/ignored/package.json": `{"name": "ignored"}`,
+	})
+
+	workspace := FindJSWorkspace(fileutils.NewDirectory(filepath.Join(root, "apps", "web"), 0, 0))
+	assert.True(t, workspace.IsMember)
+	assert.Equal(t, "web", workspace.PackageName)
+	assert.Equal(t, "pnpm", workspace.PackageManager)
+
+	workspace = FindJSWorkspace(fileutils.NewDirectory(filepath.Join(root, "apps", "ignored"), 0, 0))
+	assert.False(t, workspace.IsMember)
+}
+
+func TestFindJSWorkspacePackageManagerField(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"package.json": `{
+			"name": "monorepo",
+			"packageManager": "pnpm@8.6.0+sha256.abcdef",
+			"workspaces": {"packages": ["libs/*"]}
+		}`,
+		"package-lock.json":      `{}`,
+		"libs/util/package.json": `{"name": "util"}`,
+	})
+
+	workspace := FindJSWorkspace(fileutils.NewDirectory(filepath.Join(root, "libs", "util"), 0, 0))
+	assert.Equal(t, "pnpm", workspace.PackageManager)
+	assert.Equal(t, "8.6.0", workspace.PackageManagerVersion)
+	assert.Equal(t, root, workspace.Root)
+}
+
+func TestResolveProjectTypeDetectsJSPackageManager(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"package.json":          `{"name": "monorepo", "packageManager": "bun@1.0.3", "workspaces": ["pkgs/*"]}`,
+		"pkgs/cli/package.json": `{"name": "cli"}`,
+		"pkgs/cli/node-version": "20.0.0",
+	})
+
+	context := makeTestGetterContext(nil)
+	context.directory = fileutils.NewDirectory(filepath.Join(root, "pkgs", "cli"), 0, 0)
+
+	projectInfo := ResolveProjectType([]ProjectType{
+		{
+			Name:                    "node",
+			Conditions:              &condition.Conditions{IfFiles: []string{"package.json"}},
+			ToolVersion:             []getters.Getter{getters.CustomGetter{Type: getters.TypeFile, From: "node-version"}},
+			PackageManagerSymbol:    "npm",
+			PackageManagerDetection: DetectionJavaScript,
+		},
+	}, context)
+
+	assert.Equal(t, "bun", projectInfo.PackageManagerSymbol)
+	assert.Equal(t, "1.0.3", projectInfo.PackageManagerVersion())
+	assert.Equal(t, "cli", projectInfo.WorkspaceName())
+}
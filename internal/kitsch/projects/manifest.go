@@ -0,0 +1,175 @@
+package projects
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
+)
+
+// ErrNoManifest is returned when no package manifest could be found.
+var ErrNoManifest = errors.New("no package manifest found")
+
+// PackageManifest is information about a package read from a manifest file.
+type PackageManifest struct {
+	// Name is the name of the package, or "" if the manifest doesn't specify one.
+	Name string
+	// Version is the version of the package.
+	Version string
+	// File is the name of the manifest file the package was read from.
+	File string
+}
+
+// manifestParser parses the contents of a manifest file.
+type manifestParser struct {
+	file  string
+	parse func(contents []byte) (name string, version string)
+}
+
+var packageJSONParser = manifestParser{file: "package.json", parse: parseJSONManifest}
+
+// manifestParsers is a list of manifest parsers for each project type.
+var manifestParsers = map[string][]manifestParser{
+	"node":      {packageJSONParser},
+	"node-yarn": {packageJSONParser},
+	"deno":      {{file: "deno.json", parse: parseJSONManifest}, packageJSONParser},
+	"rust":      {{file: "Cargo.toml", parse: parseCargoToml}},
+	"python":    {{file: "pyproject.toml", parse: parsePyprojectToml}},
+	"php":       {{file: "composer.json", parse: parseJSONManifest}},
+	"java":      {{file: "gradle.properties", parse: parseGradleProperties}},
+}
+
+// fallbackManifestParsers are tried, in order, if the project type has no parsers
+// or none of its parsers found a version.
+var fallbackManifestParsers = []manifestParser{
+	{file: "gradle.properties", parse: parseGradleProperties},
+	{file: "VERSION", parse: parseVersionFile},
+}
+
+// MatchProjectType returns the first project type whose conditions match the
+// given directory, or nil if no project type matches.  Unlike
+// `ResolveProjectType()`, this will not try to find the version of each project
+// type's tool, so no external commands are run.
+func MatchProjectType(
+	projectTypes []ProjectType,
+	directory fileutils.Directory,
+	environment condition.Environment,
+) *ProjectType {
+	for index := range projectTypes {
+		conditions := projectTypes[index].Conditions
+		if !conditions.IsEmpty() && conditions.Matches(directory, environment) {
+			return &projectTypes[index]
+		}
+	}
+	return nil
+}
+
+// ReadPackageManifest reads the name and version of the package in the given
+// file system, using the manifest for the given project type.  If the project
+// type's manifest can't be found or has no version, this will fall back to
+// "gradle.properties" or a "VERSION" file.
+func ReadPackageManifest(projectType string, fsys fs.FS) (PackageManifest, error) {
+	parsers := append(manifestParsers[projectType], fallbackManifestParsers...)
+
+	for _, parser := range parsers {
+		contents, err := fs.ReadFile(fsys, parser.file)
+		if err != nil {
+			continue
+		}
+
+		name, version := parser.parse(contents)
+		if version != "" {
+			return PackageManifest{Name: name, Version: version, File: parser.file}, nil
+		}
+	}
+
+	return PackageManifest{}, ErrNoManifest
+}
+
+func parseJSONManifest(contents []byte) (string, string) {
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		return "", ""
+	}
+	return manifest.Name, manifest.Version
+}
+
+// tomlPackage is a `[package]` style TOML section.  Version is an interface
+// because Cargo allows `version.workspace = true`.
+type tomlPackage struct {
+	Name    string      `toml:"name"`
+	Version interface{} `toml:"version"`
+}
+
+func (pkg tomlPackage) version() string {
+	version, _ := pkg.Version.(string)
+	return version
+}
+
+func parseCargoToml(contents []byte) (string, string) {
+	var manifest struct {
+		Package   tomlPackage `toml:"package"`
+		Workspace struct {
+			Package tomlPackage `toml:"package"`
+		} `toml:"workspace"`
+	}
+	if err := toml.Unmarshal(contents, &manifest); err != nil {
+		return "", ""
+	}
+
+	version := manifest.Package.version()
+	if version == "" {
+		version = manifest.Workspace.Package.version()
+	}
+	return manifest.Package.Name, version
+}
+
+func parsePyprojectToml(contents []byte) (string, string) {
+	var manifest struct {
+		Project tomlPackage `toml:"project"`
+		Tool    struct {
+			Poetry tomlPackage `toml:"poetry"`
+		} `toml:"tool"`
+	}
+	if err := toml.Unmarshal(contents, &manifest); err != nil {
+		return "", ""
+	}
+
+	if version := manifest.Project.version(); version != "" {
+		return manifest.Project.Name, version
+	}
+	return manifest.Tool.Poetry.Name, manifest.Tool.Poetry.version()
+}
+
+var gradlePropertyRegex = regexp.MustCompile(`^\s*([\w.]+)\s*[=:]\s*(.*?)\s*$`)
+
+func parseGradleProperties(contents []byte) (string, string) {
+	name := ""
+	version := ""
+	for _, line := range strings.Split(string(contents), "\n") {
+		matches := gradlePropertyRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		switch matches[1] {
+		case "version":
+			version = matches[2]
+		case "name", "archivesBaseName":
+			name = matches[2]
+		}
+	}
+	return name, version
+}
+
+func parseVersionFile(contents []byte) (string, string) {
+	lines := strings.SplitN(strings.TrimSpace(string(contents)), "\n", 2)
+	return "", strings.TrimSpace(lines[0])
+}
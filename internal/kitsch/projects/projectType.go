@@ -4,6 +4,7 @@ package projects
 
 import (
 	"github.com/jwalton/kitsch/internal/kitsch/condition"
+	"github.com/jwalton/kitsch/internal/kitsch/getters"
 )
 
 //go:generate go run ../genSchema/main.go --private ProjectType
@@ -14,20 +15,70 @@ type ProjectType struct {
 	// Name is the name of this project type.
 	Name string `yaml:"name"`
 	// Style is a default style for this project type.
-	Style string `yaml:"style"`
+	Style string `yaml:"style,omitempty"`
 	// Conditions are the conditions that must be met for this project type to be used.
 	Conditions *condition.Conditions `yaml:"conditions,omitempty" jsonschema:",ref"`
 	// ToolSymbol is the default symbol to use for this project type.
-	ToolSymbol string `yaml:"toolSymbol"`
+	ToolSymbol string `yaml:"toolSymbol,omitempty"`
+	// Icon is an optional icon to show for this project type.
+	Icon string `yaml:"icon,omitempty"`
 	// ToolVersion is used to retrieve the version of the build tool for this project.
-	ToolVersion getterList `yaml:"toolVersion" jsonschema:",ref=GetterList"`
+	ToolVersion getterList `yaml:"toolVersion,omitempty" jsonschema:",ref=GetterList"`
+	// VersionCommand is a shorthand for ToolVersion.  If ToolVersion is not
+	// specified, this command will be run to find the version of the tool.  The
+	// output is cached until the executable changes.
+	VersionCommand string `yaml:"versionCommand,omitempty"`
+	// VersionFile is a shorthand for ToolVersion.  If ToolVersion is not
+	// specified, the tool version will be read from this file, which may be in
+	// the current folder or any ancestor folder.  If both VersionCommand and
+	// VersionFile are specified, VersionCommand is tried first.
+	VersionFile string `yaml:"versionFile,omitempty"`
+	// VersionRegex is a regular expression used to extract the version from the
+	// output of VersionCommand or the contents of VersionFile.  If the regex has
+	// a capture group, the first capture group is used as the version.
+	VersionRegex string `yaml:"versionRegex,omitempty"`
 	// PackageManagerSymbol is the optional default symbol to use for the
 	// package manager for this project type.
-	PackageManagerSymbol string `yaml:"packageManagerSymbol"`
+	PackageManagerSymbol string `yaml:"packageManagerSymbol,omitempty"`
 	// PackageManagerVersion is, if specified, used to retrieve the version of the
 	// package manager for this project.
-	PackageManagerVersion getterList `yaml:"packageManagerVersion"  jsonschema:",ref=GetterList"`
+	PackageManagerVersion getterList `yaml:"packageManagerVersion,omitempty"  jsonschema:",ref=GetterList"`
 	// PackageVersion is, if specified, used to retrieve the version of the
 	// project's package.
-	PackageVersion getterList `yaml:"packageVersion" jsonschema:",ref=GetterList"`
+	PackageVersion getterList `yaml:"packageVersion,omitempty" jsonschema:",ref=GetterList"`
+	// PinnedTool is the name of this project's tool in version files like
+	// `.tool-versions` or `mise.toml` (e.g. "node").  If set, the version pinned
+	// by these files will be compared against the installed ToolVersion.
+	PinnedTool string `yaml:"pinnedTool,omitempty"`
+	// PackageManagerDetection, if set, is used to detect which package manager
+	// is in use, instead of always using PackageManagerSymbol.  The only
+	// supported value is "javascript", which detects npm, yarn, pnpm, or bun
+	// from lockfiles and the "packageManager" field in package.json.
+	PackageManagerDetection string `yaml:"packageManagerDetection,omitempty" jsonschema:",enum=javascript"`
+}
+
+// toolVersionGetters returns the getters to use to find the version of this
+// project type's tool.
+func (projectType *ProjectType) toolVersionGetters() []getters.Getter {
+	if projectType.ToolVersion != nil {
+		return projectType.ToolVersion
+	}
+
+	result := []getters.Getter{}
+	if projectType.VersionCommand != "" {
+		result = append(result, getters.CustomGetter{
+			Type:  getters.TypeCustom,
+			From:  projectType.VersionCommand,
+			Regex: projectType.VersionRegex,
+			Cache: getters.CacheSettings{Enabled: true},
+		})
+	}
+	if projectType.VersionFile != "" {
+		result = append(result, getters.CustomGetter{
+			Type:  getters.TypeAncestorFile,
+			From:  projectType.VersionFile,
+			Regex: projectType.VersionRegex,
+		})
+	}
+	return result
 }
@@ -10,9 +10,15 @@ var projectTypeJSONSchema = `{
     "style": {"type": "string", "description": "Style is a default style for this project type."},
     "conditions": {"$ref": "#/definitions/Conditions"},
     "toolSymbol": {"type": "string", "description": "ToolSymbol is the default symbol to use for this project type."},
+    "icon": {"type": "string", "description": "Icon is an optional icon to show for this project type."},
     "toolVersion": {"$ref": "#/definitions/GetterList"},
+    "versionCommand": {"type": "string", "description": "VersionCommand is a shorthand for ToolVersion.  If ToolVersion is not specified, this command will be run to find the version of the tool.  The output is cached until the executable changes."},
+    "versionFile": {"type": "string", "description": "VersionFile is a shorthand for ToolVersion.  If ToolVersion is not specified, the tool version will be read from this file, which may be in the current folder or any ancestor folder.  If both VersionCommand and VersionFile are specified, VersionCommand is tried first."},
+    "versionRegex": {"type": "string", "description": "VersionRegex is a regular expression used to extract the version from the output of VersionCommand or the contents of VersionFile.  If the regex has a capture group, the first capture group is used as the version."},
     "packageManagerSymbol": {"type": "string", "description": "PackageManagerSymbol is the optional default symbol to use for the package manager for this project type."},
     "packageManagerVersion": {"$ref": "#/definitions/GetterList"},
-    "packageVersion": {"$ref": "#/definitions/GetterList"}
+    "packageVersion": {"$ref": "#/definitions/GetterList"},
+    "pinnedTool": {"type": "string", "description": "PinnedTool is the name of this project's tool in version files like ` + "`" + `.tool-versions` + "`" + ` or ` + "`" + `mise.toml` + "`" + ` (e.g. \"node\").  If set, the version pinned by these files will be compared against the installed ToolVersion."},
+    "packageManagerDetection": {"type": "string", "description": "PackageManagerDetection, if set, is used to detect which package manager is in use, instead of always using PackageManagerSymbol.  The only supported value is \"javascript\", which detects npm, yarn, pnpm, or bun from lockfiles and the \"packageManager\" field in package.json.", "enum": ["javascript"]}
   }}`
 
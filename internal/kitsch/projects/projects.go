@@ -4,8 +4,10 @@ package projects
 
 import (
 	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/kitsch/condition"
 	"github.com/jwalton/kitsch/internal/kitsch/getters"
 	"github.com/jwalton/kitsch/internal/kitsch/log"
+	"github.com/jwalton/kitsch/internal/kitsch/toolversions"
 )
 
 // ProjectInfo represents resolved information about the current project.
@@ -19,6 +21,8 @@ type ProjectInfo struct {
 	Style string
 	// ToolSymbol is the symbol for this project's build tool.
 	ToolSymbol string
+	// Icon is the icon for this project type, or "" if none.
+	Icon string
 	// ToolVersion is the version of this project's build tool.
 	ToolVersion string
 	// PackageManagerSymbol is, if available, the symbol for this project's package manager.
@@ -27,6 +31,27 @@ type ProjectInfo struct {
 	packageVersion              string
 	packageManagerVersionLoaded bool
 	packageVersionLoaded        bool
+	pin                         toolversions.Pin
+	pinLoaded                   bool
+	jsWorkspace                 *JSWorkspace
+}
+
+// WorkspaceName returns the name of the current package if it is a member of
+// a workspace (e.g. a yarn, npm, or pnpm workspace in a monorepo), or "" otherwise.
+func (projectInfo *ProjectInfo) WorkspaceName() string {
+	if projectInfo.jsWorkspace == nil || !projectInfo.jsWorkspace.IsMember {
+		return ""
+	}
+	return projectInfo.jsWorkspace.PackageName
+}
+
+// WorkspaceRoot returns the root folder of the workspace the current package
+// is a member of, or "" if the current package is not part of a workspace.
+func (projectInfo *ProjectInfo) WorkspaceRoot() string {
+	if projectInfo.jsWorkspace == nil || !projectInfo.jsWorkspace.IsMember {
+		return ""
+	}
+	return projectInfo.jsWorkspace.Root
 }
 
 // PackageManagerVersion is, if available, the version of this project's package manager.
@@ -55,6 +80,36 @@ func (projectInfo *ProjectInfo) PackageVersion() string {
 	return projectInfo.packageVersion
 }
 
+// PinnedVersion returns the version of this project's tool pinned by a version
+// file such as `.tool-versions` or `.nvmrc`, or "" if no version is pinned.
+func (projectInfo *ProjectInfo) PinnedVersion() string {
+	return projectInfo.getPin().Version
+}
+
+// PinnedVersionFile returns the path to the file which pinned the tool version,
+// or "" if no version is pinned.
+func (projectInfo *ProjectInfo) PinnedVersionFile() string {
+	return projectInfo.getPin().File
+}
+
+// VersionMismatch returns true if a version is pinned, and the installed
+// ToolVersion does not match it.
+func (projectInfo *ProjectInfo) VersionMismatch() bool {
+	pinned := projectInfo.PinnedVersion()
+	return pinned != "" && !toolversions.Matches(pinned, projectInfo.ToolVersion)
+}
+
+func (projectInfo *ProjectInfo) getPin() toolversions.Pin {
+	if !projectInfo.pinLoaded {
+		if projectInfo.projectType.PinnedTool != "" {
+			resolver := projectInfo.getterContext.GetToolVersions()
+			projectInfo.pin, _ = resolver.Get(projectInfo.projectType.PinnedTool)
+		}
+		projectInfo.pinLoaded = true
+	}
+	return projectInfo.pin
+}
+
 func getStringValue(getter []getters.Getter, getterContext getters.GetterContext) (string, error) {
 	if len(getter) == 0 {
 		return "", nil
@@ -84,32 +139,69 @@ func ResolveProjectType(
 	projectTypes []ProjectType,
 	getterContext getters.GetterContext,
 ) *ProjectInfo {
+	// If the getter context can also evaluate environment conditions, use it.
+	environment, _ := getterContext.(condition.Environment)
+
 	for _, projectType := range projectTypes {
-		if !projectType.Conditions.Matches(getterContext.GetWorkingDirectory()) {
+		if !projectType.Conditions.Matches(getterContext.GetWorkingDirectory(), environment) {
 			continue
 		}
 
-		toolVersion, err := getStringValue(projectType.ToolVersion, getterContext)
+		toolVersion, err := getStringValue(projectType.toolVersionGetters(), getterContext)
 		if err != nil || toolVersion == "" {
 			// If we can't get a toolVersion, skip this project type.
 			log.Info("Could not get tool version for project type", projectType.Name, err)
 			continue
 		}
 
-		return &ProjectInfo{
+		projectInfo := &ProjectInfo{
 			projectType:          projectType,
 			getterContext:        getterContext,
 			Name:                 projectType.Name,
 			Style:                projectType.Style,
 			ToolSymbol:           projectType.ToolSymbol,
+			Icon:                 projectType.Icon,
 			ToolVersion:          toolVersion,
 			PackageManagerSymbol: projectType.PackageManagerSymbol,
 		}
+
+		if projectType.PackageManagerDetection == DetectionJavaScript {
+			projectInfo.detectJSPackageManager()
+		}
+
+		return projectInfo
 	}
 
 	return nil
 }
 
+// detectJSPackageManager works out which JavaScript package manager is in use,
+// and replaces the package manager from the project type if it differs.
+func (projectInfo *ProjectInfo) detectJSPackageManager() {
+	workspace := FindJSWorkspace(projectInfo.getterContext.GetWorkingDirectory())
+	projectInfo.jsWorkspace = workspace
+	if workspace == nil || workspace.PackageManager == "" {
+		return
+	}
+
+	if workspace.PackageManagerVersion != "" {
+		// The "packageManager" field pins an exact version, which corepack
+		// will use, so there's no need to ask the package manager.
+		projectInfo.packageManagerVersion = workspace.PackageManagerVersion
+		projectInfo.packageManagerVersionLoaded = true
+	} else if workspace.PackageManager != projectInfo.PackageManagerSymbol {
+		projectInfo.projectType.PackageManagerVersion = []getters.Getter{
+			getters.CustomGetter{
+				Type:  getters.TypeCustom,
+				From:  workspace.PackageManager + " --version",
+				Cache: getters.CacheSettings{Enabled: true},
+			},
+		}
+	}
+
+	projectInfo.PackageManagerSymbol = workspace.PackageManager
+}
+
 //JSONSchemaDefinitions is a string containing JSON schema definitions for objects in the projects package.
 var JSONSchemaDefinitions = "\"ProjectType\": " + projectTypeJSONSchema + ",\n" +
 	heredoc.Doc(`"GetterList": {
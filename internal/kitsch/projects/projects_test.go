@@ -8,6 +8,7 @@ import (
 	"github.com/jwalton/kitsch/internal/cache"
 	"github.com/jwalton/kitsch/internal/fileutils"
 	"github.com/jwalton/kitsch/internal/kitsch/getters"
+	"github.com/jwalton/kitsch/internal/kitsch/toolversions"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,6 +29,8 @@ type testGetterContext struct {
 	home      string
 	cache     cache.Cache
 	env       map[string]string
+
+	toolVersions *toolversions.Resolver
 }
 
 // GetWorkingDirectory returns the current working directory.
@@ -45,11 +48,24 @@ func (context *testGetterContext) Getenv(key string) string {
 	return context.env[key]
 }
 
+// LookPath returns the path to the specified executable on the PATH.
+func (context *testGetterContext) LookPath(file string) (string, error) {
+	return fileutils.LookPathSafe(file)
+}
+
 // GetValueCache returns the value cache.
 func (context *testGetterContext) GetValueCache() cache.Cache {
 	return context.cache
 }
 
+// GetToolVersions returns the resolver for pinned tool versions.
+func (context *testGetterContext) GetToolVersions() *toolversions.Resolver {
+	if context.toolVersions == nil {
+		context.toolVersions = toolversions.NewResolver(context.directory)
+	}
+	return context.toolVersions
+}
+
 func makeTestGetterContext(fsys fstest.MapFS) *testGetterContext {
 	return &testGetterContext{
 		directory: fileutils.NewDirectoryTestFS("/foo/bar", fsys),
@@ -0,0 +1,304 @@
+package script
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// builtins are the functions available to every script.
+var builtins map[string]Function
+
+func init() {
+	builtins = map[string]Function{
+		"upper":      stringFunction(strings.ToUpper),
+		"lower":      stringFunction(strings.ToLower),
+		"trim":       stringFunction(strings.TrimSpace),
+		"basename":   stringFunction(path.Base),
+		"dirname":    stringFunction(path.Dir),
+		"trimPrefix": stringPairFunction(func(s, prefix string) interface{} { return strings.TrimPrefix(s, prefix) }),
+		"trimSuffix": stringPairFunction(func(s, suffix string) interface{} { return strings.TrimSuffix(s, suffix) }),
+		"hasPrefix":  stringPairFunction(func(s, prefix string) interface{} { return strings.HasPrefix(s, prefix) }),
+		"hasSuffix":  stringPairFunction(func(s, suffix string) interface{} { return strings.HasSuffix(s, suffix) }),
+		"split": stringPairFunction(func(s, sep string) interface{} {
+			parts := strings.Split(s, sep)
+			result := make([]interface{}, len(parts))
+			for index, part := range parts {
+				result[index] = part
+			}
+			return result
+		}),
+		"contains": builtinContains,
+		"default":  builtinDefault,
+		"join":     builtinJoin,
+		"len":      builtinLen,
+		"matches":  builtinMatches,
+		"number":   builtinNumber,
+		"replace":  builtinReplace,
+		"string":   builtinString,
+		"substr":   builtinSubstr,
+	}
+}
+
+// regexpCache caches compiled regular expressions used by `matches()`.
+var regexpCache = map[string]*regexp.Regexp{}
+var regexpCacheMutex sync.Mutex
+
+func checkArgCount(args []interface{}, min int, max int) error {
+	if len(args) < min || len(args) > max {
+		if min == max {
+			return fmt.Errorf("expected %d arguments, got %d", min, len(args))
+		}
+		return fmt.Errorf("expected %d to %d arguments, got %d", min, max, len(args))
+	}
+	return nil
+}
+
+func stringArg(args []interface{}, index int) (string, error) {
+	str, ok := args[index].(string)
+	if !ok {
+		return "", fmt.Errorf("argument %d must be a string, got %s", index+1, typeName(args[index]))
+	}
+	return str, nil
+}
+
+func numberArg(args []interface{}, index int) (int, error) {
+	number, ok := args[index].(float64)
+	if !ok {
+		return 0, fmt.Errorf("argument %d must be a number, got %s", index+1, typeName(args[index]))
+	}
+	return int(number), nil
+}
+
+// stringFunction creates a Function from a function which takes a string.
+func stringFunction(fn func(string) string) Function {
+	return func(args []interface{}) (interface{}, error) {
+		if err := checkArgCount(args, 1, 1); err != nil {
+			return nil, err
+		}
+		str, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return fn(str), nil
+	}
+}
+
+// stringPairFunction creates a Function from a function which takes two strings.
+func stringPairFunction(fn func(string, string) interface{}) Function {
+	return func(args []interface{}) (interface{}, error) {
+		if err := checkArgCount(args, 2, 2); err != nil {
+			return nil, err
+		}
+		first, err := stringArg(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		second, err := stringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return fn(first, second), nil
+	}
+}
+
+// builtinContains returns true if a string contains a substring, or a list
+// contains a value.
+func builtinContains(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	if str, ok := args[0].(string); ok {
+		substr, err := stringArg(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return strings.Contains(str, substr), nil
+	}
+
+	rv := reflect.ValueOf(args[0])
+	if args[0] != nil && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+		for index := 0; index < rv.Len(); index++ {
+			if equal(normalize(rv.Index(index).Interface()), args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return nil, fmt.Errorf("argument 1 must be a string or list, got %s", typeName(args[0]))
+}
+
+// builtinDefault returns the first argument if it is truthy, or the second
+// argument otherwise.
+func builtinDefault(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 2, 2); err != nil {
+		return nil, err
+	}
+	if IsTruthy(args[0]) {
+		return args[0], nil
+	}
+	return args[1], nil
+}
+
+func builtinJoin(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 2, 2); err != nil {
+		return nil, err
+	}
+	sep, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := reflect.ValueOf(args[0])
+	if args[0] == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("argument 1 must be a list, got %s", typeName(args[0]))
+	}
+
+	parts := make([]string, rv.Len())
+	for index := range parts {
+		parts[index] = ToString(normalize(rv.Index(index).Interface()))
+	}
+	return strings.Join(parts, sep), nil
+}
+
+func builtinLen(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+	length, ok := lengthOf(args[0])
+	if !ok {
+		return nil, fmt.Errorf("cannot get length of %s", typeName(args[0]))
+	}
+	return float64(length), nil
+}
+
+// builtinMatches returns true if a string matches a regular expression.
+func builtinMatches(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 2, 2); err != nil {
+		return nil, err
+	}
+	str, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := stringArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	regexpCacheMutex.Lock()
+	re, ok := regexpCache[pattern]
+	if !ok {
+		re, err = regexp.Compile(pattern)
+		if err == nil {
+			regexpCache[pattern] = re
+		}
+	}
+	regexpCacheMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return re.MatchString(str), nil
+}
+
+// builtinNumber converts a value to a number.
+func builtinNumber(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch v := args[0].(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return float64(1), nil
+		}
+		return float64(0), nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert %q to a number", v)
+		}
+		return number, nil
+	}
+
+	return nil, fmt.Errorf("cannot convert %s to a number", typeName(args[0]))
+}
+
+// builtinReplace replaces all occurrences of a string with another string.
+func builtinReplace(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 3, 3); err != nil {
+		return nil, err
+	}
+	parts := make([]string, 3)
+	for index := range parts {
+		str, err := stringArg(args, index)
+		if err != nil {
+			return nil, err
+		}
+		parts[index] = str
+	}
+	return strings.ReplaceAll(parts[0], parts[1], parts[2]), nil
+}
+
+func builtinString(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 1, 1); err != nil {
+		return nil, err
+	}
+	return ToString(args[0]), nil
+}
+
+// builtinSubstr returns the characters from `start` up to, but not including,
+// `end`.  Negative indexes count from the end of the string.
+func builtinSubstr(args []interface{}) (interface{}, error) {
+	if err := checkArgCount(args, 2, 3); err != nil {
+		return nil, err
+	}
+	str, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(str)
+
+	start, err := numberArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	end := len(runes)
+	if len(args) == 3 {
+		end, err = numberArg(args, 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	start = clampIndex(start, len(runes))
+	end = clampIndex(end, len(runes))
+	if start >= end {
+		return "", nil
+	}
+	return string(runes[start:end]), nil
+}
+
+// clampIndex converts a possibly negative index into an index between 0 and
+// length.
+func clampIndex(index int, length int) int {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
@@ -0,0 +1,378 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// node is a node in the syntax tree of a script.
+type node interface {
+	eval(environment *Environment) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+type listNode struct {
+	items []node
+}
+
+type variableNode struct {
+	name string
+	pos  int
+}
+
+type memberNode struct {
+	object node
+	key    node
+	pos    int
+}
+
+type callNode struct {
+	name string
+	args []node
+	pos  int
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+	pos     int
+}
+
+type binaryNode struct {
+	op    string
+	left  node
+	right node
+	pos   int
+}
+
+type ternaryNode struct {
+	condition node
+	ifTrue    node
+	ifFalse   node
+}
+
+func (n literalNode) eval(environment *Environment) (interface{}, error) {
+	return n.value, nil
+}
+
+func (n listNode) eval(environment *Environment) (interface{}, error) {
+	result := make([]interface{}, len(n.items))
+	for index, item := range n.items {
+		value, err := item.eval(environment)
+		if err != nil {
+			return nil, err
+		}
+		result[index] = value
+	}
+	return result, nil
+}
+
+func (n variableNode) eval(environment *Environment) (interface{}, error) {
+	value, ok := environment.Variables[n.name]
+	if !ok {
+		return nil, fmt.Errorf("%d: unknown variable %q", n.pos+1, n.name)
+	}
+	return normalize(value), nil
+}
+
+func (n memberNode) eval(environment *Environment) (interface{}, error) {
+	object, err := n.object.eval(environment)
+	if err != nil {
+		return nil, err
+	}
+	key, err := n.key.eval(environment)
+	if err != nil {
+		return nil, err
+	}
+	return member(object, key), nil
+}
+
+func (n callNode) eval(environment *Environment) (interface{}, error) {
+	fn, ok := environment.lookupFunction(n.name)
+	if !ok {
+		return nil, fmt.Errorf("%d: unknown function %q", n.pos+1, n.name)
+	}
+
+	args := make([]interface{}, len(n.args))
+	for index, arg := range n.args {
+		value, err := arg.eval(environment)
+		if err != nil {
+			return nil, err
+		}
+		args[index] = value
+	}
+
+	result, err := fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%d: %s: %w", n.pos+1, n.name, err)
+	}
+	return normalize(result), nil
+}
+
+func (n unaryNode) eval(environment *Environment) (interface{}, error) {
+	value, err := n.operand.eval(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "!" {
+		return !IsTruthy(value), nil
+	}
+
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%d: cannot negate %s", n.pos+1, typeName(value))
+	}
+	return -number, nil
+}
+
+func (n binaryNode) eval(environment *Environment) (interface{}, error) {
+	left, err := n.left.eval(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit `&&` and `||`.
+	switch n.op {
+	case "&&":
+		if !IsTruthy(left) {
+			return left, nil
+		}
+		return n.right.eval(environment)
+	case "||":
+		if IsTruthy(left) {
+			return left, nil
+		}
+		return n.right.eval(environment)
+	}
+
+	right, err := n.right.eval(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	}
+
+	// "+" concatenates if either side is a string.
+	if n.op == "+" {
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+		if leftIsString || rightIsString {
+			return ToString(left) + ToString(right), nil
+		}
+	}
+
+	// Strings can be compared with each other.
+	if leftString, ok := left.(string); ok {
+		if rightString, ok := right.(string); ok {
+			switch n.op {
+			case "<":
+				return leftString < rightString, nil
+			case "<=":
+				return leftString <= rightString, nil
+			case ">":
+				return leftString > rightString, nil
+			case ">=":
+				return leftString >= rightString, nil
+			}
+		}
+	}
+
+	leftNumber, leftOK := left.(float64)
+	rightNumber, rightOK := right.(float64)
+	if !leftOK || !rightOK {
+		return nil, fmt.Errorf("%d: cannot apply %q to %s and %s", n.pos+1, n.op, typeName(left), typeName(right))
+	}
+
+	switch n.op {
+	case "+":
+		return leftNumber + rightNumber, nil
+	case "-":
+		return leftNumber - rightNumber, nil
+	case "*":
+		return leftNumber * rightNumber, nil
+	case "/":
+		if rightNumber == 0 {
+			return nil, fmt.Errorf("%d: division by zero", n.pos+1)
+		}
+		return leftNumber / rightNumber, nil
+	case "%":
+		if rightNumber == 0 {
+			return nil, fmt.Errorf("%d: division by zero", n.pos+1)
+		}
+		return math.Mod(leftNumber, rightNumber), nil
+	case "<":
+		return leftNumber < rightNumber, nil
+	case "<=":
+		return leftNumber <= rightNumber, nil
+	case ">":
+		return leftNumber > rightNumber, nil
+	case ">=":
+		return leftNumber >= rightNumber, nil
+	}
+
+	return nil, fmt.Errorf("%d: unknown operator %q", n.pos+1, n.op)
+}
+
+func (n ternaryNode) eval(environment *Environment) (interface{}, error) {
+	condition, err := n.condition.eval(environment)
+	if err != nil {
+		return nil, err
+	}
+	if IsTruthy(condition) {
+		return n.ifTrue.eval(environment)
+	}
+	return n.ifFalse.eval(environment)
+}
+
+// normalize converts a value into one of the types scripts work with.  All
+// numbers are converted to float64, and lazy values are computed.
+func normalize(value interface{}) interface{} {
+	if lazy, ok := value.(LazyValue); ok {
+		value = lazy()
+	}
+
+	switch v := value.(type) {
+	case nil, string, bool, float64, []interface{}, map[string]interface{}:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	}
+	return value
+}
+
+// member returns the value of the given key in a map, struct, or list, or nil
+// if there is no such key.
+func member(object interface{}, key interface{}) interface{} {
+	if object == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(object)
+	switch rv.Kind() {
+	case reflect.Map:
+		name, ok := key.(string)
+		if !ok || rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		value := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !value.IsValid() {
+			return nil
+		}
+		return normalize(value.Interface())
+
+	case reflect.Struct:
+		name, ok := key.(string)
+		if !ok {
+			return nil
+		}
+		// Match fields case-insensitively, so "git.branch" finds "Branch".
+		field := rv.FieldByNameFunc(func(field string) bool { return strings.EqualFold(field, name) })
+		if field.IsValid() && field.CanInterface() {
+			return normalize(field.Interface())
+		}
+		// Values which are computed lazily are exposed as methods which take
+		// no arguments and return a single value.
+		for index := 0; index < rv.NumMethod(); index++ {
+			method := rv.Type().Method(index)
+			if strings.EqualFold(method.Name, name) &&
+				method.Type.NumIn() == 1 && method.Type.NumOut() == 1 {
+				return normalize(rv.Method(index).Call(nil)[0].Interface())
+			}
+		}
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		number, ok := key.(float64)
+		if !ok {
+			return nil
+		}
+		index := int(number)
+		if index < 0 {
+			index += rv.Len()
+		}
+		if index < 0 || index >= rv.Len() {
+			return nil
+		}
+		return normalize(rv.Index(index).Interface())
+	}
+
+	return nil
+}
+
+// equal returns true if two values are equal.
+func equal(left interface{}, right interface{}) bool {
+	if left == nil || right == nil {
+		return left == nil && right == nil
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// lengthOf returns the length of a string, list, or map.
+func lengthOf(value interface{}) (int, bool) {
+	if value == nil {
+		return 0, false
+	}
+	if str, ok := value.(string); ok {
+		return len([]rune(str)), true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len(), true
+	}
+	return 0, false
+}
+
+// typeName returns a description of the type of a value, for error messages.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		return "list"
+	default:
+		return "object"
+	}
+}
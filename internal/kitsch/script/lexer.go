@@ -0,0 +1,127 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenNumber
+	tokenString
+	tokenIdentifier
+	tokenOperator
+)
+
+type token struct {
+	typ   tokenType
+	value string
+	// pos is the offset of the token in the source.
+	pos int
+}
+
+// operators is the list of operators, with longer operators first so they
+// take precedence over their prefixes.
+var operators = []string{
+	"==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "!", "?", ":", "(", ")", "[", "]", ",", ".",
+}
+
+// lex splits a script into tokens.
+func lex(source string) ([]token, error) {
+	tokens := []token{}
+	pos := 0
+
+	for pos < len(source) {
+		ch, size := utf8.DecodeRuneInString(source[pos:])
+
+		switch {
+		case unicode.IsSpace(ch):
+			pos += size
+
+		case ch >= '0' && ch <= '9':
+			start := pos
+			for pos < len(source) && (isDigit(source[pos]) || source[pos] == '.') {
+				pos++
+			}
+			if _, err := strconv.ParseFloat(source[start:pos], 64); err != nil {
+				return nil, fmt.Errorf("%d: invalid number %q", start+1, source[start:pos])
+			}
+			tokens = append(tokens, token{typ: tokenNumber, value: source[start:pos], pos: start})
+
+		case ch == '_' || unicode.IsLetter(ch):
+			start := pos
+			for pos < len(source) {
+				next, nextSize := utf8.DecodeRuneInString(source[pos:])
+				if next != '_' && !(next >= '0' && next <= '9') && !unicode.IsLetter(next) {
+					break
+				}
+				pos += nextSize
+			}
+			tokens = append(tokens, token{typ: tokenIdentifier, value: source[start:pos], pos: start})
+
+		case ch == '"' || ch == '\'':
+			value, end, err := lexString(source, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{typ: tokenString, value: value, pos: pos})
+			pos = end
+
+		default:
+			found := false
+			for _, op := range operators {
+				if strings.HasPrefix(source[pos:], op) {
+					tokens = append(tokens, token{typ: tokenOperator, value: op, pos: pos})
+					pos += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("%d: unexpected character %q", pos+1, ch)
+			}
+		}
+	}
+
+	tokens = append(tokens, token{typ: tokenEOF, pos: len(source)})
+	return tokens, nil
+}
+
+// lexString reads a quoted string starting at `start`.  Returns the unquoted
+// value of the string, and the offset of the first character after the string.
+func lexString(source string, start int) (string, int, error) {
+	quote := source[start]
+	result := strings.Builder{}
+
+	for pos := start + 1; pos < len(source); pos++ {
+		ch := source[pos]
+		switch {
+		case ch == quote:
+			return result.String(), pos + 1, nil
+		case ch == '\\' && pos+1 < len(source):
+			pos++
+			switch source[pos] {
+			case 'n':
+				result.WriteByte('\n')
+			case 't':
+				result.WriteByte('\t')
+			default:
+				result.WriteByte(source[pos])
+			}
+		default:
+			result.WriteByte(ch)
+		}
+	}
+
+	return "", 0, fmt.Errorf("%d: unterminated string", start+1)
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,244 @@
+package script
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// parser is a recursive descent parser for scripts.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// binaryPrecedence lists the binary operators, from lowest to highest precedence.
+var binaryPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.typ != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isOperator returns true if the next token is one of the given operators.
+func (p *parser) isOperator(ops ...string) bool {
+	tok := p.peek()
+	if tok.typ != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if tok.value == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.isOperator(op) {
+		return p.unexpected()
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	tok := p.peek()
+	if tok.typ == tokenEOF {
+		return fmt.Errorf("%d: unexpected end of script", tok.pos+1)
+	}
+	return fmt.Errorf("%d: unexpected %q", tok.pos+1, tok.value)
+}
+
+func (p *parser) parseProgram() (node, error) {
+	result, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().typ != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return result, nil
+}
+
+func (p *parser) parseTernary() (node, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("?") {
+		return condition, nil
+	}
+	p.next()
+
+	ifTrue, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	ifFalse, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	return ternaryNode{condition: condition, ifTrue: ifTrue, ifFalse: ifFalse}, nil
+}
+
+func (p *parser) parseBinary(level int) (node, error) {
+	if level >= len(binaryPrecedence) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(binaryPrecedence[level]...) {
+		op := p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op.value, left: left, right: right, pos: op.pos}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("!", "-") {
+		op := p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: op.value, operand: operand, pos: op.pos}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	result, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isOperator("."):
+			p.next()
+			if p.peek().typ != tokenIdentifier {
+				return nil, p.unexpected()
+			}
+			name := p.next()
+			result = memberNode{object: result, key: literalNode{value: name.value}, pos: name.pos}
+
+		case p.isOperator("["):
+			pos := p.next().pos
+			key, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			result = memberNode{object: result, key: key, pos: pos}
+
+		default:
+			return result, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	start := p.pos
+	tok := p.next()
+
+	switch tok.typ {
+	case tokenNumber:
+		value, _ := strconv.ParseFloat(tok.value, 64)
+		return literalNode{value: value}, nil
+
+	case tokenString:
+		return literalNode{value: tok.value}, nil
+
+	case tokenIdentifier:
+		switch tok.value {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "nil":
+			return literalNode{value: nil}, nil
+		}
+
+		if p.isOperator("(") {
+			p.next()
+			args, err := p.parseList(")")
+			if err != nil {
+				return nil, err
+			}
+			return callNode{name: tok.value, args: args, pos: tok.pos}, nil
+		}
+		return variableNode{name: tok.value, pos: tok.pos}, nil
+
+	case tokenOperator:
+		switch tok.value {
+		case "(":
+			result, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			return result, p.expect(")")
+		case "[":
+			items, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return listNode{items: items}, nil
+		}
+	}
+
+	p.pos = start
+	return nil, p.unexpected()
+}
+
+// parseList parses a comma separated list of expressions, up to and including
+// the given closing operator.
+func (p *parser) parseList(closing string) ([]node, error) {
+	items := []node{}
+	if p.isOperator(closing) {
+		p.next()
+		return items, nil
+	}
+
+	for {
+		item, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if p.isOperator(",") {
+			p.next()
+			continue
+		}
+		return items, p.expect(closing)
+	}
+}
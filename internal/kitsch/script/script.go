@@ -0,0 +1,128 @@
+// Package script implements a small expression language which can be used to
+// compute values in a prompt.  Scripts are compiled once when the configuration
+// is loaded, and are evaluated in-process, so they are much cheaper than
+// running an external command every time the prompt is shown.
+//
+// A script is a single expression, for example:
+//
+//	git.branch == "main" ? "🚀" : upper(trimPrefix(git.branch, "feature/"))
+//
+// Scripts support string, number, boolean, and list literals, `nil`, the
+// operators `+ - * / % == != < <= > >= && || !`, the ternary operator `? :`,
+// member access with `.` or `[]`, and calls to functions.
+//
+package script
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Function is a function which can be called from a script.
+type Function func(args []interface{}) (interface{}, error)
+
+// LazyValue is a variable which is only computed if the script uses it.
+type LazyValue func() interface{}
+
+// Environment is the set of variables and functions available to a script.
+type Environment struct {
+	// Variables are the variables available to the script.  Values can be
+	// strings, numbers, booleans, maps with string keys, slices, structs, or
+	// a LazyValue.
+	Variables map[string]interface{}
+	// Functions are extra functions available to the script, in addition to
+	// the built-in functions.
+	Functions map[string]Function
+}
+
+// Program is a compiled script.
+type Program struct {
+	source string
+	root   node
+}
+
+// Compile compiles a script.
+func Compile(source string) (*Program, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+	root, err := p.parseProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{source: source, root: root}, nil
+}
+
+// Source returns the source code for the program.
+func (program *Program) Source() string {
+	return program.source
+}
+
+// Run evaluates the program and returns the result.
+func (program *Program) Run(environment Environment) (interface{}, error) {
+	return program.root.eval(&environment)
+}
+
+// lookupFunction finds the function with the given name.
+func (environment *Environment) lookupFunction(name string) (Function, bool) {
+	if fn, ok := environment.Functions[name]; ok {
+		return fn, true
+	}
+	fn, ok := builtins[name]
+	return fn, ok
+}
+
+// ToString converts the result of a script into a string.  `nil` and `false`
+// are converted to "".
+func ToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		if v {
+			return "true"
+		}
+		return ""
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for index, item := range v {
+			parts[index] = ToString(item)
+		}
+		return strings.Join(parts, " ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// IsTruthy returns false if the value is nil, false, 0, "", or an empty list or
+// map, and true otherwise.
+func IsTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		if length, ok := lengthOf(v); ok {
+			return length != 0
+		}
+		return true
+	}
+}
@@ -0,0 +1,142 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testGlobals struct {
+	CWD  string
+	Jobs int
+}
+
+func (globals testGlobals) Hostname() string {
+	return "lucid"
+}
+
+func run(t *testing.T, source string) interface{} {
+	program, err := Compile(source)
+	require.NoError(t, err)
+
+	result, err := program.Run(Environment{
+		Variables: map[string]interface{}{
+			"globals": testGlobals{CWD: "/Users/jwalton/dev", Jobs: 2},
+			"branch":  "feature/widgets",
+			"tags":    []string{"v1.0.0", "v1.1.0"},
+			"obj":     map[string]interface{}{"name": "kitsch", "nested": map[string]string{"a": "b"}},
+			"lazy":    LazyValue(func() interface{} { return "computed" }),
+			"café":    "au lait",
+		},
+		Functions: map[string]Function{
+			"greet": func(args []interface{}) (interface{}, error) { return "hello " + ToString(args[0]), nil },
+		},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestLiterals(t *testing.T) {
+	assert.Equal(t, float64(12.5), run(t, "12.5"))
+	assert.Equal(t, "a \"b\"\n", run(t, `"a \"b\"\n"`))
+	assert.Equal(t, "single", run(t, `'single'`))
+	assert.Equal(t, true, run(t, "true"))
+	assert.Equal(t, nil, run(t, "nil"))
+	assert.Equal(t, []interface{}{float64(1), "two"}, run(t, `[1, "two"]`))
+}
+
+func TestUnicode(t *testing.T) {
+	assert.Equal(t, "au lait", run(t, "café"))
+	assert.Equal(t, "héllo ✓", run(t, `"héllo ✓"`))
+	assert.Equal(t, "café au lait", run(t, `"café " + café`))
+}
+
+func TestOperators(t *testing.T) {
+	assert.Equal(t, float64(7), run(t, "1 + 2 * 3"))
+	assert.Equal(t, float64(9), run(t, "(1 + 2) * 3"))
+	assert.Equal(t, float64(1), run(t, "7 % 3"))
+	assert.Equal(t, float64(-2), run(t, "-2"))
+	assert.Equal(t, "jobs: 2", run(t, `"jobs: " + globals.jobs`))
+	assert.Equal(t, true, run(t, `globals.Jobs >= 2 && branch != "main"`))
+	assert.Equal(t, true, run(t, `"a" < "b"`))
+	assert.Equal(t, false, run(t, `!branch`))
+	assert.Equal(t, "fallback", run(t, `"" || "fallback"`))
+	assert.Equal(t, "yes", run(t, `globals.Jobs > 1 ? "yes" : "no"`))
+	assert.Equal(t, "b", run(t, `false ? "a" : true ? "b" : "c"`))
+}
+
+func TestMemberAccess(t *testing.T) {
+	assert.Equal(t, "/Users/jwalton/dev", run(t, "globals.CWD"))
+	assert.Equal(t, "/Users/jwalton/dev", run(t, "globals.cwd"))
+	assert.Equal(t, "lucid", run(t, "globals.hostname"))
+	assert.Equal(t, "kitsch", run(t, `obj["name"]`))
+	assert.Equal(t, "b", run(t, "obj.nested.a"))
+	assert.Equal(t, nil, run(t, "obj.missing.a"))
+	assert.Equal(t, "v1.1.0", run(t, "tags[-1]"))
+	assert.Equal(t, nil, run(t, "tags[5]"))
+	assert.Equal(t, "computed", run(t, "lazy"))
+}
+
+func TestFunctions(t *testing.T) {
+	assert.Equal(t, "WIDGETS", run(t, `upper(trimPrefix(branch, "feature/"))`))
+	assert.Equal(t, "hello world", run(t, `greet("world")`))
+	assert.Equal(t, true, run(t, `hasPrefix(branch, "feature/")`))
+	assert.Equal(t, true, run(t, `contains(tags, "v1.0.0")`))
+	assert.Equal(t, float64(15), run(t, `len(branch)`))
+	assert.Equal(t, "feat", run(t, `substr(branch, 0, 4)`))
+	assert.Equal(t, "gets", run(t, `substr(branch, -4)`))
+	assert.Equal(t, "feature-widgets", run(t, `replace(branch, "/", "-")`))
+	assert.Equal(t, "widgets", run(t, `split(branch, "/")[1]`))
+	assert.Equal(t, "v1.0.0,v1.1.0", run(t, `join(tags, ",")`))
+	assert.Equal(t, true, run(t, `matches(branch, "^feature/w")`))
+	assert.Equal(t, "none", run(t, `default(obj.missing, "none")`))
+	assert.Equal(t, float64(42), run(t, `number("42")`))
+	assert.Equal(t, "dev", run(t, `basename(globals.cwd)`))
+}
+
+func TestCompileErrors(t *testing.T) {
+	for source, message := range map[string]string{
+		`1 +`:         "4: unexpected end of script",
+		`"unclosed`:   "1: unterminated string",
+		`(1 + 2`:      "7: unexpected end of script",
+		`a b`:         `3: unexpected "b"`,
+		`1 ? 2`:       "6: unexpected end of script",
+		`obj.`:        "5: unexpected end of script",
+		`foo(1,)`:     `7: unexpected ")"`,
+		"1 # comment": `3: unexpected character '#'`,
+		"1 € 2":       `3: unexpected character '€'`,
+	} {
+		_, err := Compile(source)
+		if assert.Error(t, err, source) {
+			assert.Equal(t, message, err.Error(), source)
+		}
+	}
+}
+
+func TestRuntimeErrors(t *testing.T) {
+	for source, message := range map[string]string{
+		`missing`:         `1: unknown variable "missing"`,
+		`nope()`:          `1: unknown function "nope"`,
+		`upper(1)`:        "1: upper: argument 1 must be a string, got number",
+		`1 - "a"`:         `3: cannot apply "-" to number and string`,
+		`1 / 0`:           "3: division by zero",
+		`upper("a", "b")`: "1: upper: expected 1 arguments, got 2",
+	} {
+		program, err := Compile(source)
+		require.NoError(t, err, source)
+		_, err = program.Run(Environment{})
+		if assert.Error(t, err, source) {
+			assert.Equal(t, message, err.Error(), source)
+		}
+	}
+}
+
+func TestToString(t *testing.T) {
+	assert.Equal(t, "", ToString(nil))
+	assert.Equal(t, "", ToString(false))
+	assert.Equal(t, "true", ToString(true))
+	assert.Equal(t, "3", ToString(float64(3)))
+	assert.Equal(t, "3.25", ToString(3.25))
+	assert.Equal(t, "a b", ToString([]interface{}{"a", "b"}))
+}
@@ -0,0 +1,167 @@
+// Package snapshot renders a prompt configuration in a set of fixtures, and
+// compares the results against previously recorded "golden" files.  This lets
+// maintainers and theme authors lock down exactly what a prompt looks like,
+// and catch any change to it.
+//
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/demo"
+	"github.com/jwalton/kitsch/internal/kitsch/modtemplate"
+	"github.com/jwalton/kitsch/internal/kitsch/modules"
+	"github.com/jwalton/kitsch/internal/kitsch/styling"
+)
+
+// DefaultTime is the current time used by fixtures which don't specify a time,
+// so modules like "time" render the same thing every time.
+var DefaultTime = time.Date(2022, time.January, 20, 19, 3, 12, 0, time.UTC)
+
+// Fixture is a situation to render the prompt in.
+type Fixture struct {
+	// Name is the name of the fixture.  This is used as the name of the
+	// golden file.
+	Name string
+	// Config is the demo context to render the prompt with.
+	Config modules.DemoConfig
+}
+
+// Status is the result of comparing a rendered prompt to its golden file.
+type Status string
+
+const (
+	// StatusMatched means the prompt matched the golden file.
+	StatusMatched Status = "matched"
+	// StatusChanged means the prompt did not match the golden file.
+	StatusChanged Status = "changed"
+	// StatusMissing means there is no golden file for the fixture.
+	StatusMissing Status = "missing"
+	// StatusUpdated means the golden file was written.
+	StatusUpdated Status = "updated"
+)
+
+// Result is the result of checking a single fixture.
+type Result struct {
+	// Fixture is the name of the fixture.
+	Fixture string
+	// GoldenFile is the path to the golden file for the fixture.
+	GoldenFile string
+	// Status is the result of comparing the prompt to the golden file.
+	Status Status
+	// Expected is the contents of the golden file, or "" if it is missing.
+	Expected string
+	// Actual is the rendered prompt.
+	Actual string
+	// Warnings are any warnings generated while rendering the prompt.
+	Warnings []string
+}
+
+// DemoFixtures returns a fixture for each of the scenarios shown by
+// `kitsch demo`.
+func DemoFixtures() []Fixture {
+	scenarios := demo.Scenarios()
+	fixtures := make([]Fixture, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		fixtures = append(fixtures, Fixture{Name: scenario.Name, Config: scenario.Config})
+	}
+	return fixtures
+}
+
+// LoadFixtures loads every ".yaml" file in the given folder as a fixture.
+// Each file is in the same format as the file passed to
+// `kitsch prompt --demo`, and the name of the fixture is the name of the file
+// without the extension.
+func LoadFixtures(dir string) ([]Fixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	fixtures := make([]Fixture, 0, len(files))
+	for _, file := range files {
+		fixture := Fixture{Name: strings.TrimSuffix(filepath.Base(file), ".yaml")}
+		if err := fixture.Config.Load(file); err != nil {
+			return nil, errors.New("error loading fixture " + file + ": " + err.Error())
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// Render renders the prompt from the given configuration in the given
+// fixture.  The prompt is always rendered with full color support, and escape
+// characters are replaced with "\e", so the result is readable and can be
+// compared with a diff tool.
+func Render(configuration *config.Config, fixture Fixture) (string, []string) {
+	demoConfig := fixture.Config
+	if demoConfig.Time.IsZero() {
+		demoConfig.Time = DefaultTime
+	}
+
+	level := gchalk.GetLevel()
+	gchalk.SetLevel(gchalk.LevelAnsi16m)
+	defer gchalk.SetLevel(level)
+
+	styles := styling.Registry{}
+	styles.AddCustomColors(configuration.ColorsForBackground(""))
+
+	context := modules.NewDemoContext(demoConfig, &styles)
+	context.ProjectTypes = configuration.ProjectsTypes
+	context.Partials, _ = modtemplate.CompilePartials(configuration.Templates)
+
+	_, prompt := modules.RenderPrompt(&context, configuration.Prompt)
+	return strings.ReplaceAll(prompt, "\x1b", `\e`), context.Warnings()
+}
+
+// Check renders each fixture, and compares the result to the fixture's
+// golden file in `goldenDir`.  If `update` is true, golden files which are
+// missing or don't match are written instead.
+func Check(configuration *config.Config, fixtures []Fixture, goldenDir string, update bool) ([]Result, error) {
+	results := make([]Result, 0, len(fixtures))
+
+	for _, fixture := range fixtures {
+		actual, warnings := Render(configuration, fixture)
+		result := Result{
+			Fixture:    fixture.Name,
+			GoldenFile: filepath.Join(goldenDir, fixture.Name+".golden"),
+			Actual:     actual,
+			Warnings:   warnings,
+		}
+
+		expected, err := os.ReadFile(result.GoldenFile)
+		switch {
+		case os.IsNotExist(err):
+			result.Status = StatusMissing
+		case err != nil:
+			return nil, err
+		case string(expected) == actual+"\n":
+			result.Status = StatusMatched
+		default:
+			result.Status = StatusChanged
+		}
+		result.Expected = strings.TrimSuffix(string(expected), "\n")
+
+		if update && result.Status != StatusMatched {
+			if err := os.MkdirAll(goldenDir, 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(result.GoldenFile, []byte(actual+"\n"), 0644); err != nil {
+				return nil, err
+			}
+			result.Status = StatusUpdated
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
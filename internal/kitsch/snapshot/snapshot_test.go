@@ -0,0 +1,71 @@
+package snapshot
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/jwalton/kitsch/internal/kitsch/projects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// TestDefaultConfig locks down the output of the default configuration.  If
+// you change the default configuration on purpose, run
+// `go test ./internal/kitsch/snapshot -update` to update the golden files.
+func TestDefaultConfig(t *testing.T) {
+	configuration, err := config.LoadDefaultConfig()
+	require.NoError(t, err)
+	configuration.ProjectsTypes = projects.MergeProjectTypes(
+		configuration.ProjectsTypes,
+		projects.DefaultProjectTypes,
+		true,
+	)
+
+	fixtures, err := LoadFixtures("testdata")
+	require.NoError(t, err)
+	fixtures = append(DemoFixtures(), fixtures...)
+
+	results, err := Check(configuration, fixtures, "testdata", *update)
+	require.NoError(t, err)
+	assert.Len(t, results, len(fixtures))
+
+	for _, result := range results {
+		if !*update {
+			assert.NotEqual(t, StatusMissing, result.Status, result.Fixture)
+			assert.Equal(t, result.Expected, result.Actual, result.Fixture)
+		}
+		assert.Empty(t, result.Warnings, result.Fixture)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	configuration, err := config.LoadDefaultConfig()
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	fixtures := DemoFixtures()[:1]
+
+	results, err := Check(configuration, fixtures, dir, false)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMissing, results[0].Status)
+
+	results, err = Check(configuration, fixtures, dir, true)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUpdated, results[0].Status)
+
+	results, err = Check(configuration, fixtures, dir, false)
+	require.NoError(t, err)
+	assert.Equal(t, StatusMatched, results[0].Status)
+
+	goldenFile := filepath.Join(dir, fixtures[0].Name+".golden")
+	require.NoError(t, os.WriteFile(goldenFile, []byte("something else\n"), 0644))
+	results, err = Check(configuration, fixtures, dir, false)
+	require.NoError(t, err)
+	assert.Equal(t, StatusChanged, results[0].Status)
+	assert.Equal(t, "something else", results[0].Expected)
+}
@@ -0,0 +1,171 @@
+package starship
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenText tokenKind = iota
+	tokenVariable
+)
+
+// formatToken is a piece of a starship format string.
+type formatToken struct {
+	kind tokenKind
+	// value is the literal text for a text token, or the variable name for
+	// a variable token.
+	value string
+	// style is the style of the text group this token is in, or "" if the
+	// token is not in a styled group.
+	style string
+}
+
+// parseFormat parses a starship format string (e.g. "[$user]($style) in ")
+// into a flat list of tokens.  Conditional groups like "($var)" are treated as
+// if they were always shown.
+func parseFormat(format string) []formatToken {
+	parser := formatParser{input: []rune(format)}
+	return parser.parse("")
+}
+
+type formatParser struct {
+	input    []rune
+	position int
+}
+
+func (parser *formatParser) peek() rune {
+	if parser.position >= len(parser.input) {
+		return 0
+	}
+	return parser.input[parser.position]
+}
+
+// parse parses tokens until the end of the input.  style is the style of the
+// group being parsed.
+func (parser *formatParser) parse(style string) []formatToken {
+	var tokens []formatToken
+	var text strings.Builder
+
+	flushText := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, formatToken{kind: tokenText, value: text.String(), style: style})
+			text.Reset()
+		}
+	}
+
+	for parser.position < len(parser.input) {
+		c := parser.input[parser.position]
+
+		switch {
+		case c == '\\' && parser.position+1 < len(parser.input):
+			text.WriteRune(parser.input[parser.position+1])
+			parser.position += 2
+
+		case c == '$':
+			flushText()
+			parser.position++
+			tokens = append(tokens, formatToken{kind: tokenVariable, value: parser.variableName(), style: style})
+
+		case c == '[':
+			flushText()
+			parser.position++
+			groupStart := parser.position
+			groupEnd := parser.skipGroup('[', ']')
+
+			groupStyle := style
+			if parser.peek() == '(' {
+				parser.position++
+				styleStart := parser.position
+				styleEnd := parser.skipGroup('(', ')')
+				groupStyle = string(parser.input[styleStart:styleEnd])
+			}
+
+			inner := formatParser{input: parser.input[groupStart:groupEnd]}
+			tokens = append(tokens, inner.parse(groupStyle)...)
+
+		case c == '(':
+			// Conditional group.
+			flushText()
+			parser.position++
+			groupStart := parser.position
+			groupEnd := parser.skipGroup('(', ')')
+			inner := formatParser{input: parser.input[groupStart:groupEnd]}
+			tokens = append(tokens, inner.parse(style)...)
+
+		default:
+			text.WriteRune(c)
+			parser.position++
+		}
+	}
+
+	flushText()
+	return tokens
+}
+
+// variableName reads a variable name, either "name" or "{name}".
+func (parser *formatParser) variableName() string {
+	if parser.peek() == '{' {
+		start := parser.position + 1
+		for parser.position < len(parser.input) && parser.input[parser.position] != '}' {
+			parser.position++
+		}
+		name := string(parser.input[start:parser.position])
+		parser.position++
+		return name
+	}
+
+	start := parser.position
+	for parser.position < len(parser.input) {
+		c := parser.input[parser.position]
+		if !(c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			break
+		}
+		parser.position++
+	}
+	return string(parser.input[start:parser.position])
+}
+
+// skipGroup advances past the closing character of a group, allowing for
+// nested groups and escapes.  The parser should be positioned just after the
+// opening character.  Returns the position of the closing character, or the
+// end of the input if the group is never closed.
+func (parser *formatParser) skipGroup(open rune, close rune) int {
+	depth := 1
+	for parser.position < len(parser.input) {
+		c := parser.input[parser.position]
+		parser.position++
+		switch c {
+		case '\\':
+			parser.position++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return parser.position - 1
+			}
+		}
+	}
+	if parser.position > len(parser.input) {
+		parser.position = len(parser.input)
+	}
+	return parser.position
+}
+
+// formatText returns the literal text from a format string, ignoring any
+// variables, along with the first style used.  This is used for things like
+// the character module's "[➜](bold green)" symbols.
+func formatText(format string) (text string, style string) {
+	var result strings.Builder
+	for _, token := range parseFormat(format) {
+		if token.kind == tokenText {
+			result.WriteString(token.value)
+			if style == "" {
+				style = token.style
+			}
+		}
+	}
+	return result.String(), style
+}
@@ -0,0 +1,48 @@
+package starship
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	tokens := parseFormat(`[$user]($style) in [\[x\]](bold red)$directory(\($git_branch\))`)
+
+	assert.Equal(t, []formatToken{
+		{kind: tokenVariable, value: "user", style: "$style"},
+		{kind: tokenText, value: " in "},
+		{kind: tokenText, value: "[x]", style: "bold red"},
+		{kind: tokenVariable, value: "directory"},
+		{kind: tokenText, value: "("},
+		{kind: tokenVariable, value: "git_branch"},
+		{kind: tokenText, value: ")"},
+	}, tokens)
+}
+
+func TestParseFormatBracedVariable(t *testing.T) {
+	tokens := parseFormat(`${custom.foo}bar`)
+
+	assert.Equal(t, []formatToken{
+		{kind: tokenVariable, value: "custom.foo"},
+		{kind: tokenText, value: "bar"},
+	}, tokens)
+}
+
+func TestParseFormatUnclosedGroup(t *testing.T) {
+	tokens := parseFormat(`[$user`)
+
+	assert.Equal(t, []formatToken{
+		{kind: tokenVariable, value: "user"},
+	}, tokens)
+}
+
+func TestFormatText(t *testing.T) {
+	text, style := formatText("[➜](bold green)")
+	assert.Equal(t, "➜", text)
+	assert.Equal(t, "bold green", style)
+
+	text, style = formatText("$symbol> ")
+	assert.Equal(t, "> ", text)
+	assert.Equal(t, "", style)
+}
@@ -0,0 +1,350 @@
+package starship
+
+import (
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// moduleConfig is the configuration for a single starship module.  It keeps
+// track of which keys have been read, so we can report any that weren't.
+type moduleConfig struct {
+	name string
+	raw  map[string]interface{}
+	used map[string]bool
+}
+
+func newModuleConfig(name string, raw map[string]interface{}) *moduleConfig {
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return &moduleConfig{
+		name: name,
+		raw:  raw,
+		// "disabled" is handled for every module.
+		used: map[string]bool{"disabled": true},
+	}
+}
+
+func (config *moduleConfig) string(key string) (string, bool) {
+	config.used[key] = true
+	value, ok := config.raw[key].(string)
+	return value, ok
+}
+
+func (config *moduleConfig) bool(key string) (bool, bool) {
+	config.used[key] = true
+	value, ok := config.raw[key].(bool)
+	return value, ok
+}
+
+func (config *moduleConfig) int(key string) (int64, bool) {
+	config.used[key] = true
+	value, ok := config.raw[key].(int64)
+	return value, ok
+}
+
+func (config *moduleConfig) stringMap(key string) (map[string]string, bool) {
+	config.used[key] = true
+	raw, ok := config.raw[key].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	result := make(map[string]string, len(raw))
+	for name, value := range raw {
+		if str, ok := value.(string); ok {
+			result[name] = str
+		}
+	}
+	return result, true
+}
+
+func (config *moduleConfig) markAllUsed() {
+	for key := range config.raw {
+		config.used[key] = true
+	}
+}
+
+// unused returns a sorted list of keys that were never read.
+func (config *moduleConfig) unused() []string {
+	var result []string
+	for key := range config.raw {
+		if !config.used[key] {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// copyString copies a string value from the starship config to the module.
+func copyString(config *moduleConfig, module *yaml.Node, from string, to string) {
+	if value, ok := config.string(from); ok {
+		addValue(module, to, value)
+	}
+}
+
+// copyInt copies an integer value from the starship config to the module.
+func copyInt(config *moduleConfig, module *yaml.Node, from string, to string) {
+	if value, ok := config.int(from); ok {
+		addValue(module, to, value)
+	}
+}
+
+// copyBool copies a boolean value from the starship config to the module.
+func copyBool(config *moduleConfig, module *yaml.Node, from string, to string) {
+	if value, ok := config.bool(from); ok {
+		addValue(module, to, value)
+	}
+}
+
+// copyStyle copies a style from the starship config to the module.
+func (converter *converter) copyStyle(config *moduleConfig, module *yaml.Node, from string, to string, notes *[]string) {
+	if value, ok := config.string(from); ok {
+		converter.addStyle(module, to, value, notes)
+	}
+}
+
+type moduleConverter func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string)
+
+// moduleConverters converts each starship module into the equivalent kitsch module.
+var moduleConverters = map[string]moduleConverter{
+	"username": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "username")
+		converter.copyStyle(config, module, "style_user", "style", notes)
+		converter.copyStyle(config, module, "style_root", "rootStyle", notes)
+		copyBool(config, module, "show_always", "showAlways")
+	},
+
+	"hostname": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "hostname")
+		converter.copyStyle(config, module, "style", "style", notes)
+		if sshOnly, ok := config.bool("ssh_only"); ok && !sshOnly {
+			addValue(module, "showAlways", true)
+		}
+	},
+
+	"directory": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "directory")
+		converter.copyStyle(config, module, "style", "style", notes)
+		copyInt(config, module, "truncation_length", "truncationLength")
+		copyBool(config, module, "truncate_to_repo", "truncateToRepo")
+		copyString(config, module, "truncation_symbol", "truncationSymbol")
+		copyString(config, module, "home_symbol", "homeSymbol")
+		copyString(config, module, "read_only", "readOnlySymbol")
+		config.used["read_only_style"] = true
+	},
+
+	"git_branch": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "git_head")
+		converter.copyStyle(config, module, "style", "style", notes)
+		if symbol, ok := config.string("symbol"); ok && symbol != "" {
+			addValue(module, "template", "{{ with .Text }}"+symbol+"{{ . }}{{ end }}")
+		}
+	},
+
+	"git_commit": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		// The git_head module shows the commit when HEAD is detached.
+		config.markAllUsed()
+	},
+
+	"git_state": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "git_state")
+		converter.copyStyle(config, module, "style", "style", notes)
+		copyString(config, module, "rebase", "rebasing")
+		copyString(config, module, "merge", "merging")
+		copyString(config, module, "revert", "reverting")
+		copyString(config, module, "cherry_pick", "cherryPicking")
+		copyString(config, module, "bisect", "bisecting")
+		copyString(config, module, "am", "aming")
+		copyString(config, module, "am_or_rebase", "rebaseAMing")
+	},
+
+	"git_status": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		// Starship shows ahead/behind in git_status; kitsch has a separate
+		// git_diverged module for this.
+		diverged := &yaml.Node{Kind: yaml.MappingNode}
+		addValue(diverged, "type", "git_diverged")
+		copyString(config, diverged, "ahead", "aheadSymbol")
+		copyString(config, diverged, "behind", "behindSymbol")
+		copyString(config, diverged, "diverged", "divergedSymbol")
+		copyString(config, diverged, "up_to_date", "upToDateSymbol")
+
+		status := &yaml.Node{Kind: yaml.MappingNode}
+		addValue(status, "type", "git_status")
+
+		addValue(module, "type", "block")
+		converter.copyStyle(config, module, "style", "style", notes)
+		addNode(module, "modules", &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{diverged, status}})
+	},
+
+	"cmd_duration": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "command_duration")
+		converter.copyStyle(config, module, "style", "style", notes)
+		copyInt(config, module, "min_time", "minTime")
+		copyBool(config, module, "show_milliseconds", "showMilliseconds")
+	},
+
+	"character": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "prompt")
+
+		successSymbol, ok := config.string("success_symbol")
+		if !ok {
+			successSymbol = "[❯](bold green)"
+		}
+		text, style := formatText(successSymbol)
+		addValue(module, "prompt", strings.TrimSpace(text)+" ")
+		converter.addStyle(module, "style", style, notes)
+
+		if errorSymbol, ok := config.string("error_symbol"); ok {
+			errorText, errorStyle := formatText(errorSymbol)
+			converter.addStyle(module, "errorStyle", errorStyle, notes)
+			if strings.TrimSpace(errorText) != strings.TrimSpace(text) {
+				*notes = append(*notes, "kitsch uses the same prompt for errors; error_symbol text was not translated")
+			}
+		} else {
+			addValue(module, "errorStyle", "bold red")
+		}
+
+		if vicmdSymbol, ok := config.string("vimcmd_symbol"); ok {
+			vicmdText, vicmdStyle := formatText(vicmdSymbol)
+			addValue(module, "vicmdPrompt", strings.TrimSpace(vicmdText)+" ")
+			converter.addStyle(module, "vicmdStyle", vicmdStyle, notes)
+		}
+	},
+
+	"time": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "time")
+		converter.copyStyle(config, module, "style", "style", notes)
+		if timeFormat, ok := config.string("time_format"); ok {
+			layout, unsupported := strftimeToLayout(timeFormat)
+			addValue(module, "layout", layout)
+			if len(unsupported) > 0 {
+				*notes = append(*notes, "unsupported time_format directives: "+strings.Join(unsupported, " "))
+			}
+		}
+	},
+
+	"jobs": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "jobs")
+		converter.copyStyle(config, module, "style", "style", notes)
+		copyString(config, module, "symbol", "symbol")
+		copyInt(config, module, "symbol_threshold", "symbolThreshold")
+		copyInt(config, module, "number_threshold", "countThreshold")
+	},
+
+	"kubernetes": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "kubernetes")
+		converter.copyStyle(config, module, "style", "style", notes)
+		copyString(config, module, "symbol", "symbol")
+		if aliases, ok := config.stringMap("context_aliases"); ok {
+			aliasesNode := &yaml.Node{Kind: yaml.MappingNode}
+			for _, name := range sortedKeys(aliases) {
+				addValue(aliasesNode, name, aliases[name])
+			}
+			addNode(module, "contextAliases", aliasesNode)
+		}
+	},
+
+	"package": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "package")
+		converter.copyStyle(config, module, "style", "style", notes)
+		if symbol, ok := config.string("symbol"); ok && symbol != "" {
+			addValue(module, "template", "{{ with .Text }}"+symbol+"{{ . }}{{ end }}")
+		}
+	},
+
+	"fill": func(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+		addValue(module, "type", "flexible_space")
+		config.used["symbol"] = true
+		config.used["style"] = true
+	},
+}
+
+// projectTypes maps starship language modules to kitsch project types.
+var projectTypes = map[string]string{
+	"dart":    "dart",
+	"deno":    "deno",
+	"elixir":  "elixir",
+	"golang":  "go",
+	"haskell": "haskell",
+	"helm":    "helm",
+	"java":    "java",
+	"julia":   "julia",
+	"lua":     "lua",
+	"nodejs":  "node",
+	"php":     "php",
+	"python":  "python",
+	"ruby":    "ruby",
+	"rust":    "rust",
+	"swift":   "swift",
+	"zig":     "zig",
+}
+
+// convertProject converts all of starship's language modules into a single
+// project module.
+func (converter *converter) convertProject(module *yaml.Node, notes *[]string) {
+	addValue(module, "type", "project")
+
+	starshipNames := make([]string, 0, len(projectTypes))
+	for name := range projectTypes {
+		starshipNames = append(starshipNames, name)
+	}
+	sort.Strings(starshipNames)
+
+	projects := &yaml.Node{Kind: yaml.MappingNode}
+	for _, starshipName := range starshipNames {
+		rawConfig, ok := converter.config[starshipName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		config := newModuleConfig(starshipName, rawConfig)
+
+		project := &yaml.Node{Kind: yaml.MappingNode}
+		converter.copyStyle(config, project, "style", "style", notes)
+		copyString(config, project, "symbol", "toolSymbol")
+		if disabled, ok := config.bool("disabled"); ok && disabled {
+			*notes = append(*notes, starshipName+" is disabled in starship, but will be shown by the project module")
+		}
+		if unused := config.unused(); len(unused) > 0 {
+			*notes = append(*notes, starshipName+": not translated: "+strings.Join(unused, ", "))
+		}
+		if len(project.Content) > 0 {
+			addNode(projects, projectTypes[starshipName], project)
+		}
+	}
+
+	if len(projects.Content) > 0 {
+		addNode(module, "projects", projects)
+	}
+}
+
+// convertCustom converts a starship custom command into a custom module.
+func convertCustom(converter *converter, config *moduleConfig, module *yaml.Node, notes *[]string) {
+	command, ok := config.string("command")
+	if !ok {
+		*notes = append(*notes, "custom module has no command")
+		return
+	}
+
+	addValue(module, "type", "custom")
+	addValue(module, "command", command)
+	converter.copyStyle(config, module, "style", "style", notes)
+
+	if when, ok := config.string("when"); ok && when != "" {
+		*notes = append(*notes, "\"when\" was not translated; consider using conditions (e.g. ifFiles): "+when)
+	}
+	if files, ok := config.raw["detect_files"].([]interface{}); ok {
+		config.used["detect_files"] = true
+		conditions := &yaml.Node{Kind: yaml.MappingNode}
+		filesNode := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, file := range files {
+			if name, ok := file.(string); ok {
+				filesNode.Content = append(filesNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name})
+			}
+		}
+		addNode(conditions, "ifFiles", filesNode)
+		addNode(module, "conditions", conditions)
+	}
+}
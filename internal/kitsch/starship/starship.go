@@ -0,0 +1,355 @@
+// Package starship converts starship (https://starship.rs) configuration files
+// into kitsch configuration files.
+//
+// The conversion is best-effort.  Starship modules which have a kitsch
+// equivalent are converted along with their styles and symbols, and anything
+// that can't be translated (custom formats, unsupported modules, and so on) is
+// noted in a comment in the generated configuration.
+//
+package starship
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultFormat is the order modules are shown in when the configuration
+// uses "$all", or has no format.  This is the subset of starship's default
+// format that has kitsch equivalents.  "nodejs" stands in for all of the
+// language modules, which are converted into a single project module.
+var defaultFormat = []string{
+	"username", "hostname", "kubernetes", "directory",
+	"git_branch", "git_commit", "git_state", "git_status",
+	"package", "nodejs", "cmd_duration", "line_break",
+	"jobs", "time", "character",
+}
+
+// disabledByDefault are starship modules that are disabled unless explicitly
+// enabled with `disabled = false`.
+var disabledByDefault = map[string]bool{
+	"kubernetes": true,
+	"time":       true,
+}
+
+// Convert converts the contents of a starship.toml file into an equivalent
+// kitsch configuration, as YAML.
+func Convert(tomlData []byte) ([]byte, error) {
+	var config map[string]interface{}
+	if _, err := toml.Decode(string(tomlData), &config); err != nil {
+		return nil, err
+	}
+
+	converter := newConverter(config)
+	root := converter.convert()
+
+	var b strings.Builder
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return []byte(b.String()), nil
+}
+
+type converter struct {
+	config  map[string]interface{}
+	palette map[string]string
+	// notes are comments to add to the top of the generated file.
+	notes []string
+	// usedProject is true once we've added a project module, so we only add one.
+	usedProject bool
+}
+
+func newConverter(config map[string]interface{}) *converter {
+	converter := &converter{config: config, palette: map[string]string{}}
+
+	if paletteName, ok := config["palette"].(string); ok {
+		palettes, _ := config["palettes"].(map[string]interface{})
+		palette, ok := palettes[paletteName].(map[string]interface{})
+		if !ok {
+			converter.notef("palette %q not found", paletteName)
+		}
+		for name, value := range palette {
+			if color, ok := value.(string); ok {
+				converter.palette[name] = color
+			}
+		}
+	}
+
+	return converter
+}
+
+func (converter *converter) notef(format string, args ...interface{}) {
+	converter.notes = append(converter.notes, fmt.Sprintf(format, args...))
+}
+
+func (converter *converter) convert() *yaml.Node {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	topLevel := newModuleConfig("", converter.config)
+	if timeout, ok := topLevel.int("command_timeout"); ok {
+		addValue(root, "timeout", timeout)
+	}
+	if scanTimeout, ok := topLevel.int("scan_timeout"); ok {
+		addValue(root, "scanTimeout", scanTimeout)
+	}
+	if addNewline, ok := topLevel.bool("add_newline"); ok && addNewline {
+		converter.notef("add_newline is not supported; kitsch never prints a blank line before the prompt")
+	}
+	if _, ok := topLevel.string("right_format"); ok {
+		converter.notef("right_format is not supported")
+	}
+	if _, ok := topLevel.string("continuation_prompt"); ok {
+		converter.notef("continuation_prompt is not supported")
+	}
+
+	if len(converter.palette) > 0 {
+		colors := &yaml.Node{Kind: yaml.MappingNode}
+		for _, name := range sortedKeys(converter.palette) {
+			color, unsupported := converter.convertStyle(converter.palette[name])
+			if len(unsupported) > 0 {
+				converter.notef("palette color %q could not be converted", name)
+				continue
+			}
+			addValue(colors, "$"+name, color)
+		}
+		addNode(root, "colors", colors)
+	}
+
+	format, ok := topLevel.string("format")
+	if !ok {
+		format = "$all"
+	}
+	addNode(root, "prompt", converter.convertFormat(format))
+
+	if len(converter.notes) > 0 {
+		root.HeadComment = "Converted from starship.\n\n" + strings.Join(converter.notes, "\n")
+	} else {
+		root.HeadComment = "Converted from starship."
+	}
+
+	return root
+}
+
+// convertFormat converts the top level format into a block module.  Each line
+// in the format becomes a separate block.
+func (converter *converter) convertFormat(format string) *yaml.Node {
+	var lines [][]*yaml.Node
+	var line []*yaml.Node
+
+	for _, token := range converter.expandAll(parseFormat(format)) {
+		if token.kind == tokenText {
+			parts := strings.Split(token.value, "\n")
+			for index, part := range parts {
+				if index > 0 {
+					lines = append(lines, line)
+					line = nil
+				}
+				if strings.TrimSpace(part) != "" {
+					line = append(line, converter.textModule(strings.TrimSpace(part), token.style))
+				}
+			}
+			continue
+		}
+
+		if token.value == "line_break" {
+			lines = append(lines, line)
+			line = nil
+			continue
+		}
+
+		if module := converter.convertModule(token.value, token.style); module != nil {
+			line = append(line, module)
+		}
+	}
+	lines = append(lines, line)
+
+	var blocks []*yaml.Node
+	for _, modules := range lines {
+		if len(modules) == 0 {
+			continue
+		}
+		block := &yaml.Node{Kind: yaml.MappingNode}
+		addValue(block, "type", "block")
+		addNode(block, "modules", &yaml.Node{Kind: yaml.SequenceNode, Content: modules})
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) == 1 {
+		return blocks[0]
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	addValue(root, "type", "block")
+	addNode(root, "join", &yaml.Node{Kind: yaml.ScalarNode, Style: yaml.DoubleQuotedStyle, Value: "\n"})
+	addNode(root, "modules", &yaml.Node{Kind: yaml.SequenceNode, Content: blocks})
+	return root
+}
+
+// expandAll replaces any "$all" variable with the default list of modules, and
+// any "$custom" variable with all the custom modules.
+func (converter *converter) expandAll(tokens []formatToken) []formatToken {
+	var result []formatToken
+	for _, token := range tokens {
+		switch {
+		case token.kind != tokenVariable:
+			result = append(result, token)
+		case token.value == "all":
+			for _, name := range defaultFormat {
+				result = append(result, formatToken{kind: tokenVariable, value: name})
+			}
+			result = append(result, converter.customModules()...)
+		case token.value == "custom":
+			result = append(result, converter.customModules()...)
+		default:
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// customModules returns a variable token for every custom module in the
+// configuration.
+func (converter *converter) customModules() []formatToken {
+	customModules, _ := converter.config["custom"].(map[string]interface{})
+	names := make([]string, 0, len(customModules))
+	for name := range customModules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tokens := make([]formatToken, 0, len(names))
+	for _, name := range names {
+		tokens = append(tokens, formatToken{kind: tokenVariable, value: "custom." + name})
+	}
+	return tokens
+}
+
+func (converter *converter) textModule(text string, style string) *yaml.Node {
+	module := &yaml.Node{Kind: yaml.MappingNode}
+	addValue(module, "type", "text")
+	addValue(module, "text", text)
+	converter.addStyle(module, "style", style, nil)
+	return module
+}
+
+// addStyle converts a starship style and adds it to the module.  Any parts of
+// the style that can't be converted are added to notes.
+func (converter *converter) addStyle(module *yaml.Node, key string, style string, notes *[]string) {
+	if style == "" || strings.Contains(style, "$") {
+		return
+	}
+	converted, unsupported := converter.convertStyle(style)
+	if len(unsupported) > 0 {
+		message := fmt.Sprintf("unsupported style %q", strings.Join(unsupported, " "))
+		if notes != nil {
+			*notes = append(*notes, message)
+		} else {
+			module.HeadComment = message
+		}
+	}
+	if converted != "" {
+		addValue(module, key, converted)
+	}
+}
+
+// convertModule converts the starship module with the given name.  Returns
+// nil if the module should be omitted.  groupStyle is the style of the
+// format group the module was in, if any.
+func (converter *converter) convertModule(name string, groupStyle string) *yaml.Node {
+	rawConfig, _ := converter.config[name].(map[string]interface{})
+	if strings.HasPrefix(name, "custom.") {
+		customModules, _ := converter.config["custom"].(map[string]interface{})
+		rawConfig, _ = customModules[strings.TrimPrefix(name, "custom.")].(map[string]interface{})
+	}
+	config := newModuleConfig(name, rawConfig)
+
+	if projectTypes[name] != "" {
+		// All language modules are shown by a single project module.
+		if converter.usedProject {
+			return nil
+		}
+		converter.usedProject = true
+		module := &yaml.Node{Kind: yaml.MappingNode}
+		var notes []string
+		converter.convertProject(module, &notes)
+		if len(notes) > 0 {
+			module.HeadComment = strings.Join(notes, "\n")
+		}
+		return module
+	}
+
+	if disabled, ok := config.bool("disabled"); ok && disabled {
+		return nil
+	} else if !ok && disabledByDefault[name] {
+		return nil
+	}
+
+	module := &yaml.Node{Kind: yaml.MappingNode}
+	var notes []string
+
+	moduleConverter, ok := moduleConverters[name]
+	switch {
+	case ok:
+		moduleConverter(converter, config, module, &notes)
+	case strings.HasPrefix(name, "custom."):
+		convertCustom(converter, config, module, &notes)
+	default:
+		converter.notef("starship module %q has no kitsch equivalent", name)
+		return nil
+	}
+
+	if len(module.Content) == 0 {
+		return nil
+	}
+
+	if _, hasStyle := config.raw["style"]; !hasStyle && groupStyle != "" && !strings.Contains(groupStyle, "$") {
+		converter.addStyle(module, "style", groupStyle, &notes)
+	}
+
+	if _, hasFormat := config.string("format"); hasFormat {
+		notes = append(notes, "custom format was not translated; use a template instead")
+	}
+	if unused := config.unused(); len(unused) > 0 {
+		notes = append(notes, "not translated: "+strings.Join(unused, ", "))
+	}
+	if len(notes) > 0 {
+		module.HeadComment = strings.Join(notes, "\n")
+	}
+
+	return module
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// addValue adds a key and value to a mapping node.
+func addValue(mapping *yaml.Node, key string, value interface{}) {
+	valueNode := &yaml.Node{}
+	if err := valueNode.Encode(value); err != nil {
+		return
+	}
+	addNode(mapping, key, valueNode)
+}
+
+// addNode adds a key and a value node to a mapping node.
+func addNode(mapping *yaml.Node, key string, value *yaml.Node) {
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
@@ -0,0 +1,106 @@
+package starship
+
+import (
+	"testing"
+
+	"github.com/jwalton/kitsch/internal/kitsch/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const starshipTestConfig = `
+command_timeout = 1000
+add_newline = true
+format = """
+$username$directory$git_branch$aws
+$nodejs$rust$character"""
+
+[username]
+show_always = true
+style_user = "bold yellow"
+
+[directory]
+truncation_length = 3
+format = "[$path]($style) "
+
+[git_branch]
+symbol = "@"
+style = "bold purple"
+
+[nodejs]
+symbol = "N "
+style = "green"
+
+[rust]
+version_format = "v${raw}"
+
+[character]
+success_symbol = "[>](bold green)"
+error_symbol = "[>](bold red)"
+`
+
+func TestConvert(t *testing.T) {
+	result, err := Convert([]byte(starshipTestConfig))
+	require.NoError(t, err)
+
+	assert.Equal(t, ""+
+		"# Converted from starship.\n"+
+		"\n"+
+		"# add_newline is not supported; kitsch never prints a blank line before the prompt\n"+
+		"# starship module \"aws\" has no kitsch equivalent\n"+
+		"timeout: 1000\n"+
+		"prompt:\n"+
+		"  type: block\n"+
+		"  join: \"\\n\"\n"+
+		"  modules:\n"+
+		"    - type: block\n"+
+		"      modules:\n"+
+		"        - type: username\n"+
+		"          style: bold yellow\n"+
+		"          showAlways: true\n"+
+		"        # custom format was not translated; use a template instead\n"+
+		"        - type: directory\n"+
+		"          truncationLength: 3\n"+
+		"        - type: git_head\n"+
+		"          style: bold magenta\n"+
+		"          template: '{{ with .Text }}@{{ . }}{{ end }}'\n"+
+		"    - type: block\n"+
+		"      modules:\n"+
+		"        # rust: not translated: version_format\n"+
+		"        - type: project\n"+
+		"          projects:\n"+
+		"            node:\n"+
+		"              style: green\n"+
+		"              toolSymbol: 'N '\n"+
+		"        - type: prompt\n"+
+		"          prompt: '> '\n"+
+		"          style: bold green\n"+
+		"          errorStyle: bold red\n",
+		string(result))
+
+	assert.Empty(t, config.CheckConfiguration(result))
+}
+
+func TestConvertDefaultFormat(t *testing.T) {
+	result, err := Convert([]byte(`
+[time]
+disabled = false
+time_format = "%H:%M"
+
+[custom.foo]
+command = "echo foo"
+detect_files = ["foo.txt"]
+`))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(result), "layout: \"15:04\"")
+	assert.Contains(t, string(result), "command: echo foo")
+	assert.Contains(t, string(result), "ifFiles:\n")
+	assert.NotContains(t, string(result), "kubernetes")
+	assert.Empty(t, config.CheckConfiguration(result))
+}
+
+func TestConvertInvalidToml(t *testing.T) {
+	_, err := Convert([]byte("format = "))
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package starship
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// styleModifiers maps starship style modifiers to kitsch modifiers.
+var styleModifiers = map[string]string{
+	"bold":          "bold",
+	"italic":        "italic",
+	"underline":     "underline",
+	"dimmed":        "dim",
+	"inverted":      "inverse",
+	"hidden":        "hidden",
+	"strikethrough": "strikethrough",
+}
+
+// ansiColorNames are the names of the first 16 ANSI colors.
+var ansiColorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"brightBlack", "brightRed", "brightGreen", "brightYellow",
+	"brightBlue", "brightMagenta", "brightCyan", "brightWhite",
+}
+
+// convertStyle converts a starship style string into a kitsch style string.
+// Any parts of the style that can't be converted are returned in `unsupported`.
+func (converter *converter) convertStyle(style string) (result string, unsupported []string) {
+	var parts []string
+
+	for _, part := range strings.Fields(style) {
+		lower := strings.ToLower(part)
+
+		if lower == "none" {
+			continue
+		}
+		if modifier, ok := styleModifiers[lower]; ok {
+			parts = append(parts, modifier)
+			continue
+		}
+
+		prefix := ""
+		color := part
+		if strings.HasPrefix(lower, "bg:") {
+			prefix = "bg:"
+			color = part[3:]
+		} else if strings.HasPrefix(lower, "fg:") {
+			color = part[3:]
+		}
+
+		if converted, ok := converter.convertColor(color); ok {
+			parts = append(parts, prefix+converted)
+		} else {
+			unsupported = append(unsupported, part)
+		}
+	}
+
+	return strings.Join(parts, " "), unsupported
+}
+
+// convertColor converts a starship color into a kitsch color.
+func (converter *converter) convertColor(color string) (string, bool) {
+	lower := strings.ToLower(color)
+
+	if _, ok := converter.palette[color]; ok {
+		return "$" + color, true
+	}
+
+	if strings.HasPrefix(color, "#") {
+		return color, true
+	}
+
+	if number, err := strconv.Atoi(color); err == nil {
+		return ansi256Color(number)
+	}
+
+	bright := false
+	if strings.HasPrefix(lower, "bright-") {
+		bright = true
+		lower = lower[len("bright-"):]
+	}
+	if lower == "purple" {
+		lower = "magenta"
+	}
+
+	for index, name := range ansiColorNames[:8] {
+		if name == lower {
+			if bright {
+				return ansiColorNames[index+8], true
+			}
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// ansi256Color converts an ANSI 256 color number into a kitsch color.
+func ansi256Color(number int) (string, bool) {
+	switch {
+	case number < 0 || number > 255:
+		return "", false
+	case number < 16:
+		return ansiColorNames[number], true
+	case number < 232:
+		// 6x6x6 color cube.
+		levels := []int{0, 95, 135, 175, 215, 255}
+		index := number - 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[index/36], levels[(index/6)%6], levels[index%6]), true
+	default:
+		// Grayscale ramp.
+		level := 8 + (number-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", level, level, level), true
+	}
+}
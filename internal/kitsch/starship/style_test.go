@@ -0,0 +1,55 @@
+package starship
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertStyle(t *testing.T) {
+	converter := newConverter(map[string]interface{}{})
+
+	tests := []struct {
+		style       string
+		expected    string
+		unsupported []string
+	}{
+		{style: "bold green", expected: "bold green"},
+		{style: "dimmed purple", expected: "dim magenta"},
+		{style: "fg:bright-red bg:#1d2230", expected: "brightRed bg:#1d2230"},
+		{style: "inverted 12", expected: "inverse brightBlue"},
+		{style: "208", expected: "#ff8700"},
+		{style: "bg:244", expected: "bg:#808080"},
+		{style: "none", expected: ""},
+		{style: "blink red", expected: "red", unsupported: []string{"blink"}},
+	}
+
+	for _, test := range tests {
+		result, unsupported := converter.convertStyle(test.style)
+		assert.Equal(t, test.expected, result, test.style)
+		assert.Equal(t, test.unsupported, unsupported, test.style)
+	}
+}
+
+func TestConvertStylePalette(t *testing.T) {
+	converter := newConverter(map[string]interface{}{
+		"palette": "mine",
+		"palettes": map[string]interface{}{
+			"mine": map[string]interface{}{"accent": "#ff8800"},
+		},
+	})
+
+	result, unsupported := converter.convertStyle("bold bg:accent")
+	assert.Equal(t, "bold bg:$accent", result)
+	assert.Nil(t, unsupported)
+}
+
+func TestStrftimeToLayout(t *testing.T) {
+	layout, unsupported := strftimeToLayout("%Y-%m-%d %T")
+	assert.Equal(t, "2006-01-02 15:04:05", layout)
+	assert.Nil(t, unsupported)
+
+	layout, unsupported = strftimeToLayout("%H:%M %q")
+	assert.Equal(t, "15:04 ", layout)
+	assert.Equal(t, []string{"%q"}, unsupported)
+}
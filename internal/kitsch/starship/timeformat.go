@@ -0,0 +1,56 @@
+package starship
+
+import "strings"
+
+// strftimeDirectives maps strftime directives to Go time layouts.
+var strftimeDirectives = map[rune]string{
+	'a': "Mon",
+	'A': "Monday",
+	'b': "Jan",
+	'B': "January",
+	'd': "02",
+	'e': "_2",
+	'h': "Jan",
+	'H': "15",
+	'I': "03",
+	'j': "002",
+	'm': "01",
+	'M': "04",
+	'p': "PM",
+	'S': "05",
+	'y': "06",
+	'Y': "2006",
+	'z': "-0700",
+	'Z': "MST",
+	'D': "01/02/06",
+	'F': "2006-01-02",
+	'R': "15:04",
+	'T': "15:04:05",
+	'r': "03:04:05 PM",
+	'%': "%",
+}
+
+// strftimeToLayout converts a strftime format, as used by starship's time
+// module, into a Go time layout.  Any directives that have no Go equivalent
+// are dropped and returned in `unsupported`.
+func strftimeToLayout(format string) (layout string, unsupported []string) {
+	var result strings.Builder
+	runes := []rune(format)
+
+	for index := 0; index < len(runes); index++ {
+		if runes[index] != '%' || index+1 >= len(runes) {
+			result.WriteRune(runes[index])
+			continue
+		}
+
+		index++
+		directive := runes[index]
+		if converted, ok := strftimeDirectives[directive]; ok {
+			result.WriteString(converted)
+		} else {
+			unsupported = append(unsupported, "%"+string(directive))
+		}
+	}
+
+	return result.String(), unsupported
+}
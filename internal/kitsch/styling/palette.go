@@ -0,0 +1,120 @@
+package styling
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ansiPrefix is the prefix for colors from the terminal's own palette.
+const ansiPrefix = "ansi:"
+
+// ansiColorNames are the names of the 16 basic ANSI colors, in palette order.
+var ansiColorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"brightBlack", "brightRed", "brightGreen", "brightYellow",
+	"brightBlue", "brightMagenta", "brightCyan", "brightWhite",
+}
+
+// base16Slots maps each Base16 slot to the palette index the standard Base16
+// terminal templates (e.g. base16-shell) assign it to.  The first 16 slots
+// fill the 16 basic ANSI colors, and the extra six slots use 16-21 from the
+// 256 color palette.
+var base16Slots = map[string]int{
+	"00": 0,
+	"01": 18,
+	"02": 19,
+	"03": 8,
+	"04": 20,
+	"05": 7,
+	"06": 21,
+	"07": 15,
+	"08": 1,
+	"09": 16,
+	"0a": 3,
+	"0b": 2,
+	"0c": 6,
+	"0d": 4,
+	"0e": 5,
+	"0f": 17,
+}
+
+// paletteColor converts a reference to a color in the terminal's palette into
+// a color that gchalk understands.  The palette colors are:
+//
+// • "ansi:red", "ansi:bright-blue", or "ansi:12" - A color from the terminal's
+// 256 color palette, by name or by index.
+//
+// • "bright-blue" - Kebab-case versions of the 16 basic color names.
+//
+// • "base08" or "base16:08" - A Base16 slot.
+//
+// Colors in the first 16 entries of the palette are returned by name (e.g.
+// "brightBlue"), and any other colors are returned as "ansi:<index>".  Returns
+// false if the color is not a palette color.
+func paletteColor(color string) (string, bool) {
+	if strings.HasPrefix(color, ansiPrefix) {
+		name := color[len(ansiPrefix):]
+		if index, err := strconv.Atoi(name); err == nil {
+			return paletteIndexColor(index)
+		}
+		return ansiColorName(name)
+	}
+
+	lower := strings.ToLower(color)
+	if strings.HasPrefix(lower, "base16:") {
+		return base16Color(lower[len("base16:"):])
+	}
+	if strings.HasPrefix(lower, "base") && len(lower) == len("base00") {
+		return base16Color(lower[len("base"):])
+	}
+
+	if strings.Contains(color, "-") {
+		return ansiColorName(color)
+	}
+
+	return "", false
+}
+
+// ansiColorName converts a color name like "brightBlue" or "bright-blue"
+// into the name of one of the 16 basic ANSI colors.
+func ansiColorName(name string) (string, bool) {
+	name = strings.ToLower(strings.ReplaceAll(name, "-", ""))
+	for _, colorName := range ansiColorNames {
+		if strings.ToLower(colorName) == name {
+			return colorName, true
+		}
+	}
+	return "", false
+}
+
+// paletteIndexColor converts an index into the 256 color palette into a color.
+func paletteIndexColor(index int) (string, bool) {
+	if index < 0 || index > 255 {
+		return "", false
+	}
+	if index < len(ansiColorNames) {
+		return ansiColorNames[index], true
+	}
+	return ansiPrefix + strconv.Itoa(index), true
+}
+
+// base16Color converts a Base16 slot like "0D" into a color.
+func base16Color(slot string) (string, bool) {
+	index, ok := base16Slots[strings.ToLower(slot)]
+	if !ok {
+		return "", false
+	}
+	return paletteIndexColor(index)
+}
+
+// ansi256Index returns the palette index for a color of the form "ansi:<index>".
+func ansi256Index(color string) (uint8, bool) {
+	if !strings.HasPrefix(color, ansiPrefix) {
+		return 0, false
+	}
+	index, err := strconv.Atoi(color[len(ansiPrefix):])
+	if err != nil || index < 0 || index > 255 {
+		return 0, false
+	}
+	return uint8(index), true
+}
@@ -2,6 +2,7 @@ package styling
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/jwalton/gchalk/pkg/ansistyles"
@@ -10,6 +11,11 @@ import (
 
 const linearGradientPrefix = "linear-gradient("
 
+// maxVariableDepth is the maximum number of custom colors or variables that
+// can refer to each other, to stop a variable which refers to itself from
+// looping forever.
+const maxVariableDepth = 10
+
 type styleDescriptor struct {
 	// fg is the foreground color of this style.  This can be any string that
 	// `gchalk.Style()` accepts (e.g. "red", "brightBlack"), a hex string
@@ -18,8 +24,19 @@ type styleDescriptor struct {
 	// bg is the background color of this style.
 	bg string
 	// modifiers is an array of modifiers (e.g. "bold").  These can be any
-	// modifier accepted by `gchalk.Style()`.
+	// modifier accepted by `gchalk.Style()`, or a styled underline (e.g.
+	// "curlyUnderline").
 	modifiers []string
+	// underlineColor is the color of the underline.
+	underlineColor string
+}
+
+// key returns a string which uniquely identifies this descriptor.  Two
+// descriptors with the same key produce the same style.
+func (descriptor styleDescriptor) key() string {
+	modifiers := append([]string(nil), descriptor.modifiers...)
+	sort.Strings(modifiers)
+	return descriptor.fg + "\x00" + descriptor.bg + "\x00" + descriptor.underlineColor + "\x00" + strings.Join(modifiers, " ")
 }
 
 // parseStyle converts a style string into a style descriptor.
@@ -92,8 +109,17 @@ func (parser *styleParser) nextToken() (token string, err error) {
 	return parser.styleString[tokenStart:parser.position], nil
 }
 
+// isStyleVariable returns true if the value of a custom color is a style made
+// up of more than one token, or is a reference to another custom color, instead
+// of a single color.
+func isStyleVariable(value string) bool {
+	return strings.HasPrefix(value, "$") ||
+		(!strings.HasPrefix(value, linearGradientPrefix) && strings.IndexAny(value, " \t") != -1)
+}
+
 // isColor returns true if the given string is a color - eiter .
 func isColor(color string) bool {
+
 	_, validAnsiStyle := ansistyles.Color[color]
 	if validAnsiStyle {
 		return true
@@ -113,7 +139,7 @@ func parseStyleToken(
 	descriptor *styleDescriptor,
 	token string,
 ) error {
-	return parseStyleTokenHelper(customColors, descriptor, token, false)
+	return parseStyleTokenHelper(customColors, descriptor, token, false, 0)
 }
 
 // parseStyleSubstring is a helper function for `ParseStyle` which parses an individual
@@ -123,13 +149,21 @@ func parseStyleTokenHelper(
 	descriptor *styleDescriptor,
 	token string,
 	isBackground bool,
+	depth int,
 ) error {
 	if color, isBg := isBgColor(token); isBg {
 		// Handle case where `token` starts with "bg:" or "bg".
-		err := parseStyleTokenHelper(customColors, descriptor, color, true)
+		err := parseStyleTokenHelper(customColors, descriptor, color, true, depth)
 		if err != nil {
 			return fmt.Errorf("unknown style \"%s\"", token)
 		}
+	} else if color, ok := paletteColor(token); ok {
+		// Handle case where `token` refers to the terminal's palette.
+		if isBackground {
+			descriptor.bg = color
+		} else {
+			descriptor.fg = color
+		}
 	} else if isColor(token) {
 		// Handle case where `token` is a color.
 		if isBackground {
@@ -140,8 +174,41 @@ func parseStyleTokenHelper(
 	} else if _, ok := ansistyles.Modifier[token]; ok {
 		// Handle case where `token` is a modifier.
 		descriptor.modifiers = append(descriptor.modifiers, token)
-	} else if color, ok := customColors[token]; ok {
+	} else if _, ok := styledUnderlines[token]; ok {
+		descriptor.modifiers = append(descriptor.modifiers, token)
+	} else if modifier, ok := modifierAliases[token]; ok {
+		descriptor.modifiers = append(descriptor.modifiers, modifier)
+	} else if strings.HasPrefix(token, underlineColorPrefix) {
+		color, ok := resolveUnderlineColor(customColors, token[len(underlineColorPrefix):])
+		if !ok {
+			return fmt.Errorf("unknown style \"%s\"", token)
+		}
+		descriptor.underlineColor = color
+	} else if color, ok := customColors[token]; ok && isStyleVariable(color) {
+		// Handle case where token is a variable which holds a whole style
+		// (e.g. "bold red"), or refers to another variable.
+		if depth >= maxVariableDepth {
+			return fmt.Errorf("too many nested variables in \"%s\"", token)
+		}
+		parser := styleParser{styleString: color}
+		for {
+			subToken, err := parser.nextToken()
+			if err != nil {
+				return err
+			}
+			if subToken == "" {
+				break
+			}
+			err = parseStyleTokenHelper(customColors, descriptor, subToken, isBackground, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+	} else if ok {
 		// Handle case where token is a custom color.
+		if resolved, isPaletteColor := paletteColor(color); isPaletteColor {
+			color = resolved
+		}
 		if isBackground {
 			descriptor.bg = color
 		} else {
@@ -153,3 +220,23 @@ func parseStyleTokenHelper(
 
 	return nil
 }
+
+// resolveUnderlineColor converts the color from an "underline:<color>" token
+// into a color.  Underlines can't be gradients, so this only accepts single
+// colors, or custom colors which hold a single color.
+func resolveUnderlineColor(customColors map[string]string, color string) (string, bool) {
+	if value, ok := customColors[color]; ok {
+		if isStyleVariable(value) {
+			return "", false
+		}
+		color = value
+	}
+
+	if resolved, ok := paletteColor(color); ok {
+		return resolved, true
+	}
+	if strings.HasPrefix(color, linearGradientPrefix) || !isColor(color) {
+		return "", false
+	}
+	return color, true
+}
@@ -70,3 +70,79 @@ func TestCustomColors(t *testing.T) {
 	_, err = parseStyle(customColors, "$banana")
 	assert.EqualError(t, err, "unknown style \"$banana\"")
 }
+
+func TestStyleVariables(t *testing.T) {
+	variables := map[string]string{
+		"$accent":   "bold #ff8800",
+		"$muted":    "$grey",
+		"$grey":     "brightBlack",
+		"$gradient": "linear-gradient(#fff, #000)",
+		"$loop":     "$loop",
+	}
+
+	style, err := parseStyle(variables, "$accent")
+	assert.NoError(t, err)
+	assert.Equal(t, styleDescriptor{fg: "#ff8800", modifiers: []string{"bold"}}, style)
+
+	style, err = parseStyle(variables, "$muted bg:$accent")
+	assert.NoError(t, err)
+	assert.Equal(t, styleDescriptor{fg: "brightBlack", bg: "#ff8800", modifiers: []string{"bold"}}, style)
+
+	style, err = parseStyle(variables, "$gradient")
+	assert.NoError(t, err)
+	assert.Equal(t, styleDescriptor{fg: "linear-gradient(#fff, #000)"}, style)
+
+	_, err = parseStyle(variables, "$loop")
+	assert.EqualError(t, err, "too many nested variables in \"$loop\"")
+}
+
+func TestPaletteColors(t *testing.T) {
+	customColors := map[string]string{
+		"$accent": "ansi:bright-cyan",
+	}
+
+	tests := []struct {
+		style    string
+		expected styleDescriptor
+	}{
+		{"ansi:red", styleDescriptor{fg: "red"}},
+		{"ansi:brightBlue", styleDescriptor{fg: "brightBlue"}},
+		{"bright-blue", styleDescriptor{fg: "brightBlue"}},
+		{"bg:bright-black", styleDescriptor{bg: "brightBlack"}},
+		{"ansi:12", styleDescriptor{fg: "brightBlue"}},
+		{"ansi:208", styleDescriptor{fg: "ansi:208"}},
+		{"base08", styleDescriptor{fg: "red"}},
+		{"base0D bg:base00", styleDescriptor{fg: "blue", bg: "black"}},
+		{"base16:09", styleDescriptor{fg: "ansi:16"}},
+		{"$accent", styleDescriptor{fg: "brightCyan"}},
+	}
+
+	for _, test := range tests {
+		style, err := parseStyle(customColors, test.style)
+		assert.NoError(t, err, test.style)
+		assert.Equal(t, test.expected, style, test.style)
+	}
+
+	for _, invalid := range []string{"ansi:banana", "ansi:256", "base10", "base16:0g", "dark-red"} {
+		_, err := parseStyle(customColors, invalid)
+		assert.EqualError(t, err, "unknown style \""+invalid+"\"")
+	}
+}
+
+func TestUnderlineModifiers(t *testing.T) {
+	customColors := map[string]string{
+		"$error": "#f00",
+		"$style": "bold red",
+	}
+
+	style, err := parseStyle(customColors, "curlyUnderline underline:$error faint")
+	assert.NoError(t, err)
+	assert.Equal(t, styleDescriptor{modifiers: []string{"curlyUnderline", "dim"}, underlineColor: "#f00"}, style)
+
+	style, err = parseStyle(customColors, "underline underline:bright-red")
+	assert.NoError(t, err)
+	assert.Equal(t, styleDescriptor{modifiers: []string{"underline"}, underlineColor: "brightRed"}, style)
+
+	_, err = parseStyle(customColors, "underline:$style")
+	assert.EqualError(t, err, "unknown style \"underline:$style\"")
+}
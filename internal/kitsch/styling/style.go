@@ -20,6 +20,15 @@ type Style struct {
 	builder    *gchalk.Builder
 	fgGradient ansigradient.Gradient
 	bgGradient ansigradient.Gradient
+	// open and close are extra escape sequences, for styled underlines and
+	// underline colors, which wrap the output of builder.
+	open  string
+	close string
+	// first and last are the colors of the first and last characters of any
+	// string styled with this style.  These are only set if this style has no
+	// gradients, since otherwise they depend on the length of the string.
+	first CharacterColors
+	last  CharacterColors
 }
 
 // CharacterColors represent the color for a single character.
@@ -34,13 +43,10 @@ type CharacterColors struct {
 func compileStyle(
 	baseBuilder *gchalk.Builder,
 	customColors map[string]string,
-	styleString string,
+	descriptor styleDescriptor,
+	styledUnderlinesSupported bool,
 ) (Style, error) {
-	descriptor, err := parseStyle(customColors, styleString)
-	if err != nil {
-		return Style{}, err
-	}
-
+	var err error
 	builder := baseBuilder
 
 	var fgGradient ansigradient.Gradient
@@ -59,6 +65,12 @@ func compileStyle(
 				token = "bg" + strings.ToUpper(token[0:1]) + token[1:]
 			}
 			builder, err = builder.WithStyle(token)
+		} else if index, ok := ansi256Index(token); ok {
+			if background {
+				builder = builder.WithBgAnsi256(index)
+			} else {
+				builder = builder.WithAnsi256(index)
+			}
 		} else if strings.HasPrefix(token, linearGradientPrefix) {
 			cssGradient := token[len(linearGradientPrefix) : len(token)-1]
 			if background {
@@ -89,19 +101,48 @@ func compileStyle(
 		return Style{}, err
 	}
 
+	open := ""
+	close := ""
+	level := baseBuilder.GetLevel()
+
 	for _, modifier := range descriptor.modifiers {
+		if code, ok := styledUnderlines[modifier]; ok {
+			if styledUnderlinesSupported && level > gchalk.LevelNone {
+				open += "\u001b[4:" + code + "m"
+				close = "\u001b[24m"
+				continue
+			}
+			// Fall back to a plain underline.
+			modifier = "underline"
+		}
 		builder, err = builder.WithStyle(modifier)
 		if err != nil {
 			return Style{}, err
 		}
 	}
 
-	return Style{
+	if descriptor.underlineColor != "" && styledUnderlinesSupported && level > gchalk.LevelNone {
+		code, err := underlineColorCode(descriptor.underlineColor, level)
+		if err != nil {
+			return Style{}, err
+		}
+		open += code
+		close += "\u001b[59m"
+	}
+
+	style := Style{
 		descriptor: descriptor,
 		builder:    builder,
 		fgGradient: fgGradient,
 		bgGradient: bgGradient,
-	}, nil
+		open:       open,
+		close:      close,
+	}
+	if fgGradient == nil && bgGradient == nil {
+		style.first, style.last = style.characterColors(0)
+	}
+
+	return style, nil
 }
 
 // Apply applies this style to the given text.
@@ -116,16 +157,30 @@ func (style *Style) ApplyGetColors(text string) (result string, first CharacterC
 		return text, first, last
 	}
 
-	if style.builder != nil {
-		result = style.builder.Paint(text)
+	if style.fgGradient == nil && style.bgGradient == nil {
+		if style.builder != nil {
+			result = style.builder.Paint(text)
+		}
+		return style.wrap(result), style.first, style.last
 	}
 
-	printWidth := 0
-	if style.fgGradient != nil || style.bgGradient != nil {
-		// TODO: This instance of gchalk is not the same instance as the one from the styleRegistry.
-		result, printWidth = ansigradient.ApplyGradientsRawLen(text, style.fgGradient, style.bgGradient, gchalk.GetLevel())
+	// TODO: This instance of gchalk is not the same instance as the one from the styleRegistry.
+	result, printWidth := ansigradient.ApplyGradientsRawLen(text, style.fgGradient, style.bgGradient, gchalk.GetLevel())
+	first, last = style.characterColors(printWidth)
+	return style.wrap(result), first, last
+}
+
+// wrap surrounds styled text with the style's extra escape sequences.
+func (style *Style) wrap(text string) string {
+	if style.open == "" || text == "" {
+		return text
 	}
+	return style.open + text + style.close
+}
 
+// characterColors returns the colors of the first and last characters of a
+// string with the given print width, styled with this style.
+func (style *Style) characterColors(printWidth int) (first CharacterColors, last CharacterColors) {
 	first.FG, last.FG = getCharacterColors(style.descriptor.fg, style.fgGradient, printWidth)
 	first.BG, last.BG = getCharacterColors(style.descriptor.bg, style.bgGradient, printWidth)
 	if first.BG != "" {
@@ -134,8 +189,7 @@ func (style *Style) ApplyGetColors(text string) (result string, first CharacterC
 	if last.BG != "" {
 		last.BG = "bg:" + last.BG
 	}
-
-	return result, first, last
+	return first, last
 }
 
 func getCharacterColors(colorString string, gradient ansigradient.Gradient, printLength int) (first string, last string) {
@@ -17,8 +17,18 @@ type Registry struct {
 	// if CustomColors["$foregroud"] = "red", then "$foreground" could be used in
 	// a style string to refer to the color red.  Custom colors must start with
 	// a "$".
-	CustomColors   map[string]string
-	styles         map[string]*Style
+	CustomColors map[string]string
+	// StyledUnderlines should be set to true if the terminal supports styled
+	// underlines (e.g. "curlyUnderline") and underline colors.  If false,
+	// styled underlines are drawn as plain underlines and underline colors are
+	// ignored.  This must be set before the first call to Get().
+	StyledUnderlines bool
+	// styles is a map of style strings to compiled styles.
+	styles map[string]*Style
+	// interned is a map of style descriptors to compiled styles, so that style
+	// strings which only differ in the order of their tokens or in whitespace
+	// (e.g. "bold red" and "red  bold") share a single style.
+	interned       map[string]*Style
 	gchalkInstance *gchalk.Builder
 }
 
@@ -34,7 +44,8 @@ func (registry *Registry) AddCustomColor(name string, color string) {
 	registry.CustomColors[name] = color
 }
 
-// AddCustomColors adds a collection of custom colors to the registry.
+// AddCustomColors adds a collection of custom colors or variables to the
+// registry.
 func (registry *Registry) AddCustomColors(colors map[string]string) {
 	for colorName, color := range colors {
 		if !strings.HasPrefix(colorName, "$") {
@@ -45,6 +56,18 @@ func (registry *Registry) AddCustomColors(colors map[string]string) {
 	}
 }
 
+// Variable returns the value of the given variable or custom color, or "" if
+// there is no such variable.  The leading "$" is optional.
+func (registry *Registry) Variable(name string) string {
+	if !strings.HasPrefix(name, "$") {
+		name = "$" + name
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	return registry.CustomColors[name]
+}
+
 // Get compiles a style string into a style, and returns the style.  Styles
 // are cached in the registry, so getting the same styleString twice will return
 // the same Style object.
@@ -63,6 +86,9 @@ func (registry *Registry) AddCustomColors(colors map[string]string) {
 //
 // • Any modifier accepted by `gchalk.Style()` (e.g. "bold", "dim", "inverse").
 //
+// • A styled underline (e.g. "curlyUnderline"), or an underline color (e.g.
+// "underline:red").
+//
 func (registry *Registry) Get(styleString string) (*Style, error) {
 	registry.mutex.Lock()
 	defer registry.mutex.Unlock()
@@ -82,14 +108,27 @@ func (registry *Registry) Get(styleString string) (*Style, error) {
 
 	if registry.styles == nil {
 		registry.styles = map[string]*Style{}
+		registry.interned = map[string]*Style{}
+	}
+
+	descriptor, err := parseStyle(registry.CustomColors, styleString)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling style \"%s\": %w", styleString, err)
+	}
+
+	key := descriptor.key()
+	if style := registry.interned[key]; style != nil {
+		registry.styles[styleString] = style
+		return style, nil
 	}
 
-	style, err := compileStyle(registry.gchalkInstance, registry.CustomColors, styleString)
+	style, err := compileStyle(registry.gchalkInstance, registry.CustomColors, descriptor, registry.StyledUnderlines)
 	if err != nil {
 		return nil, fmt.Errorf("error compiling style \"%s\": %w", styleString, err)
 	}
 
 	registry.styles[styleString] = &style
+	registry.interned[key] = &style
 
 	return &style, nil
 }
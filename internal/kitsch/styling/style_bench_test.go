@@ -16,7 +16,7 @@ func BenchmarkParseStyle(b *testing.B) {
 		}
 
 		// Clear the style from the cache.
-		delete(styles.styles, "red")
+		styles.styles = nil
 	}
 }
 
@@ -33,6 +33,10 @@ func TestApply(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "\u001b[48;2;255;255;255mtest\u001b[49m", style.Apply("test"))
 
+	style, err = styles.Get("ansi:208 bg:base0D")
+	assert.NoError(t, err)
+	assert.Equal(t, "\u001b[44m\u001b[38;5;208mtest\u001b[39m\u001b[49m", style.Apply("test"))
+
 	style, err = styles.Get("orangered")
 	assert.NoError(t, err)
 	assert.Equal(t, "\u001b[38;2;255;69;0mtest\u001b[39m", style.Apply("test"))
@@ -43,7 +47,48 @@ func TestApply(t *testing.T) {
 	assert.Equal(t, "\u001b[37mtest\u001b[39m", style.Apply("test"))
 }
 
-// TODO: Add tests for ApplyGetColors
+func TestApplyGetColors(t *testing.T) {
+	styles := testStyleRegistry()
+
+	style, err := styles.Get("red bg:#00f bold")
+	assert.NoError(t, err)
+	text, first, last := style.ApplyGetColors("test")
+	assert.Equal(t, style.Apply("test"), text)
+	assert.Equal(t, CharacterColors{FG: "red", BG: "bg:#00f"}, first)
+	assert.Equal(t, CharacterColors{FG: "red", BG: "bg:#00f"}, last)
+
+	style, err = styles.Get("linear-gradient(#f00, #00f)")
+	assert.NoError(t, err)
+	_, first, last = style.ApplyGetColors("test")
+	assert.Equal(t, CharacterColors{FG: "#ff0000"}, first)
+	assert.Equal(t, CharacterColors{FG: "#0000ff"}, last)
+}
+
+func TestApplyGetColorsAllocations(t *testing.T) {
+	styles := testStyleRegistry()
+	style, err := styles.Get("red bg:#00f bold")
+	assert.NoError(t, err)
+
+	// The only allocation should be the styled string itself.
+	allocs := testing.AllocsPerRun(100, func() {
+		style.ApplyGetColors("test")
+	})
+	assert.LessOrEqual(t, allocs, 1.0)
+}
+
+func TestInternStyles(t *testing.T) {
+	styles := testStyleRegistry()
+
+	style1, err := styles.Get("bold red")
+	assert.NoError(t, err)
+	style2, err := styles.Get("  red   bold ")
+	assert.NoError(t, err)
+	assert.Same(t, style1, style2)
+
+	style3, err := styles.Get("red")
+	assert.NoError(t, err)
+	assert.NotSame(t, style1, style3)
+}
 
 func TestGradient(t *testing.T) {
 	styles := testStyleRegistry()
@@ -77,3 +122,25 @@ func TestAddCustomColors(t *testing.T) {
 	_, err := styles.Get("$blue")
 	assert.NoError(t, err)
 }
+
+func TestStyledUnderlines(t *testing.T) {
+	styles := testStyleRegistry()
+	styles.StyledUnderlines = true
+
+	style, err := styles.Get("curlyUnderline underline:#f00")
+	assert.NoError(t, err)
+	assert.Equal(t, "\u001b[4:3m\u001b[58;2;255;0;0mtest\u001b[24m\u001b[59m", style.Apply("test"))
+
+	style, err = styles.Get("underline underline:red faint")
+	assert.NoError(t, err)
+	assert.Equal(t, "\u001b[58;5;1m\u001b[4m\u001b[2mtest\u001b[22m\u001b[24m\u001b[59m", style.Apply("test"))
+
+	// Terminals without styled underlines fall back to a plain underline.
+	styles = testStyleRegistry()
+	style, err = styles.Get("doubleUnderline underline:red")
+	assert.NoError(t, err)
+	assert.Equal(t, "\u001b[4mtest\u001b[24m", style.Apply("test"))
+
+	_, err = styles.Get("underline:linear-gradient(#f00, #00f)")
+	assert.Error(t, err)
+}
@@ -52,9 +52,15 @@ func TxtFuncMap(styles *Registry) template.FuncMap {
 		return styled
 	}
 
+	// variable returns the value of a variable or custom color.
+	variable := func(name string) string {
+		return styles.Variable(name)
+	}
+
 	return template.FuncMap{
 		"style":   style,
 		"fgColor": fgColor,
 		"bgColor": bgColor,
+		"var":     variable,
 	}
 }
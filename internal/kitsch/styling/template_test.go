@@ -64,3 +64,13 @@ func TestBgColorFunc(t *testing.T) {
 	tmpl3 := testCompileTemplate("test", `{{ . | bgColor "bg:red"}}`)
 	assert.Equal(t, "\u001B[41mfoo\u001B[49m", testTemplateToString(tmpl3, "foo"))
 }
+
+func TestVarFunc(t *testing.T) {
+	styles := Registry{}
+	styles.AddCustomColors(map[string]string{"$icon": "", "$accent": "red"})
+
+	tmpl := template.Must(template.New("test").Funcs(TxtFuncMap(&styles)).Parse(
+		`{{ var "$icon" }} {{ var "accent" }} [{{ var "missing" }}]`,
+	))
+	assert.Equal(t, " red []", testTemplateToString(tmpl, nil))
+}
@@ -0,0 +1,55 @@
+package styling
+
+import (
+	"strconv"
+
+	"github.com/jwalton/gchalk"
+	"github.com/jwalton/gchalk/pkg/ansistyles"
+	"github.com/jwalton/kitsch/internal/colortools"
+)
+
+// underlineColorPrefix is the prefix for setting the color of an underline
+// (e.g. "underline:red").
+const underlineColorPrefix = "underline:"
+
+// styledUnderlines maps each styled underline modifier to its SGR 4 subparameter.
+var styledUnderlines = map[string]string{
+	"doubleUnderline": "2",
+	"curlyUnderline":  "3",
+	"dottedUnderline": "4",
+	"dashedUnderline": "5",
+}
+
+// modifierAliases are alternate names for modifiers gchalk already supports.
+var modifierAliases = map[string]string{
+	"faint": "dim",
+}
+
+// underlineColorCode returns the SGR sequence which sets the underline color
+// to the given color, at the given color level.  Named colors use their index
+// in the terminal's palette.
+func underlineColorCode(color string, level gchalk.ColorLevel) (string, error) {
+	if index, ok := ansi256Index(color); ok {
+		return "\u001b[58;5;" + strconv.Itoa(int(index)) + "m", nil
+	}
+
+	if name, ok := ansiColorName(color); ok {
+		for index, colorName := range ansiColorNames {
+			if colorName == name {
+				return "\u001b[58;5;" + strconv.Itoa(index) + "m", nil
+			}
+		}
+	}
+	if color == "grey" || color == "gray" {
+		return "\u001b[58;5;8m", nil
+	}
+
+	c, err := colortools.ParseColor(color)
+	if err != nil {
+		return "", err
+	}
+	if level >= gchalk.LevelAnsi16m {
+		return "\u001b[58;2;" + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B)) + "m", nil
+	}
+	return "\u001b[58;5;" + strconv.Itoa(int(ansistyles.RGBToAnsi256(c.R, c.G, c.B))) + "m", nil
+}
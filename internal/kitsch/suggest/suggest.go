@@ -0,0 +1,89 @@
+// Package suggest finds likely corrections for misspelled words, for "did you
+// mean" style error messages.
+package suggest
+
+import "strings"
+
+// Closest returns the candidate which is most similar to word, or "" if no
+// candidate is close enough to be a likely misspelling.  Comparisons are
+// case-insensitive.
+func Closest(word string, candidates []string) string {
+	lowerWord := strings.ToLower(word)
+
+	// Allow roughly one mistake for every three characters, but always allow
+	// at least one, and never allow more than three.
+	maxDistance := len(word) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	} else if maxDistance > 3 {
+		maxDistance = 3
+	}
+
+	best := ""
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(lowerWord, strings.ToLower(candidate))
+		if distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// DidYouMean returns a string like ` (did you mean "foo"?)` if there is a
+// close match for word in candidates, or "" otherwise.
+func DidYouMean(word string, candidates []string) string {
+	if closest := Closest(word, candidates); closest != "" {
+		return ` (did you mean "` + closest + `"?)`
+	}
+	return ""
+}
+
+// editDistance returns the optimal string alignment distance between two
+// strings; this is the Levenshtein distance, except that swapping two adjacent
+// characters counts as a single edit.
+func editDistance(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+
+	// distances[i][j] is the distance between ra[:i] and rb[:j].
+	distances := make([][]int, len(ra)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(rb)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			distance := minimum(
+				distances[i-1][j]+1,
+				distances[i][j-1]+1,
+				distances[i-1][j-1]+cost,
+			)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				distance = minimum(distance, distances[i-2][j-2]+1)
+			}
+			distances[i][j] = distance
+		}
+	}
+
+	return distances[len(ra)][len(rb)]
+}
+
+func minimum(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
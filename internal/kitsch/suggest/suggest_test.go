@@ -0,0 +1,31 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"git_head", "git_status", "git_state", "directory", "prompt"}
+
+	assert.Equal(t, "git_head", Closest("gti_head", candidates))
+	assert.Equal(t, "directory", Closest("Directroy", candidates))
+	assert.Equal(t, "prompt", Closest("prompt", candidates))
+	assert.Equal(t, "", Closest("kubernetes", candidates))
+	assert.Equal(t, "", Closest("x", candidates))
+	assert.Equal(t, "style", Closest("stlye", []string{"style", "template"}))
+}
+
+func TestDidYouMean(t *testing.T) {
+	assert.Equal(t, ` (did you mean "template"?)`, DidYouMean("tempalte", []string{"style", "template"}))
+	assert.Equal(t, "", DidYouMean("foo", []string{"style", "template"}))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("", ""))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 1, editDistance("stlye", "style"))
+	assert.Equal(t, 1, editDistance("tempalte", "template"))
+}
@@ -0,0 +1,269 @@
+// Package toolversions finds the version of a tool that a project has pinned,
+// by reading version files like asdf's `.tool-versions`, mise's `mise.toml`,
+// `.nvmrc`, `.python-version`, and `.ruby-version`.
+//
+package toolversions
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jwalton/kitsch/internal/fileutils"
+)
+
+// Pin is a version of a tool pinned by a version file.
+type Pin struct {
+	// Tool is the canonical name of the tool (e.g. "node", "python").
+	Tool string
+	// Version is the pinned version, as written in the file.
+	Version string
+	// File is the full path to the file the version was read from.
+	File string
+}
+
+// toolAliases maps alternate tool names to canonical tool names.  asdf plugins
+// are not always named after the tool they install.
+var toolAliases = map[string]string{
+	"nodejs": "node",
+	"golang": "go",
+}
+
+// idiomaticFiles are single-tool version files, and the tool they pin.
+var idiomaticFiles = []struct {
+	file string
+	tool string
+}{
+	{".nvmrc", "node"},
+	{".node-version", "node"},
+	{".python-version", "python"},
+	{".ruby-version", "ruby"},
+	{".go-version", "go"},
+	{".java-version", "java"},
+}
+
+// miseFiles are the mise configuration files we will read, in priority order.
+var miseFiles = []string{
+	"mise.local.toml",
+	".mise.local.toml",
+	"mise.toml",
+	".mise.toml",
+	filepath.Join(".config", "mise.toml"),
+	filepath.Join(".mise", "config.toml"),
+}
+
+// CanonicalTool returns the canonical name for a tool.
+func CanonicalTool(tool string) string {
+	if alias, ok := toolAliases[tool]; ok {
+		return alias
+	}
+	return tool
+}
+
+// Resolver finds pinned tool versions for a directory.  Version files are
+// read the first time a version is requested, and the results are cached.
+// A Resolver is safe to use from multiple goroutines.
+type Resolver struct {
+	dir  fileutils.Directory
+	once sync.Once
+	pins map[string]Pin
+}
+
+// NewResolver returns a new Resolver for the given directory.  Version files
+// are found and read through `dir`, so they share its cached walk of the
+// directory's ancestors.
+func NewResolver(dir fileutils.Directory) *Resolver {
+	return &Resolver{dir: dir}
+}
+
+// Get returns the pinned version for the given tool.  The second return value
+// will be false if no version is pinned.
+func (resolver *Resolver) Get(tool string) (Pin, bool) {
+	resolver.once.Do(resolver.load)
+	pin, ok := resolver.pins[CanonicalTool(tool)]
+	return pin, ok
+}
+
+// versionFile is a version file we found in one of the resolver's ancestors.
+type versionFile struct {
+	// path is the full path to the file.
+	path string
+	// folder is the folder the file was found in.
+	folder string
+	// priority is the precedence of this file within its folder.  Lower
+	// values win.
+	priority int
+	parse    func(contents []byte) map[string]string
+}
+
+// load reads every version file in the resolver's directory and its
+// ancestors.  The version file closest to the directory wins.  Within a single
+// directory, mise configuration takes precedence over `.tool-versions`, which
+// takes precedence over tool specific files like `.nvmrc`.
+func (resolver *Resolver) load() {
+	resolver.pins = map[string]Pin{}
+	if resolver.dir == nil {
+		return
+	}
+
+	var files []versionFile
+	priority := 0
+	find := func(name string, parse func(contents []byte) map[string]string) {
+		for _, path := range resolver.dir.FindAllInAncestors(name) {
+			files = append(files, versionFile{
+				path:     path,
+				folder:   strings.TrimSuffix(path, name),
+				priority: priority,
+				parse:    parse,
+			})
+		}
+		priority++
+	}
+
+	for _, name := range miseFiles {
+		find(name, parseMiseToml)
+	}
+	find(".tool-versions", parseToolVersions)
+	for _, idiomatic := range idiomaticFiles {
+		tool := idiomatic.tool
+		find(idiomatic.file, func(contents []byte) map[string]string {
+			if version := parseIdiomaticFile(contents); version != "" {
+				return map[string]string{tool: version}
+			}
+			return nil
+		})
+	}
+
+	// Every folder is an ancestor of the resolver's directory, so the
+	// longest path is the closest folder.
+	sort.SliceStable(files, func(i, j int) bool {
+		if len(files[i].folder) != len(files[j].folder) {
+			return len(files[i].folder) > len(files[j].folder)
+		}
+		return files[i].priority < files[j].priority
+	})
+
+	for _, file := range files {
+		if contents, err := resolver.dir.ReadFile(file.path); err == nil {
+			resolver.addAll(file.path, file.parse(contents))
+		}
+	}
+}
+
+// addAll adds versions for any tools that have not already been pinned.
+func (resolver *Resolver) addAll(file string, versions map[string]string) {
+	for tool, version := range versions {
+		tool = CanonicalTool(tool)
+		if _, exists := resolver.pins[tool]; !exists {
+			resolver.pins[tool] = Pin{Tool: tool, Version: version, File: file}
+		}
+	}
+}
+
+// parseToolVersions parses an asdf `.tool-versions` file.  Each line is a tool
+// name followed by one or more versions; the first version is the preferred one.
+func parseToolVersions(contents []byte) map[string]string {
+	result := map[string]string{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		if index := strings.Index(line, "#"); index != -1 {
+			line = line[:index]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if _, exists := result[fields[0]]; !exists {
+			result[fields[0]] = fields[1]
+		}
+	}
+	return result
+}
+
+// parseMiseToml reads the `[tools]` section from a mise configuration file.
+// A tool's version may be a string, a list of strings, or a table with
+// a `version` key.
+func parseMiseToml(contents []byte) map[string]string {
+	var config struct {
+		Tools map[string]interface{} `toml:"tools"`
+	}
+	result := map[string]string{}
+
+	if err := toml.Unmarshal(contents, &config); err != nil {
+		return result
+	}
+
+	for tool, value := range config.Tools {
+		if version := miseVersion(value); version != "" {
+			result[tool] = version
+		}
+	}
+	return result
+}
+
+func miseVersion(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		if len(v) > 0 {
+			return miseVersion(v[0])
+		}
+	case map[string]interface{}:
+		return miseVersion(v["version"])
+	}
+	return ""
+}
+
+// parseIdiomaticFile reads a single-tool version file like `.nvmrc`, which
+// contains only a version, optionally followed by comments.
+func parseIdiomaticFile(contents []byte) string {
+	for _, line := range strings.Split(string(contents), "\n") {
+		if index := strings.Index(line, "#"); index != -1 {
+			line = line[:index]
+		}
+		line = strings.TrimSpace(line)
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// Matches returns true if the installed version satisfies the pinned version.
+// A pinned version matches if each of its dot-separated parts matches the
+// installed version, so "20" and "20.11" both match "20.11.1".  Pins that are
+// not version numbers (e.g. "lts/*", "system", "latest") always match, since
+// we can't tell what they resolve to.
+func Matches(pinned string, installed string) bool {
+	pinned = normalizeVersion(pinned)
+	installed = normalizeVersion(installed)
+
+	if pinned == "" || installed == "" || !isVersionNumber(pinned) {
+		return true
+	}
+
+	pinnedParts := strings.Split(pinned, ".")
+	installedParts := strings.Split(installed, ".")
+	if len(pinnedParts) > len(installedParts) {
+		return false
+	}
+
+	for index, part := range pinnedParts {
+		if part != installedParts[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeVersion(version string) string {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(version, "v")
+	return version
+}
+
+func isVersionNumber(version string) bool {
+	return version[0] >= '0' && version[0] <= '9'
+}
@@ -0,0 +1,101 @@
+package toolversions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/jwalton/kitsch/internal/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path string, contents string) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(path, []byte(contents), 0644)
+	assert.NoError(t, err)
+}
+
+func TestParseToolVersions(t *testing.T) {
+	versions := parseToolVersions([]byte(heredoc.Doc(`
+		# Comment
+		nodejs 20.11.1
+		python 3.11.4 3.10.2 # Two versions
+		ruby
+	`)))
+
+	assert.Equal(t, map[string]string{
+		"nodejs": "20.11.1",
+		"python": "3.11.4",
+	}, versions)
+}
+
+func TestParseMiseToml(t *testing.T) {
+	versions := parseMiseToml([]byte(heredoc.Doc(`
+		[env]
+		FOO = "bar"
+
+		[tools]
+		node = "20"
+		python = ["3.11", "3.10"]
+		ruby = { version = "3.2.2" }
+	`)))
+
+	assert.Equal(t, map[string]string{
+		"node":   "20",
+		"python": "3.11",
+		"ruby":   "3.2.2",
+	}, versions)
+}
+
+func TestResolverNearestWins(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".tool-versions"), "nodejs 18.0.0\npython 3.9.0\n")
+	writeFile(t, filepath.Join(root, "project", ".nvmrc"), "v20.11.1\n")
+	cwd := filepath.Join(root, "project", "src")
+	assert.NoError(t, os.MkdirAll(cwd, 0755))
+
+	resolver := NewResolver(fileutils.NewDirectory(cwd, 0, 0))
+
+	pin, ok := resolver.Get("node")
+	assert.True(t, ok)
+	assert.Equal(t, Pin{
+		Tool:    "node",
+		Version: "v20.11.1",
+		File:    filepath.Join(root, "project", ".nvmrc"),
+	}, pin)
+
+	pin, ok = resolver.Get("python")
+	assert.True(t, ok)
+	assert.Equal(t, "3.9.0", pin.Version)
+
+	_, ok = resolver.Get("ruby")
+	assert.False(t, ok)
+}
+
+func TestResolverPrecedenceInSameFolder(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "mise.toml"), "[tools]\nnode = \"22\"\n")
+	writeFile(t, filepath.Join(root, ".tool-versions"), "nodejs 20.0.0\nruby 3.1.0\n")
+	writeFile(t, filepath.Join(root, ".ruby-version"), "3.3.0\n")
+
+	resolver := NewResolver(fileutils.NewDirectory(root, 0, 0))
+
+	pin, _ := resolver.Get("nodejs")
+	assert.Equal(t, "22", pin.Version)
+	pin, _ = resolver.Get("ruby")
+	assert.Equal(t, "3.1.0", pin.Version)
+}
+
+func TestMatches(t *testing.T) {
+	assert.True(t, Matches("20", "20.11.1"))
+	assert.True(t, Matches("v20.11.1", "20.11.1"))
+	assert.True(t, Matches("3.11", "3.11.4"))
+	assert.True(t, Matches("lts/*", "20.11.1"))
+	assert.True(t, Matches("system", "3.11.4"))
+	assert.True(t, Matches("3.11", ""))
+	assert.False(t, Matches("18", "20.11.1"))
+	assert.False(t, Matches("3.11.4", "3.11"))
+	assert.False(t, Matches("3.1", "3.11.4"))
+}
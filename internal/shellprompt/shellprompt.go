@@ -3,6 +3,8 @@
 package shellprompt
 
 import (
+	"strings"
+
 	"github.com/jwalton/go-ansiparser"
 )
 
@@ -16,11 +18,60 @@ func AddZeroWidthCharacterEscapes(shell string, prompt string) string {
 	case "bash":
 		// https://www.gnu.org/software/bash/manual/html_node/Controlling-the-Prompt.html#Controlling-the-Prompt
 		return addZeroWidthCharacterEscapes(prompt, "\\[", "\\]")
+	case "tcsh":
+		return addZeroWidthCharacterEscapes(prompt, "%{", "%}")
 	}
 
 	return prompt
 }
 
+// EscapeSpecialCharacters escapes any characters in the prompt which the given
+// shell would otherwise interpret.  Escape codes in the prompt are left alone.
+func EscapeSpecialCharacters(shell string, prompt string) string {
+	var replacer *strings.Replacer
+
+	switch shell {
+	case "xonsh":
+		// xonsh treats the prompt as a format string, where "{field}" is
+		// replaced with a value.
+		// https://xon.sh/tutorial.html#customizing-the-prompt
+		replacer = strings.NewReplacer("{", "{{", "}", "}}")
+	case "tcsh":
+		// https://www.tcsh.org/manual/html/Special_shell_variables.html#prompt
+		replacer = strings.NewReplacer("%", "%%", "!", "\\!")
+	default:
+		return prompt
+	}
+
+	parsed := ansiparser.Parse(prompt)
+	result := ""
+
+	for _, part := range parsed {
+		if part.Type == ansiparser.EscapeCode {
+			result += part.Content
+		} else {
+			result += replacer.Replace(part.Content)
+		}
+	}
+
+	return result
+}
+
+// StripEscapeCodes removes all escape codes from the prompt, leaving only the
+// printable text.
+func StripEscapeCodes(prompt string) string {
+	parsed := ansiparser.Parse(prompt)
+	result := strings.Builder{}
+
+	for _, part := range parsed {
+		if part.Type != ansiparser.EscapeCode {
+			result.WriteString(part.Content)
+		}
+	}
+
+	return result.String()
+}
+
 func addZeroWidthCharacterEscapes(prompt string, start string, end string) string {
 	parsed := ansiparser.Parse(prompt)
 	result := ""
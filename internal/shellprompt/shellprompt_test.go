@@ -0,0 +1,36 @@
+package shellprompt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeSpecialCharacters(t *testing.T) {
+	assert.Equal(t, "{{user}} $ ", EscapeSpecialCharacters("xonsh", "{user} $ "))
+	assert.Equal(t, "{user} $ ", EscapeSpecialCharacters("bash", "{user} $ "))
+	assert.Equal(t, "\x1b[31m100%%\\!\x1b[39m", EscapeSpecialCharacters("tcsh", "\x1b[31m100%!\x1b[39m"))
+}
+
+func TestAddZeroWidthCharacterEscapes(t *testing.T) {
+	prompt := "\x1b[31m$\x1b[39m "
+	assert.Equal(t, "\\[\x1b[31m\\]$\\[\x1b[39m\\] ", AddZeroWidthCharacterEscapes("bash", prompt))
+	assert.Equal(t, "%{\x1b[31m%}$%{\x1b[39m%} ", AddZeroWidthCharacterEscapes("zsh", prompt))
+	assert.Equal(t, "%{\x1b[31m%}$%{\x1b[39m%} ", AddZeroWidthCharacterEscapes("tcsh", prompt))
+	assert.Equal(t, prompt, AddZeroWidthCharacterEscapes("xonsh", prompt))
+
+	// Erasing to the end of the line takes up no space.
+	assert.Equal(t, "$\\[\x1b[K\\]", AddZeroWidthCharacterEscapes("bash", "$\x1b[K"))
+}
+
+func TestStripEscapeCodes(t *testing.T) {
+	assert.Equal(t, "~/dev $ ", StripEscapeCodes("\x1b[34m~/dev\x1b[39m \x1b]8;;file:///\a$\x1b]8;;\a "))
+}
+
+func TestToTmux(t *testing.T) {
+	assert.Equal(t, "#[fg=red]master#[fg=default] ##1", ToTmux("\x1b[31mmaster\x1b[39m #1"))
+	assert.Equal(t, "#[bold,fg=brightblue,bg=colour236]~#[nobold,nodim]", ToTmux("\x1b[1;94;48;5;236m~\x1b[22m"))
+	assert.Equal(t, "#[fg=#ff8000]x#[default]", ToTmux("\x1b[38;2;255;128;0mx\x1b[0m"))
+	// Hyperlinks are removed.
+	assert.Equal(t, "dir", ToTmux("\x1b]8;;file:///tmp\adir\x1b]8;;\a"))
+}
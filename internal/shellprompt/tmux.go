@@ -0,0 +1,124 @@
+package shellprompt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jwalton/go-ansiparser"
+)
+
+// tmuxBasicColors are the names tmux uses for the 8 basic ANSI colors.
+var tmuxBasicColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// tmuxAttributes maps SGR parameters to tmux style attributes.
+var tmuxAttributes = map[int]string{
+	1:  "bold",
+	2:  "dim",
+	3:  "italics",
+	4:  "underscore",
+	5:  "blink",
+	7:  "reverse",
+	8:  "hidden",
+	9:  "strikethrough",
+	22: "nobold,nodim",
+	23: "noitalics",
+	24: "nounderscore",
+	25: "noblink",
+	27: "noreverse",
+	28: "nohidden",
+	29: "nostrikethrough",
+	53: "overline",
+	55: "nooverline",
+}
+
+// ToTmux converts a prompt with ANSI escape codes into a string that can be
+// used in a tmux status line, where styles are set with `#[...]` directives.
+// Escape codes that tmux has no equivalent for, like hyperlinks, are removed.
+func ToTmux(prompt string) string {
+	parsed := ansiparser.Parse(prompt)
+	result := strings.Builder{}
+
+	for _, part := range parsed {
+		if part.Type != ansiparser.EscapeCode {
+			// "#" starts a format in tmux.
+			result.WriteString(strings.ReplaceAll(part.Content, "#", "##"))
+		} else if style := sgrToTmux(part.Content); style != "" {
+			result.WriteString("#[" + style + "]")
+		}
+	}
+
+	return result.String()
+}
+
+// sgrToTmux converts an SGR escape code (e.g. "\x1b[1;31m") into a tmux style
+// (e.g. "bold,fg=red").  Returns "" if the escape code is not an SGR code.
+func sgrToTmux(code string) string {
+	if !strings.HasPrefix(code, "\x1b[") || !strings.HasSuffix(code, "m") {
+		return ""
+	}
+
+	paramString := code[2 : len(code)-1]
+	if paramString == "" {
+		return "default"
+	}
+
+	var params []int
+	for _, param := range strings.Split(paramString, ";") {
+		value, err := strconv.Atoi(param)
+		if err != nil {
+			return ""
+		}
+		params = append(params, value)
+	}
+
+	var styles []string
+	for index := 0; index < len(params); index++ {
+		param := params[index]
+
+		switch {
+		case param == 0:
+			styles = append(styles, "default")
+		case param >= 30 && param <= 37:
+			styles = append(styles, "fg="+tmuxBasicColors[param-30])
+		case param >= 90 && param <= 97:
+			styles = append(styles, "fg=bright"+tmuxBasicColors[param-90])
+		case param >= 40 && param <= 47:
+			styles = append(styles, "bg="+tmuxBasicColors[param-40])
+		case param >= 100 && param <= 107:
+			styles = append(styles, "bg=bright"+tmuxBasicColors[param-100])
+		case param == 39:
+			styles = append(styles, "fg=default")
+		case param == 49:
+			styles = append(styles, "bg=default")
+		case param == 38 || param == 48:
+			color, used := extendedColor(params[index+1:])
+			index += used
+			if color != "" {
+				if param == 38 {
+					styles = append(styles, "fg="+color)
+				} else {
+					styles = append(styles, "bg="+color)
+				}
+			}
+		default:
+			if attribute, ok := tmuxAttributes[param]; ok {
+				styles = append(styles, attribute)
+			}
+		}
+	}
+
+	return strings.Join(styles, ",")
+}
+
+// extendedColor converts the parameters following a 38 or 48 SGR parameter
+// into a tmux color.  Returns the color, and the number of parameters used.
+func extendedColor(params []int) (string, int) {
+	if len(params) >= 2 && params[0] == 5 {
+		return fmt.Sprintf("colour%d", params[1]), 2
+	}
+	if len(params) >= 4 && params[0] == 2 {
+		return fmt.Sprintf("#%02x%02x%02x", params[1], params[2], params[3]), 4
+	}
+	return "", len(params)
+}
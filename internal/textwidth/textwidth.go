@@ -0,0 +1,159 @@
+// Package textwidth works out how many columns a string will occupy when it is
+// printed to a terminal.
+//
+// Counting bytes or runes gets this wrong for a lot of strings we might put in
+// a prompt: CJK characters and most emoji take up two columns, combining
+// marks and zero-width joiners take up none, a sequence like "👩🏻‍🚀" is several
+// runes but a single two-column character, and ANSI escape codes don't take up
+// any space at all.
+package textwidth
+
+import (
+	"strings"
+
+	"github.com/jwalton/go-ansiparser"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// emojiPresentationSelector is the variation selector which asks for a
+// character to be drawn as a (two column) emoji, as in "⚠️".
+const emojiPresentationSelector = '\uFE0F'
+
+// Width returns the number of columns the given string will occupy when
+// printed to a terminal.  ANSI escape codes in the string are ignored.
+func Width(s string) int {
+	width := 0
+
+	tokenizer := ansiparser.NewStringTokenizer(s)
+	for tokenizer.Next() {
+		token := tokenizer.Token()
+		if token.Type != ansiparser.String {
+			continue
+		}
+		if token.IsASCII {
+			width += len(token.Content)
+		} else {
+			width += stringWidth(token.Content)
+		}
+	}
+
+	return width
+}
+
+// GraphemeWidth returns the number of columns a single grapheme cluster (a
+// single user-perceived character, which may be made up of several runes)
+// will occupy when printed to a terminal.
+func GraphemeWidth(grapheme string) int {
+	if len(grapheme) == 1 {
+		return 1
+	}
+
+	if strings.ContainsRune(grapheme, emojiPresentationSelector) {
+		return 2
+	}
+
+	// A pair of regional indicators is a flag.
+	runes := []rune(grapheme)
+	if len(runes) == 2 && isRegionalIndicator(runes[0]) && isRegionalIndicator(runes[1]) {
+		return 2
+	}
+
+	return runewidth.StringWidth(grapheme)
+}
+
+// Truncate shortens the given string so it occupies at most `width` columns.
+// If the string needs to be truncated, `ellipsis` is added at the point where
+// it was cut.  ANSI escape codes are preserved, even the ones in the part of
+// the string which was removed, so any style which is reset at the end of the
+// string is still reset.
+func Truncate(s string, width int, ellipsis string) string {
+	if Width(s) <= width {
+		return s
+	}
+
+	available := width - Width(ellipsis)
+	if available < 0 {
+		// Not enough room for the ellipsis.
+		available = width
+		ellipsis = ""
+	}
+
+	var result strings.Builder
+	used := 0
+	truncated := false
+
+	tokenizer := ansiparser.NewStringTokenizer(s)
+	for tokenizer.Next() {
+		token := tokenizer.Token()
+		if token.Type != ansiparser.String {
+			result.WriteString(token.Content)
+			continue
+		}
+		if truncated {
+			continue
+		}
+
+		graphemes := uniseg.NewGraphemes(token.Content)
+		for graphemes.Next() {
+			grapheme := graphemes.Str()
+			graphemeWidth := GraphemeWidth(grapheme)
+			if used+graphemeWidth > available {
+				result.WriteString(ellipsis)
+				truncated = true
+				break
+			}
+			result.WriteString(grapheme)
+			used += graphemeWidth
+		}
+	}
+
+	return result.String()
+}
+
+// Align is how to align text when padding it to a given width.
+type Align string
+
+const (
+	// AlignLeft adds padding to the end of the text.
+	AlignLeft Align = "left"
+	// AlignRight adds padding to the start of the text.
+	AlignRight Align = "right"
+	// AlignCenter splits the padding between the start and end of the text.
+	// If the padding can't be split evenly, the extra space goes at the end.
+	AlignCenter Align = "center"
+)
+
+// Pad adds spaces to the given string so it occupies at least `width`
+// columns.  Strings which are already `width` columns or wider are returned
+// unchanged.
+func Pad(s string, width int, align Align) string {
+	padding := width - Width(s)
+	if padding <= 0 {
+		return s
+	}
+
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", padding) + s
+	case AlignCenter:
+		left := padding / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", padding-left)
+	default:
+		return s + strings.Repeat(" ", padding)
+	}
+}
+
+// stringWidth returns the width of a string which has no ANSI escape codes.
+func stringWidth(s string) int {
+	width := 0
+	graphemes := uniseg.NewGraphemes(s)
+	for graphemes.Next() {
+		width += GraphemeWidth(graphemes.Str())
+	}
+	return width
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
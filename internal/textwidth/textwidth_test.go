@@ -0,0 +1,59 @@
+package textwidth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWidth(t *testing.T) {
+	for _, test := range []struct {
+		str      string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"\x1b[31mhello\x1b[39m", 5},
+		{"日本語", 6},
+		{"é", 1},
+		{"👩🏻‍🚀", 2},
+		{"⚠️", 2},
+		{"⚠", 1},
+		{"🇨🇦", 2},
+		{"\x1b[1m☕\x1b[22m main", 7},
+	} {
+		assert.Equal(t, test.expected, Width(test.str), "%q", test.str)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	for _, test := range []struct {
+		str      string
+		width    int
+		expected string
+	}{
+		{"hello", 5, "hello"},
+		{"hello world", 8, "hello w…"},
+		{"日本語", 4, "日…"},
+		{"日本語", 5, "日本…"},
+		{"a👩🏻‍🚀b", 3, "a…"},
+		{"\x1b[31mhello world\x1b[39m", 6, "\x1b[31mhello…\x1b[39m"},
+		{"\x1b[31mhello\x1b[39m \x1b[32mworld\x1b[39m", 4, "\x1b[31mhel…\x1b[39m\x1b[32m\x1b[39m"},
+		{"hello", 0, ""},
+	} {
+		actual := Truncate(test.str, test.width, "…")
+		assert.Equal(t, test.expected, actual, "%q", test.str)
+		assert.LessOrEqual(t, Width(actual), test.width)
+	}
+
+	assert.Equal(t, "he", Truncate("hello", 2, "..."))
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, "ab   ", Pad("ab", 5, AlignLeft))
+	assert.Equal(t, "   ab", Pad("ab", 5, AlignRight))
+	assert.Equal(t, " ab  ", Pad("ab", 5, AlignCenter))
+	assert.Equal(t, "日本 ", Pad("日本", 5, AlignLeft))
+	assert.Equal(t, "  \x1b[31mab\x1b[39m", Pad("\x1b[31mab\x1b[39m", 4, AlignRight))
+	assert.Equal(t, "abcdef", Pad("abcdef", 5, AlignCenter))
+}
@@ -0,0 +1,71 @@
+package sampleconfig
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jwalton/kitsch/internal/kitsch/suggest"
+)
+
+//go:embed presets/*.yaml
+var presetFiles embed.FS
+
+// Preset is a complete configuration bundled with kitsch.
+type Preset struct {
+	// Name is the name of the preset.
+	Name string
+	// Description is a short description of the preset, taken from the
+	// comment on the first line of the preset's file.
+	Description string
+}
+
+// Presets returns a list of all bundled presets, sorted by name.
+func Presets() []Preset {
+	entries, err := presetFiles.ReadDir("presets")
+	if err != nil {
+		return nil
+	}
+
+	presets := make([]Preset, 0, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".yaml")
+		data, err := GetPreset(name)
+		if err != nil {
+			continue
+		}
+		presets = append(presets, Preset{Name: name, Description: presetDescription(data)})
+	}
+
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets
+}
+
+// GetPreset returns the YAML configuration for the named preset.
+func GetPreset(name string) ([]byte, error) {
+	data, err := presetFiles.ReadFile(path.Join("presets", name+".yaml"))
+	if err != nil {
+		var names []string
+		for _, preset := range Presets() {
+			names = append(names, preset.Name)
+		}
+		return nil, fmt.Errorf("unknown preset %q%s", name, suggest.DidYouMean(name, names))
+	}
+	return data, nil
+}
+
+// presetDescription returns the comment from the first line of a preset.
+func presetDescription(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "#"))
+		}
+	}
+	return ""
+}